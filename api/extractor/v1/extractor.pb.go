@@ -0,0 +1,916 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: extractor/v1/extractor.proto
+
+package extractorv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExtractServiceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Service name, e.g. dynamodb.
+	ServiceName string `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Classify unsupported operations with Bedrock.
+	Classify bool `protobuf:"varint,2,opt,name=classify,proto3" json:"classify,omitempty"`
+	// Maximum number of operations per page; all matching operations when 0.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Cursor of the page to return, the next_cursor of the previous response.
+	Cursor string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Only operations of this type, e.g. control_plane.
+	Type string `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	// Only supported operations when true, only unsupported ones when false.
+	Supported *bool `protobuf:"varint,6,opt,name=supported,proto3,oneof" json:"supported,omitempty"`
+	// Only operations whose name starts with this prefix.
+	NamePrefix    string `protobuf:"bytes,7,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractServiceRequest) Reset() {
+	*x = ExtractServiceRequest{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractServiceRequest) ProtoMessage() {}
+
+func (x *ExtractServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractServiceRequest.ProtoReflect.Descriptor instead.
+func (*ExtractServiceRequest) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExtractServiceRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *ExtractServiceRequest) GetClassify() bool {
+	if x != nil {
+		return x.Classify
+	}
+	return false
+}
+
+func (x *ExtractServiceRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ExtractServiceRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ExtractServiceRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ExtractServiceRequest) GetSupported() bool {
+	if x != nil && x.Supported != nil {
+		return *x.Supported
+	}
+	return false
+}
+
+func (x *ExtractServiceRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+type ExtractServiceResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName            string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	TotalOperations        int32                  `protobuf:"varint,2,opt,name=total_operations,json=totalOperations,proto3" json:"total_operations,omitempty"`
+	SupportedOperations    int32                  `protobuf:"varint,3,opt,name=supported_operations,json=supportedOperations,proto3" json:"supported_operations,omitempty"`
+	ControlPlaneOperations int32                  `protobuf:"varint,4,opt,name=control_plane_operations,json=controlPlaneOperations,proto3" json:"control_plane_operations,omitempty"`
+	SupportCoverage        float64                `protobuf:"fixed64,5,opt,name=support_coverage,json=supportCoverage,proto3" json:"support_coverage,omitempty"`
+	RelevantCoverage       float64                `protobuf:"fixed64,6,opt,name=relevant_coverage,json=relevantCoverage,proto3" json:"relevant_coverage,omitempty"`
+	Operations             []*Operation           `protobuf:"bytes,7,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Per-operation JSON as written to <service>-operations.json, for fields not modeled here.
+	OperationsJson string `protobuf:"bytes,8,opt,name=operations_json,json=operationsJson,proto3" json:"operations_json,omitempty"`
+	// Cursor of the next page, empty on the last page.
+	NextCursor string `protobuf:"bytes,9,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// Number of operations matching the filters across all pages.
+	MatchedOperations int32 `protobuf:"varint,10,opt,name=matched_operations,json=matchedOperations,proto3" json:"matched_operations,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ExtractServiceResponse) Reset() {
+	*x = ExtractServiceResponse{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractServiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractServiceResponse) ProtoMessage() {}
+
+func (x *ExtractServiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractServiceResponse.ProtoReflect.Descriptor instead.
+func (*ExtractServiceResponse) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExtractServiceResponse) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *ExtractServiceResponse) GetTotalOperations() int32 {
+	if x != nil {
+		return x.TotalOperations
+	}
+	return 0
+}
+
+func (x *ExtractServiceResponse) GetSupportedOperations() int32 {
+	if x != nil {
+		return x.SupportedOperations
+	}
+	return 0
+}
+
+func (x *ExtractServiceResponse) GetControlPlaneOperations() int32 {
+	if x != nil {
+		return x.ControlPlaneOperations
+	}
+	return 0
+}
+
+func (x *ExtractServiceResponse) GetSupportCoverage() float64 {
+	if x != nil {
+		return x.SupportCoverage
+	}
+	return 0
+}
+
+func (x *ExtractServiceResponse) GetRelevantCoverage() float64 {
+	if x != nil {
+		return x.RelevantCoverage
+	}
+	return 0
+}
+
+func (x *ExtractServiceResponse) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *ExtractServiceResponse) GetOperationsJson() string {
+	if x != nil {
+		return x.OperationsJson
+	}
+	return ""
+}
+
+func (x *ExtractServiceResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ExtractServiceResponse) GetMatchedOperations() int32 {
+	if x != nil {
+		return x.MatchedOperations
+	}
+	return 0
+}
+
+type Operation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// control_plane, data_plane, unknown, unclassified or unsampled.
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// implemented, partially-implemented, intentionally-ignored, excluded, planned or unsupported.
+	SupportStatus      string      `protobuf:"bytes,3,opt,name=support_status,json=supportStatus,proto3" json:"support_status,omitempty"`
+	Locations          []*Location `protobuf:"bytes,4,rep,name=locations,proto3" json:"locations,omitempty"`
+	Relevance          float64     `protobuf:"fixed64,5,opt,name=relevance,proto3" json:"relevance,omitempty"`
+	Declarative        bool        `protobuf:"varint,6,opt,name=declarative,proto3" json:"declarative,omitempty"`
+	ApiVersion         string      `protobuf:"bytes,7,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	ReleaseStage       string      `protobuf:"bytes,8,opt,name=release_stage,json=releaseStage,proto3" json:"release_stage,omitempty"`
+	Consistency        string      `protobuf:"bytes,9,opt,name=consistency,proto3" json:"consistency,omitempty"`
+	ExclusionReason    string      `protobuf:"bytes,10,opt,name=exclusion_reason,json=exclusionReason,proto3" json:"exclusion_reason,omitempty"`
+	PredictedResource  string      `protobuf:"bytes,11,opt,name=predicted_resource,json=predictedResource,proto3" json:"predicted_resource,omitempty"`
+	CloudformationType string      `protobuf:"bytes,12,opt,name=cloudformation_type,json=cloudformationType,proto3" json:"cloudformation_type,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Operation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Operation) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Operation) GetSupportStatus() string {
+	if x != nil {
+		return x.SupportStatus
+	}
+	return ""
+}
+
+func (x *Operation) GetLocations() []*Location {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *Operation) GetRelevance() float64 {
+	if x != nil {
+		return x.Relevance
+	}
+	return 0
+}
+
+func (x *Operation) GetDeclarative() bool {
+	if x != nil {
+		return x.Declarative
+	}
+	return false
+}
+
+func (x *Operation) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *Operation) GetReleaseStage() string {
+	if x != nil {
+		return x.ReleaseStage
+	}
+	return ""
+}
+
+func (x *Operation) GetConsistency() string {
+	if x != nil {
+		return x.Consistency
+	}
+	return ""
+}
+
+func (x *Operation) GetExclusionReason() string {
+	if x != nil {
+		return x.ExclusionReason
+	}
+	return ""
+}
+
+func (x *Operation) GetPredictedResource() string {
+	if x != nil {
+		return x.PredictedResource
+	}
+	return ""
+}
+
+func (x *Operation) GetCloudformationType() string {
+	if x != nil {
+		return x.CloudformationType
+	}
+	return ""
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          string                 `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Line          int32                  `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Location) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Location) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+type GeneratePolicyRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Partition of the resource ARNs; aws when empty.
+	Partition     string `protobuf:"bytes,2,opt,name=partition,proto3" json:"partition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratePolicyRequest) Reset() {
+	*x = GeneratePolicyRequest{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratePolicyRequest) ProtoMessage() {}
+
+func (x *GeneratePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratePolicyRequest.ProtoReflect.Descriptor instead.
+func (*GeneratePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GeneratePolicyRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *GeneratePolicyRequest) GetPartition() string {
+	if x != nil {
+		return x.Partition
+	}
+	return ""
+}
+
+type GeneratePolicyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Managed policies to attach to the controller role, more than one when the
+	// policy exceeds the managed policy size limit.
+	Policies      []*Policy        `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	Findings      []*PolicyFinding `protobuf:"bytes,2,rep,name=findings,proto3" json:"findings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratePolicyResponse) Reset() {
+	*x = GeneratePolicyResponse{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratePolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratePolicyResponse) ProtoMessage() {}
+
+func (x *GeneratePolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratePolicyResponse.ProtoReflect.Descriptor instead.
+func (*GeneratePolicyResponse) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GeneratePolicyResponse) GetPolicies() []*Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+func (x *GeneratePolicyResponse) GetFindings() []*PolicyFinding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+type Policy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Suggested managed policy name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// IAM policy document JSON.
+	Document      string `protobuf:"bytes,2,opt,name=document,proto3" json:"document,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Policy) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Policy) GetDocument() string {
+	if x != nil {
+		return x.Document
+	}
+	return ""
+}
+
+type PolicyFinding struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Severity string                 `protobuf:"bytes,1,opt,name=severity,proto3" json:"severity,omitempty"`
+	Rule     string                 `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	// Index of the policy in GeneratePolicyResponse.policies.
+	Policy int32 `protobuf:"varint,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	// Statement index within the policy, -1 for the whole policy.
+	Statement     int32  `protobuf:"varint,4,opt,name=statement,proto3" json:"statement,omitempty"`
+	Message       string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyFinding) Reset() {
+	*x = PolicyFinding{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyFinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyFinding) ProtoMessage() {}
+
+func (x *PolicyFinding) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyFinding.ProtoReflect.Descriptor instead.
+func (*PolicyFinding) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PolicyFinding) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *PolicyFinding) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *PolicyFinding) GetPolicy() int32 {
+	if x != nil {
+		return x.Policy
+	}
+	return 0
+}
+
+func (x *PolicyFinding) GetStatement() int32 {
+	if x != nil {
+		return x.Statement
+	}
+	return 0
+}
+
+func (x *PolicyFinding) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ClassifyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Service the operations belong to, used as context in the prompt.
+	ServiceName   string   `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Operations    []string `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClassifyRequest) Reset() {
+	*x = ClassifyRequest{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassifyRequest) ProtoMessage() {}
+
+func (x *ClassifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassifyRequest.ProtoReflect.Descriptor instead.
+func (*ClassifyRequest) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ClassifyRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *ClassifyRequest) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type ClassifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ControlPlane  []string               `protobuf:"bytes,1,rep,name=control_plane,json=controlPlane,proto3" json:"control_plane,omitempty"`
+	DataPlane     []string               `protobuf:"bytes,2,rep,name=data_plane,json=dataPlane,proto3" json:"data_plane,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClassifyResponse) Reset() {
+	*x = ClassifyResponse{}
+	mi := &file_extractor_v1_extractor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassifyResponse) ProtoMessage() {}
+
+func (x *ClassifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_extractor_v1_extractor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassifyResponse.ProtoReflect.Descriptor instead.
+func (*ClassifyResponse) Descriptor() ([]byte, []int) {
+	return file_extractor_v1_extractor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ClassifyResponse) GetControlPlane() []string {
+	if x != nil {
+		return x.ControlPlane
+	}
+	return nil
+}
+
+func (x *ClassifyResponse) GetDataPlane() []string {
+	if x != nil {
+		return x.DataPlane
+	}
+	return nil
+}
+
+var File_extractor_v1_extractor_proto protoreflect.FileDescriptor
+
+const file_extractor_v1_extractor_proto_rawDesc = "" +
+	"\n" +
+	"\x1cextractor/v1/extractor.proto\x12\fextractor.v1\"\xea\x01\n" +
+	"\x15ExtractServiceRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x1a\n" +
+	"\bclassify\x18\x02 \x01(\bR\bclassify\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12!\n" +
+	"\tsupported\x18\x06 \x01(\bH\x00R\tsupported\x88\x01\x01\x12\x1f\n" +
+	"\vname_prefix\x18\a \x01(\tR\n" +
+	"namePrefixB\f\n" +
+	"\n" +
+	"_supported\"\xdd\x03\n" +
+	"\x16ExtractServiceResponse\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12)\n" +
+	"\x10total_operations\x18\x02 \x01(\x05R\x0ftotalOperations\x121\n" +
+	"\x14supported_operations\x18\x03 \x01(\x05R\x13supportedOperations\x128\n" +
+	"\x18control_plane_operations\x18\x04 \x01(\x05R\x16controlPlaneOperations\x12)\n" +
+	"\x10support_coverage\x18\x05 \x01(\x01R\x0fsupportCoverage\x12+\n" +
+	"\x11relevant_coverage\x18\x06 \x01(\x01R\x10relevantCoverage\x127\n" +
+	"\n" +
+	"operations\x18\a \x03(\v2\x17.extractor.v1.OperationR\n" +
+	"operations\x12'\n" +
+	"\x0foperations_json\x18\b \x01(\tR\x0eoperationsJson\x12\x1f\n" +
+	"\vnext_cursor\x18\t \x01(\tR\n" +
+	"nextCursor\x12-\n" +
+	"\x12matched_operations\x18\n" +
+	" \x01(\x05R\x11matchedOperations\"\xc3\x03\n" +
+	"\tOperation\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12%\n" +
+	"\x0esupport_status\x18\x03 \x01(\tR\rsupportStatus\x124\n" +
+	"\tlocations\x18\x04 \x03(\v2\x16.extractor.v1.LocationR\tlocations\x12\x1c\n" +
+	"\trelevance\x18\x05 \x01(\x01R\trelevance\x12 \n" +
+	"\vdeclarative\x18\x06 \x01(\bR\vdeclarative\x12\x1f\n" +
+	"\vapi_version\x18\a \x01(\tR\n" +
+	"apiVersion\x12#\n" +
+	"\rrelease_stage\x18\b \x01(\tR\freleaseStage\x12 \n" +
+	"\vconsistency\x18\t \x01(\tR\vconsistency\x12)\n" +
+	"\x10exclusion_reason\x18\n" +
+	" \x01(\tR\x0fexclusionReason\x12-\n" +
+	"\x12predicted_resource\x18\v \x01(\tR\x11predictedResource\x12/\n" +
+	"\x13cloudformation_type\x18\f \x01(\tR\x12cloudformationType\"2\n" +
+	"\bLocation\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\"X\n" +
+	"\x15GeneratePolicyRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x1c\n" +
+	"\tpartition\x18\x02 \x01(\tR\tpartition\"\x83\x01\n" +
+	"\x16GeneratePolicyResponse\x120\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x14.extractor.v1.PolicyR\bpolicies\x127\n" +
+	"\bfindings\x18\x02 \x03(\v2\x1b.extractor.v1.PolicyFindingR\bfindings\"8\n" +
+	"\x06Policy\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bdocument\x18\x02 \x01(\tR\bdocument\"\x8f\x01\n" +
+	"\rPolicyFinding\x12\x1a\n" +
+	"\bseverity\x18\x01 \x01(\tR\bseverity\x12\x12\n" +
+	"\x04rule\x18\x02 \x01(\tR\x04rule\x12\x16\n" +
+	"\x06policy\x18\x03 \x01(\x05R\x06policy\x12\x1c\n" +
+	"\tstatement\x18\x04 \x01(\x05R\tstatement\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"T\n" +
+	"\x0fClassifyRequest\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\tR\n" +
+	"operations\"V\n" +
+	"\x10ClassifyResponse\x12#\n" +
+	"\rcontrol_plane\x18\x01 \x03(\tR\fcontrolPlane\x12\x1d\n" +
+	"\n" +
+	"data_plane\x18\x02 \x03(\tR\tdataPlane2\x97\x02\n" +
+	"\x10ExtractorService\x12[\n" +
+	"\x0eExtractService\x12#.extractor.v1.ExtractServiceRequest\x1a$.extractor.v1.ExtractServiceResponse\x12[\n" +
+	"\x0eGeneratePolicy\x12#.extractor.v1.GeneratePolicyRequest\x1a$.extractor.v1.GeneratePolicyResponse\x12I\n" +
+	"\bClassify\x12\x1d.extractor.v1.ClassifyRequest\x1a\x1e.extractor.v1.ClassifyResponseBOZMgithub.com/aws-controllers-k8s/ack-api-extractor/api/extractor/v1;extractorv1b\x06proto3"
+
+var (
+	file_extractor_v1_extractor_proto_rawDescOnce sync.Once
+	file_extractor_v1_extractor_proto_rawDescData []byte
+)
+
+func file_extractor_v1_extractor_proto_rawDescGZIP() []byte {
+	file_extractor_v1_extractor_proto_rawDescOnce.Do(func() {
+		file_extractor_v1_extractor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_extractor_v1_extractor_proto_rawDesc), len(file_extractor_v1_extractor_proto_rawDesc)))
+	})
+	return file_extractor_v1_extractor_proto_rawDescData
+}
+
+var file_extractor_v1_extractor_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_extractor_v1_extractor_proto_goTypes = []any{
+	(*ExtractServiceRequest)(nil),  // 0: extractor.v1.ExtractServiceRequest
+	(*ExtractServiceResponse)(nil), // 1: extractor.v1.ExtractServiceResponse
+	(*Operation)(nil),              // 2: extractor.v1.Operation
+	(*Location)(nil),               // 3: extractor.v1.Location
+	(*GeneratePolicyRequest)(nil),  // 4: extractor.v1.GeneratePolicyRequest
+	(*GeneratePolicyResponse)(nil), // 5: extractor.v1.GeneratePolicyResponse
+	(*Policy)(nil),                 // 6: extractor.v1.Policy
+	(*PolicyFinding)(nil),          // 7: extractor.v1.PolicyFinding
+	(*ClassifyRequest)(nil),        // 8: extractor.v1.ClassifyRequest
+	(*ClassifyResponse)(nil),       // 9: extractor.v1.ClassifyResponse
+}
+var file_extractor_v1_extractor_proto_depIdxs = []int32{
+	2, // 0: extractor.v1.ExtractServiceResponse.operations:type_name -> extractor.v1.Operation
+	3, // 1: extractor.v1.Operation.locations:type_name -> extractor.v1.Location
+	6, // 2: extractor.v1.GeneratePolicyResponse.policies:type_name -> extractor.v1.Policy
+	7, // 3: extractor.v1.GeneratePolicyResponse.findings:type_name -> extractor.v1.PolicyFinding
+	0, // 4: extractor.v1.ExtractorService.ExtractService:input_type -> extractor.v1.ExtractServiceRequest
+	4, // 5: extractor.v1.ExtractorService.GeneratePolicy:input_type -> extractor.v1.GeneratePolicyRequest
+	8, // 6: extractor.v1.ExtractorService.Classify:input_type -> extractor.v1.ClassifyRequest
+	1, // 7: extractor.v1.ExtractorService.ExtractService:output_type -> extractor.v1.ExtractServiceResponse
+	5, // 8: extractor.v1.ExtractorService.GeneratePolicy:output_type -> extractor.v1.GeneratePolicyResponse
+	9, // 9: extractor.v1.ExtractorService.Classify:output_type -> extractor.v1.ClassifyResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_extractor_v1_extractor_proto_init() }
+func file_extractor_v1_extractor_proto_init() {
+	if File_extractor_v1_extractor_proto != nil {
+		return
+	}
+	file_extractor_v1_extractor_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_extractor_v1_extractor_proto_rawDesc), len(file_extractor_v1_extractor_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_extractor_v1_extractor_proto_goTypes,
+		DependencyIndexes: file_extractor_v1_extractor_proto_depIdxs,
+		MessageInfos:      file_extractor_v1_extractor_proto_msgTypes,
+	}.Build()
+	File_extractor_v1_extractor_proto = out.File
+	file_extractor_v1_extractor_proto_goTypes = nil
+	file_extractor_v1_extractor_proto_depIdxs = nil
+}
@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: extractor/v1/extractor.proto
+
+package extractorv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ExtractorService_ExtractService_FullMethodName = "/extractor.v1.ExtractorService/ExtractService"
+	ExtractorService_GeneratePolicy_FullMethodName = "/extractor.v1.ExtractorService/GeneratePolicy"
+	ExtractorService_Classify_FullMethodName       = "/extractor.v1.ExtractorService/Classify"
+)
+
+// ExtractorServiceClient is the client API for ExtractorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ExtractorService exposes operation extraction, policy generation and classification
+// to callers written in any language.
+type ExtractorServiceClient interface {
+	// ExtractService extracts the operations of a service and their controller support.
+	ExtractService(ctx context.Context, in *ExtractServiceRequest, opts ...grpc.CallOption) (*ExtractServiceResponse, error)
+	// GeneratePolicy generates the least-privilege IAM policy of a service's controller.
+	GeneratePolicy(ctx context.Context, in *GeneratePolicyRequest, opts ...grpc.CallOption) (*GeneratePolicyResponse, error)
+	// Classify classifies operation names as control plane or data plane with Bedrock.
+	Classify(ctx context.Context, in *ClassifyRequest, opts ...grpc.CallOption) (*ClassifyResponse, error)
+}
+
+type extractorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExtractorServiceClient(cc grpc.ClientConnInterface) ExtractorServiceClient {
+	return &extractorServiceClient{cc}
+}
+
+func (c *extractorServiceClient) ExtractService(ctx context.Context, in *ExtractServiceRequest, opts ...grpc.CallOption) (*ExtractServiceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtractServiceResponse)
+	err := c.cc.Invoke(ctx, ExtractorService_ExtractService_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extractorServiceClient) GeneratePolicy(ctx context.Context, in *GeneratePolicyRequest, opts ...grpc.CallOption) (*GeneratePolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GeneratePolicyResponse)
+	err := c.cc.Invoke(ctx, ExtractorService_GeneratePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extractorServiceClient) Classify(ctx context.Context, in *ClassifyRequest, opts ...grpc.CallOption) (*ClassifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClassifyResponse)
+	err := c.cc.Invoke(ctx, ExtractorService_Classify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExtractorServiceServer is the server API for ExtractorService service.
+// All implementations must embed UnimplementedExtractorServiceServer
+// for forward compatibility.
+//
+// ExtractorService exposes operation extraction, policy generation and classification
+// to callers written in any language.
+type ExtractorServiceServer interface {
+	// ExtractService extracts the operations of a service and their controller support.
+	ExtractService(context.Context, *ExtractServiceRequest) (*ExtractServiceResponse, error)
+	// GeneratePolicy generates the least-privilege IAM policy of a service's controller.
+	GeneratePolicy(context.Context, *GeneratePolicyRequest) (*GeneratePolicyResponse, error)
+	// Classify classifies operation names as control plane or data plane with Bedrock.
+	Classify(context.Context, *ClassifyRequest) (*ClassifyResponse, error)
+	mustEmbedUnimplementedExtractorServiceServer()
+}
+
+// UnimplementedExtractorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExtractorServiceServer struct{}
+
+func (UnimplementedExtractorServiceServer) ExtractService(context.Context, *ExtractServiceRequest) (*ExtractServiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtractService not implemented")
+}
+func (UnimplementedExtractorServiceServer) GeneratePolicy(context.Context, *GeneratePolicyRequest) (*GeneratePolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GeneratePolicy not implemented")
+}
+func (UnimplementedExtractorServiceServer) Classify(context.Context, *ClassifyRequest) (*ClassifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Classify not implemented")
+}
+func (UnimplementedExtractorServiceServer) mustEmbedUnimplementedExtractorServiceServer() {}
+func (UnimplementedExtractorServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeExtractorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExtractorServiceServer will
+// result in compilation errors.
+type UnsafeExtractorServiceServer interface {
+	mustEmbedUnimplementedExtractorServiceServer()
+}
+
+func RegisterExtractorServiceServer(s grpc.ServiceRegistrar, srv ExtractorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedExtractorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ExtractorService_ServiceDesc, srv)
+}
+
+func _ExtractorService_ExtractService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtractServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtractorServiceServer).ExtractService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtractorService_ExtractService_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtractorServiceServer).ExtractService(ctx, req.(*ExtractServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtractorService_GeneratePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeneratePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtractorServiceServer).GeneratePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtractorService_GeneratePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtractorServiceServer).GeneratePolicy(ctx, req.(*GeneratePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtractorService_Classify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClassifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtractorServiceServer).Classify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtractorService_Classify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtractorServiceServer).Classify(ctx, req.(*ClassifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExtractorService_ServiceDesc is the grpc.ServiceDesc for ExtractorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExtractorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "extractor.v1.ExtractorService",
+	HandlerType: (*ExtractorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExtractService",
+			Handler:    _ExtractorService_ExtractService_Handler,
+		},
+		{
+			MethodName: "GeneratePolicy",
+			Handler:    _ExtractorService_GeneratePolicy_Handler,
+		},
+		{
+			MethodName: "Classify",
+			Handler:    _ExtractorService_Classify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "extractor/v1/extractor.proto",
+}
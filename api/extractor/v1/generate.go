@@ -0,0 +1,4 @@
+// Package extractorv1 holds the gRPC contract of the extractor server, generated from extractor.proto.
+package extractorv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative extractor/v1/extractor.proto
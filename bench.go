@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// benchOptions holds the flags of the bench command
+type benchOptions struct {
+	services   string
+	iterations int
+	classify   bool
+	cpuProfile string
+	memProfile string
+}
+
+// benchResult accumulates the timings of one service across iterations
+type benchResult struct {
+	modelParse     time.Duration
+	controllerScan time.Duration
+	classification time.Duration
+	policyGen      time.Duration
+	total          time.Duration
+	runs           int
+}
+
+// newBenchCommand builds the command measuring extraction throughput
+func newBenchCommand() *cobra.Command {
+	opts := &benchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bench --service=<service1>[,service2...]",
+		Short: "Measure per-phase extraction timings and memory usage",
+		Long: `Runs extraction for the given services, reporting the average time spent
+parsing models, scanning controllers, classifying operations and generating
+policies, along with memory statistics. No output files are written.
+Optionally writes CPU and heap profiles for go tool pprof.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.services == "" {
+				return fmt.Errorf("--service is required")
+			}
+			if opts.iterations < 1 {
+				return fmt.Errorf("--iterations must be at least 1")
+			}
+			return runBench(opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.services, "service", "", "AWS service name(s), comma-separated")
+	flags.IntVar(&opts.iterations, "iterations", 1, "Number of times to run extraction for each service")
+	flags.BoolVar(&opts.classify, "classify", false, "Include Bedrock classification in the measurement")
+	flags.StringVar(&opts.cpuProfile, "cpuprofile", "", "Write a CPU profile to this file")
+	flags.StringVar(&opts.memProfile, "memprofile", "", "Write a heap profile to this file")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+
+	return cmd
+}
+
+// runBench runs the benchmark and prints its report
+func runBench(opts *benchOptions) error {
+	if opts.cpuProfile != "" {
+		f, err := os.Create(opts.cpuProfile)
+		if err != nil {
+			return fmt.Errorf("error creating CPU profile: %w", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return fmt.Errorf("error starting CPU profile: %w", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	benchStart := time.Now()
+
+	services := strings.Split(opts.services, ",")
+	results := make(map[string]*benchResult)
+	for i := 0; i < opts.iterations; i++ {
+		for _, serviceName := range services {
+			serviceName = strings.TrimSpace(serviceName)
+			result, ok := results[serviceName]
+			if !ok {
+				result = &benchResult{}
+				results[serviceName] = result
+			}
+
+			start := time.Now()
+			serviceOps, err := extractor.ExtractDetailedOperationsFromService(serviceName, opts.classify)
+			if err != nil {
+				fmt.Printf("Error extracting operations for %s: %v\n", serviceName, err)
+				continue
+			}
+
+			policyStart := time.Now()
+			extractor.GenerateSinglePolicy(serviceName, serviceOps.Operations)
+			result.policyGen += time.Since(policyStart)
+
+			result.total += time.Since(start)
+			result.modelParse += serviceOps.Timings.ModelParse
+			result.controllerScan += serviceOps.Timings.ControllerScan
+			result.classification += serviceOps.Timings.Classification
+			result.runs++
+		}
+	}
+
+	elapsed := time.Since(benchStart)
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	fmt.Printf("%-20s %12s %12s %12s %12s %12s\n", "SERVICE", "MODEL", "SCAN", "CLASSIFY", "POLICY", "TOTAL")
+	totalRuns := 0
+	for _, serviceName := range services {
+		serviceName = strings.TrimSpace(serviceName)
+		result := results[serviceName]
+		if result == nil || result.runs == 0 {
+			continue
+		}
+		runs := time.Duration(result.runs)
+		fmt.Printf("%-20s %12s %12s %12s %12s %12s\n", serviceName,
+			(result.modelParse / runs).Round(time.Microsecond),
+			(result.controllerScan / runs).Round(time.Microsecond),
+			(result.classification / runs).Round(time.Microsecond),
+			(result.policyGen / runs).Round(time.Microsecond),
+			(result.total / runs).Round(time.Microsecond))
+		totalRuns += result.runs
+	}
+
+	fmt.Printf("\n%d extraction(s) in %s", totalRuns, elapsed.Round(time.Millisecond))
+	if elapsed > 0 {
+		fmt.Printf(" (%.2f services/s)", float64(totalRuns)/elapsed.Seconds())
+	}
+	fmt.Println()
+	fmt.Printf("Allocated: %.1f MiB total, %d allocations\n", float64(after.TotalAlloc-before.TotalAlloc)/(1<<20), after.Mallocs-before.Mallocs)
+	fmt.Printf("Heap in use: %.1f MiB, GC cycles: %d\n", float64(after.HeapInuse)/(1<<20), after.NumGC-before.NumGC)
+
+	if opts.memProfile != "" {
+		f, err := os.Create(opts.memProfile)
+		if err != nil {
+			return fmt.Errorf("error creating heap profile: %w", err)
+		}
+		defer f.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return fmt.Errorf("error writing heap profile: %w", err)
+		}
+	}
+
+	return nil
+}
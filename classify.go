@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newClassifyCommand builds the command classifying an arbitrary list of operation names
+func newClassifyCommand() *cobra.Command {
+	var serviceName, file, promptTemplate, agentInstruction string
+
+	cmd := &cobra.Command{
+		Use:   "classify [operation...]",
+		Short: "Classify a list of operation names as control plane or data plane",
+		Long: `Sends operation names given as arguments, or read from --file ("-" for stdin,
+one or more comma-separated names per line, # starts a comment), to the
+Bedrock classifier and prints the ClassificationResult JSON. Neither the
+models repository nor a controller checkout is needed.
+
+Names may be prefixed with their service, e.g. dynamodb:CreateTable, to mix
+services in one list; each service is classified separately and the result
+keeps the prefixed names. Unprefixed names belong to --service.`,
+		Example: `  ack-api-extractor classify --service=dynamodb CreateTable GetItem
+  echo "s3:PutBucketPolicy,s3:GetObject" | ack-api-extractor classify --file=-`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := args
+			if file != "" {
+				fileNames, err := readNameList(file)
+				if err != nil {
+					return fmt.Errorf("error reading operation list: %w", err)
+				}
+				names = append(names, fileNames...)
+			}
+			names = uniqueNames(names)
+			if len(names) == 0 {
+				return fmt.Errorf("no operations given")
+			}
+
+			if promptTemplate != "" {
+				if err := extractor.LoadPromptTemplate(promptTemplate); err != nil {
+					return err
+				}
+			}
+			if agentInstruction != "" {
+				if err := extractor.LoadAgentInstruction(agentInstruction); err != nil {
+					return err
+				}
+			}
+
+			// Group names by service, remembering how each operation was written
+			groups := make(map[string][]extractor.Operation)
+			written := make(map[string]map[string]string)
+			for _, name := range names {
+				service, operation, prefixed := strings.Cut(name, ":")
+				if !prefixed {
+					service, operation = serviceName, name
+				}
+				if written[service] == nil {
+					written[service] = make(map[string]string)
+				}
+				written[service][operation] = name
+				groups[service] = append(groups[service], extractor.Operation{Name: operation})
+			}
+
+			services := make([]string, 0, len(groups))
+			for service := range groups {
+				services = append(services, service)
+			}
+			sort.Strings(services)
+
+			// Batch progress would corrupt the JSON printed to stdout
+			reportProgressToStderr()
+			result := &extractor.ClassificationResult{ControlPlane: []string{}, DataPlane: []string{}}
+			for _, service := range services {
+				classification, err := extractor.ClassifyOperations(service, groups[service])
+				if err != nil {
+					return fmt.Errorf("failed to classify %s operations: %w", service, err)
+				}
+				for _, operation := range classification.ControlPlane {
+					result.ControlPlane = append(result.ControlPlane, originalName(written[service], operation))
+				}
+				for _, operation := range classification.DataPlane {
+					result.DataPlane = append(result.DataPlane, originalName(written[service], operation))
+				}
+				for _, correction := range classification.Corrections {
+					correction.Operation = originalName(written[service], correction.Operation)
+					result.Corrections = append(result.Corrections, correction)
+				}
+			}
+
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serviceName, "service", "aws", "Service the unprefixed operation names belong to, used as context in the prompt")
+	flags.StringVarP(&file, "file", "f", "", "File with operation names, or - to read them from stdin")
+	flags.StringVar(&promptTemplate, "prompt-template", "", "Path to a template file overriding the embedded classification prompt")
+	flags.StringVar(&agentInstruction, "agent-instruction", "", "Path to a text file overriding the embedded system instruction of the classification agent")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+	cmd.MarkFlagFilename("prompt-template")
+	cmd.MarkFlagFilename("agent-instruction", "txt")
+
+	return cmd
+}
+
+// originalName returns an operation name as the user wrote it, with its service prefix if it had one
+func originalName(written map[string]string, operation string) string {
+	if name, ok := written[operation]; ok {
+		return name
+	}
+	return operation
+}
@@ -0,0 +1,15 @@
+// Command ack-api-extractor-analyzer reports AWS API calls in ACK controller code that the
+// controller's recommended IAM policy does not allow. Run it through go vet:
+//
+//	go vet -vettool=$(which ack-api-extractor-analyzer) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/aws-controllers-k8s/ack-api-extractor/pkg/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newControllerCallsCommand builds the command listing the AWS API calls of a controller without the service model
+func newControllerCallsCommand() *cobra.Command {
+	var serviceName, format, output string
+
+	cmd := &cobra.Command{
+		Use:   "controller-calls [<controller-dir>]",
+		Short: "List the AWS API calls a controller makes, without the models repository",
+		Long: `Scans the Go code of an ACK controller for AWS SDK calls, through its own
+service client and clients of other services, and lists each call's IAM action
+with the file and line of every call site. It is the inverse of extraction:
+no service model is needed, which makes it a lightweight audit of the
+permissions a controller needs. The controller is given as a directory named
+<service>-controller, or with --service from --controllers-dir.`,
+		Example: `  ack-api-extractor controller-calls ../s3-controller
+  ack-api-extractor controller-calls --service=sfn --format=json --output=sfn-calls.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				dir, err := filepath.Abs(args[0])
+				if err != nil {
+					return err
+				}
+				if err := extractor.SetControllersDir(filepath.Dir(dir)); err != nil {
+					return err
+				}
+				if serviceName == "" {
+					serviceName = strings.TrimSuffix(filepath.Base(dir), "-controller")
+				}
+				if filepath.Base(dir) != serviceName+"-controller" {
+					return fmt.Errorf("controller directory %s must be named %s-controller", args[0], serviceName)
+				}
+			}
+			if serviceName == "" {
+				return fmt.Errorf("either a controller directory or --service is required")
+			}
+			if output != "" && format != "json" {
+				return fmt.Errorf("--output requires --format=json")
+			}
+
+			audit, err := extractor.AuditControllerCalls(serviceName)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case "json":
+				if output != "" {
+					if err := extractor.WriteControllerCallsJSON(audit, output); err != nil {
+						return fmt.Errorf("error writing %s: %w", output, err)
+					}
+					fmt.Printf("%s: %d actions → %s\n", serviceName, len(audit.Actions), output)
+					return nil
+				}
+				data, err := json.MarshalIndent(audit, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			case "text":
+				printControllerCalls(audit)
+			default:
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serviceName, "service", "", "Service of the controller (default derived from the controller directory name)")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	flags.StringVar(&output, "output", "", "File to write the JSON audit to instead of stdout (requires --format=json)")
+
+	return cmd
+}
+
+// printControllerCalls prints the actions of a controller audit with their call sites
+func printControllerCalls(audit *extractor.ControllerCalls) {
+	for _, call := range audit.Calls {
+		kind := ""
+		if call.CrossService {
+			kind = " (cross-service)"
+		}
+		fmt.Printf("%s%s\n", call.Action, kind)
+		for _, location := range call.Locations {
+			fmt.Printf("  %s:%d\n", location.File, location.Line)
+		}
+	}
+	fmt.Printf("\n%s: %d actions, %d call sites\n", audit.ServiceName, len(audit.Actions), countCallSites(audit.Calls))
+}
+
+// countCallSites returns the number of locations of a set of calls
+func countCallSites(calls []extractor.UnmodeledCall) int {
+	sites := 0
+	for _, call := range calls {
+		sites += len(call.Locations)
+	}
+	return sites
+}
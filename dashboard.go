@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// clearLine returns the cursor to the start of the terminal line and erases it
+const clearLine = "\r\033[K"
+
+// classificationDashboard keeps a status line with the running classification totals below the
+// output on a terminal. Everything printed to stdout is passed through a pipe, so the status line
+// is erased before other output and drawn again once a line is complete.
+type classificationDashboard struct {
+	mu       sync.Mutex
+	terminal *os.File
+	writer   *os.File
+	done     chan struct{}
+	status   string
+	// lineOpen is set while output without a trailing newline is on the terminal
+	lineOpen bool
+	stopped  bool
+}
+
+// startClassificationDashboard redirects stdout and the progress reporter through a dashboard
+// when stdout is a terminal, and returns nil otherwise
+func startClassificationDashboard() *classificationDashboard {
+	terminal := os.Stdout
+	if info, err := terminal.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 || os.Getenv("TERM") == "dumb" {
+		return nil
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+
+	d := &classificationDashboard{terminal: terminal, writer: writer, done: make(chan struct{})}
+	os.Stdout = writer
+	text := extractor.NewTextProgressReporter(writer)
+	extractor.SetProgressReporter(extractor.ProgressFunc(func(event extractor.ProgressEvent) {
+		text.Report(event)
+		switch event.Kind {
+		case extractor.ProgressBatchClassified, extractor.ProgressThrottled:
+			d.update()
+		}
+	}))
+	go d.forward(reader)
+	return d
+}
+
+// forward copies stdout to the terminal, erasing the status line before and drawing it after
+// every complete line
+func (d *classificationDashboard) forward(reader io.Reader) {
+	defer close(d.done)
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			d.mu.Lock()
+			if !d.lineOpen && d.status != "" {
+				io.WriteString(d.terminal, clearLine)
+			}
+			d.terminal.Write(buffer[:n])
+			d.lineOpen = !bytes.HasSuffix(buffer[:n], []byte("\n"))
+			if !d.lineOpen && d.status != "" {
+				io.WriteString(d.terminal, d.status)
+			}
+			d.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// update draws the status line with the current classification metrics
+func (d *classificationDashboard) update() {
+	status := formatClassificationMetrics(extractor.ClassificationMetrics())
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status = status
+	if !d.lineOpen && !d.stopped {
+		io.WriteString(d.terminal, clearLine+status)
+	}
+}
+
+// Stop restores stdout and the progress reporter and erases the status line
+func (d *classificationDashboard) Stop() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return
+	}
+	d.stopped = true
+	d.mu.Unlock()
+
+	os.Stdout = d.terminal
+	extractor.SetProgressReporter(extractor.NewTextProgressReporter(d.terminal))
+	d.writer.Close()
+	<-d.done
+	if !d.lineOpen && d.status != "" {
+		io.WriteString(d.terminal, clearLine)
+	}
+}
+
+// formatClassificationMetrics summarizes the batches classified so far, their latency and the
+// estimated spend in one line
+func formatClassificationMetrics(metrics extractor.BedrockMetrics) string {
+	line := fmt.Sprintf("Classification: %d batch(es), p50 %s, p95 %s, est. $%.3f",
+		metrics.Batches, formatSeconds(metrics.BatchSecondsP50), formatSeconds(metrics.BatchSecondsP95), metrics.EstimatedCostUSD)
+	if metrics.Throttled > 0 {
+		line += fmt.Sprintf(", %d throttled", metrics.Throttled)
+	}
+	return line
+}
+
+// formatSeconds rounds a number of seconds for display, e.g. 4.2s
+func formatSeconds(seconds float64) string {
+	return (time.Duration(seconds * float64(time.Second))).Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newDoctorCommand builds the command diagnosing the environment and suggesting fixes
+func newDoctorCommand() *cobra.Command {
+	var skipBedrock bool
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check environment prerequisites and print remediation steps",
+		Long: `Runs the self-check of the models, controllers and cache directories, then
+verifies the AWS setup of the optional integrations: credentials, region and
+caller identity, Bedrock model access through a minimal classification agent
+invocation, and the IAM permissions of --classify, compare-managed-policy and
+simulate, simulated against the caller's policies. Every problem comes with a
+remediation step. Exits non-zero only when a required check fails; AWS problems
+are warnings since plain extraction works without AWS access.`,
+		Example: `  ack-api-extractor doctor
+  ack-api-extractor doctor --skip-bedrock --format=json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printCheckResults(extractor.RunDoctor(skipBedrock), format)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&skipBedrock, "skip-bedrock", false, "Skip the Bedrock invocation, which is billed like a tiny classification")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+
+	return cmd
+}
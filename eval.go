@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newEvalCommand builds the command evaluating classifiers against a golden dataset
+func newEvalCommand() *cobra.Command {
+	var classifiers, services []string
+	var golden, classificationRules, format, output string
+	var minAccuracy float64
+
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Measure classifiers against a golden dataset of hand-labeled operations",
+		Long: `Classifies every operation of a golden dataset with each --classifier and
+reports its accuracy and the precision, recall and F1 score of the control
+plane and data plane classes, followed by the operations it got wrong. The
+first classifier is the baseline: the others are reported with the change
+of every score against it, so a prompt or model change can be validated
+before rollout.
+
+Classifiers are bedrock (the configured agent and prompt), bedrock:<file>
+(the agent with another prompt template), heuristic (the verb-based fallback
+used when Bedrock fails) and rules (the classification rules alone, leaving
+operations they do not match unclassified). Operations a classifier does not
+classify count as mistakes.
+
+The golden dataset embedded in the extractor labels operations of common
+services; --golden reads another one in the same format.`,
+		Example: `  ack-api-extractor eval --classifier=bedrock,bedrock:prompts/candidate.tmpl
+  ack-api-extractor eval --classifier=heuristic,rules --service=dynamodb,s3
+  ack-api-extractor eval --format=json --output=eval.json --min-accuracy=0.95`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && format != "json" {
+				return fmt.Errorf("--output requires --format=json")
+			}
+			if format != "json" && format != "text" {
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+			if len(classifiers) == 0 {
+				return fmt.Errorf("--classifier is required")
+			}
+
+			if classificationRules != "" {
+				if err := extractor.LoadClassificationRules(classificationRules); err != nil {
+					return err
+				}
+			}
+			goldenSet, err := extractor.LoadGoldenSet(golden)
+			if err != nil {
+				return err
+			}
+			if len(services) > 0 {
+				if err := goldenSet.FilterServices(services); err != nil {
+					return err
+				}
+			}
+			var built []*extractor.Classifier
+			for _, spec := range classifiers {
+				classifier, err := extractor.NewClassifier(spec)
+				if err != nil {
+					return err
+				}
+				built = append(built, classifier)
+			}
+
+			// Batch progress would corrupt the JSON printed to stdout
+			if format == "json" && output == "" {
+				reportProgressToStderr()
+			}
+			evaluation := &extractor.ClassificationEvaluation{Golden: goldenSet.Source, Operations: goldenSet.Operations()}
+			for _, classifier := range built {
+				evaluation.Classifiers = append(evaluation.Classifiers, extractor.EvaluateClassifier(goldenSet, classifier))
+			}
+
+			switch {
+			case format == "text":
+				printClassificationEvaluation(evaluation)
+			case output != "":
+				if err := extractor.WriteClassificationEvaluationJSON(evaluation, output); err != nil {
+					return fmt.Errorf("error writing %s: %w", output, err)
+				}
+				fmt.Printf("%d classifier(s) evaluated on %d operation(s) → %s\n", len(evaluation.Classifiers), evaluation.Operations, output)
+			default:
+				evaluation.SchemaVersion = extractor.SchemaVersion
+				data, err := json.MarshalIndent(evaluation, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			}
+
+			if minAccuracy > 0 {
+				for _, result := range evaluation.Classifiers {
+					if result.Accuracy < minAccuracy {
+						return fmt.Errorf("%s accuracy %.1f%% is below --min-accuracy %.1f%%", result.Classifier, result.Accuracy*100, minAccuracy*100)
+					}
+				}
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&classifiers, "classifier", []string{"bedrock"}, "Classifiers to evaluate, comma-separated, the first being the baseline: bedrock, bedrock:<prompt template>, heuristic or rules")
+	flags.StringVar(&golden, "golden", "", "Golden dataset of labeled operations to evaluate against instead of the embedded one")
+	flags.StringSliceVar(&services, "service", nil, "Limit the evaluation to services of the golden dataset, comma-separated")
+	flags.StringVar(&classificationRules, "classification-rules", "", "YAML file of classification rules checked before the embedded rules, applied by the bedrock and rules classifiers")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	flags.StringVar(&output, "output", "", "File to write the JSON evaluation to instead of stdout (requires --format=json)")
+	flags.Float64Var(&minAccuracy, "min-accuracy", 0, "Fail when a classifier's accuracy is below this fraction, e.g. 0.95")
+	cmd.MarkFlagFilename("golden", "yaml", "yml")
+	cmd.MarkFlagFilename("classification-rules", "yaml", "yml")
+
+	return cmd
+}
+
+// printClassificationEvaluation prints the scores and mistakes of every classifier, with the change
+// of each score against the first classifier
+func printClassificationEvaluation(evaluation *extractor.ClassificationEvaluation) {
+	baseline := evaluation.Classifiers[0]
+	for i, result := range evaluation.Classifiers {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s: accuracy %s", result.Classifier, formatScore(result.Accuracy, baseline.Accuracy, i > 0))
+		if result.Unclassified > 0 {
+			fmt.Printf(", %d unclassified", result.Unclassified)
+		}
+		fmt.Println()
+		for j, class := range result.Classes {
+			base := baseline.Classes[j]
+			fmt.Printf("  %-13s precision %s  recall %s  F1 %s\n", class.Type, formatScore(class.Precision, base.Precision, i > 0),
+				formatScore(class.Recall, base.Recall, i > 0), formatScore(class.F1, base.F1, i > 0))
+		}
+		for _, mistake := range result.Mistakes {
+			actual := string(mistake.Actual)
+			if actual == "" {
+				actual = "unclassified"
+			}
+			fmt.Printf("  ✗ %s:%s expected %s, got %s\n", mistake.Service, mistake.Operation, mistake.Expected, actual)
+		}
+		failed := make([]string, 0, len(result.Errors))
+		for service := range result.Errors {
+			failed = append(failed, service)
+		}
+		sort.Strings(failed)
+		for _, service := range failed {
+			fmt.Printf("  Error classifying %s: %s\n", service, result.Errors[service])
+		}
+	}
+	fmt.Printf("\n%d classifier(s) evaluated on %d operation(s) from %s\n", len(evaluation.Classifiers), evaluation.Operations, evaluation.Golden)
+}
+
+// formatScore formats a score as a percentage, followed by its change against the baseline when compared
+func formatScore(score, baseline float64, compared bool) string {
+	formatted := fmt.Sprintf("%5.1f%%", score*100)
+	if compared {
+		formatted += fmt.Sprintf(" (%+.1f)", (score-baseline)*100)
+	}
+	return formatted
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newLintModelCommand builds the command reporting anomalies in service models
+func newLintModelCommand() *cobra.Command {
+	var services string
+
+	cmd := &cobra.Command{
+		Use:   "lint-model --service=<service1>[,service2...]",
+		Short: "Report anomalies in service models that can cause partial extraction",
+		Long: `Parses the API model of each service and reports anomalies such as a missing
+service shape, operations without input or output, references to undefined
+shapes and operations not listed by the service shape. Exits non-zero when
+any error-level diagnostic is found.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if services == "" {
+				return fmt.Errorf("--service is required")
+			}
+
+			failed := false
+			for _, serviceName := range strings.Split(services, ",") {
+				serviceName = strings.TrimSpace(serviceName)
+				diagnostics, err := extractor.LintServiceModel(serviceName)
+				if err != nil {
+					fmt.Printf("Error loading model for %s: %v\n", serviceName, err)
+					failed = true
+					continue
+				}
+
+				counts := make(map[extractor.Severity]int)
+				for _, diagnostic := range diagnostics {
+					counts[diagnostic.Severity]++
+					if diagnostic.Shape != "" {
+						fmt.Printf("%s: %s [%s] %s: %s\n", serviceName, diagnostic.Severity, diagnostic.Rule, diagnostic.Shape, diagnostic.Message)
+					} else {
+						fmt.Printf("%s: %s [%s] %s\n", serviceName, diagnostic.Severity, diagnostic.Rule, diagnostic.Message)
+					}
+				}
+				fmt.Printf("%s: %d error(s), %d warning(s), %d info\n", serviceName,
+					counts[extractor.SeverityError], counts[extractor.SeverityWarning], counts[extractor.SeverityInfo])
+				if counts[extractor.SeverityError] > 0 {
+					failed = true
+				}
+			}
+
+			if failed {
+				return fmt.Errorf("model lint failed")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&services, "service", "", "AWS service name(s), comma-separated")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+
+	return cmd
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newCompareManagedPolicyCommand builds the command diffing an AWS managed policy against the generated policy
+func newCompareManagedPolicyCommand() *cobra.Command {
+	var serviceName, managedPolicy, policyFile, partition, format string
+
+	cmd := &cobra.Command{
+		Use:   "compare-managed-policy --service=<service> --managed-policy=<name>",
+		Short: "Report what an AWS managed policy grants beyond what the controller needs",
+		Long: `Fetches the default version of an AWS managed policy such as
+AmazonDynamoDBFullAccess and compares it with the least-privilege actions the
+generated policy grants the controller. Wildcards on the service's own prefix
+are expanded against the actions of the service model, so the report lists
+every action the managed policy grants that the controller never calls, the
+actions and wildcards it grants on other services, and the needed actions it
+does not grant. Use it as evidence when replacing FullAccess policies.
+Fetching requires AWS credentials allowed to call iam:GetPolicy and
+iam:GetPolicyVersion; --policy-file compares a saved policy document instead.`,
+		Example: `  ack-api-extractor compare-managed-policy --service=dynamodb --managed-policy=AmazonDynamoDBFullAccess
+  ack-api-extractor compare-managed-policy --service=s3 --policy-file=AmazonS3FullAccess.json --format=json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if serviceName == "" {
+				return fmt.Errorf("--service is required")
+			}
+			if managedPolicy == "" && policyFile == "" {
+				return fmt.Errorf("--managed-policy or --policy-file is required")
+			}
+
+			// Extraction progress would corrupt JSON printed to stdout
+			reportProgressToStderr()
+			serviceOps, err := extractor.ExtractDetailedOperationsFromService(serviceName, false)
+			if err != nil {
+				return err
+			}
+			required := append(extractor.PolicyActions(serviceName, serviceOps.Operations), extractor.CrossServiceActions(serviceOps.UnmodeledCalls)...)
+
+			var document []byte
+			var name, versionID string
+			if policyFile != "" {
+				document, err = os.ReadFile(policyFile)
+				if err != nil {
+					return fmt.Errorf("failed to read policy: %w", err)
+				}
+				name = policyFile
+			} else {
+				name = extractor.ManagedPolicyARN(managedPolicy, partition)
+				document, versionID, err = extractor.FetchManagedPolicy(name)
+				if err != nil {
+					return err
+				}
+			}
+
+			comparison, err := extractor.CompareManagedPolicy(serviceName, serviceOps.Operations, required, document)
+			if err != nil {
+				return fmt.Errorf("failed to compare %s: %w", name, err)
+			}
+			comparison.ManagedPolicy = name
+			comparison.VersionID = versionID
+
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(comparison, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			case "text":
+				printManagedPolicyComparison(comparison)
+			default:
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serviceName, "service", "", "AWS service name")
+	flags.StringVar(&managedPolicy, "managed-policy", "", "Name or ARN of the AWS managed policy, e.g. AmazonDynamoDBFullAccess")
+	flags.StringVar(&policyFile, "policy-file", "", "Policy document to compare instead of fetching the managed policy")
+	flags.StringVar(&partition, "partition", extractor.DefaultPartition, "Partition of the managed policy ARN")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+	cmd.MarkFlagFilename("policy-file", "json")
+
+	return cmd
+}
+
+// printManagedPolicyComparison prints a managed policy comparison as text
+func printManagedPolicyComparison(comparison *extractor.ManagedPolicyComparison) {
+	fmt.Printf("%s grants %d %s action(s), the controller needs %d\n",
+		comparison.ManagedPolicy, comparison.GrantedActions, comparison.ServiceName, comparison.RequiredActions)
+	if len(comparison.ExcessActions) > 0 {
+		fmt.Printf("\nGranted but not needed (%d, %.1f%% of granted):\n", len(comparison.ExcessActions), comparison.ExcessShare)
+		for _, action := range comparison.ExcessActions {
+			fmt.Printf("  %s\n", action)
+		}
+	}
+	if len(comparison.OtherServiceActions) > 0 {
+		fmt.Println("\nGranted on other services:")
+		for _, action := range comparison.OtherServiceActions {
+			fmt.Printf("  %s\n", action)
+		}
+	}
+	if len(comparison.MissingActions) > 0 {
+		fmt.Println("\nNeeded but not granted:")
+		for _, action := range comparison.MissingActions {
+			fmt.Printf("  %s\n", action)
+		}
+	}
+	for _, note := range comparison.Notes {
+		fmt.Printf("\nNote: %s\n", note)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newMigrateCommand builds the command upgrading output files written by older versions
+func newMigrateCommand() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate <file-or-directory>...",
+		Short: "Upgrade output files written by older versions to the current schema",
+		Long: fmt.Sprintf(`Rewrites JSON documents written by earlier versions of the extractor in the
+current schema (version %d). Directories are migrated file by file, without
+descending into subdirectories. Files that are not extractor documents, such
+as IAM policies and OpenAPI specs, are skipped. Documents without a
+schema_version field were written before versioning and are version 1.`, extractor.SchemaVersion),
+		Example: `  ack-api-extractor migrate ./output
+  ack-api-extractor migrate --dry-run old/s3-operations.json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := migrationFiles(args)
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, file := range files {
+				if err := migrateFile(file, dryRun); err != nil {
+					fmt.Fprintf(os.Stderr, "Error migrating %s: %v\n", file, err)
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d file(s) failed to migrate", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what would be migrated without rewriting files")
+
+	return cmd
+}
+
+// migrationFiles expands directories to the JSON files they contain
+func migrationFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", path, err)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// migrateFile upgrades a single file in place and reports what was done
+func migrateFile(file string, dryRun bool) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	result, err := extractor.MigrateDocument(data)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case result.Kind == "":
+		fmt.Printf("%s: skipped, not an extractor document\n", file)
+	case result.FromVersion == extractor.SchemaVersion:
+		fmt.Printf("%s: %s document is current\n", file, result.Kind)
+	case dryRun:
+		fmt.Printf("%s: %s document would be migrated from schema %d to %d\n", file, result.Kind, result.FromVersion, extractor.SchemaVersion)
+	default:
+		if err := os.WriteFile(file, result.Data, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s document migrated from schema %d to %d\n", file, result.Kind, result.FromVersion, extractor.SchemaVersion)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newNamingAuditCommand builds the command checking operation names against ACK code generator conventions
+func newNamingAuditCommand() *cobra.Command {
+	var services, format string
+
+	cmd := &cobra.Command{
+		Use:   "naming-audit --service=<service1>[,service2...]",
+		Short: "Report operations the ACK code generator likely can't wire up automatically",
+		Long: `Maps every operation of a service to the ACK code generator operation type its
+name implies (Create, ReadOne, ReadMany, Update, Delete, GetAttributes,
+SetAttributes) and reports operations with nonstandard verbs such as Register*
+or Provision*, and resources whose Create operation has no matching read or
+delete operation. Operations overridden or ignored in the controller's
+generator.yaml are taken into account. The result estimates how much
+generator.yaml customization a new controller needs.`,
+		Example: `  ack-api-extractor naming-audit --service=sagemaker,servicecatalog`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if services == "" {
+				return fmt.Errorf("--service is required")
+			}
+
+			var audits []*extractor.NamingAudit
+			for _, serviceName := range strings.Split(services, ",") {
+				serviceName = strings.TrimSpace(serviceName)
+				audit, err := extractor.AuditServiceNaming(serviceName)
+				if err != nil {
+					fmt.Printf("Error auditing %s: %v\n", serviceName, err)
+					continue
+				}
+				audits = append(audits, audit)
+			}
+
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(audits, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			case "text":
+				for _, audit := range audits {
+					printNamingAudit(audit)
+				}
+			default:
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&services, "service", "", "AWS service name(s), comma-separated")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+
+	return cmd
+}
+
+// printNamingAudit prints the findings of a naming audit
+func printNamingAudit(audit *extractor.NamingAudit) {
+	for _, naming := range audit.Operations {
+		if !naming.Standard && !naming.Configured && !naming.Ignored {
+			fmt.Printf("%s: nonstandard verb %q in %s\n", audit.ServiceName, naming.Verb, naming.Operation)
+		}
+	}
+	for _, resource := range audit.Resources {
+		if len(resource.Missing) > 0 {
+			fmt.Printf("%s: resource %s has no %s operation\n", audit.ServiceName, resource.Resource, strings.Join(resource.Missing, " and "))
+		}
+	}
+	fmt.Printf("%s: %d operation(s), %d nonstandard, %d resource(s) with incomplete lifecycle, ~%d generator.yaml customization(s)\n",
+		audit.ServiceName, len(audit.Operations), audit.NonStandard, audit.IncompleteResources, audit.CustomizationEstimate)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newOpsCommand builds the command applying set operations to the operations of output files
+func newOpsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ops",
+		Short: "Intersect, unite or subtract the operations of services in output files",
+		Long: `Applies set operations to the operations of services read from output files:
+<service>-operations.json files, combined operations.json files or output
+directories. Every service is one set. Operations are compared by name, e.g.
+to find the operations both the dynamodb and dax controllers implement, or by
+IAM action, e.g. to list every action a multi-controller install needs.`,
+	}
+
+	cmd.AddCommand(newSetOperationCommand(extractor.SetIntersect, "Operations every service has"))
+	cmd.AddCommand(newSetOperationCommand(extractor.SetUnion, "Operations any of the services has"))
+	cmd.AddCommand(newSetOperationCommand(extractor.SetDifference, "Operations of the first service none of the others has"))
+
+	return cmd
+}
+
+// newSetOperationCommand builds the subcommand of one set operation
+func newSetOperationCommand(operation extractor.SetOperation, short string) *cobra.Command {
+	var key, opType, format string
+	var supportedOnly bool
+
+	cmd := &cobra.Command{
+		Use:   string(operation) + " <file-or-dir> <file-or-dir>...",
+		Short: short,
+		Example: fmt.Sprintf(`  ack-api-extractor ops %[1]s results/dynamodb-operations.json results/dax-operations.json
+  ack-api-extractor ops %[1]s --by=action --supported-only ./results`, operation),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			setKey, err := extractor.ParseSetKey(key)
+			if err != nil {
+				return err
+			}
+			filter := extractor.OperationFilter{}
+			if opType != "" {
+				if filter.Type, err = extractor.ParseOperationType(opType); err != nil {
+					return err
+				}
+			}
+			if supportedOnly {
+				filter.Supported = &supportedOnly
+			}
+
+			services, err := readOperationsPaths(args)
+			if err != nil {
+				return err
+			}
+			result, err := extractor.CombineOperationSets(operation, setKey, services, filter)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			case "text":
+				for _, member := range result.Members {
+					if operation == extractor.SetIntersect {
+						fmt.Println(member.Name)
+						continue
+					}
+					fmt.Printf("%-50s %s\n", member.Name, strings.Join(member.Services, ", "))
+				}
+				fmt.Fprintf(os.Stderr, "%d %s(s) in the %s of %s\n", len(result.Members), setKey, operation, strings.Join(result.Services, ", "))
+			default:
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&key, "by", string(extractor.SetKeyName), "Compare operations by name or by IAM action")
+	flags.StringVar(&opType, "type", "", "Only operations of this type, e.g. control_plane")
+	flags.BoolVar(&supportedOnly, "supported-only", false, "Only operations the controllers support")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// readOperationsPaths reads the services of operations files and output directories, later
+// paths replacing services of earlier ones
+func readOperationsPaths(paths []string) ([]*extractor.ServiceOperations, error) {
+	var services []*extractor.ServiceOperations
+	index := make(map[string]int)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		var pathServices []*extractor.ServiceOperations
+		if info.IsDir() {
+			pathServices, err = extractor.ReadOperationsDir(path)
+		} else {
+			pathServices, err = extractor.ReadOperationsFile(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, serviceOps := range pathServices {
+			if i, ok := index[serviceOps.ServiceName]; ok {
+				services[i] = serviceOps
+				continue
+			}
+			index[serviceOps.ServiceName] = len(services)
+			services = append(services, serviceOps)
+		}
+	}
+	return services, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// runExtractToSink runs the extraction into a local directory, or for a remote --output such as
+// s3://bucket/prefix into a staging directory whose files are published to the sink afterwards
+func runExtractToSink(opts *extractOptions) error {
+	sink, err := extractor.ParseOutputSink(opts.output)
+	if err != nil {
+		return err
+	}
+	if sink == nil && opts.inPlace {
+		if err := runExtract(opts); err != nil {
+			return err
+		}
+		return failedServicesError(opts, "")
+	}
+	if sink == nil {
+		return runExtractAtomically(opts)
+	}
+	if opts.inPlace {
+		fmt.Println("Warning: --in-place has no effect with a remote output, files are published once the run finished")
+	}
+	if opts.resume {
+		fmt.Println("Warning: --resume has no effect with a remote output, the checkpoint is not published")
+	}
+
+	staging, err := os.MkdirTemp("", "ack-api-extractor-output-")
+	if err != nil {
+		return fmt.Errorf("error creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	opts.outputLocation = opts.output
+	opts.output = staging
+	if err := runExtract(opts); err != nil {
+		return err
+	}
+
+	published, err := sink.Publish(context.Background(), staging)
+	if err != nil {
+		return fmt.Errorf("error publishing output to %s: %w", sink, err)
+	}
+	uploaded := 0
+	for _, file := range published {
+		if file.Uploaded {
+			uploaded++
+		}
+	}
+	fmt.Printf("Published %d file(s) → %s (%d uploaded, %d unchanged)\n", len(published), sink, uploaded, len(published)-uploaded)
+	return failedServicesError(opts, "")
+}
+
+// runExtractAtomically runs the extraction into <output>.partial and swaps it into the output
+// directory once every service succeeded, or with --commit-partial once the run finished. Otherwise
+// the output directory is left untouched and the staging directory is kept, with the checkpoint a
+// rerun with --resume picks up when the run had one. A run with failed services returns an error
+// either way.
+func runExtractAtomically(opts *extractOptions) error {
+	batch, err := extractor.BeginOutputBatch(opts.output, opts.resume)
+	if err != nil {
+		return fmt.Errorf("error preparing output directory: %w", err)
+	}
+
+	opts.outputLocation = opts.output
+	opts.previousOutput = opts.output
+	opts.output = batch.Staging
+	if err := runExtract(opts); err != nil {
+		if batch.Abandon() {
+			fmt.Printf("Output kept in %s, %s is unchanged\n", batch.Staging, batch.Dir)
+		}
+		return err
+	}
+	if opts.failedServices > 0 && !opts.commitPartial {
+		batch.Abandon()
+		hint := ""
+		if _, err := os.Stat(filepath.Join(batch.Staging, checkpointFile)); err == nil {
+			hint = "; rerun with --resume to retry the failed services"
+		}
+		fmt.Printf("Output kept in %s, %s is unchanged%s\n", batch.Staging, batch.Dir, hint)
+		return failedServicesError(opts, "output not replaced")
+	}
+
+	if err := batch.Commit(); err != nil {
+		return fmt.Errorf("error replacing output directory: %w", err)
+	}
+	fmt.Printf("Output → %s\n", batch.Dir)
+	return failedServicesError(opts, "output of the other services written")
+}
+
+// failedServicesError returns the error a run with failed services ends with, nil when every
+// service succeeded
+func failedServicesError(opts *extractOptions, outcome string) error {
+	if opts.failedServices == 0 {
+		return nil
+	}
+	if outcome == "" {
+		return fmt.Errorf("%d service(s) failed", opts.failedServices)
+	}
+	return fmt.Errorf("%d service(s) failed; %s", opts.failedServices, outcome)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws-controllers-k8s/ack-api-extractor/pkg/extractortest"
+)
+
+// runExtractCommand runs the extraction command of a workspace with the given arguments
+func runExtractCommand(t *testing.T, w *extractortest.Workspace, args ...string) error {
+	t.Helper()
+	cmd := newRootCommand()
+	cmd.SetArgs(append([]string{"--models-dir=" + w.ModelsDir, "--controllers-dir=" + w.ControllersDir, "--cache-dir=" + t.TempDir(), "--force"}, args...))
+	return cmd.Execute()
+}
+
+func TestRunExtractAtomically(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("foo").Operations("CreateBar"))
+	w.AddModel(t, extractortest.NewModel("bar").Operations("CreateBaz"))
+	output := filepath.Join(t.TempDir(), "out")
+	partial := output + ".partial"
+
+	// Every service succeeded: the output directory is replaced
+	if err := runExtractCommand(t, w, "--service=foo", "--output="+output); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(output, "foo-operations.json")); err != nil {
+		t.Fatalf("foo was not written: %v", err)
+	}
+	if _, err := os.Stat(partial); !os.IsNotExist(err) {
+		t.Error("the staging directory is left after a successful run")
+	}
+
+	// A failed service leaves the output directory alone and fails the run
+	err := runExtractCommand(t, w, "--service=bar,missing", "--output="+output)
+	if err == nil || !strings.Contains(err.Error(), "1 service(s) failed; output not replaced") {
+		t.Fatalf("run with a failed service returned %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(output, "bar-operations.json")); !os.IsNotExist(err) {
+		t.Error("the output directory was replaced although a service failed")
+	}
+	if _, err := os.Stat(filepath.Join(partial, "bar-operations.json")); err != nil {
+		t.Errorf("the staging directory lost the output of bar: %v", err)
+	}
+
+	// --commit-partial publishes the services that succeeded and still fails the run
+	err = runExtractCommand(t, w, "--service=bar,missing", "--output="+output, "--commit-partial")
+	if err == nil || !strings.Contains(err.Error(), "1 service(s) failed") {
+		t.Fatalf("run with --commit-partial returned %v", err)
+	}
+	for _, name := range []string{"foo-operations.json", "bar-operations.json"} {
+		if _, err := os.Stat(filepath.Join(output, name)); err != nil {
+			t.Errorf("%s missing after --commit-partial: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package extractor
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AccessLevel is the access level the Service Authorization Reference assigns an IAM action
+type AccessLevel string
+
+const (
+	AccessLevelList                  AccessLevel = "List"
+	AccessLevelRead                  AccessLevel = "Read"
+	AccessLevelWrite                 AccessLevel = "Write"
+	AccessLevelPermissionsManagement AccessLevel = "Permissions management"
+	AccessLevelTagging               AccessLevel = "Tagging"
+)
+
+const (
+	// AccessLevelSourceCurated marks access levels taken from the embedded dataset
+	AccessLevelSourceCurated = "curated"
+	// AccessLevelSourceName marks access levels derived from the operation name
+	AccessLevelSourceName = "name"
+)
+
+// accessLevelOrder is the order access levels are reported and granted in
+var accessLevelOrder = []AccessLevel{
+	AccessLevelList, AccessLevelRead, AccessLevelWrite, AccessLevelPermissionsManagement, AccessLevelTagging,
+}
+
+//go:embed datasets/access_levels.yaml
+var accessLevelDataset []byte
+
+// curatedAccessLevels maps IAM service prefixes to action names or patterns to their access level
+var curatedAccessLevels = mustParseAccessLevels(accessLevelDataset)
+
+// readAccessVerbs are the verbs of operations at the Read access level
+var readAccessVerbs = map[string]bool{
+	"Check": true, "Describe": true, "Download": true, "Estimate": true, "Get": true, "Head": true,
+	"Lookup": true, "Preview": true, "Query": true, "Scan": true, "Search": true, "Select": true, "View": true,
+}
+
+// taggingNamePattern matches operations managing the tags of a resource
+var taggingNamePattern = regexp.MustCompile(`^(Tag|Untag)[A-Z]|Tags?($|[A-Z])|Tagging$`)
+
+// permissionsNamePattern matches operations managing who may access a resource: resource-based
+// policies, permissions, grants and ACLs. Policies of other kinds, e.g. lifecycle or scaling
+// policies, configure the resource instead.
+var permissionsNamePattern = regexp.MustCompile(`^[A-Z][a-z]+Polic(y|ies)($|[A-Z])|(Resource|Key|Bucket|Repository|Registry|Role|User|Group|Queue|Topic|Function|Access|Trust|Secret|Vault|Domain|Endpoint|Stream|Table|Layer)Polic(y|ies)|Permissions?($|[A-Z])|Grant($|[A-Z])|Acl($|[A-Z])|ACL`)
+
+// mustParseAccessLevels parses the embedded access level dataset
+func mustParseAccessLevels(data []byte) map[string]map[string]AccessLevel {
+	levels, err := parseAccessLevels(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid access level dataset: %v", err))
+	}
+	return levels
+}
+
+// parseAccessLevels reads a file mapping IAM service prefixes to actions and their access levels
+func parseAccessLevels(data []byte) (map[string]map[string]AccessLevel, error) {
+	levels := make(map[string]map[string]AccessLevel)
+	if err := yaml.Unmarshal(data, &levels); err != nil {
+		return nil, err
+	}
+	for prefix, actions := range levels {
+		for action, level := range actions {
+			if !level.Valid() {
+				return nil, fmt.Errorf("invalid access level %q for %s:%s", level, prefix, action)
+			}
+		}
+	}
+	return levels, nil
+}
+
+// LoadAccessLevels adds the access levels of a YAML file to the embedded dataset, overriding
+// entries for the same action:
+//
+//	dynamodb:
+//	  PartiQLSelect: Read
+func LoadAccessLevels(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read access level file %s: %w", path, err)
+	}
+	levels, err := parseAccessLevels(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse access level file %s: %w", path, err)
+	}
+
+	for prefix, actions := range levels {
+		if curatedAccessLevels[prefix] == nil {
+			curatedAccessLevels[prefix] = make(map[string]AccessLevel)
+		}
+		for action, level := range actions {
+			curatedAccessLevels[prefix][action] = level
+		}
+	}
+	return nil
+}
+
+// Valid reports whether the access level is one of the levels of the Service Authorization Reference
+func (l AccessLevel) Valid() bool {
+	for _, level := range accessLevelOrder {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyAccessLevels tags each operation with the access level of its IAM action
+func ApplyAccessLevels(serviceName string, operations []Operation) {
+	for i := range operations {
+		operations[i].AccessLevel, operations[i].AccessLevelSource = ActionAccessLevel(operationIAMAction(serviceName, operations[i]))
+	}
+}
+
+// ActionAccessLevel returns the access level of an IAM action (<prefix>:<Action>) and where it
+// came from. A curated entry for the action wins over the longest matching curated pattern, which
+// wins over the name: tagging operations that don't only read are Tagging, List operations List,
+// other reading verbs Read, and operations on resource-based policies, permissions, grants and
+// ACLs Permissions management. Everything else is Write.
+func ActionAccessLevel(action string) (AccessLevel, string) {
+	prefix, name, _ := strings.Cut(action, ":")
+	curated := curatedAccessLevels[prefix]
+	if level, ok := curated[name]; ok {
+		return level, AccessLevelSourceCurated
+	}
+	var matched AccessLevel
+	longest := -1
+	for pattern, level := range curated {
+		if base, isPattern := strings.CutSuffix(pattern, "*"); isPattern && strings.HasPrefix(name, base) && len(base) > longest {
+			matched, longest = level, len(base)
+		}
+	}
+	if longest >= 0 {
+		return matched, AccessLevelSourceCurated
+	}
+	return accessLevelFromName(name), AccessLevelSourceName
+}
+
+// accessLevelFromName derives the access level of an action from its name
+func accessLevelFromName(name string) AccessLevel {
+	verb := operationVerb(strings.TrimPrefix(name, "Batch"))
+	reads := verb == "List" || readAccessVerbs[verb]
+	tagging := IsTaggingOperation(name) || taggingNamePattern.MatchString(name)
+	switch {
+	case tagging && reads:
+		return AccessLevelRead
+	case tagging:
+		return AccessLevelTagging
+	case verb == "List":
+		return AccessLevelList
+	case reads:
+		return AccessLevelRead
+	case permissionsNamePattern.MatchString(name):
+		return AccessLevelPermissionsManagement
+	}
+	return AccessLevelWrite
+}
+
+// groupPolicyByAccessLevel makes generated policies grant actions in one statement per access level
+var groupPolicyByAccessLevel bool
+
+// SetGroupPolicyByAccessLevel makes generated policies grant actions in one statement per access
+// level instead of separating only the tagging actions
+func SetGroupPolicyByAccessLevel(enabled bool) {
+	groupPolicyByAccessLevel = enabled
+}
+
+// accessLevelStatements grants actions on resource in one statement per access level, in the
+// order of the Service Authorization Reference, named after the level, e.g. PermissionsManagement
+func accessLevelStatements(actions []string, resource string) []PolicyStatement {
+	byLevel := make(map[AccessLevel][]string)
+	for _, action := range actions {
+		level, _ := ActionAccessLevel(action)
+		byLevel[level] = append(byLevel[level], action)
+	}
+	var statements []PolicyStatement
+	for _, level := range accessLevelOrder {
+		if len(byLevel[level]) > 0 {
+			statements = append(statements, PolicyStatement{
+				Sid:      accessLevelSid(level),
+				Effect:   "Allow",
+				Action:   byLevel[level],
+				Resource: resource,
+			})
+		}
+	}
+	return statements
+}
+
+// accessLevelSid turns an access level into a statement ID, e.g. Permissions management into PermissionsManagement
+func accessLevelSid(level AccessLevel) string {
+	var sid strings.Builder
+	for _, word := range strings.Fields(string(level)) {
+		sid.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return sid.String()
+}
+
+// CountAccessLevels returns the number of operations per access level
+func CountAccessLevels(operations []Operation) map[AccessLevel]int {
+	counts := make(map[AccessLevel]int)
+	for _, op := range operations {
+		if op.AccessLevel != "" {
+			counts[op.AccessLevel]++
+		}
+	}
+	return counts
+}
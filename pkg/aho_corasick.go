@@ -0,0 +1,126 @@
+package extractor
+
+// patternMatcher finds every occurrence of a fixed set of patterns in a single pass
+// over the input, using the Aho-Corasick automaton. Patterns only match as whole
+// identifiers, optionally extended by an SDK suffix, so "DescribeTable" matches
+// "DescribeTableWithContext" but not "DescribeTableReplicaAutoScaling".
+type patternMatcher struct {
+	nodes   []matcherNode
+	lengths []int
+}
+
+// sdkIdentifierSuffixes extend an operation name into the identifiers the AWS SDKs derive from it
+var sdkIdentifierSuffixes = map[string]bool{
+	"Input": true, "Output": true, "Request": true, "WithContext": true, "Pages": true, "PagesWithContext": true,
+}
+
+// matcherNode is a state of the automaton
+type matcherNode struct {
+	next    map[byte]int
+	fail    int
+	outputs []int // indexes of the patterns ending at this state, including via fail links
+}
+
+// newPatternMatcher builds a matcher for the given patterns
+func newPatternMatcher(patterns []string) *patternMatcher {
+	m := &patternMatcher{nodes: []matcherNode{{next: make(map[byte]int)}}, lengths: make([]int, len(patterns))}
+
+	for i, pattern := range patterns {
+		m.lengths[i] = len(pattern)
+		if pattern == "" {
+			continue
+		}
+		state := 0
+		for j := 0; j < len(pattern); j++ {
+			next, ok := m.nodes[state].next[pattern[j]]
+			if !ok {
+				m.nodes = append(m.nodes, matcherNode{next: make(map[byte]int)})
+				next = len(m.nodes) - 1
+				m.nodes[state].next[pattern[j]] = next
+			}
+			state = next
+		}
+		m.nodes[state].outputs = append(m.nodes[state].outputs, i)
+	}
+
+	// Breadth-first construction of failure links
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for b, child := range m.nodes[state].next {
+			fail := m.nodes[state].fail
+			for {
+				if next, ok := m.nodes[fail].next[b]; ok && next != child {
+					m.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					m.nodes[child].fail = 0
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			m.nodes[child].outputs = append(m.nodes[child].outputs, m.nodes[m.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+// match calls found once for every pattern index occurring in text as an identifier
+func (m *patternMatcher) match(text string, found func(pattern int)) {
+	m.scan(text, true, found)
+}
+
+// contains calls found once for every pattern index occurring anywhere in text
+func (m *patternMatcher) contains(text string, found func(pattern int)) {
+	m.scan(text, false, found)
+}
+
+// scan calls found once for every pattern index occurring in text, only counting occurrences
+// that are identifiers when identifiers is set
+func (m *patternMatcher) scan(text string, identifiers bool, found func(pattern int)) {
+	seen := make(map[int]bool)
+	state := 0
+	for i := 0; i < len(text); i++ {
+		for {
+			if next, ok := m.nodes[state].next[text[i]]; ok {
+				state = next
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = m.nodes[state].fail
+		}
+		for _, pattern := range m.nodes[state].outputs {
+			if !seen[pattern] && (!identifiers || isIdentifierMatch(text, i+1-m.lengths[pattern], i+1)) {
+				seen[pattern] = true
+				found(pattern)
+			}
+		}
+	}
+}
+
+// isIdentifierMatch reports whether text[start:end] is a whole identifier or one extended by an
+// SDK suffix
+func isIdentifierMatch(text string, start, end int) bool {
+	if start > 0 && isIdentifierByte(text[start-1]) {
+		return false
+	}
+	suffixEnd := end
+	for suffixEnd < len(text) && isIdentifierByte(text[suffixEnd]) {
+		suffixEnd++
+	}
+	return suffixEnd == end || sdkIdentifierSuffixes[text[end:suffixEnd]]
+}
+
+// isIdentifierByte reports whether b can be part of a Go identifier
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
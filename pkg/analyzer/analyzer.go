@@ -0,0 +1,206 @@
+// Package analyzer reports AWS API calls in ACK controller code that the controller's recommended
+// IAM policy does not allow, as a golang.org/x/tools/go/analysis Analyzer for go vet and editors.
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// recommendedPolicyFile is the controller-relative path of the recommended inline policy
+var recommendedPolicyFile = filepath.Join("config", "iam", "recommended-inline-policy")
+
+// controllerModulePattern matches the module path of an ACK controller and captures its service
+var controllerModulePattern = regexp.MustCompile(`aws-controllers-k8s/([a-z0-9]+)-controller(?:/|$)`)
+
+var (
+	serviceFlag   string
+	policyFlag    string
+	modelsDirFlag string
+)
+
+// Analyzer reports calls to AWS SDK operations whose IAM action the recommended policy lacks
+var Analyzer = &analysis.Analyzer{
+	Name: "ackpolicy",
+	Doc: `report AWS API calls missing from the controller's recommended IAM policy
+
+Every call to an AWS SDK operation, through the controller's own service client
+or a client of another service, is checked against the Allow statements of
+config/iam/recommended-inline-policy, including wildcard actions. Packages of
+controllers without a recommended inline policy are not checked.`,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&serviceFlag, "service", "", "service of the controller (default derived from the module path, e.g. s3 for s3-controller)")
+	Analyzer.Flags.StringVar(&policyFlag, "policy", "", "recommended policy file (default config/iam/recommended-inline-policy in the module root)")
+	Analyzer.Flags.StringVar(&modelsDirFlag, "models-dir", "", "AWS service models directory used to resolve the service's IAM prefix (optional)")
+}
+
+// sdkCall is a call to an AWS SDK operation
+type sdkCall struct {
+	call   *ast.CallExpr
+	action string
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if len(pass.Files) == 0 {
+		return nil, nil
+	}
+	if err := configureModels(); err != nil {
+		return nil, err
+	}
+
+	serviceName := serviceFlag
+	if serviceName == "" {
+		match := controllerModulePattern.FindStringSubmatch(pass.Pkg.Path())
+		if match == nil {
+			return nil, nil
+		}
+		serviceName = match[1]
+	}
+
+	policyFile := policyFlag
+	if policyFile == "" {
+		root := moduleRoot(pass.Fset.File(pass.Files[0].Pos()).Name())
+		if root == "" {
+			return nil, nil
+		}
+		policyFile = filepath.Join(root, recommendedPolicyFile)
+	}
+	policy, err := readPolicy(policyFile)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	var calls []sdkCall
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		if strings.HasSuffix(pass.Fset.File(call.Pos()).Name(), "_test.go") {
+			return
+		}
+		if packageName, operation, ok := sdkOperation(pass.TypesInfo, call); ok {
+			calls = append(calls, sdkCall{call: call, action: extractor.SDKOperationAction(serviceName, packageName, operation)})
+		}
+	})
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	actions := make([]string, len(calls))
+	for i, call := range calls {
+		actions[i] = call.action
+	}
+	patch, err := extractor.PatchPolicyDocument(policy, actions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s: %w", policyFile, err)
+	}
+	missing := make(map[string]bool, len(patch.Missing))
+	for _, action := range patch.Missing {
+		missing[action] = true
+	}
+	for _, call := range calls {
+		if missing[call.action] {
+			pass.Reportf(call.call.Pos(), "%s is not allowed by the recommended policy %s", call.action, filepath.Base(policyFile))
+		}
+	}
+	return nil, nil
+}
+
+// sdkOperation returns the SDK service package and operation of a call to an AWS SDK operation:
+// a method of an SDK service package taking the operation's <Operation>Input struct, which covers
+// v2 clients and the v1 <Operation>WithContext, <Operation>Pages and <Operation>Request variants
+func sdkOperation(info *types.Info, call *ast.CallExpr) (string, string, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	method, ok := info.Uses[selector.Sel].(*types.Func)
+	if !ok || method.Pkg() == nil {
+		return "", "", false
+	}
+	signature, ok := method.Type().(*types.Signature)
+	if !ok || signature.Recv() == nil {
+		return "", "", false
+	}
+	packageName, ok := extractor.SDKServicePackage(method.Pkg().Path())
+	if !ok {
+		return "", "", false
+	}
+
+	params := signature.Params()
+	for i := 0; i < params.Len(); i++ {
+		pointer, ok := params.At(i).Type().(*types.Pointer)
+		if !ok {
+			continue
+		}
+		named, ok := pointer.Elem().(*types.Named)
+		if !ok || named.Obj().Pkg() == nil {
+			continue
+		}
+		inputPackage, ok := extractor.SDKServicePackage(named.Obj().Pkg().Path())
+		if operation, isInput := strings.CutSuffix(named.Obj().Name(), "Input"); ok && isInput && inputPackage == packageName && operation != "" {
+			return packageName, operation, true
+		}
+	}
+	return "", "", false
+}
+
+// moduleRoot returns the directory of the go.mod file governing a source file, empty when there is none
+func moduleRoot(file string) string {
+	for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// policies caches policy files, which every package of a controller is checked against
+var policies sync.Map
+
+// readPolicy reads a policy file once, returning nil without an error when it does not exist
+func readPolicy(path string) ([]byte, error) {
+	if cached, ok := policies.Load(path); ok {
+		return cached.([]byte), nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recommended policy: %w", err)
+	}
+	policies.Store(path, data)
+	return data, nil
+}
+
+var configureModelsOnce sync.Once
+
+// configureModels points the extractor at the models directory once, so IAM prefixes that differ
+// from the service name (e.g. states for sfn) are resolved
+func configureModels() error {
+	var err error
+	configureModelsOnce.Do(func() {
+		if modelsDirFlag != "" {
+			err = extractor.SetModelsDir(modelsDirFlag)
+		}
+	})
+	return err
+}
@@ -0,0 +1,57 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// operationAnnotationPattern matches comments declaring operations a controller implements
+// indirectly, e.g. // ack-operation: PutLifecycleConfiguration, DeleteLifecycle
+var operationAnnotationPattern = regexp.MustCompile(`^\s*//\s*ack-operation:\s*(.+?)\s*$`)
+
+// parseOperationAnnotation returns the operation names an ack-operation comment declares
+func parseOperationAnnotation(line string) []string {
+	if !strings.Contains(line, "ack-operation:") {
+		return nil
+	}
+	match := operationAnnotationPattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(match[1], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// findUnknownAnnotations returns a warning for every ack-operation comment in the controller
+// naming an operation the service model does not define, typically a typo or a renamed operation
+func findUnknownAnnotations(serviceName string, modelOperations []string) []string {
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return nil
+	}
+
+	modeled := make(map[string]bool, len(modelOperations))
+	for _, name := range modelOperations {
+		modeled[name] = true
+	}
+
+	var warnings []string
+	files, _ := listControllerFiles(controllerPath)
+	for _, path := range files {
+		relPath := controllerRelPath(controllerPath, path)
+		forEachLine(path, func(line string, lineNum int) {
+			for _, name := range parseOperationAnnotation(line) {
+				if !modeled[name] {
+					warnings = append(warnings, fmt.Sprintf("ack-operation comment at %s:%d declares %s, which is not an operation of the model", relPath, lineNum, name))
+				}
+			}
+		})
+	}
+	return warnings
+}
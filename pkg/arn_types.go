@@ -0,0 +1,157 @@
+package extractor
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// otherARNTypesStatementSid names the policy statement granting the actions no ARN type lists
+const otherARNTypesStatementSid = "OtherResourceTypes"
+
+//go:embed datasets/arn_types.yaml
+var arnTypeDataset []byte
+
+// ARNType is a resource type of the Service Authorization Reference and the actions granted on it
+type ARNType struct {
+	Type string `yaml:"type"`
+	// ARN is the documented ARN format, e.g. arn:${Partition}:dynamodb:${Region}:${Account}:table/${TableName}
+	ARN     string   `yaml:"arn"`
+	Actions []string `yaml:"actions"`
+}
+
+// arnTypes maps IAM service prefixes to their ARN types
+var arnTypes = mustParseARNTypes(arnTypeDataset)
+
+// arnVariable matches the variables of a documented ARN format
+var arnVariable = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// groupPolicyByARNType makes generated policies grant actions in one statement per ARN type
+var groupPolicyByARNType bool
+
+// SetGroupPolicyByARNType makes generated policies grant actions in one statement per ARN type,
+// scoped to the ARNs of that type, instead of one statement on every resource of the service
+func SetGroupPolicyByARNType(enabled bool) {
+	groupPolicyByARNType = enabled
+}
+
+// mustParseARNTypes parses the embedded ARN type dataset
+func mustParseARNTypes(data []byte) map[string][]ARNType {
+	types, err := parseARNTypes(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid ARN type dataset: %v", err))
+	}
+	return types
+}
+
+// parseARNTypes reads a file mapping IAM service prefixes to ARN types
+func parseARNTypes(data []byte) (map[string][]ARNType, error) {
+	types := make(map[string][]ARNType)
+	if err := yaml.Unmarshal(data, &types); err != nil {
+		return nil, err
+	}
+	for prefix, entries := range types {
+		for i, entry := range entries {
+			if entry.Type == "" {
+				return nil, fmt.Errorf("ARN type %d of %s has no type", i+1, prefix)
+			}
+			if !strings.HasPrefix(entry.ARN, "arn:${Partition}:") {
+				return nil, fmt.Errorf("ARN type %s of %s has ARN %q, expected it to start with arn:${Partition}:", entry.Type, prefix, entry.ARN)
+			}
+		}
+	}
+	return types, nil
+}
+
+// LoadARNTypes replaces the ARN types of the services in a YAML file:
+//
+//	dynamodb:
+//	  - type: table
+//	    arn: arn:${Partition}:dynamodb:${Region}:${Account}:table/${TableName}
+//	    actions: [CreateTable, DescribeTable]
+func LoadARNTypes(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read ARN type file %s: %w", path, err)
+	}
+	types, err := parseARNTypes(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse ARN type file %s: %w", path, err)
+	}
+
+	for prefix, entries := range types {
+		arnTypes[prefix] = entries
+	}
+	return nil
+}
+
+// grants reports whether an action name is granted on the ARN type
+func (t ARNType) grants(name string) bool {
+	for _, pattern := range t.Actions {
+		if base, isPrefix := strings.CutSuffix(pattern, "*"); isPrefix && strings.HasPrefix(name, base) {
+			return true
+		}
+		if pattern == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resourcePattern returns the ARNs of the type in a partition, with every other variable a wildcard
+func (t ARNType) resourcePattern(partition string) string {
+	return arnVariable.ReplaceAllStringFunc(t.ARN, func(variable string) string {
+		if variable == "${Partition}" {
+			return partition
+		}
+		return "*"
+	})
+}
+
+// arnTypeStatements grants actions in one statement per ARN type of their service, in dataset
+// order and named after the type, e.g. GlobalTable for global-table, numbered when the types of
+// two services share a name. An action of several types is granted in each of their statements;
+// the actions no type lists are returned.
+func arnTypeStatements(actions []string, partition string) (statements []PolicyStatement, unmapped []string) {
+	mapped, sids := make(map[string]bool), make(map[string]bool)
+	byPrefix := make(map[string][]string)
+	var prefixes []string
+	for _, action := range actions {
+		prefix, _, _ := strings.Cut(action, ":")
+		if byPrefix[prefix] == nil {
+			prefixes = append(prefixes, prefix)
+		}
+		byPrefix[prefix] = append(byPrefix[prefix], action)
+	}
+
+	for _, prefix := range prefixes {
+		for _, arnType := range arnTypes[prefix] {
+			var granted []string
+			for _, action := range byPrefix[prefix] {
+				if _, name, _ := strings.Cut(action, ":"); arnType.grants(name) {
+					granted = append(granted, action)
+					mapped[action] = true
+				}
+			}
+			if len(granted) > 0 {
+				statements = append(statements, PolicyStatement{
+					Sid:      uniqueSid(sids, statementSidSuffix(arnType.Type)),
+					Effect:   "Allow",
+					Action:   granted,
+					Resource: arnType.resourcePattern(partition),
+				})
+			}
+		}
+	}
+
+	for _, action := range actions {
+		if !mapped[action] {
+			unmapped = append(unmapped, action)
+		}
+	}
+	return statements, unmapped
+}
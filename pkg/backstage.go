@@ -0,0 +1,150 @@
+package extractor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// backstageAPIVersion is the apiVersion of the generated catalog entities
+	backstageAPIVersion = "backstage.io/v1alpha1"
+	// backstageAnnotationPrefix prefixes the annotations carrying ACK coverage facts
+	backstageAnnotationPrefix = "aws-controllers-k8s.io/"
+)
+
+// NewBackstageEntities describes the ACK controller of a service as a Backstage Component whose
+// annotations carry the coverage facts of the extraction. When openAPIFile is set, an API entity
+// whose definition is that file is added and provided by the component.
+func NewBackstageEntities(serviceOps *ServiceOperations, options BackstageOptions, openAPIFile string) []BackstageEntity {
+	serviceName := serviceOps.ServiceName
+	title := serviceName
+	if serviceOps.Metadata != nil && serviceOps.Metadata.SDKID != "" {
+		title = serviceOps.Metadata.SDKID
+	}
+	controlPlane, supportedControlPlane := CountControlPlaneOperations(serviceOps.Operations)
+	counts := CountSupportStatus(serviceOps.Operations)
+
+	annotations := map[string]string{
+		backstageAnnotationPrefix + "service":              serviceName,
+		backstageAnnotationPrefix + "total-operations":     fmt.Sprint(len(serviceOps.Operations)),
+		backstageAnnotationPrefix + "supported-operations": fmt.Sprint(counts[SupportImplemented] + counts[SupportPartiallyImplemented]),
+		backstageAnnotationPrefix + "support-coverage":     fmt.Sprintf("%.1f", SupportCoverage(counts)),
+	}
+	if controlPlane > 0 {
+		annotations[backstageAnnotationPrefix+"control-plane-operations"] = fmt.Sprint(controlPlane)
+		annotations[backstageAnnotationPrefix+"supported-control-plane-operations"] = fmt.Sprint(supportedControlPlane)
+		annotations[backstageAnnotationPrefix+"control-plane-coverage"] = fmt.Sprintf("%.1f", percentage(supportedControlPlane, controlPlane))
+	}
+	if serviceOps.ControllerRelease != "" {
+		annotations[backstageAnnotationPrefix+"controller-release"] = serviceOps.ControllerRelease
+	}
+
+	component := BackstageEntity{
+		APIVersion: backstageAPIVersion,
+		Kind:       "Component",
+		Metadata: BackstageMetadata{
+			Name:        serviceName + "-controller",
+			Title:       fmt.Sprintf("ACK %s controller", title),
+			Description: fmt.Sprintf("AWS Controllers for Kubernetes controller for %s", title),
+			Annotations: annotations,
+			Tags:        []string{"ack", "aws", serviceName},
+		},
+		Spec: map[string]interface{}{
+			"type":      "service",
+			"lifecycle": options.Lifecycle,
+			"owner":     options.Owner,
+		},
+	}
+	if serviceOps.NoController {
+		annotations[backstageAnnotationPrefix+"controller"] = "none"
+	} else {
+		repository := fmt.Sprintf("aws-controllers-k8s/%s-controller", serviceName)
+		annotations["github.com/project-slug"] = repository
+		component.Metadata.Links = []BackstageLink{{URL: "https://github.com/" + repository, Title: "Controller repository"}}
+	}
+	if options.System != "" {
+		component.Spec["system"] = options.System
+	}
+
+	if openAPIFile == "" {
+		return []BackstageEntity{component}
+	}
+
+	apiName := serviceName + "-api"
+	component.Spec["providesApis"] = []string{apiName}
+	api := BackstageEntity{
+		APIVersion: backstageAPIVersion,
+		Kind:       "API",
+		Metadata: BackstageMetadata{
+			Name:        apiName,
+			Title:       fmt.Sprintf("%s API", title),
+			Description: fmt.Sprintf("Operations of the %s API with their ACK support status", title),
+			Tags:        []string{"aws", serviceName},
+		},
+		Spec: map[string]interface{}{
+			"type":       "openapi",
+			"lifecycle":  options.Lifecycle,
+			"owner":      options.Owner,
+			"definition": map[string]string{"$text": "./" + openAPIFile},
+		},
+	}
+	if options.System != "" {
+		api.Spec["system"] = options.System
+	}
+	return []BackstageEntity{component, api}
+}
+
+// WriteBackstageCatalog writes entities to a multi-document catalog-info YAML file
+func WriteBackstageCatalog(entities []BackstageEntity, outputPath string) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, entity := range entities {
+		if err := encoder.Encode(entity); err != nil {
+			return fmt.Errorf("failed to marshal catalog entity %s: %w", entity.Metadata.Name, err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to marshal catalog entities: %w", err)
+	}
+	return writeOutputFile(outputPath, buf.Bytes())
+}
+
+// RenderCoverageMarkdown renders the coverage of a service as a Markdown page for TechDocs
+func RenderCoverageMarkdown(serviceOps *ServiceOperations) string {
+	title := serviceOps.ServiceName
+	if serviceOps.Metadata != nil && serviceOps.Metadata.SDKID != "" {
+		title = serviceOps.Metadata.SDKID
+	}
+	controlPlane, supportedControlPlane := CountControlPlaneOperations(serviceOps.Operations)
+	counts := CountSupportStatus(serviceOps.Operations)
+
+	var page strings.Builder
+	fmt.Fprintf(&page, "# ACK coverage of %s\n\n", title)
+	if serviceOps.ControllerRelease != "" {
+		fmt.Fprintf(&page, "Controller release: `%s`\n\n", serviceOps.ControllerRelease)
+	}
+	page.WriteString("| | Operations | Supported | Coverage |\n|---|---:|---:|---:|\n")
+	fmt.Fprintf(&page, "| All | %d | %d | %.1f%% |\n", len(serviceOps.Operations), counts[SupportImplemented]+counts[SupportPartiallyImplemented], SupportCoverage(counts))
+	if controlPlane > 0 {
+		fmt.Fprintf(&page, "| Control plane | %d | %d | %.1f%% |\n", controlPlane, supportedControlPlane, percentage(supportedControlPlane, controlPlane))
+	}
+
+	page.WriteString("\n## Operations\n\n| Operation | Type | Support status |\n|---|---|---|\n")
+	for _, op := range serviceOps.Operations {
+		opType := string(op.Type)
+		if opType == "" {
+			opType = "-"
+		}
+		fmt.Fprintf(&page, "| %s | %s | %s |\n", op.Name, opType, op.SupportStatus)
+	}
+	return page.String()
+}
+
+// WriteCoverageMarkdown writes the Markdown coverage page of a service
+func WriteCoverageMarkdown(serviceOps *ServiceOperations, outputPath string) error {
+	return writeOutputFile(outputPath, []byte(RenderCoverageMarkdown(serviceOps)))
+}
@@ -0,0 +1,52 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultBadgeLabel is the label of coverage badges
+const DefaultBadgeLabel = "ACK coverage"
+
+// badgeCacheSeconds lets shields.io cache badges for an hour, as the outputs change nightly at most
+const badgeCacheSeconds = 3600
+
+// badgeColors maps the minimum coverage of each color, from the highest
+var badgeColors = []struct {
+	minCoverage float64
+	color       string
+}{
+	{90, "brightgreen"},
+	{75, "green"},
+	{50, "yellow"},
+	{25, "orange"},
+	{0, "red"},
+}
+
+// NewCoverageBadge creates the shields.io endpoint badge showing the coverage of a service,
+// e.g. "ACK coverage | 72%", colored from red to bright green
+func NewCoverageBadge(serviceOps *ServiceOperations, label string) *CoverageBadge {
+	coverage := OperationsCoverage(serviceOps.Operations)
+	badge := &CoverageBadge{
+		SchemaVersion: 1,
+		Label:         label,
+		Message:       fmt.Sprintf("%.0f%%", coverage),
+		CacheSeconds:  badgeCacheSeconds,
+	}
+	for _, threshold := range badgeColors {
+		if coverage >= threshold.minCoverage {
+			badge.Color = threshold.color
+			break
+		}
+	}
+	return badge
+}
+
+// WriteCoverageBadgeJSON writes a coverage badge to a JSON file
+func WriteCoverageBadgeJSON(badge *CoverageBadge, outputPath string) error {
+	data, err := json.MarshalIndent(badge, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal badge: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
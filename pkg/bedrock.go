@@ -3,20 +3,93 @@ package extractor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime/types"
+	"github.com/aws/smithy-go"
 )
 
 
 const maxOperationsPerBatch = 100
 
+// runSessionID distinguishes the agent sessions of this process from those of earlier runs
+var runSessionID = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// classificationModelID is the Bedrock foundation model the inline classification agent runs on
+const classificationModelID = "us.anthropic.claude-3-5-sonnet-20241022-v2:0"
+
+// followUpInstruction is the system instruction of the turns of an agent session after the first. The
+// API requires an instruction with every call, so instead of repeating the full instruction, which
+// the session already holds, later turns point back to it.
+const followUpInstruction = "Keep classifying the AWS API operations you are sent as instructed at the start of this session, responding with only the JSON object."
+
+// agentResponse is the text an invocation of the classification agent returned and the tokens it used
+type agentResponse struct {
+	text  string
+	usage tokenUsage
+}
+
+// tokenUsage counts the input and output tokens of the foundation model in an invocation
+type tokenUsage struct {
+	input  int
+	output int
+}
+
+// add counts the usage reported by a model invocation trace of the response stream
+func (u *tokenUsage) add(event types.InlineAgentResponseStream) {
+	trace, ok := event.(*types.InlineAgentResponseStreamMemberTrace)
+	if !ok {
+		return
+	}
+	orchestration, ok := trace.Value.Trace.(*types.TraceMemberOrchestrationTrace)
+	if !ok {
+		return
+	}
+	output, ok := orchestration.Value.(*types.OrchestrationTraceMemberModelInvocationOutput)
+	if !ok || output.Value.Metadata == nil || output.Value.Metadata.Usage == nil {
+		return
+	}
+	u.input += int(aws.ToInt32(output.Value.Metadata.Usage.InputTokens))
+	u.output += int(aws.ToInt32(output.Value.Metadata.Usage.OutputTokens))
+}
+
+// classificationPrompt is the agent instruction and prompt template a classification uses and the
+// agent sessions it runs in
+type classificationPrompt struct {
+	instruction string
+	template    *template.Template
+	// runID distinguishes the agent sessions of classifications sharing it from all others
+	runID string
+}
+
+// defaultClassificationPrompt returns the configured agent instruction and prompt template, run in
+// the sessions of this process
+func defaultClassificationPrompt() classificationPrompt {
+	return classificationPrompt{instruction: agentInstruction, template: classificationTemplate, runID: runSessionID}
+}
+
+// sessionID returns the agent session used for the batches of a service
+func (p classificationPrompt) sessionID(serviceName string) string {
+	return fmt.Sprintf("classification-%s-%s", p.runID, serviceName)
+}
+
 // ClassifyOperations uses AWS Bedrock Inline Agent to classify operations as control plane vs data plane
 func ClassifyOperations(serviceName string, operations []Operation) (*ClassificationResult, error) {
+	return classifyOperationsWith(defaultClassificationPrompt(), serviceName, operations)
+}
+
+// classifyOperationsWith classifies operations with the Bedrock inline agent, rendering the prompt
+// and running the agent sessions of the given classification prompt
+func classifyOperationsWith(prompt classificationPrompt, serviceName string, operations []Operation) (*ClassificationResult, error) {
 	if len(operations) == 0 {
 		return &ClassificationResult{
 			ControlPlane: []string{},
@@ -29,14 +102,24 @@ func ClassifyOperations(serviceName string, operations []Operation) (*Classifica
 		operationNames = append(operationNames, op.Name)
 	}
 
-	return classifyInBatches(serviceName, operationNames, maxOperationsPerBatch)
+	result, err := classifyInBatches(prompt, serviceName, operationNames, maxOperationsPerBatch)
+	if err != nil {
+		return nil, err
+	}
+	// Known misclassifications are corrected deterministically
+	correctClassification(serviceName, result)
+	return result, nil
 }
 
 // classifyInBatches processes large operation lists in smaller batches
-func classifyInBatches(serviceName string, operationNames []string, batchSize int) (*ClassificationResult, error) {
+func classifyInBatches(prompt classificationPrompt, serviceName string, operationNames []string, batchSize int) (*ClassificationResult, error) {
 	var allControlPlane []string
 	var allDataPlane []string
 
+	// Later batches reuse the agent session that received the full prompt
+	sessionID := prompt.sessionID(serviceName)
+	primed := false
+
 	for i := 0; i < len(operationNames); i += batchSize {
 		end := i + batchSize
 		if end > len(operationNames) {
@@ -44,19 +127,51 @@ func classifyInBatches(serviceName string, operationNames []string, batchSize in
 		}
 
 		batch := operationNames[i:end]
-		fmt.Printf("Processing batch %d/%d (%d operations)\n", 
-			(i/batchSize)+1, (len(operationNames)+batchSize-1)/batchSize, len(batch))
+		batchNumber, batches := (i/batchSize)+1, (len(operationNames)+batchSize-1)/batchSize
+		reportProgress(ProgressEvent{Kind: ProgressBatchStarted, Service: serviceName, Batch: batchNumber, Batches: batches, Operations: len(batch),
+			Message: fmt.Sprintf("Processing batch %d/%d (%d operations)", batchNumber, batches, len(batch))})
+
+		if activeCheckpoint != nil {
+			if result, ok := activeCheckpoint.lookupBatch(serviceName, batch); ok {
+				reportProgress(ProgressEvent{Kind: ProgressBatchReused, Service: serviceName, Batch: batchNumber, Batches: batches, Operations: len(batch),
+					Message: fmt.Sprintf("Reusing checkpointed classification for batch %d", batchNumber)})
+				recordCheckpointBatchHit()
+				allControlPlane = append(allControlPlane, result.ControlPlane...)
+				allDataPlane = append(allDataPlane, result.DataPlane...)
+				continue
+			}
+		}
 
-		inputText := buildClassificationInput(serviceName, batch)
-		response, err := invokeInlineAgent(inputText)
+		inputText, followUp, err := buildBatchInput(prompt.template, serviceName, batch, primed)
+		instruction := prompt.instruction
+		if primed {
+			instruction = followUpInstruction
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to build classification input for batch %d: %w", (i/batchSize)+1, err)
+		}
+		recordPrompt(len(inputText), followUp)
+		batchStart := time.Now()
+		response, err := invokeWithBackoff(instruction, sessionID, inputText)
 		if err != nil {
 			return nil, fmt.Errorf("failed to invoke inline agent for batch %d: %w", (i/batchSize)+1, err)
 		}
 
-		result, err := parseClassificationResponse(response)
+		result, err := parseClassificationResponse(response.text)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse classification response for batch %d: %w", (i/batchSize)+1, err)
 		}
+		primed = true
+		recordBatch(time.Since(batchStart), len(response.text), response.usage)
+		reportProgress(ProgressEvent{Kind: ProgressBatchClassified, Service: serviceName, Batch: batchNumber, Batches: batches, Operations: len(batch),
+			InputTokens: response.usage.input, OutputTokens: response.usage.output,
+			Message: fmt.Sprintf("Classified batch %d/%d (%d input, %d output tokens)", batchNumber, batches, response.usage.input, response.usage.output)})
+
+		if activeCheckpoint != nil {
+			if err := activeCheckpoint.recordBatch(serviceName, batch, *result); err != nil {
+				reportWarning(serviceName, "failed to write checkpoint: %v", err)
+			}
+		}
 
 		allControlPlane = append(allControlPlane, result.ControlPlane...)
 		allDataPlane = append(allDataPlane, result.DataPlane...)
@@ -68,103 +183,33 @@ func classifyInBatches(serviceName string, operationNames []string, batchSize in
 	}, nil
 }
 
-// buildClassificationInput creates the input text for operation classification
-func buildClassificationInput(serviceName string, operations []string) string {
-	operationList := strings.Join(operations, ", ")
-	
-	prompt := fmt.Sprintf(`You are an AWS architecture expert. Your task is to classify AWS API operations into two categories based on their primary purpose in cloud infrastructure management.
-
-## CLASSIFICATION CATEGORIES:
-
-**CONTROL_PLANE**: Operations that manage the AWS infrastructure itself - creating, configuring, deleting, or modifying AWS resources and their settings. These operations affect the structure, permissions, configuration, or existence of AWS resources.
-
-**DATA_PLANE**: Operations that work with data stored within existing AWS resources. These operations read, write, query, or manipulate application data but do not change the underlying resource configuration.
-
-## DETAILED CLASSIFICATION RULES:
-
-### CONTROL_PLANE Operations:
-- **Resource Lifecycle**: Create*, Delete*, Update* operations that manage resource existence
-- **Resource Configuration**: Put*Policy, Put*Configuration, Update*Settings, Modify*Attributes
-- **Resource Permissions**: Attach*, Detach*, Associate*, Disassociate* permissions/policies
-- **Resource Metadata**: Tag/Untag operations, Update*Tags
-- **Infrastructure Management**: Enable*, Disable*, Start*, Stop*, Restart* services
-- **Access Control**: Operations that grant/revoke access to resources
-- **Monitoring Setup**: Put*MetricFilter, Create*Alarm, Put*Retention
-
-### DATA_PLANE Operations:
-- **Data Access**: Get*, Describe*, List* data within resources (not resource configuration)
-- **Data Manipulation**: Put*, Post*, Update*, Delete* data items/objects (not resources)
-- **Data Queries**: Query*, Scan*, Search*, Select* operations
-- **Data Streaming**: Read*, Write* streams, Consume*, Produce* messages
-- **Data Processing**: Execute*, Invoke*, Process*, Transform* operations on data
-- **Data Transfer**: Upload*, Download*, Import*, Export* data content
-- **Transactional Operations**: Begin*, Commit*, Rollback* data transactions
-
-## SERVICE-SPECIFIC EXAMPLES:
-
-**DynamoDB**:
-- CONTROL_PLANE: CreateTable, DeleteTable, UpdateTable, TagResource, PutItem (creates table structure)
-- DATA_PLANE: GetItem, PutItem (inserts data), Query, Scan, UpdateItem (modifies data), DeleteItem (removes data)
-
-**S3**:
-- CONTROL_PLANE: CreateBucket, DeleteBucket, PutBucketPolicy, PutBucketEncryption, PutBucketVersioning
-- DATA_PLANE: GetObject, PutObject, DeleteObject, ListObjects, CopyObject, HeadObject
-
-**IAM**:
-- CONTROL_PLANE: CreateRole, DeleteRole, AttachRolePolicy, CreateUser, CreatePolicy, TagRole
-- DATA_PLANE: GetUser, GetRole, ListUsers, ListRoles, GetPolicy (reading existing configurations)
-
-**Lambda**:
-- CONTROL_PLANE: CreateFunction, DeleteFunction, UpdateFunctionCode, PutProvisionedConcurrencyConfig
-- DATA_PLANE: Invoke, InvokeAsync (executing the function with data)
-
-**EC2**:
-- CONTROL_PLANE: RunInstances, TerminateInstances, CreateSecurityGroup, AuthorizeSecurityGroupIngress
-- DATA_PLANE: DescribeInstances, DescribeImages, GetConsoleOutput (reading instance data)
-
-**RDS**:
-- CONTROL_PLANE: CreateDBInstance, DeleteDBInstance, ModifyDBInstance, CreateDBSnapshot
-- DATA_PLANE: DescribeDBInstances, DescribeDBSnapshots (reading database metadata)
-
-## EDGE CASES AND GUIDANCE:
-
-1. **Describe Operations**: 
-   - CONTROL_PLANE if describing resource configuration (DescribeTable schema, DescribeSecurityGroups)
-   - DATA_PLANE if describing data content (DescribeStream data, DescribeLogEvents)
-
-2. **List Operations**:
-   - CONTROL_PLANE if listing resources (ListTables, ListBuckets, ListFunctions)
-   - DATA_PLANE if listing data within resources (ListObjects in bucket, ListStreams data)
-
-3. **Update Operations**:
-   - CONTROL_PLANE if updating resource configuration (UpdateTable provisioning, UpdateFunctionConfiguration)
-   - DATA_PLANE if updating data content (UpdateItem in table, UpdateRecord in stream)
-
-4. **Ambiguous Cases**: When in doubt, classify as DATA_PLANE as these operations are typically more common.
-
-## TASK:
-Classify these %s service operations: %s
-
-## OUTPUT FORMAT:
-Respond with ONLY valid JSON in exactly this format:
-{
-  "control_plane": ["operation1", "operation2"],
-  "data_plane": ["operation3", "operation4"]
+// classificationSessionID returns the agent session used for the batches of a service in this run
+func classificationSessionID(serviceName string) string {
+	return defaultClassificationPrompt().sessionID(serviceName)
 }
 
-Ensure every operation from the input list appears in exactly one category. Do not add explanations or additional text.`, serviceName, operationList)
-
-	return prompt
+// buildBatchInput renders the prompt of a batch: the full prompt until the session has received it,
+// and the short follow-up prompt afterwards when the template defines one
+func buildBatchInput(tmpl *template.Template, serviceName string, batch []string, primed bool) (string, bool, error) {
+	if primed {
+		inputText, ok, err := buildFollowUpInput(tmpl, serviceName, batch)
+		if err != nil || ok {
+			return inputText, ok, err
+		}
+	}
+	inputText, err := buildClassificationInput(tmpl, serviceName, batch)
+	return inputText, false, err
 }
 
-// invokeInlineAgent creates and invokes an inline Bedrock agent for operation classification
-func invokeInlineAgent(inputText string) (string, error) {
+// invokeInlineAgent creates and invokes an inline Bedrock agent for operation classification within
+// an agent session, which keeps the earlier prompts and responses of the session as context
+func invokeInlineAgent(instruction, sessionID, inputText string) (agentResponse, error) {
 	ctx := context.Background()
 	
 	// Load AWS configuration
 	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to load AWS config: %w", err)
+		return agentResponse{}, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	// Create Bedrock Agent Runtime client
@@ -172,32 +217,28 @@ func invokeInlineAgent(inputText string) (string, error) {
 
 	// Invoke the inline agent
 	result, err := client.InvokeInlineAgent(ctx, &bedrockagentruntime.InvokeInlineAgentInput{
-		FoundationModel: aws.String("us.anthropic.claude-3-5-sonnet-20241022-v2:0"),
-		Instruction: aws.String(`You are an AWS architecture expert specialized in classifying AWS API operations.
-Your task is to classify AWS API operations into two categories:
-1. CONTROL_PLANE: Operations that manage AWS infrastructure (create, configure, delete resources)  
-2. DATA_PLANE: Operations that work with data within existing resources
-
-Respond with ONLY valid JSON in this format:
-{
-  "control_plane": ["operation1", "operation2"],
-  "data_plane": ["operation3", "operation4"] 
-}
-
-Ensure every operation from the input list appears in exactly one category.`),
+		FoundationModel: aws.String(classificationModelID),
+		Instruction: aws.String(instruction),
 		AgentName:   aws.String("OperationClassifier"),
 		InputText:   aws.String(inputText),
-		SessionId:   aws.String("classification-session"),
-		EnableTrace: aws.Bool(false),
+		SessionId:   aws.String(sessionID),
+		// Traces report the tokens used and guardrail interventions, which otherwise look like
+		// malformed responses
+		EnableTrace:            aws.Bool(true),
+		GuardrailConfiguration: guardrail,
 	})
 
 	if err != nil {
-		return "", fmt.Errorf("failed to invoke inline agent: %w", err)
+		return agentResponse{}, fmt.Errorf("failed to invoke inline agent: %w", err)
 	}
 
-	// Extract text from the response stream
+	// Extract text and token usage from the response stream
 	var responseText strings.Builder
+	var usage tokenUsage
+	intervened := false
 	for event := range result.GetStream().Events() {
+		intervened = intervened || guardrailIntervened(event)
+		usage.add(event)
 		if chunk, ok := event.(*types.InlineAgentResponseStreamMemberChunk); ok {
 			if chunk.Value.Bytes != nil {
 				responseText.Write(chunk.Value.Bytes)
@@ -206,10 +247,57 @@ Ensure every operation from the input list appears in exactly one category.`),
 	}
 
 	if err := result.GetStream().Err(); err != nil {
-		return "", fmt.Errorf("error reading stream: %w", err)
+		return agentResponse{}, fmt.Errorf("error reading stream: %w", err)
+	}
+	if intervened {
+		return agentResponse{}, fmt.Errorf("guardrail %s intervened in the classification", GuardrailDescription())
 	}
 
-	return responseText.String(), nil
+	return agentResponse{text: responseText.String(), usage: usage}, nil
+}
+
+const (
+	// maxInvokeAttempts bounds retries of throttled Bedrock calls
+	maxInvokeAttempts = 5
+	// baseRetryDelay is the first backoff delay, doubled on every retry
+	baseRetryDelay = 2 * time.Second
+)
+
+// throttlingErrorCodes are API error codes worth retrying with backoff
+var throttlingErrorCodes = map[string]bool{
+	"ThrottlingException":           true,
+	"TooManyRequestsException":      true,
+	"ServiceQuotaExceededException": true,
+	"ServiceUnavailableException":   true,
+}
+
+// invokeWithBackoff invokes the inline agent, retrying throttled calls with exponential backoff and jitter
+func invokeWithBackoff(instruction, sessionID, inputText string) (agentResponse, error) {
+	delay := baseRetryDelay
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		response, err := invokeInlineAgent(instruction, sessionID, inputText)
+		throttled := err != nil && isThrottlingError(err)
+		recordInvocation(time.Since(start), err, throttled)
+		if err == nil || attempt == maxInvokeAttempts || !throttled {
+			return response, err
+		}
+
+		wait := delay + time.Duration(rand.Int63n(int64(delay)))
+		reportProgress(ProgressEvent{Kind: ProgressThrottled,
+			Message: fmt.Sprintf("Throttled by Bedrock, retrying in %s (attempt %d/%d)", wait.Round(time.Millisecond), attempt+1, maxInvokeAttempts)})
+		time.Sleep(wait)
+		delay *= 2
+	}
+}
+
+// isThrottlingError reports whether an error is a throttling or transient capacity error
+func isThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return throttlingErrorCodes[apiErr.ErrorCode()]
+	}
+	return false
 }
 
 // parseClassificationResponse parses the JSON response from Bedrock
@@ -248,16 +336,23 @@ func ApplyClassification(operations []Operation, classification *ClassificationR
 	for _, op := range classification.DataPlane {
 		dataPlaneMap[op] = true
 	}
+	corrections := make(map[string]ClassificationCorrection)
+	for _, correction := range classification.Corrections {
+		corrections[correction.Operation] = correction
+	}
 
 	// Apply classification to operations
 	for i := range operations {
 		if controlPlaneMap[operations[i].Name] {
-			operations[i].Type = "control_plane"
+			operations[i].Type = OperationTypeControlPlane
 		} else if dataPlaneMap[operations[i].Name] {
-			operations[i].Type = "data_plane"
+			operations[i].Type = OperationTypeDataPlane
 		} else {
 			// Default to data_plane if not found
-			operations[i].Type = "data_plane"
+			operations[i].Type = OperationTypeDataPlane
+		}
+		if correction, ok := corrections[operations[i].Name]; ok {
+			operations[i].Correction = &correction
 		}
 	}
 
@@ -267,10 +362,10 @@ func ApplyClassification(operations []Operation, classification *ClassificationR
 // CountControlPlaneOperations counts control plane operations and how many are supported
 func CountControlPlaneOperations(operations []Operation) (controlPlane int, supportedControlPlane int) {
 	for _, op := range operations {
-		if op.Type == "control_plane" {
+		if op.Type.IsControlPlane() {
 			controlPlane++
-			// Count as supported if it has file and line info (implemented in controller)
-			if op.File != "" && op.Line > 0 {
+			// Count as supported if the controller has code calling it
+			if op.IsSupported() {
 				supportedControlPlane++
 			}
 		}
@@ -0,0 +1,136 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/smithy-go"
+)
+
+// BedrockProblem is the category of a failed Bedrock access check
+type BedrockProblem string
+
+const (
+	BedrockNoCredentials    BedrockProblem = "no_credentials"
+	BedrockNoRegion         BedrockProblem = "no_region"
+	BedrockInvalidToken     BedrockProblem = "invalid_credentials"
+	BedrockAccessDenied     BedrockProblem = "access_denied"
+	BedrockModelNotEnabled  BedrockProblem = "model_not_enabled"
+	BedrockModelNotInRegion BedrockProblem = "model_not_in_region"
+	BedrockQuotaExceeded    BedrockProblem = "quota_exceeded"
+	BedrockGuardrailDenied  BedrockProblem = "guardrail"
+	BedrockUnknown          BedrockProblem = "unknown"
+)
+
+// BedrockAccessError explains why classification cannot use Bedrock and how to fix it
+type BedrockAccessError struct {
+	Problem     BedrockProblem
+	Region      string
+	Remediation string
+	Err         error
+}
+
+// bedrockProblemDescriptions describe each problem category in error messages
+var bedrockProblemDescriptions = map[BedrockProblem]string{
+	BedrockNoCredentials:    "no usable AWS credentials",
+	BedrockNoRegion:         "no AWS region",
+	BedrockInvalidToken:     "AWS rejected the credentials",
+	BedrockAccessDenied:     "the caller may not invoke Bedrock agents",
+	BedrockModelNotEnabled:  "the classification model is not enabled",
+	BedrockModelNotInRegion: "the classification model is not available in the region",
+	BedrockQuotaExceeded:    "the Bedrock quota is exhausted",
+	BedrockGuardrailDenied:  "the guardrail blocked the probe",
+	BedrockUnknown:          "the Bedrock probe failed",
+}
+
+func (e *BedrockAccessError) Error() string {
+	return fmt.Sprintf("%s: %v; to fix, %s", bedrockProblemDescriptions[e.Problem], e.Err, e.Remediation)
+}
+
+func (e *BedrockAccessError) Unwrap() error {
+	return e.Err
+}
+
+// invalidTokenErrorCodes are API error codes of credentials AWS does not accept
+var invalidTokenErrorCodes = map[string]bool{
+	"UnrecognizedClientException": true,
+	"InvalidSignatureException":   true,
+	"ExpiredTokenException":       true,
+	"InvalidClientTokenId":        true,
+}
+
+// CheckBedrockAccess verifies with a tiny probe invocation that the classification agent can run:
+// credentials and a region are configured, the caller may call bedrock:InvokeInlineAgent and the
+// classification model is enabled in the region. Failures are returned as *BedrockAccessError.
+// A throttled probe passes, since throttling is retried during classification.
+func CheckBedrockAccess() error {
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return &BedrockAccessError{Problem: BedrockNoCredentials, Err: err,
+			Remediation: "fix the AWS configuration files or the AWS_PROFILE environment variable"}
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return &BedrockAccessError{Problem: BedrockNoCredentials, Err: err,
+			Remediation: "configure credentials with aws configure or aws sso login, or set AWS_PROFILE"}
+	}
+	if cfg.Region == "" {
+		return &BedrockAccessError{Problem: BedrockNoRegion, Err: errors.New("AWS_REGION is unset and the profile sets none"),
+			Remediation: "set AWS_REGION or a region in the AWS profile to a region where Bedrock is available, e.g. us-west-2"}
+	}
+
+	_, err = invokeInlineAgent(agentInstruction, classificationSessionID("preflight"), "Reply with OK.")
+	if err == nil || isThrottlingError(err) && !isQuotaError(err) {
+		return nil
+	}
+	return DiagnoseBedrockError(err, cfg.Region)
+}
+
+// DiagnoseBedrockError translates an error of a classification agent invocation into its
+// problem category and the steps that fix it
+func DiagnoseBedrockError(err error, region string) *BedrockAccessError {
+	modelAccess := fmt.Sprintf("request access to %s under Bedrock model access in %s", classificationModelID, region)
+	diagnosis := &BedrockAccessError{Problem: BedrockUnknown, Region: region, Err: err,
+		Remediation: fmt.Sprintf("check network access to Bedrock in %s, %s and allow bedrock:InvokeInlineAgent and bedrock:InvokeModel", region, modelAccess)}
+
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		if guardrail != nil && strings.Contains(err.Error(), "guardrail") {
+			diagnosis.Problem = BedrockGuardrailDenied
+			diagnosis.Remediation = fmt.Sprintf("check that guardrail %s allows the classification prompt, or run without --guardrail-id", GuardrailDescription())
+		}
+		return diagnosis
+	}
+
+	message := strings.ToLower(apiErr.ErrorMessage())
+	switch code := apiErr.ErrorCode(); {
+	case invalidTokenErrorCodes[code]:
+		diagnosis.Problem = BedrockInvalidToken
+		diagnosis.Remediation = "refresh expired credentials, e.g. with aws sso login, or check AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY"
+	case code == "AccessDeniedException" && strings.Contains(message, "access to the model"):
+		diagnosis.Problem = BedrockModelNotEnabled
+		diagnosis.Remediation = modelAccess
+	case code == "AccessDeniedException":
+		diagnosis.Problem = BedrockAccessDenied
+		diagnosis.Remediation = "allow bedrock:InvokeInlineAgent and bedrock:InvokeModel for the caller"
+		if guardrail != nil {
+			diagnosis.Remediation += fmt.Sprintf(", and bedrock:ApplyGuardrail on guardrail %s", GuardrailDescription())
+		}
+	case code == "ResourceNotFoundException", code == "ValidationException" && strings.Contains(message, "model"):
+		diagnosis.Problem = BedrockModelNotInRegion
+		diagnosis.Remediation = fmt.Sprintf("use a region where %s is available through its cross-region inference profile, e.g. us-west-2, or %s", classificationModelID, modelAccess)
+	case isQuotaError(err):
+		diagnosis.Problem = BedrockQuotaExceeded
+		diagnosis.Remediation = fmt.Sprintf("request a higher Bedrock quota for %s in %s through Service Quotas, or classify fewer operations with --classify-sample", classificationModelID, region)
+	}
+	return diagnosis
+}
+
+// isQuotaError reports whether an error is a service quota error, which retrying does not fix
+func isQuotaError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "ServiceQuotaExceededException"
+}
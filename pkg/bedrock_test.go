@@ -0,0 +1,51 @@
+package extractor
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime/types"
+)
+
+// modelInvocationTrace is a response stream event reporting the tokens of a model invocation
+func modelInvocationTrace(input, output int32) types.InlineAgentResponseStream {
+	return &types.InlineAgentResponseStreamMemberTrace{Value: types.InlineAgentTracePart{
+		Trace: &types.TraceMemberOrchestrationTrace{Value: &types.OrchestrationTraceMemberModelInvocationOutput{
+			Value: types.OrchestrationModelInvocationOutput{Metadata: &types.Metadata{
+				Usage: &types.Usage{InputTokens: aws.Int32(input), OutputTokens: aws.Int32(output)},
+			}},
+		}},
+	}}
+}
+
+func TestTokenUsage(t *testing.T) {
+	var usage tokenUsage
+	for _, event := range []types.InlineAgentResponseStream{
+		modelInvocationTrace(1200, 80),
+		&types.InlineAgentResponseStreamMemberChunk{Value: types.InlineAgentPayloadPart{Bytes: []byte(`{"control_plane": []}`)}},
+		&types.InlineAgentResponseStreamMemberTrace{Value: types.InlineAgentTracePart{Trace: &types.TraceMemberGuardrailTrace{}}},
+		modelInvocationTrace(300, 20),
+	} {
+		usage.add(event)
+	}
+	if usage != (tokenUsage{input: 1500, output: 100}) {
+		t.Errorf("usage = %+v, want 1500 input and 100 output tokens", usage)
+	}
+}
+
+func TestEstimatedCostPrefersReportedTokens(t *testing.T) {
+	ResetState()
+	t.Cleanup(ResetState)
+
+	recordPrompt(4_000_000, false)
+	recordBatch(0, 400_000, tokenUsage{})
+	if cost := ClassificationMetrics().EstimatedCostUSD; cost != 4.5 {
+		t.Errorf("estimated cost from characters = %.2f, want 4.50", cost)
+	}
+
+	recordBatch(0, 0, tokenUsage{input: 2_000_000, output: 100_000})
+	metrics := ClassificationMetrics()
+	if metrics.InputTokens != 2_000_000 || metrics.OutputTokens != 100_000 || metrics.EstimatedCostUSD != 7.5 {
+		t.Errorf("%d input and %d output tokens cost %.2f, want 7.50", metrics.InputTokens, metrics.OutputTokens, metrics.EstimatedCostUSD)
+	}
+}
@@ -0,0 +1,167 @@
+package extractor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Checkpoint records the progress of a run so an interrupted run can resume:
+// the classification of every completed batch and the results of completed services.
+// Each is appended to the checkpoint file as a line of its own when it completes, so recording
+// progress costs the same however much is already recorded.
+type Checkpoint struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+
+	Batches  map[string]ClassificationResult
+	Services map[string]*ServiceOperations
+}
+
+// checkpointRecord is a line of the checkpoint file: a completed batch or a completed service
+type checkpointRecord struct {
+	Batch   string                `json:"batch,omitempty"`
+	Result  *ClassificationResult `json:"result,omitempty"`
+	Service *ServiceOperations    `json:"service,omitempty"`
+}
+
+// activeCheckpoint is appended to after every batch and service when checkpointing is enabled
+var activeCheckpoint *Checkpoint
+
+// EnableCheckpoint turns on checkpointing to a file, loading the progress already recorded in it
+// by an earlier run. A last line left incomplete by a crash is dropped.
+func EnableCheckpoint(path string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{
+		path:     path,
+		Batches:  make(map[string]ClassificationResult),
+		Services: make(map[string]*ServiceOperations),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+	complete := checkpoint.load(data)
+	if complete < len(data) {
+		if err := os.Truncate(path, int64(complete)); err != nil {
+			return nil, fmt.Errorf("failed to repair checkpoint %s: %w", path, err)
+		}
+	}
+
+	checkpoint.file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint %s: %w", path, err)
+	}
+	activeCheckpoint = checkpoint
+	return checkpoint, nil
+}
+
+// load applies the records of a checkpoint file and returns the length of its complete lines
+func (c *Checkpoint) load(data []byte) int {
+	complete := 0
+	for complete < len(data) {
+		end := bytes.IndexByte(data[complete:], '\n')
+		if end < 0 {
+			break
+		}
+		var record checkpointRecord
+		if err := json.Unmarshal(data[complete:complete+end], &record); err != nil {
+			break
+		}
+		switch {
+		case record.Service != nil:
+			c.Services[record.Service.ServiceName] = record.Service
+		case record.Batch != "" && record.Result != nil:
+			c.Batches[record.Batch] = *record.Result
+		}
+		complete += end + 1
+	}
+	return complete
+}
+
+// CompletedService returns the recorded result of a service finished by a previous run
+func (c *Checkpoint) CompletedService(serviceName string) (*ServiceOperations, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	serviceOps, ok := c.Services[serviceName]
+	return serviceOps, ok
+}
+
+// CompleteService records a finished service and appends it to the checkpoint
+func (c *Checkpoint) CompleteService(serviceOps *ServiceOperations) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Services[serviceOps.ServiceName] = serviceOps
+	return c.append(checkpointRecord{Service: serviceOps})
+}
+
+// Close closes the checkpoint file, keeping it for a later run to resume from
+func (c *Checkpoint) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
+
+// Remove deletes the checkpoint file once a run has completed and stops checkpointing to it
+func (c *Checkpoint) Remove() error {
+	if err := c.Close(); err != nil {
+		return err
+	}
+	if activeCheckpoint == c {
+		activeCheckpoint = nil
+	}
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// lookupBatch returns the recorded classification of a batch
+func (c *Checkpoint) lookupBatch(serviceName string, batch []string) (ClassificationResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.Batches[batchKey(serviceName, batch)]
+	return result, ok
+}
+
+// recordBatch records the classification of a batch and appends it to the checkpoint
+func (c *Checkpoint) recordBatch(serviceName string, batch []string, result ClassificationResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := batchKey(serviceName, batch)
+	c.Batches[key] = result
+	return c.append(checkpointRecord{Batch: key, Result: &result})
+}
+
+// append writes a record as a single line; the caller holds c.mu. A crash can only leave the last
+// line incomplete, which the next run drops.
+func (c *Checkpoint) append(record checkpointRecord) error {
+	if c.file == nil {
+		return fmt.Errorf("checkpoint %s is closed", c.path)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	if _, err := c.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// batchKey identifies a batch by its service and operation names
+func batchKey(serviceName string, batch []string) string {
+	sum := sha256.Sum256([]byte(serviceName + "\n" + strings.Join(batch, "\n")))
+	return hex.EncodeToString(sum[:])
+}
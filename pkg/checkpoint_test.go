@@ -0,0 +1,73 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckpointAppendsAndResumes(t *testing.T) {
+	t.Cleanup(ResetState)
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+
+	checkpoint, err := EnableCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := ClassificationResult{ControlPlane: []string{"CreateBar"}, DataPlane: []string{"PutRecord"}}
+	if err := checkpoint.recordBatch("foo", []string{"CreateBar", "PutRecord"}, batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkpoint.CompleteService(&ServiceOperations{ServiceName: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkpoint.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash while appending leaves an incomplete last line
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"service":{"service_na`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	resumed, err := EnableCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Close()
+	if result, ok := resumed.lookupBatch("foo", []string{"CreateBar", "PutRecord"}); !ok || !reflect.DeepEqual(result, batch) {
+		t.Errorf("resumed batch %+v (found %t), want %+v", result, ok, batch)
+	}
+	if _, ok := resumed.CompletedService("bar"); !ok {
+		t.Error("resumed checkpoint lost the completed service")
+	}
+	if err := resumed.CompleteService(&ServiceOperations{ServiceName: "baz"}); err != nil {
+		t.Fatal(err)
+	}
+	resumed.Close()
+
+	again, err := EnableCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if len(again.Services) != 2 || len(again.Batches) != 1 {
+		t.Errorf("checkpoint holds %d service(s) and %d batch(es) after repair, want 2 and 1", len(again.Services), len(again.Batches))
+	}
+	if err := again.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("checkpoint still exists after Remove: %v", err)
+	}
+	// Later batches must not be recorded to the removed checkpoint
+	if activeCheckpoint != nil {
+		t.Error("the removed checkpoint is still active")
+	}
+}
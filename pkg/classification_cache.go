@@ -0,0 +1,204 @@
+package extractor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ClassificationCache remembers Bedrock classifications per operation across services.
+// Related services often expose identically named operations (TagResource, UntagResource,
+// ListTagsForResource, ...) whose classification can be reused instead of asking the model again.
+type ClassificationCache struct {
+	mu sync.Mutex
+	// Entries maps a classification key to the type assigned by each service that classified it
+	Entries map[string]map[string]OperationType `json:"entries"`
+}
+
+// classificationKeyFunc returns the cache key of an operation's classification
+type classificationKeyFunc func(operationName string) string
+
+// classificationKeys returns the cache keys of a service's operations. A key is the operation name
+// followed by a hash of the prompt, the Bedrock model and the operation's input and output members,
+// so a classification is only reused when everything the model was shown is the same.
+func classificationKeys(model *AWSServiceModel, prompt classificationPrompt) classificationKeyFunc {
+	shapes := operationShapes(model)
+	fingerprint := prompt.fingerprint()
+	return func(operationName string) string {
+		shape := shapes[operationName]
+		sum := sha256.Sum256([]byte(strings.Join([]string{
+			fingerprint,
+			classificationModelID,
+			strings.Join(sortedMembers(model, shape.Input), ","),
+			strings.Join(sortedMembers(model, shape.Output), ","),
+		}, "\n")))
+		return operationName + "/" + hex.EncodeToString(sum[:8])
+	}
+}
+
+// sortedMembers returns the sorted member names of an operation's input or output structure
+func sortedMembers(model *AWSServiceModel, ref *ShapeReference) []string {
+	if ref == nil {
+		return nil
+	}
+	var names []string
+	for name := range model.Shapes[ref.Target].Members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fingerprint returns a hash of the agent instruction and every template of the prompt
+func (p classificationPrompt) fingerprint() string {
+	parts := []string{p.instruction}
+	for _, tmpl := range p.template.Templates() {
+		if tmpl.Tree != nil {
+			parts = append(parts, tmpl.Name()+"\x00"+tmpl.Tree.Root.String())
+		}
+	}
+	sort.Strings(parts[1:])
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// applyModelClassification applies a Bedrock classification to operations, holds back drifted
+// classifications and records the result in the shared cache. Operations the answer does not name,
+// e.g. because it was truncated, default to the data plane but are not recorded, so the guess is
+// never reused for other services. It returns the classified operations and the drift warnings.
+func applyModelClassification(serviceName string, operations []Operation, classification *ClassificationResult, key classificationKeyFunc) ([]Operation, []string) {
+	classified := ApplyClassification(operations, classification)
+	warnings := checkClassificationDrift(serviceName, classified, key)
+
+	answered := make(map[string]bool)
+	for _, name := range append(append([]string{}, classification.ControlPlane...), classification.DataPlane...) {
+		answered[name] = true
+	}
+	var recorded []Operation
+	for _, op := range classified {
+		if answered[op.Name] {
+			recorded = append(recorded, op)
+		}
+	}
+	sharedClassificationCache.Record(serviceName, recorded, key)
+	return classified, warnings
+}
+
+// sharedClassificationCache is consulted by every extraction in the process
+var sharedClassificationCache = NewClassificationCache()
+
+// NewClassificationCache creates an empty classification cache
+func NewClassificationCache() *ClassificationCache {
+	return &ClassificationCache{Entries: make(map[string]map[string]OperationType)}
+}
+
+// LoadClassificationCache replaces the shared cache with the contents of a cache file.
+// A missing file is not an error so the first run can create it, and files written before entries
+// were keyed by prompt, model and shape load empty.
+func LoadClassificationCache(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read classification cache %s: %w", path, err)
+	}
+
+	cache := NewClassificationCache()
+	if err := json.Unmarshal(data, cache); err != nil {
+		return fmt.Errorf("failed to parse classification cache %s: %w", path, err)
+	}
+	if cache.Entries == nil {
+		cache.Entries = make(map[string]map[string]OperationType)
+	}
+
+	sharedClassificationCache = cache
+	return nil
+}
+
+// SaveClassificationCache writes the shared cache to a file
+func SaveClassificationCache(path string) error {
+	sharedClassificationCache.mu.Lock()
+	data, err := json.MarshalIndent(sharedClassificationCache, "", "  ")
+	sharedClassificationCache.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal classification cache: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// Reuse assigns cached types to operations that other services already classified consistently
+// under the same key. It returns the operations it could classify and the ones that still need the model.
+func (c *ClassificationCache) Reuse(serviceName string, operations []Operation, key classificationKeyFunc) (reused []Operation, remaining []Operation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, op := range operations {
+		if opType, ok := c.lookup(serviceName, key(op.Name)); ok {
+			op.Type = opType
+			reused = append(reused, op)
+		} else {
+			remaining = append(remaining, op)
+		}
+	}
+	return reused, remaining
+}
+
+// Record stores the classified types of a service's operations
+func (c *ClassificationCache) Record(serviceName string, operations []Operation, key classificationKeyFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, op := range operations {
+		if !op.Type.IsClassified() {
+			continue
+		}
+		k := key(op.Name)
+		if c.Entries[k] == nil {
+			c.Entries[k] = make(map[string]OperationType)
+		}
+		c.Entries[k][serviceName] = op.Type
+	}
+}
+
+// lookup returns the type other services agree on for a classification key.
+// Operations that sibling services classified differently are not reused.
+func (c *ClassificationCache) lookup(serviceName, key string) (OperationType, bool) {
+	var opType OperationType
+	for service, t := range c.Entries[key] {
+		if service == serviceName {
+			continue
+		}
+		if opType != "" && opType != t {
+			return "", false
+		}
+		opType = t
+	}
+	return opType, opType != ""
+}
+
+// conflicting reports whether services classified an operation differently under the same key
+func (c *ClassificationCache) conflicting(operationName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, services := range c.Entries {
+		if !strings.HasPrefix(key, operationName+"/") {
+			continue
+		}
+		var opType OperationType
+		for _, t := range services {
+			if opType != "" && opType != t {
+				return true
+			}
+			opType = t
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package extractor
+
+import (
+	"testing"
+	"text/template"
+)
+
+// cacheTestModel returns a model whose TagResource operation takes the given input members
+func cacheTestModel(namespace string, inputMembers ...string) *AWSServiceModel {
+	members := make(map[string]ShapeReference)
+	for _, name := range inputMembers {
+		members[name] = ShapeReference{Target: "smithy.api#String"}
+	}
+	return &AWSServiceModel{Shapes: map[string]ServiceShape{
+		namespace + "#TagResource":        {Type: "operation", Input: &ShapeReference{Target: namespace + "#TagResourceRequest"}},
+		namespace + "#TagResourceRequest": {Type: "structure", Members: members},
+	}}
+}
+
+func TestClassificationCacheKeys(t *testing.T) {
+	prompt := classificationPrompt{instruction: "Classify.", template: template.Must(template.New("prompt").Parse("{{.OperationList}}"))}
+	foo := classificationKeys(cacheTestModel("com.amazonaws.foo", "ResourceArn", "Tags"), prompt)
+	bar := classificationKeys(cacheTestModel("com.amazonaws.bar", "Tags", "ResourceArn"), prompt)
+	baz := classificationKeys(cacheTestModel("com.amazonaws.baz", "StreamName", "Tags"), prompt)
+
+	if foo("TagResource") != bar("TagResource") {
+		t.Error("operations with the same members in different namespaces have different keys")
+	}
+	if foo("TagResource") == baz("TagResource") {
+		t.Error("operations with different input members share a key")
+	}
+	reworded := prompt
+	reworded.instruction = "Classify carefully."
+	if classificationKeys(cacheTestModel("com.amazonaws.foo", "ResourceArn", "Tags"), reworded)("TagResource") == foo("TagResource") {
+		t.Error("a different agent instruction kept the key")
+	}
+	retemplated := prompt
+	retemplated.template = template.Must(template.New("prompt").Parse("Operations: {{.OperationList}}"))
+	if classificationKeys(cacheTestModel("com.amazonaws.foo", "ResourceArn", "Tags"), retemplated)("TagResource") == foo("TagResource") {
+		t.Error("a different prompt template kept the key")
+	}
+
+	cache := NewClassificationCache()
+	cache.Record("foo", []Operation{{Name: "TagResource", Type: OperationTypeControlPlane}}, foo)
+	if reused, _ := cache.Reuse("bar", []Operation{{Name: "TagResource"}}, bar); len(reused) != 1 || reused[0].Type != OperationTypeControlPlane {
+		t.Errorf("bar reused %v, want TagResource as control plane", reused)
+	}
+	if reused, remaining := cache.Reuse("baz", []Operation{{Name: "TagResource"}}, baz); len(reused) != 0 || len(remaining) != 1 {
+		t.Errorf("baz reused %v for an operation with a different shape", reused)
+	}
+}
+
+func TestModelClassificationRecordsOnlyAnsweredOperations(t *testing.T) {
+	t.Cleanup(ResetState)
+	sharedClassificationCache = NewClassificationCache()
+	prompt := classificationPrompt{instruction: "Classify.", template: template.Must(template.New("prompt").Parse("{{.OperationList}}"))}
+	key := classificationKeys(cacheTestModel("com.amazonaws.foo"), prompt)
+
+	// The answer was cut off before it named PutRecord
+	operations := []Operation{{Name: "CreateBar"}, {Name: "PutRecord"}}
+	classified, _ := applyModelClassification("foo", operations, &ClassificationResult{ControlPlane: []string{"CreateBar"}}, key)
+	if classified[0].Type != OperationTypeControlPlane || classified[1].Type != OperationTypeDataPlane {
+		t.Errorf("classified %+v, want CreateBar control plane and PutRecord defaulted to data plane", classified)
+	}
+
+	reused, remaining := sharedClassificationCache.Reuse("bar", []Operation{{Name: "CreateBar"}, {Name: "PutRecord"}}, key)
+	if len(reused) != 1 || reused[0].Name != "CreateBar" || len(remaining) != 1 || remaining[0].Name != "PutRecord" {
+		t.Errorf("bar reused %v and still needs %v, want only CreateBar reused", reused, remaining)
+	}
+}
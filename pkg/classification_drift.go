@@ -0,0 +1,50 @@
+package extractor
+
+import "fmt"
+
+// acceptClassificationDrift makes new classifications replace conflicting earlier ones
+var acceptClassificationDrift bool
+
+// SetAcceptClassificationDrift sets whether classifications that flip an operation between planes
+// replace the earlier classification instead of being held back for confirmation
+func SetAcceptClassificationDrift(accept bool) {
+	acceptClassificationDrift = accept
+}
+
+// previous returns the type a service assigned to a classification key in an earlier classification
+func (c *ClassificationCache) previous(serviceName, key string) (OperationType, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	opType, ok := c.Entries[key][serviceName]
+	return opType, ok
+}
+
+// checkClassificationDrift compares new classifications with the ones recorded for the service by
+// earlier runs. Model output is not deterministic, so an operation flipping between the control
+// and data plane keeps its earlier type and is flagged for confirmation, unless drift is accepted.
+// It returns a warning per drifted operation.
+func checkClassificationDrift(serviceName string, operations []Operation, key classificationKeyFunc) []string {
+	var warnings []string
+	for i := range operations {
+		op := &operations[i]
+		previous, ok := sharedClassificationCache.previous(serviceName, key(op.Name))
+		if !ok || previous == op.Type || !isPlane(previous) || !isPlane(op.Type) {
+			continue
+		}
+
+		op.ClassificationDrift = &ClassificationDrift{Previous: previous, Proposed: op.Type, Accepted: acceptClassificationDrift}
+		if acceptClassificationDrift {
+			warnings = append(warnings, fmt.Sprintf("classification of %s changed from %s to %s", op.Name, previous, op.Type))
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("classification of %s flipped from %s to %s, keeping %s until confirmed", op.Name, previous, op.Type, previous))
+		op.Type = previous
+	}
+	return warnings
+}
+
+// isPlane reports whether a type assigns an operation to the control or the data plane
+func isPlane(opType OperationType) bool {
+	return opType.IsControlPlane() || opType.IsDataPlane()
+}
@@ -0,0 +1,272 @@
+package extractor
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed datasets/classification_golden.yaml
+var goldenDataset []byte
+
+// GoldenSet is a dataset of operations labeled by hand, which classifiers are evaluated against
+type GoldenSet struct {
+	// Source is the file the dataset was read from, or "embedded"
+	Source   string
+	Services []GoldenService
+}
+
+// GoldenService is the labeled operations of a single service
+type GoldenService struct {
+	Service      string   `yaml:"service"`
+	ControlPlane []string `yaml:"control_plane"`
+	DataPlane    []string `yaml:"data_plane"`
+}
+
+// Operations counts the labeled operations of the dataset
+func (g *GoldenSet) Operations() int {
+	count := 0
+	for _, service := range g.Services {
+		count += len(service.ControlPlane) + len(service.DataPlane)
+	}
+	return count
+}
+
+// FilterServices keeps only the given services of the dataset, failing for services it has no
+// labels for
+func (g *GoldenSet) FilterServices(services []string) error {
+	wanted := make(map[string]bool, len(services))
+	for _, service := range services {
+		wanted[service] = true
+	}
+	var kept []GoldenService
+	for _, service := range g.Services {
+		if wanted[service.Service] {
+			kept = append(kept, service)
+			delete(wanted, service.Service)
+		}
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for service := range wanted {
+			missing = append(missing, service)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("no labeled operations for %s in %s", strings.Join(missing, ", "), g.Source)
+	}
+	g.Services = kept
+	return nil
+}
+
+// LoadGoldenSet reads a golden dataset, the embedded one when path is empty
+func LoadGoldenSet(path string) (*GoldenSet, error) {
+	if path == "" {
+		return parseGoldenSet("embedded", goldenDataset)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden dataset %s: %w", path, err)
+	}
+	golden, err := parseGoldenSet(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid golden dataset %s: %w", path, err)
+	}
+	return golden, nil
+}
+
+// parseGoldenSet reads the labeled services of a dataset, rejecting operations labeled twice
+func parseGoldenSet(source string, data []byte) (*GoldenSet, error) {
+	golden := &GoldenSet{Source: source}
+	if err := yaml.Unmarshal(data, &golden.Services); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for i, service := range golden.Services {
+		if service.Service == "" {
+			return nil, fmt.Errorf("entry %d has no service", i+1)
+		}
+		for _, name := range append(append([]string{}, service.ControlPlane...), service.DataPlane...) {
+			key := service.Service + ":" + name
+			if seen[key] {
+				return nil, fmt.Errorf("%s is labeled more than once", key)
+			}
+			seen[key] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("no labeled operations")
+	}
+	return golden, nil
+}
+
+// Classifier labels the operations of a service; operations missing from its result are unclassified
+type Classifier struct {
+	Name     string
+	classify func(serviceName string, operations []Operation) (*ClassificationResult, error)
+}
+
+// NewClassifier builds a classifier from its specification: bedrock for the configured agent and
+// prompt, bedrock:<file> for the agent with another prompt template, heuristic for the verb-based
+// fallback used when Bedrock fails, or rules for the classification rules alone
+func NewClassifier(spec string) (*Classifier, error) {
+	kind, argument, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "bedrock":
+		return newBedrockClassifier(spec, argument)
+	case "heuristic":
+		if argument != "" {
+			break
+		}
+		return &Classifier{Name: spec, classify: func(serviceName string, operations []Operation) (*ClassificationResult, error) {
+			return classificationResultOf(ClassifyHeuristically(serviceName, operations)), nil
+		}}, nil
+	case "rules":
+		if argument != "" {
+			break
+		}
+		return &Classifier{Name: spec, classify: classifyByRules}, nil
+	}
+	return nil, fmt.Errorf("unknown classifier %q, expected bedrock, bedrock:<prompt template>, heuristic or rules", spec)
+}
+
+// newBedrockClassifier classifies with the Bedrock agent, rendering prompts from the template file
+// when one is given. Every Bedrock classifier uses agent sessions of its own, so one prompt does
+// not see the conversation of another.
+func newBedrockClassifier(name, promptTemplate string) (*Classifier, error) {
+	prompt := classificationPrompt{instruction: agentInstruction, template: classificationTemplate, runID: strconv.FormatInt(time.Now().UnixNano(), 36)}
+	if promptTemplate != "" {
+		var err error
+		if prompt.template, err = readPromptTemplate(promptTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Classifier{Name: name, classify: func(serviceName string, operations []Operation) (*ClassificationResult, error) {
+		return classifyOperationsWith(prompt, serviceName, operations)
+	}}, nil
+}
+
+// classifyByRules labels the operations a classification rule matches and leaves the rest unclassified
+func classifyByRules(serviceName string, operations []Operation) (*ClassificationResult, error) {
+	var classified []Operation
+	for _, op := range operations {
+		if rule := matchClassificationRule(serviceName, op.Name); rule != nil {
+			op.Type = rule.Type
+			classified = append(classified, op)
+		}
+	}
+	return classificationResultOf(classified), nil
+}
+
+// classificationResultOf lists classified operations by type
+func classificationResultOf(operations []Operation) *ClassificationResult {
+	result := &ClassificationResult{ControlPlane: []string{}, DataPlane: []string{}}
+	for _, op := range operations {
+		switch op.Type {
+		case OperationTypeControlPlane:
+			result.ControlPlane = append(result.ControlPlane, op.Name)
+		case OperationTypeDataPlane:
+			result.DataPlane = append(result.DataPlane, op.Name)
+		}
+	}
+	return result
+}
+
+// EvaluateClassifier classifies every operation of the golden dataset and measures the precision
+// and recall of each operation type. A service the classifier fails on is recorded and its
+// operations count as unclassified.
+func EvaluateClassifier(golden *GoldenSet, classifier *Classifier) ClassifierEvaluation {
+	evaluation := ClassifierEvaluation{Classifier: classifier.Name, Mistakes: []EvaluationMistake{}}
+	types := []OperationType{OperationTypeControlPlane, OperationTypeDataPlane}
+	counts := make(map[OperationType]*ClassMetrics, len(types))
+	for _, operationType := range types {
+		counts[operationType] = &ClassMetrics{Type: operationType}
+	}
+
+	correct, total := 0, 0
+	for _, service := range golden.Services {
+		expected := make(map[string]OperationType)
+		var operations []Operation
+		for _, name := range service.ControlPlane {
+			expected[name] = OperationTypeControlPlane
+			operations = append(operations, Operation{Name: name})
+		}
+		for _, name := range service.DataPlane {
+			expected[name] = OperationTypeDataPlane
+			operations = append(operations, Operation{Name: name})
+		}
+
+		actual := make(map[string]OperationType)
+		result, err := classifier.classify(service.Service, operations)
+		if err != nil {
+			if evaluation.Errors == nil {
+				evaluation.Errors = make(map[string]string)
+			}
+			evaluation.Errors[service.Service] = err.Error()
+		} else {
+			for _, name := range result.ControlPlane {
+				actual[name] = OperationTypeControlPlane
+			}
+			for _, name := range result.DataPlane {
+				actual[name] = OperationTypeDataPlane
+			}
+		}
+
+		for _, op := range operations {
+			want, got := expected[op.Name], actual[op.Name]
+			total++
+			if got == want {
+				correct++
+				counts[want].TruePositives++
+				continue
+			}
+			counts[want].FalseNegatives++
+			if got == "" {
+				evaluation.Unclassified++
+			} else {
+				counts[got].FalsePositives++
+			}
+			evaluation.Mistakes = append(evaluation.Mistakes, EvaluationMistake{Service: service.Service, Operation: op.Name, Expected: want, Actual: got})
+		}
+	}
+
+	if total > 0 {
+		evaluation.Accuracy = float64(correct) / float64(total)
+	}
+	for _, operationType := range types {
+		metrics := counts[operationType]
+		metrics.Precision = ratio(metrics.TruePositives, metrics.TruePositives+metrics.FalsePositives)
+		metrics.Recall = ratio(metrics.TruePositives, metrics.TruePositives+metrics.FalseNegatives)
+		if metrics.Precision+metrics.Recall > 0 {
+			metrics.F1 = 2 * metrics.Precision * metrics.Recall / (metrics.Precision + metrics.Recall)
+		}
+		evaluation.Classes = append(evaluation.Classes, *metrics)
+	}
+	return evaluation
+}
+
+// ratio divides two counts, returning 0 when the denominator is 0
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+// WriteClassificationEvaluationJSON writes a classifier evaluation to a JSON file
+func WriteClassificationEvaluationJSON(evaluation *ClassificationEvaluation, outputPath string) error {
+	evaluation.SchemaVersion = SchemaVersion
+	data, err := json.MarshalIndent(evaluation, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal classification evaluation JSON: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
@@ -0,0 +1,87 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClassifierKeepsConfiguredPrompt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candidate.tmpl")
+	if err := os.WriteFile(path, []byte("Classify {{.OperationList}} of {{.ServiceName}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configured, session := classificationTemplate, classificationSessionID("foo")
+
+	for _, spec := range []string{"bedrock", "bedrock:" + path} {
+		if _, err := NewClassifier(spec); err != nil {
+			t.Fatalf("NewClassifier(%s): %v", spec, err)
+		}
+	}
+	if classificationTemplate != configured || classificationSessionID("foo") != session {
+		t.Error("building Bedrock classifiers changed the configured prompt template or agent session")
+	}
+
+	if _, err := NewClassifier("bedrock:" + filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("NewClassifier accepted a missing prompt template")
+	}
+	for _, spec := range []string{"heuristic:x", "rules:x", "llm"} {
+		if _, err := NewClassifier(spec); err == nil {
+			t.Errorf("NewClassifier accepted %q", spec)
+		}
+	}
+}
+
+func TestBuildBatchInputUsesGivenTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candidate.tmpl")
+	content := `Classify {{.OperationList}} of {{.ServiceName}}{{define "followup"}}Also {{.OperationList}}{{end}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := readPromptTemplate(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		primed   bool
+		input    string
+		followUp bool
+	}{
+		{false, "Classify CreateBar, PutRecord of foo", false},
+		{true, "Also CreateBar, PutRecord", true},
+	}
+	for _, test := range tests {
+		input, followUp, err := buildBatchInput(tmpl, "foo", []string{"CreateBar", "PutRecord"}, test.primed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if input != test.input || followUp != test.followUp {
+			t.Errorf("primed %t: got %q (follow-up %t), want %q (follow-up %t)", test.primed, input, followUp, test.input, test.followUp)
+		}
+	}
+}
+
+func TestEvaluateClassifier(t *testing.T) {
+	golden, err := parseGoldenSet("test", []byte(`
+- service: foo
+  control_plane: [CreateBar, DeleteBar]
+  data_plane: [PutRecord, GetRecords]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	classifier := &Classifier{Name: "fixed", classify: func(serviceName string, operations []Operation) (*ClassificationResult, error) {
+		return &ClassificationResult{ControlPlane: []string{"CreateBar", "PutRecord"}, DataPlane: []string{"GetRecords"}}, nil
+	}}
+
+	evaluation := EvaluateClassifier(golden, classifier)
+	if evaluation.Accuracy != 0.5 || evaluation.Unclassified != 1 || len(evaluation.Mistakes) != 2 {
+		t.Errorf("accuracy %.2f, %d unclassified, %d mistakes, want 0.50, 1 and 2", evaluation.Accuracy, evaluation.Unclassified, len(evaluation.Mistakes))
+	}
+	controlPlane, dataPlane := evaluation.Classes[0], evaluation.Classes[1]
+	if controlPlane.Precision != 0.5 || controlPlane.Recall != 0.5 || dataPlane.Precision != 1 || dataPlane.Recall != 0.5 {
+		t.Errorf("control plane %.2f/%.2f, data plane %.2f/%.2f, want 0.50/0.50 and 1.00/0.50",
+			controlPlane.Precision, controlPlane.Recall, dataPlane.Precision, dataPlane.Recall)
+	}
+}
@@ -0,0 +1,70 @@
+package extractor
+
+import "fmt"
+
+// ClassificationFailurePolicy decides what happens to the operations of a service when their
+// Bedrock classification fails
+type ClassificationFailurePolicy string
+
+const (
+	// ClassificationFailureContinue marks the operations unknown and extracts the service anyway
+	ClassificationFailureContinue ClassificationFailurePolicy = "continue"
+	// ClassificationFailureFail fails the extraction of the service
+	ClassificationFailureFail ClassificationFailurePolicy = "fail"
+	// ClassificationFailureHeuristic classifies the operations from their names instead
+	ClassificationFailureHeuristic ClassificationFailurePolicy = "heuristic"
+)
+
+// classificationFailurePolicy is applied when the classification of a service fails
+var classificationFailurePolicy = ClassificationFailureContinue
+
+// ParseClassificationFailurePolicy parses a classification failure policy: continue, fail or heuristic
+func ParseClassificationFailurePolicy(value string) (ClassificationFailurePolicy, error) {
+	switch policy := ClassificationFailurePolicy(value); policy {
+	case ClassificationFailureContinue, ClassificationFailureFail, ClassificationFailureHeuristic:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid classification failure policy %q, expected %s, %s or %s",
+		value, ClassificationFailureContinue, ClassificationFailureFail, ClassificationFailureHeuristic)
+}
+
+// SetClassificationFailurePolicy sets what happens when the classification of a service fails:
+// continuing with unknown operations, the default, failing the service, or classifying heuristically
+func SetClassificationFailurePolicy(policy ClassificationFailurePolicy) error {
+	if _, err := ParseClassificationFailurePolicy(string(policy)); err != nil {
+		return err
+	}
+	classificationFailurePolicy = policy
+	return nil
+}
+
+// ClassifyHeuristically classifies operations without Bedrock: the first classification rule
+// matching an operation decides its type, otherwise operations whose verb manages or reads a
+// resource (see Relevance) are control plane and all others data plane. Heuristic
+// classifications are flagged and never cached, so a later run classifies them with Bedrock.
+func ClassifyHeuristically(serviceName string, operations []Operation) []Operation {
+	for i := range operations {
+		verb := operationVerb(operations[i].Name)
+		switch rule := matchClassificationRule(serviceName, operations[i].Name); {
+		case rule != nil:
+			operations[i].Type = rule.Type
+		case declarativeVerbs[verb] || readVerbs[verb]:
+			operations[i].Type = OperationTypeControlPlane
+		default:
+			operations[i].Type = OperationTypeDataPlane
+		}
+		operations[i].HeuristicClassification = true
+	}
+	return operations
+}
+
+// CountHeuristicClassifications counts the operations classified heuristically after a failed classification
+func CountHeuristicClassifications(operations []Operation) int {
+	count := 0
+	for _, op := range operations {
+		if op.HeuristicClassification {
+			count++
+		}
+	}
+	return count
+}
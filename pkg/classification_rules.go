@@ -0,0 +1,147 @@
+package extractor
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed datasets/classification_rules.yaml
+var classificationRulesDataset []byte
+
+// ClassificationRule overrides the type Bedrock classified matching operations as
+type ClassificationRule struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Type     OperationType
+	Services map[string]bool
+}
+
+// classificationRuleFile is a rule as written in a rules file
+type classificationRuleFile struct {
+	Name     string   `yaml:"name"`
+	Pattern  string   `yaml:"pattern"`
+	Type     string   `yaml:"type"`
+	Services []string `yaml:"services"`
+}
+
+// classificationRules are checked in order after every Bedrock classification
+var classificationRules = mustParseClassificationRules(classificationRulesDataset)
+
+// mustParseClassificationRules parses the embedded classification rules
+func mustParseClassificationRules(data []byte) []ClassificationRule {
+	rules, err := parseClassificationRules(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid classification rules dataset: %v", err))
+	}
+	return rules
+}
+
+// parseClassificationRules reads a list of classification rules
+func parseClassificationRules(data []byte) ([]ClassificationRule, error) {
+	var entries []classificationRuleFile
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	rules := make([]ClassificationRule, 0, len(entries))
+	for i, entry := range entries {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		pattern, err := regexp.Compile(entry.Pattern)
+		if err != nil || entry.Pattern == "" {
+			return nil, fmt.Errorf("rule %s has an invalid pattern %q", entry.Name, entry.Pattern)
+		}
+		operationType, err := ParseOperationType(entry.Type)
+		if err != nil || (operationType != OperationTypeControlPlane && operationType != OperationTypeDataPlane) {
+			return nil, fmt.Errorf("rule %s has type %q, expected %s or %s", entry.Name, entry.Type, OperationTypeControlPlane, OperationTypeDataPlane)
+		}
+		rule := ClassificationRule{Name: entry.Name, Pattern: pattern, Type: operationType}
+		if len(entry.Services) > 0 {
+			rule.Services = make(map[string]bool, len(entry.Services))
+			for _, service := range entry.Services {
+				rule.Services[service] = true
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// LoadClassificationRules reads classification rules from a YAML file. They are checked before the
+// embedded rules, so they can override them:
+//
+//   - name: s3-object-acl
+//     pattern: '^(Get|Put)ObjectAcl$'
+//     type: data_plane
+//     services: [s3]
+func LoadClassificationRules(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read classification rules %s: %w", path, err)
+	}
+	rules, err := parseClassificationRules(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse classification rules %s: %w", path, err)
+	}
+	classificationRules = append(rules, classificationRules...)
+	return nil
+}
+
+// matchClassificationRule returns the first rule matching an operation of a service, nil for none
+func matchClassificationRule(serviceName, operationName string) *ClassificationRule {
+	for i, rule := range classificationRules {
+		if rule.Services != nil && !rule.Services[serviceName] {
+			continue
+		}
+		if rule.Pattern.MatchString(operationName) {
+			return &classificationRules[i]
+		}
+	}
+	return nil
+}
+
+// CountClassificationCorrections counts the operations whose classification a rule corrected
+func CountClassificationCorrections(operations []Operation) int {
+	count := 0
+	for _, op := range operations {
+		if op.Correction != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// correctClassification moves operations whose classification contradicts a rule to the rule's
+// plane and records each correction in the result
+func correctClassification(serviceName string, result *ClassificationResult) {
+	corrected := &ClassificationResult{ControlPlane: []string{}, DataPlane: []string{}, Corrections: result.Corrections}
+	add := func(operationName string, classified OperationType) {
+		operationType := classified
+		if rule := matchClassificationRule(serviceName, operationName); rule != nil && rule.Type != classified {
+			operationType = rule.Type
+			corrected.Corrections = append(corrected.Corrections, ClassificationCorrection{
+				Operation: operationName,
+				Rule:      rule.Name,
+				Original:  classified,
+				Corrected: rule.Type,
+			})
+		}
+		if operationType == OperationTypeControlPlane {
+			corrected.ControlPlane = append(corrected.ControlPlane, operationName)
+		} else {
+			corrected.DataPlane = append(corrected.DataPlane, operationName)
+		}
+	}
+	for _, operationName := range result.ControlPlane {
+		add(operationName, OperationTypeControlPlane)
+	}
+	for _, operationName := range result.DataPlane {
+		add(operationName, OperationTypeDataPlane)
+	}
+	*result = *corrected
+}
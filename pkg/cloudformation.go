@@ -0,0 +1,109 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// cfnResourceTrait marks a Smithy resource shape as a CloudFormation resource
+const cfnResourceTrait = "aws.cloudformation#cfnResource"
+
+// cfnResourceTraitValue represents the fields of the aws.cloudformation#cfnResource trait used by the extractor
+type cfnResourceTraitValue struct {
+	Name string `json:"name"`
+}
+
+// MapCloudFormationResources maps the resource shapes carrying the cfnResource trait to
+// CloudFormation resource type names (AWS::<Service>::<Resource>) and the operations
+// bound directly to each resource. The service part comes from the cloudFormationName
+// of the aws.api#service trait.
+func MapCloudFormationResources(model *AWSServiceModel) []CloudFormationResource {
+	metadata := ExtractServiceMetadata(model)
+	if metadata.CloudFormationName == "" {
+		return nil
+	}
+
+	var resources []CloudFormationResource
+	for shapeName, shape := range model.Shapes {
+		raw, ok := shape.Traits[cfnResourceTrait]
+		if shape.Type != "resource" || !ok {
+			continue
+		}
+
+		var trait cfnResourceTraitValue
+		if err := json.Unmarshal(raw, &trait); err != nil {
+			continue
+		}
+		resourceName := trait.Name
+		if resourceName == "" {
+			resourceName = extractOperationName(shapeName)
+		}
+
+		var operations []string
+		for _, target := range resourceOperationTargets(shape) {
+			if name := extractOperationName(target); name != "" {
+				operations = append(operations, name)
+			}
+		}
+		sort.Strings(operations)
+
+		resources = append(resources, CloudFormationResource{
+			Type:       fmt.Sprintf("AWS::%s::%s", metadata.CloudFormationName, resourceName),
+			Operations: operations,
+		})
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Type < resources[j].Type })
+	return resources
+}
+
+// ApplyCloudFormationTypes sets the CloudFormation resource type of every operation bound to a
+// CloudFormation resource and marks the resources whose operations the controller supports
+func ApplyCloudFormationTypes(operations []Operation, resources []CloudFormationResource) {
+	typeByOperation := cloudFormationTypes(resources)
+
+	supportedTypes := make(map[string]bool)
+	for i := range operations {
+		cfnType, ok := typeByOperation[operations[i].Name]
+		if !ok {
+			continue
+		}
+		operations[i].CloudFormationType = cfnType
+		if operations[i].IsSupported() {
+			supportedTypes[cfnType] = true
+		}
+	}
+
+	for i := range resources {
+		resources[i].ACKSupported = supportedTypes[resources[i].Type]
+	}
+}
+
+// resourceOperationTargets returns the operation targets bound directly to a resource shape
+func resourceOperationTargets(shape ServiceShape) []string {
+	var targets []string
+	for _, op := range shape.Operations {
+		targets = append(targets, op.Target)
+	}
+	for _, op := range shape.CollectionOperations {
+		targets = append(targets, op.Target)
+	}
+	for _, ref := range []*ShapeReference{shape.Create, shape.Put, shape.Read, shape.Update, shape.Delete, shape.List} {
+		if ref != nil && ref.Target != "" {
+			targets = append(targets, ref.Target)
+		}
+	}
+	return targets
+}
+
+// cloudFormationTypes maps operation names to the CloudFormation resource type they are bound to
+func cloudFormationTypes(resources []CloudFormationResource) map[string]string {
+	typeByOperation := make(map[string]string)
+	for _, resource := range resources {
+		for _, name := range resource.Operations {
+			typeByOperation[name] = resource.Type
+		}
+	}
+	return typeByOperation
+}
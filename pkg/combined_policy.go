@@ -0,0 +1,77 @@
+package extractor
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CombinedPolicyName returns the suggested name of the index-th of count managed policies of the
+// role shared by all controllers of a cluster
+func CombinedPolicyName(index, count int) string {
+	if count > 1 {
+		return fmt.Sprintf("ack-controllers-%d", index+1)
+	}
+	return "ack-controllers"
+}
+
+// GenerateCombinedPolicy creates one IAM policy granting the supported operations of several services,
+// for a single role shared by every ACK controller of a cluster. Statements keep the layout of the
+// per-service policies with the service prefixed to their Sid, e.g. S3Tagging, numbered when two
+// services still end up with the same Sid. Actions an earlier statement already grants on the same
+// resource or on "*" are dropped, so cross-service calls shared by several controllers and operations
+// of services sharing an IAM prefix are granted once. Services without supported operations are
+// skipped and returned.
+func GenerateCombinedPolicy(services map[string]*ServiceOperations, partition string) (*IAMPolicy, []string, error) {
+	if err := ValidatePartition(partition); err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(services))
+	for serviceName := range services {
+		names = append(names, serviceName)
+	}
+	sort.Strings(names)
+
+	combined := &IAMPolicy{Version: "2012-10-17"}
+	granted := make(map[string]map[string]bool)
+	sids := make(map[string]bool)
+	var skipped []string
+	for _, serviceName := range names {
+		serviceOps := services[serviceName]
+		if len(PolicyActions(serviceName, serviceOps.Operations)) == 0 {
+			skipped = append(skipped, serviceName)
+			continue
+		}
+		policy, err := GeneratePartitionPolicy(serviceName, serviceOps.Operations, partition)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate policy for %s: %w", serviceName, err)
+		}
+		AddCrossServiceStatements(policy, serviceOps.UnmodeledCalls)
+
+		for _, stmt := range policy.Statement {
+			resource, _ := stmt.Resource.(string)
+			var actions []string
+			for _, action := range stmt.Action {
+				if granted[action]["*"] || granted[action][resource] {
+					continue
+				}
+				if granted[action] == nil {
+					granted[action] = make(map[string]bool)
+				}
+				granted[action][resource] = true
+				actions = append(actions, action)
+			}
+			if len(actions) == 0 {
+				continue
+			}
+			stmt.Sid = uniqueSid(sids, statementSidSuffix(serviceName)+stmt.Sid)
+			stmt.Action = actions
+			combined.Statement = append(combined.Statement, stmt)
+		}
+	}
+
+	if len(combined.Statement) == 0 {
+		return nil, skipped, fmt.Errorf("no supported operations found for any of the %d service(s)", len(services))
+	}
+	return combined, skipped, nil
+}
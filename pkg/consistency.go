@@ -0,0 +1,100 @@
+package extractor
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConsistencyModel describes the read-after-write semantics of an operation
+type ConsistencyModel string
+
+const (
+	// ConsistencyStrong means reads reflect all writes that completed before them
+	ConsistencyStrong ConsistencyModel = "strong"
+	// ConsistencyEventual means reads may return stale data for a while after a write
+	ConsistencyEventual ConsistencyModel = "eventual"
+	// ConsistencyConfigurable means the caller chooses, e.g. through a ConsistentRead parameter
+	ConsistencyConfigurable ConsistencyModel = "configurable"
+)
+
+const (
+	// ConsistencySourceCurated marks consistency taken from the embedded dataset
+	ConsistencySourceCurated = "curated"
+	// ConsistencySourceModel marks consistency derived from the operation's input members
+	ConsistencySourceModel = "model"
+	// ConsistencySourceDocumentation marks consistency derived from the operation's documentation
+	ConsistencySourceDocumentation = "documentation"
+)
+
+// consistencyWildcard applies a curated consistency to every operation of a service
+const consistencyWildcard = "*"
+
+// consistentReadMember is the input member letting callers request strongly consistent reads
+const consistentReadMember = "ConsistentRead"
+
+//go:embed datasets/consistency.yaml
+var consistencyDataset []byte
+
+// curatedConsistency maps service names to operation names to their consistency
+var curatedConsistency = mustParseConsistencyDataset(consistencyDataset)
+
+// eventualConsistencyPattern matches documentation stating that an operation is eventually consistent
+var eventualConsistencyPattern = regexp.MustCompile(`(?i)eventual(ly)?[\s-]+consisten(t|cy)`)
+
+// mustParseConsistencyDataset parses the embedded consistency dataset
+func mustParseConsistencyDataset(data []byte) map[string]map[string]ConsistencyModel {
+	var dataset map[string]map[string]ConsistencyModel
+	if err := yaml.Unmarshal(data, &dataset); err != nil {
+		panic(fmt.Sprintf("invalid consistency dataset: %v", err))
+	}
+	return dataset
+}
+
+// ApplyConsistency tags each operation with its read-after-write consistency, so controllers know
+// whether a resource can be read back right after it was created. A curated entry for the
+// operation wins over a ConsistentRead input member, which wins over documentation mentioning
+// eventual consistency, which wins over a curated entry for the whole service. Operations
+// without any signal are left untagged.
+func ApplyConsistency(serviceName string, operations []Operation, model *AWSServiceModel) {
+	shapes := operationShapes(model)
+	for i := range operations {
+		operations[i].Consistency, operations[i].ConsistencySource = operationConsistency(serviceName, operations[i].Name, shapes[operations[i].Name], model)
+	}
+}
+
+// operationConsistency returns the consistency of a single operation and where it came from
+func operationConsistency(serviceName, operationName string, shape ServiceShape, model *AWSServiceModel) (ConsistencyModel, string) {
+	curated := curatedConsistency[serviceName]
+	if consistency, ok := curated[operationName]; ok {
+		return consistency, ConsistencySourceCurated
+	}
+	if hasConsistentReadMember(model, shape) {
+		return ConsistencyConfigurable, ConsistencySourceModel
+	}
+	if eventualConsistencyPattern.MatchString(stringTrait(shape.Traits, documentationTrait)) {
+		return ConsistencyEventual, ConsistencySourceDocumentation
+	}
+	if consistency, ok := curated[consistencyWildcard]; ok {
+		return consistency, ConsistencySourceCurated
+	}
+	return "", ""
+}
+
+// hasConsistentReadMember reports whether an operation's input has a boolean ConsistentRead member
+func hasConsistentReadMember(model *AWSServiceModel, operation ServiceShape) bool {
+	if operation.Input == nil {
+		return false
+	}
+	member, ok := model.Shapes[operation.Input.Target].Members[consistentReadMember]
+	if !ok {
+		return false
+	}
+	switch member.Target {
+	case "smithy.api#Boolean", "smithy.api#PrimitiveBoolean":
+		return true
+	}
+	return model.Shapes[member.Target].Type == "boolean"
+}
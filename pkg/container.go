@@ -0,0 +1,210 @@
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Well-known mount points checked when the extractor runs in a container without configured directories
+var (
+	containerModelsMounts      = []string{"/models", "/workspace/api-models-aws/models"}
+	containerControllersMounts = []string{"/controllers", "/workspace"}
+)
+
+// cacheFallbackWarning makes sure the read-only cache warning is printed once per run
+var cacheFallbackWarning sync.Once
+
+// InContainer reports whether the extractor appears to run inside a container
+func InContainer() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	data, err := os.ReadFile("/proc/1/cgroup")
+	if err != nil {
+		return false
+	}
+	for _, runtime := range []string{"docker", "kubepods", "containerd", "libpod"} {
+		if strings.Contains(string(data), runtime) {
+			return true
+		}
+	}
+	return false
+}
+
+// ModelsDir returns the configured directory containing the AWS service model directories
+func ModelsDir() string {
+	return modelsDir()
+}
+
+// ControllersDir returns the configured directory containing the <service>-controller directories
+func ControllersDir() string {
+	return controllersRoot
+}
+
+// DetectMounts falls back to well-known container mount points for the models and controllers
+// directories when the defaults don't exist. Directories set explicitly are left untouched; callers
+// pass whether each one was configured. The mount points that were applied are returned.
+func DetectMounts(modelsConfigured, controllersConfigured bool) []string {
+	var applied []string
+	if !modelsConfigured && !isDir(modelsRoot) {
+		if mount := firstDir(containerModelsMounts); mount != "" {
+			modelsRoot = mount
+			applied = append(applied, mount)
+		}
+	}
+	if !controllersConfigured && !hasControllers(controllersRoot) {
+		for _, mount := range containerControllersMounts {
+			if hasControllers(mount) {
+				controllersRoot = mount
+				applied = append(applied, mount)
+				break
+			}
+		}
+	}
+	return applied
+}
+
+// ListControllers returns the service names of the controller directories the controller path
+// template matches, the <service>-controller directories in the controllers directory by default
+func ListControllers() ([]string, error) {
+	if _, err := os.ReadDir(controllersRoot); err != nil && strings.Contains(ControllerPathTemplate(), "{root}") {
+		return nil, fmt.Errorf("failed to read controllers directory %s: %w", controllersRoot, err)
+	}
+
+	var services []string
+	for serviceName, path := range listPathTemplateServices(ControllerPathTemplate(), controllersRoot) {
+		if isDir(path) {
+			services = append(services, serviceName)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// CheckWritable verifies a directory can be created and written to by creating and removing a probe file
+func CheckWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// RunSelfCheck verifies the directories and tools the extractor depends on. outputDir is checked
+// for write access when set.
+func RunSelfCheck(outputDir string) []SelfCheckResult {
+	var results []SelfCheckResult
+	add := func(name string, status SelfCheckStatus, remediation, format string, args ...interface{}) {
+		results = append(results, SelfCheckResult{Name: name, Status: status, Message: fmt.Sprintf(format, args...), Remediation: remediation})
+	}
+
+	if InContainer() {
+		add("runtime", SelfCheckOK, "", "running in a container")
+	} else {
+		add("runtime", SelfCheckOK, "", "running on the host")
+	}
+
+	modelsRemediation := "clone https://github.com/aws/api-models-aws next to this repository, or point --models-dir (ACK_EXTRACTOR_MODELS_DIR) at its models directory"
+
+	services, err := ListAvailableServices()
+	_, modelsErr := os.Stat(modelsDir())
+	switch {
+	case modelsErr != nil && slices.Contains(ModelSources(), GoSDKModelSource):
+		if _, err := exec.LookPath("go"); err != nil {
+			add("models", SelfCheckFail, modelsRemediation, "models directory %s not found, and go is not on the PATH to derive models from aws-sdk-go-v2 packages", modelsDir())
+		} else {
+			add("models", SelfCheckWarn, modelsRemediation, "models directory %s not found, models are derived from the aws-sdk-go-v2 packages controllers depend on", modelsDir())
+		}
+	case err != nil:
+		add("models", SelfCheckFail, modelsRemediation, "%v", err)
+	case len(services) == 0:
+		add("models", SelfCheckFail, modelsRemediation, "no service model directories in %s", modelsDir())
+	default:
+		if _, err := findServiceModelJSONFile(services[0]); err != nil {
+			add("models", SelfCheckFail, modelsRemediation, "%d service(s) in %s, but %s has no readable model: %v", len(services), modelsDir(), services[0], err)
+		} else {
+			add("models", SelfCheckOK, "", "%d service(s) in %s", len(services), modelsDir())
+		}
+	}
+
+	controllersRemediation := "clone the <service>-controller repositories into one directory and point --controllers-dir (ACK_EXTRACTOR_CONTROLLERS_DIR) at it"
+	controllers, err := ListControllers()
+	switch {
+	case err != nil:
+		add("controllers", SelfCheckFail, controllersRemediation, "%v", err)
+	case len(controllers) == 0:
+		add("controllers", SelfCheckWarn, controllersRemediation, "no controller directories matching %s in %s (only --no-controller runs will work)", ControllerPathTemplate(), controllersRoot)
+	default:
+		add("controllers", SelfCheckOK, "", "%d controller(s) in %s", len(controllers), controllersRoot)
+	}
+
+	if dir, err := CacheDir(); err != nil {
+		add("cache", SelfCheckWarn, "set --cache-dir (ACK_EXTRACTOR_CACHE_DIR) to a writable directory", "%v (caching disabled)", err)
+	} else if err := CheckWritable(dir); err != nil {
+		add("cache", SelfCheckWarn, "set --cache-dir (ACK_EXTRACTOR_CACHE_DIR) to a writable directory", "%v", err)
+	} else {
+		add("cache", SelfCheckOK, "", "%s is writable", dir)
+	}
+
+	if outputDir != "" {
+		if err := CheckWritable(outputDir); err != nil {
+			add("output", SelfCheckFail, "create the output directory or mount it writable", "%v", err)
+		} else {
+			add("output", SelfCheckOK, "", "%s is writable", outputDir)
+		}
+	}
+
+	if path, err := exec.LookPath("git"); err != nil {
+		add("git", SelfCheckWarn, "install git and add it to the PATH", "git not found on PATH (needed by --resolve-owners and policy-diff)")
+	} else {
+		add("git", SelfCheckOK, "", "%s", path)
+	}
+	return results
+}
+
+// cacheFallbackDir returns the temporary directory used when the user cache directory is unavailable or read-only
+func cacheFallbackDir() string {
+	return filepath.Join(os.TempDir(), appDirName)
+}
+
+// isDir reports whether path is an existing directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// firstDir returns the first of the paths that is an existing directory
+func firstDir(paths []string) string {
+	for _, path := range paths {
+		if isDir(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// hasControllers reports whether the controller path template matches at least one controller
+// directory below dir
+func hasControllers(dir string) bool {
+	for _, match := range listPathTemplateServices(ControllerPathTemplate(), dir) {
+		if isDir(match) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AuditControllerCalls lists every AWS API call in a controller's code with its IAM action and
+// locations. Only the controller is read, not the service model, so it answers which permissions
+// a controller needs without a models checkout; the IAM prefix of the controller's own service
+// comes from the model when one is available and from generator.yaml otherwise.
+func AuditControllerCalls(serviceName string) (*ControllerCalls, error) {
+	if findControllerForService(serviceName) == "" {
+		return nil, fmt.Errorf("controller not found for service %s", serviceName)
+	}
+
+	calls := findControllerCalls(serviceName, func(string, bool) bool { return true })
+	audit := &ControllerCalls{
+		SchemaVersion:     SchemaVersion,
+		ServiceName:       serviceName,
+		ControllerRelease: ControllerReleaseTag(serviceName),
+		Actions:           make([]string, 0, len(calls)),
+		Calls:             calls,
+	}
+	if audit.Calls == nil {
+		audit.Calls = []UnmodeledCall{}
+	}
+	for _, call := range calls {
+		audit.Actions = append(audit.Actions, call.Action)
+	}
+	return audit, nil
+}
+
+// WriteControllerCallsJSON writes a controller call audit to a JSON file
+func WriteControllerCallsJSON(audit *ControllerCalls, outputPath string) error {
+	audit.SchemaVersion = SchemaVersion
+	data, err := json.MarshalIndent(audit, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal controller calls JSON: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
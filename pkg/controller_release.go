@@ -0,0 +1,218 @@
+package extractor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// releaseDownloadTimeout bounds the download of a controller source tarball
+const releaseDownloadTimeout = 5 * time.Minute
+
+// ControllerRelease is a tagged release of an ACK controller
+type ControllerRelease struct {
+	Service string
+	Tag     string
+	// Path is the directory the release source was extracted to
+	Path string
+}
+
+// controllerReleases maps service names to the releases scanned instead of the controllers directory
+var controllerReleases = make(map[string]*ControllerRelease)
+
+// ParseControllerRelease parses a release reference like s3-controller@v1.0.4. The -controller
+// suffix is optional and the tag "latest" resolves to the latest published release.
+func ParseControllerRelease(reference string) (*ControllerRelease, error) {
+	name, tag, ok := strings.Cut(reference, "@")
+	service := strings.TrimSuffix(name, "-controller")
+	if !ok || service == "" || tag == "" {
+		return nil, fmt.Errorf("invalid controller release %q, expected <service>-controller@<tag>", reference)
+	}
+	if err := checkReleasePathElement("service", service); err != nil {
+		return nil, err
+	}
+	if err := checkReleasePathElement("tag", tag); err != nil {
+		return nil, err
+	}
+	return &ControllerRelease{Service: service, Tag: tag}, nil
+}
+
+// checkReleasePathElement rejects a service or tag that would not stay a single element of the
+// release path in the cache directory
+func checkReleasePathElement(kind, name string) error {
+	if !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid controller release %s %q", kind, name)
+	}
+	return nil
+}
+
+// UseControllerRelease downloads the source tarball of a controller release into the cache directory
+// unless it was downloaded before, and scans it instead of the controllers directory. Release tags
+// are immutable, so a cached release is never downloaded again.
+func UseControllerRelease(release *ControllerRelease) error {
+	// Releases may be built without ParseControllerRelease
+	for kind, name := range map[string]string{"service": release.Service, "tag": release.Tag} {
+		if err := checkReleasePathElement(kind, name); err != nil {
+			return err
+		}
+	}
+	repo := release.Service + "-controller"
+	client := newGitHubClient("")
+	if release.Tag == "latest" {
+		var latest struct {
+			TagName string `json:"tag_name"`
+		}
+		if err := client.do(http.MethodGet, fmt.Sprintf("/repos/%s/%s/releases/latest", ackGitHubOrg, url.PathEscape(repo)), nil, &latest); err != nil {
+			return fmt.Errorf("failed to resolve the latest release of %s: %w", repo, err)
+		}
+		if err := checkReleasePathElement("tag", latest.TagName); err != nil {
+			return fmt.Errorf("failed to resolve the latest release of %s: %w", repo, err)
+		}
+		release.Tag = latest.TagName
+	}
+
+	releasesDir, err := CachePath("controller-releases")
+	if err != nil {
+		return err
+	}
+	release.Path = filepath.Join(releasesDir, repo+"@"+release.Tag)
+	if !isDir(release.Path) {
+		if err := downloadControllerSource(client, repo, "refs/tags/"+release.Tag, release.Path); err != nil {
+			return err
+		}
+	}
+
+	controllerReleases[release.Service] = release
+	return nil
+}
+
+// ControllerReleaseTag returns the release tag scanned for a service, empty when the controllers
+// directory is scanned
+func ControllerReleaseTag(serviceName string) string {
+	if release, ok := controllerReleases[serviceName]; ok {
+		return release.Tag
+	}
+	return ""
+}
+
+// useControllerCommit downloads the source of a controller repository commit into the cache
+// directory unless it was downloaded before, and scans it instead of the controllers directory until
+// the returned function restores the previous selection. Commits are immutable like release tags.
+func useControllerCommit(client *githubClient, serviceName, sha string) (func(), error) {
+	if _, err := hex.DecodeString(sha); err != nil || sha == "" {
+		return nil, fmt.Errorf("invalid commit %q", sha)
+	}
+	repo := serviceName + "-controller"
+	releasesDir, err := CachePath("controller-releases")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(releasesDir, repo+"@"+sha)
+	if !isDir(path) {
+		if err := downloadControllerSource(client, repo, sha, path); err != nil {
+			return nil, err
+		}
+	}
+
+	previous, selected := controllerReleases[serviceName]
+	controllerReleases[serviceName] = &ControllerRelease{Service: serviceName, Tag: sha, Path: path}
+	return func() {
+		if selected {
+			controllerReleases[serviceName] = previous
+		} else {
+			delete(controllerReleases, serviceName)
+		}
+	}, nil
+}
+
+// downloadControllerSource extracts the source tarball of a ref, a tag like refs/tags/v1.0.4 or a
+// commit, to dest. The tarball is extracted to a temporary directory next to dest first, so
+// interrupted downloads leave no partial source.
+func downloadControllerSource(client *githubClient, repo, ref, dest string) error {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/tarball/%s", githubAPIURL, ackGitHubOrg, url.PathEscape(repo), escapeRefPath(ref))
+	resp, err := client.open(endpoint, releaseDownloadTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to download %s@%s: %w", repo, ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s of %s/%s not found", ref, ackGitHubOrg, repo)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s@%s returned %s", repo, ref, resp.Status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dest), ".download-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extractSourceTarball(resp.Body, tmp); err != nil {
+		return fmt.Errorf("failed to extract %s@%s: %w", repo, ref, err)
+	}
+	if err := os.Rename(tmp, dest); err != nil && !isDir(dest) {
+		return fmt.Errorf("failed to store %s@%s: %w", repo, ref, err)
+	}
+	return nil
+}
+
+// extractSourceTarball extracts the regular files of a gzipped source tarball to dir, stripping the
+// <owner>-<repo>-<commit> directory GitHub wraps the sources in. Links and entries escaping dir are skipped.
+func extractSourceTarball(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, name, ok := strings.Cut(header.Name, "/")
+		if !ok || name == "" || !filepath.IsLocal(name) {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, copyErr := io.Copy(f, archive)
+			closeErr := f.Close()
+			if copyErr != nil {
+				return copyErr
+			}
+			if closeErr != nil {
+				return closeErr
+			}
+		}
+	}
+}
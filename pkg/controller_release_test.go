@@ -0,0 +1,65 @@
+package extractor
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseControllerRelease(t *testing.T) {
+	tests := []struct {
+		reference string
+		service   string
+		tag       string
+		wantErr   bool
+	}{
+		{reference: "s3-controller@v1.0.4", service: "s3", tag: "v1.0.4"},
+		{reference: "dynamodb@latest", service: "dynamodb", tag: "latest"},
+		{reference: "s3-controller", wantErr: true},
+		{reference: "@v1.0.4", wantErr: true},
+		{reference: "s3-controller@", wantErr: true},
+		{reference: "../s3-controller@v1.0.4", wantErr: true},
+		{reference: "s3@../../etc", wantErr: true},
+		{reference: "s3@v1/../../etc", wantErr: true},
+		{reference: `s3@v1\evil`, wantErr: true},
+		{reference: "s3@..", wantErr: true},
+		{reference: "s3@/etc", wantErr: true},
+	}
+	for _, tt := range tests {
+		release, err := ParseControllerRelease(tt.reference)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: parsed as %+v, want an error", tt.reference, release)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.reference, err)
+			continue
+		}
+		if release.Service != tt.service || release.Tag != tt.tag {
+			t.Errorf("%s: parsed as %s@%s, want %s@%s", tt.reference, release.Service, release.Tag, tt.service, tt.tag)
+		}
+	}
+}
+
+func TestUseControllerReleaseRejectsLatestTagOutsideCache(t *testing.T) {
+	setupGitHubClientTest(t)
+	downloads := 0
+	fakeGitHubServer(t, func(rw http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/releases/latest") {
+			rw.Write([]byte(`{"tag_name": "../../evil"}`))
+			return
+		}
+		downloads++
+		http.NotFound(rw, req)
+	})
+
+	err := UseControllerRelease(&ControllerRelease{Service: "s3", Tag: "latest"})
+	if err == nil || !strings.Contains(err.Error(), `invalid controller release tag "../../evil"`) {
+		t.Errorf("latest release ../../evil returned %v, want an invalid tag error", err)
+	}
+	if downloads != 0 || ControllerReleaseTag("s3") != "" {
+		t.Errorf("release with an invalid tag was downloaded %d time(s) or selected", downloads)
+	}
+}
@@ -0,0 +1,78 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DataPlaneOperationsSuffix is the file name suffix of data plane operation exports, which share
+// the -operations.json suffix with the operations files but are not read as such
+const DataPlaneOperationsSuffix = "-dataplane-operations.json"
+
+// dataPlaneStatementSid is the Sid of the statement granting the data plane actions of an application policy
+const dataPlaneStatementSid = "DataPlane"
+
+// NewDataPlaneOperations collects the operations classified as data plane, which applications
+// using ACK-provisioned resources call, whether or not the controller references them
+func NewDataPlaneOperations(serviceName string, operations []Operation) *DataPlaneOperations {
+	export := &DataPlaneOperations{SchemaVersion: SchemaVersion, ServiceName: serviceName, Operations: []Operation{}}
+	for _, op := range operations {
+		if op.Type == OperationTypeDataPlane && op.SupportStatus != SupportExcluded {
+			export.Operations = append(export.Operations, op)
+		}
+	}
+	export.DataPlaneOperations = len(export.Operations)
+	return export
+}
+
+// WriteDataPlaneOperationsJSON writes the data plane operations of a service to a JSON file
+func WriteDataPlaneOperationsJSON(export *DataPlaneOperations, outputPath string) error {
+	export.SchemaVersion = SchemaVersion
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal data plane operations JSON: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
+
+// GenerateDataPlanePolicy creates the application-facing IAM policy of a service, granting the
+// actions of its data plane operations on the service's resources in the given partition
+func GenerateDataPlanePolicy(serviceName string, operations []Operation, partition string) (*IAMPolicy, error) {
+	if err := ValidatePartition(partition); err != nil {
+		return nil, err
+	}
+
+	var actions []string
+	for _, op := range NewDataPlaneOperations(serviceName, operations).Operations {
+		actions = append(actions, operationIAMAction(serviceName, op))
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("no data plane operations found for service %s", serviceName)
+	}
+
+	actions, wildcardOnly := splitByResourceLevelSupport(actions)
+	policy := IAMPolicy{Version: "2012-10-17", Statement: []PolicyStatement{}}
+	if len(actions) > 0 {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Sid:      dataPlaneStatementSid,
+			Effect:   "Allow",
+			Action:   actions,
+			Resource: serviceResourcePattern(serviceName, cachedServiceMetadata(serviceName), partition),
+		})
+	}
+	if len(wildcardOnly) > 0 {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Sid:      wildcardOnlyStatementSid,
+			Effect:   "Allow",
+			Action:   wildcardOnly,
+			Resource: "*",
+		})
+	}
+	return &policy, nil
+}
+
+// isDataPlaneOperationsFile reports whether a file name is a data plane operation export
+func isDataPlaneOperationsFile(name string) bool {
+	return strings.HasSuffix(name, DataPlaneOperationsSuffix)
+}
@@ -0,0 +1,67 @@
+package extractor
+
+// dependencyReasons explains why an operation of a lifecycle verb needs a read counterpart
+var dependencyReasons = map[string]string{
+	"Create": "stabilization",
+	"Delete": "existence checks",
+}
+
+// ApplyDependencies infers the read operations create and delete operations depend on: a controller
+// reads a created resource until it stabilizes and reads a resource before deleting it. The read
+// counterparts of a resource are Describe<Resource>, Get<Resource>, Describe<Resources> and
+// List<Resources>, of which the controller needs any one. Operations whose resource has no read
+// operation in the model get no dependency.
+func ApplyDependencies(operations []Operation) []DependencyGap {
+	exists := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		exists[op.Name] = true
+	}
+	for i := range operations {
+		operations[i].Requires = operationRequires(operations[i].Name, exists)
+	}
+	return FindDependencyGaps(operations)
+}
+
+// operationRequires returns the read operations among the existing ones an operation depends on
+func operationRequires(operationName string, exists map[string]bool) []string {
+	naming := classifyOperationName(operationName)
+	if _, ok := dependencyReasons[naming.Verb]; !ok || naming.Resource == "" {
+		return nil
+	}
+	var requires []string
+	for _, candidate := range readCounterparts(naming.Resource) {
+		if exists[candidate] {
+			requires = append(requires, candidate)
+		}
+	}
+	return requires
+}
+
+// FindDependencyGaps returns the supported operations none of whose required read operations are supported
+func FindDependencyGaps(operations []Operation) []DependencyGap {
+	supported := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		supported[op.Name] = op.IsSupported()
+	}
+
+	var gaps []DependencyGap
+	for _, op := range operations {
+		if len(op.Requires) == 0 || !op.IsSupported() {
+			continue
+		}
+		readSupported := false
+		for _, read := range op.Requires {
+			readSupported = readSupported || supported[read]
+		}
+		if !readSupported {
+			gaps = append(gaps, DependencyGap{Operation: op.Name, Requires: op.Requires, Reason: dependencyReasons[classifyOperationName(op.Name).Verb]})
+		}
+	}
+	return gaps
+}
+
+// readCounterparts returns the names of the operations that can read a resource, in preference order
+func readCounterparts(resource string) []string {
+	plural := pluralize(resource)
+	return []string{"Describe" + resource, "Get" + resource, "Describe" + plural, "List" + plural}
+}
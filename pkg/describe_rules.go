@@ -0,0 +1,206 @@
+package extractor
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed datasets/describe_rules.yaml
+var describeRulesDataset []byte
+
+// describedMemberMatches is how many members of a structure must match described_members
+const describedMemberMatches = 2
+
+// DescribeRulePack classifies Describe and List operations from the members of their output
+// structure, deciding the edge cases the classification prompt leaves to Bedrock
+type DescribeRulePack struct {
+	Verbs   map[string]bool
+	Ignored *regexp.Regexp
+	Rules   []DescribeRule
+}
+
+// DescribeRule classifies the operations whose output members match its patterns
+type DescribeRule struct {
+	Name             string
+	Type             OperationType
+	OutputMembers    *regexp.Regexp
+	DescribedMembers *regexp.Regexp
+}
+
+// describeRulePackFile is the rule pack as written in its dataset
+type describeRulePackFile struct {
+	Verbs          []string `yaml:"verbs"`
+	IgnoredMembers string   `yaml:"ignored_members"`
+	Rules          []struct {
+		Name             string `yaml:"name"`
+		Type             string `yaml:"type"`
+		OutputMembers    string `yaml:"output_members"`
+		DescribedMembers string `yaml:"described_members"`
+	} `yaml:"rules"`
+}
+
+// describeRules classify unsupported Describe and List operations before Bedrock is asked
+var describeRules = mustParseDescribeRules(describeRulesDataset)
+
+// mustParseDescribeRules parses the embedded Describe rule pack
+func mustParseDescribeRules(data []byte) *DescribeRulePack {
+	pack, err := parseDescribeRules(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid describe rules dataset: %v", err))
+	}
+	return pack
+}
+
+// parseDescribeRules reads a Describe rule pack
+func parseDescribeRules(data []byte) (*DescribeRulePack, error) {
+	var file describeRulePackFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	pack := &DescribeRulePack{Verbs: make(map[string]bool, len(file.Verbs))}
+	for _, verb := range file.Verbs {
+		pack.Verbs[verb] = true
+	}
+	if file.IgnoredMembers != "" {
+		ignored, err := regexp.Compile(file.IgnoredMembers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignored_members pattern %q", file.IgnoredMembers)
+		}
+		pack.Ignored = ignored
+	}
+	for i, entry := range file.Rules {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		operationType, err := ParseOperationType(entry.Type)
+		if err != nil || (operationType != OperationTypeControlPlane && operationType != OperationTypeDataPlane) {
+			return nil, fmt.Errorf("rule %s has type %q, expected %s or %s", entry.Name, entry.Type, OperationTypeControlPlane, OperationTypeDataPlane)
+		}
+		if (entry.OutputMembers == "") == (entry.DescribedMembers == "") {
+			return nil, fmt.Errorf("rule %s needs exactly one of output_members and described_members", entry.Name)
+		}
+		rule := DescribeRule{Name: entry.Name, Type: operationType}
+		pattern := entry.OutputMembers + entry.DescribedMembers
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s has an invalid pattern %q", entry.Name, pattern)
+		}
+		if entry.OutputMembers != "" {
+			rule.OutputMembers = compiled
+		} else {
+			rule.DescribedMembers = compiled
+		}
+		pack.Rules = append(pack.Rules, rule)
+	}
+	return pack, nil
+}
+
+// Match returns the first rule classifying an operation from its output structure in the model,
+// nil when the operation's verb is not covered, it has no output or no rule matches
+func (p *DescribeRulePack) Match(model *AWSServiceModel, operation ServiceShape, operationName string) *DescribeRule {
+	if !p.Verbs[operationVerb(operationName)] || operation.Output == nil {
+		return nil
+	}
+	output, ok := model.Shapes[operation.Output.Target]
+	if !ok || output.Type != "structure" {
+		return nil
+	}
+
+	for i, rule := range p.Rules {
+		if rule.OutputMembers != nil && p.countMatches(output, rule.OutputMembers) > 0 {
+			return &p.Rules[i]
+		}
+		if rule.DescribedMembers != nil && p.describes(model, output, rule.DescribedMembers) {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+// describes reports whether the output structure or a structure its members hold, directly or as
+// list or map elements, has enough members matching pattern
+func (p *DescribeRulePack) describes(model *AWSServiceModel, output ServiceShape, pattern *regexp.Regexp) bool {
+	if p.countMatches(output, pattern) >= describedMemberMatches {
+		return true
+	}
+	for name, member := range output.Members {
+		if p.Ignored != nil && p.Ignored.MatchString(upperFirst(name)) {
+			continue
+		}
+		target := model.Shapes[member.Target]
+		switch {
+		case target.Type == "list" && target.Member != nil:
+			target = model.Shapes[target.Member.Target]
+		case target.Type == "map" && target.Value != nil:
+			target = model.Shapes[target.Value.Target]
+		}
+		if target.Type == "structure" && p.countMatches(target, pattern) >= describedMemberMatches {
+			return true
+		}
+	}
+	return false
+}
+
+// countMatches counts the members of a structure matching pattern, leaving out pagination members
+func (p *DescribeRulePack) countMatches(shape ServiceShape, pattern *regexp.Regexp) int {
+	count := 0
+	for name := range shape.Members {
+		name = upperFirst(name)
+		if p.Ignored != nil && p.Ignored.MatchString(name) {
+			continue
+		}
+		if pattern.MatchString(name) {
+			count++
+		}
+	}
+	return count
+}
+
+// upperFirst returns a member name with its first letter in upper case, e.g. TableName for tableName
+func upperFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	if r == utf8.RuneError {
+		return name
+	}
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// applyDescribeRules classifies the Describe and List operations a rule of the rule pack decides.
+// Operations a classification rule matches by name are left to it. It returns the operations it
+// classified and the ones that still need classification.
+func applyDescribeRules(serviceName string, operations []Operation, model *AWSServiceModel) (classified []Operation, remaining []Operation) {
+	shapes := operationShapes(model)
+	for _, op := range operations {
+		shape, ok := shapes[op.Name]
+		if !ok || matchClassificationRule(serviceName, op.Name) != nil {
+			remaining = append(remaining, op)
+			continue
+		}
+		rule := describeRules.Match(model, shape, op.Name)
+		if rule == nil {
+			remaining = append(remaining, op)
+			continue
+		}
+		op.Type = rule.Type
+		op.DescribeRule = rule.Name
+		classified = append(classified, op)
+	}
+	return classified, remaining
+}
+
+// CountRuleClassifications counts the operations the Describe rule pack classified without Bedrock
+func CountRuleClassifications(operations []Operation) int {
+	count := 0
+	for _, op := range operations {
+		if op.DescribeRule != "" {
+			count++
+		}
+	}
+	return count
+}
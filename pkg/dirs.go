@@ -0,0 +1,155 @@
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// appDirName is the directory the extractor uses below the user cache and config directories
+const appDirName = "ack-api-extractor"
+
+// cacheRoot overrides the default cache directory when set
+var cacheRoot string
+
+// configFile overrides the default config.yaml in the config directory when set
+var configFile string
+
+// UserConfig holds defaults read from config.yaml in the config directory. Relative paths are
+// resolved against the directory of the config file, so they don't depend on the working directory.
+type UserConfig struct {
+	ModelsDir      string   `yaml:"models_dir"`
+	ControllersDir string   `yaml:"controllers_dir"`
+	CacheDir       string   `yaml:"cache_dir"`
+	ModelSources   []string `yaml:"model_sources"`
+	// ModelPathTemplate and ControllerPathTemplate replace the api-models-aws and <service>-controller
+	// layouts, e.g. {root}/models/{service}/service/*.json
+	ModelPathTemplate      string `yaml:"model_path_template"`
+	ControllerPathTemplate string `yaml:"controller_path_template"`
+	// GuardrailID and GuardrailVersion attach a Bedrock Guardrail to every classification
+	GuardrailID      string `yaml:"guardrail_id"`
+	GuardrailVersion string `yaml:"guardrail_version"`
+	// Settings holds every other key, the values of command line flags named like the key with
+	// underscores for dashes, e.g. generate_policies: true
+	Settings map[string]interface{} `yaml:",inline"`
+	// Path is the file the config was read from, empty when there is none
+	Path string `yaml:"-"`
+}
+
+// SetConfigFile reads the configuration from path instead of config.yaml in the config directory
+func SetConfigFile(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config file %s: %w", path, err)
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return fmt.Errorf("config file unusable: %w", err)
+	}
+	configFile = abs
+	return nil
+}
+
+// SetCacheDir overrides the cache directory. Relative paths are resolved against the current working directory.
+func SetCacheDir(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve cache directory %s: %w", dir, err)
+	}
+	cacheRoot = abs
+	return nil
+}
+
+// CacheDir returns the directory for caches that can be recreated, $XDG_CACHE_HOME/ack-api-extractor
+// (~/.cache/ack-api-extractor) by default. Without a home directory, as in distroless containers,
+// a directory below the system temporary directory is used.
+func CacheDir() (string, error) {
+	if cacheRoot != "" {
+		return cacheRoot, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return cacheFallbackDir(), nil
+	}
+	return filepath.Join(dir, appDirName), nil
+}
+
+// ConfigDir returns the configuration directory, $XDG_CONFIG_HOME/ack-api-extractor
+// (~/.config/ack-api-extractor) by default
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, appDirName), nil
+}
+
+// CachePath returns the path of a file in the cache directory, creating the directory when needed.
+// A default cache directory on a read-only filesystem falls back to the system temporary directory;
+// a cache directory set explicitly must be writable.
+func CachePath(name string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	if err := CheckWritable(dir); err != nil {
+		if cacheRoot != "" {
+			return "", fmt.Errorf("cache directory unusable: %w", err)
+		}
+		fallback := cacheFallbackDir()
+		if fallbackErr := CheckWritable(fallback); fallbackErr != nil {
+			return "", fmt.Errorf("cache directory unusable: %w", err)
+		}
+		cacheFallbackWarning.Do(func() {
+			reportWarning("", "%v, caching in %s instead", err, fallback)
+		})
+		dir = fallback
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// LoadUserConfig reads config.yaml from the config directory, or the file set with SetConfigFile.
+// A missing config.yaml yields an empty config.
+func LoadUserConfig() (*UserConfig, error) {
+	config := &UserConfig{}
+	path := configFile
+	if path == "" {
+		dir, err := ConfigDir()
+		if err != nil {
+			return config, nil
+		}
+		path = filepath.Join(dir, "config.yaml")
+	}
+	dir := filepath.Dir(path)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	config.Path = path
+
+	for _, field := range []*string{&config.ModelsDir, &config.ControllersDir, &config.CacheDir} {
+		if *field != "" && !filepath.IsAbs(*field) {
+			*field = filepath.Join(dir, *field)
+		}
+	}
+	for _, field := range []*string{&config.ModelPathTemplate, &config.ControllerPathTemplate} {
+		if *field != "" && !strings.HasPrefix(*field, "{root}") && !filepath.IsAbs(*field) {
+			*field = filepath.ToSlash(filepath.Join(dir, *field))
+		}
+	}
+	for i, source := range config.ModelSources {
+		if !isRemoteModelSource(source) && source != GoSDKModelSource && !filepath.IsAbs(source) {
+			config.ModelSources[i] = filepath.Join(dir, source)
+		}
+	}
+	return config, nil
+}
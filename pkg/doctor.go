@@ -0,0 +1,142 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// integrationPermissions lists the IAM actions the optional AWS integrations call
+var integrationPermissions = []struct {
+	feature string
+	actions []string
+}{
+	{feature: "--classify", actions: []string{"bedrock:InvokeInlineAgent", "bedrock:InvokeModel"}},
+	{feature: "compare-managed-policy", actions: []string{"iam:GetPolicy", "iam:GetPolicyVersion"}},
+	{feature: "simulate", actions: []string{"iam:SimulateCustomPolicy"}},
+}
+
+// RunDoctor runs the self-checks and verifies the AWS setup the optional integrations need:
+// credentials, the caller identity, Bedrock model access through a minimal agent invocation
+// unless skipBedrock is set, and the IAM permissions of each integration. AWS problems are
+// warnings, since only the integrations need AWS access.
+func RunDoctor(skipBedrock bool) []SelfCheckResult {
+	results := RunSelfCheck("")
+	add := func(name string, status SelfCheckStatus, remediation, format string, args ...interface{}) {
+		results = append(results, SelfCheckResult{Name: name, Status: status, Message: fmt.Sprintf(format, args...), Remediation: remediation})
+	}
+	credentialsRemediation := "configure credentials with aws configure or aws sso login, or set AWS_PROFILE or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY"
+
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		add("credentials", SelfCheckWarn, credentialsRemediation, "failed to load AWS config: %v", err)
+		return results
+	}
+	credentials, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		add("credentials", SelfCheckWarn, credentialsRemediation, "no AWS credentials found: %v", err)
+		return results
+	}
+	add("credentials", SelfCheckOK, "", "found through %s", credentials.Source)
+
+	if cfg.Region == "" {
+		add("region", SelfCheckWarn, "set AWS_REGION or a region in the AWS profile, e.g. us-west-2", "no AWS region configured")
+		return results
+	}
+	add("region", SelfCheckOK, "", "%s", cfg.Region)
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		add("identity", SelfCheckWarn, "check network access to STS and refresh expired credentials, e.g. with aws sso login", "failed to verify the credentials: %v", err)
+		return results
+	}
+	callerARN := aws.ToString(identity.Arn)
+	add("identity", SelfCheckOK, "", "%s", callerARN)
+
+	if skipBedrock {
+		add("bedrock", SelfCheckWarn, "", "skipped")
+	} else if _, err := invokeInlineAgent(agentInstruction, classificationSessionID("doctor"), "Reply with OK."); err != nil {
+		diagnosis := DiagnoseBedrockError(err, cfg.Region)
+		add("bedrock", SelfCheckWarn, diagnosis.Remediation,
+			"classification agent invocation failed (%s): %v", diagnosis.Problem, err)
+	} else {
+		add("bedrock", SelfCheckOK, "", "%s answered%s", classificationModelID, guardrailSuffix())
+	}
+
+	results = append(results, checkIntegrationPermissions(ctx, iam.NewFromConfig(cfg), principalARN(callerARN))...)
+	return results
+}
+
+// guardrailSuffix describes the attached guardrail for check messages
+func guardrailSuffix() string {
+	if guardrail == nil {
+		return ""
+	}
+	return " through guardrail " + GuardrailDescription()
+}
+
+// checkIntegrationPermissions simulates the principal's policies for the actions of each integration
+func checkIntegrationPermissions(ctx context.Context, client *iam.Client, principal string) []SelfCheckResult {
+	var actions []string
+	for _, integration := range integrationPermissions {
+		actions = append(actions, integration.actions...)
+	}
+
+	allowed := make(map[string]bool)
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(client, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principal),
+		ActionNames:     actions,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return []SelfCheckResult{{
+				Name:        "permissions",
+				Status:      SelfCheckWarn,
+				Message:     fmt.Sprintf("could not simulate the policies of %s: %v", principal, err),
+				Remediation: "allow iam:SimulatePrincipalPolicy on your own principal, or verify the permissions of the integrations manually",
+			}}
+		}
+		for _, result := range page.EvaluationResults {
+			allowed[aws.ToString(result.EvalActionName)] = result.EvalDecision == "allowed"
+		}
+	}
+
+	var results []SelfCheckResult
+	for _, integration := range integrationPermissions {
+		var denied []string
+		for _, action := range integration.actions {
+			if !allowed[action] {
+				denied = append(denied, action)
+			}
+		}
+		if len(denied) == 0 {
+			results = append(results, SelfCheckResult{Name: "permissions", Status: SelfCheckOK, Message: fmt.Sprintf("%s is allowed", integration.feature)})
+			continue
+		}
+		results = append(results, SelfCheckResult{
+			Name:        "permissions",
+			Status:      SelfCheckWarn,
+			Message:     fmt.Sprintf("%s needs %s", integration.feature, strings.Join(denied, ", ")),
+			Remediation: fmt.Sprintf("grant %s to %s", strings.Join(denied, ", "), principal),
+		})
+	}
+	return results
+}
+
+// principalARN turns a caller ARN into the IAM principal whose policies apply: assumed role
+// sessions (arn:aws:sts::<account>:assumed-role/<role>/<session>) map to the role
+func principalARN(callerARN string) string {
+	parts := strings.SplitN(callerARN, ":", 6)
+	if len(parts) != 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return callerARN
+	}
+	role := strings.Split(strings.TrimPrefix(parts[5], "assumed-role/"), "/")[0]
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], role)
+}
@@ -0,0 +1,174 @@
+package extractor
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const (
+	requiredTrait  = "smithy.api#required"
+	enumValueTrait = "smithy.api#enumValue"
+	rangeTrait     = "smithy.api#range"
+
+	// maxExampleDepth bounds recursion into nested and recursive structures
+	maxExampleDepth = 8
+)
+
+// rangeTraitValue represents the smithy.api#range trait
+type rangeTraitValue struct {
+	Min *float64 `json:"min"`
+}
+
+// GenerateServiceExamples loads the model of a service and builds a minimal example
+// request payload for each of the given operations
+func GenerateServiceExamples(serviceName string, operations []Operation) (*ServiceExamples, error) {
+	model, err := loadServiceModel(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]string)
+	for shapeName, shape := range model.Shapes {
+		if shape.Type == "operation" {
+			targets[extractOperationName(shapeName)] = shapeName
+		}
+	}
+
+	examples := &ServiceExamples{SchemaVersion: SchemaVersion, ServiceName: serviceName, Examples: make(map[string]interface{})}
+	for _, op := range operations {
+		target, ok := targets[op.Name]
+		if !ok {
+			continue
+		}
+		examples.Examples[op.Name] = ExampleInput(model, target)
+	}
+	return examples, nil
+}
+
+// ExampleInput builds a minimal request payload for an operation from the required
+// members of its input shape, filled with type-appropriate placeholders
+func ExampleInput(model *AWSServiceModel, operationTarget string) map[string]interface{} {
+	operation, ok := model.Shapes[operationTarget]
+	if !ok || operation.Input == nil {
+		return map[string]interface{}{}
+	}
+
+	example, ok := exampleValue(model, operation.Input.Target, "", 0, make(map[string]bool)).(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return example
+}
+
+// exampleValue returns a placeholder value for a shape; structures only include required members
+func exampleValue(model *AWSServiceModel, target, memberName string, depth int, visiting map[string]bool) interface{} {
+	shape, ok := resolveShape(model, target)
+	// Prelude shapes have no members, so they are never too deep or recursive
+	if _, prelude := smithyPrelude[target]; !prelude && (!ok || depth > maxExampleDepth || visiting[target]) {
+		return nil
+	}
+	visiting[target] = true
+	defer delete(visiting, target)
+
+	switch shape.Type {
+	case "string":
+		return placeholderString(memberName)
+	case "boolean":
+		return false
+	case "byte", "short", "integer", "long", "bigInteger":
+		return int(rangeMinimum(shape.Traits, 1))
+	case "float", "double", "bigDecimal":
+		return rangeMinimum(shape.Traits, 1)
+	case "timestamp":
+		return "2024-01-01T00:00:00Z"
+	case "blob":
+		return "ZXhhbXBsZQ=="
+	case "document":
+		return map[string]interface{}{}
+	case "enum", "intEnum":
+		return firstEnumValue(shape)
+	case "list", "set":
+		if shape.Member == nil {
+			return []interface{}{}
+		}
+		element := exampleValue(model, shape.Member.Target, memberName, depth+1, visiting)
+		if element == nil {
+			return []interface{}{}
+		}
+		return []interface{}{element}
+	case "map":
+		if shape.Value == nil {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"key": exampleValue(model, shape.Value.Target, memberName, depth+1, visiting)}
+	case "union":
+		// A union needs exactly one member set; use the first one alphabetically
+		names := sortedMemberNames(shape)
+		if len(names) == 0 {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{
+			names[0]: exampleValue(model, shape.Members[names[0]].Target, names[0], depth+1, visiting),
+		}
+	case "structure":
+		result := make(map[string]interface{})
+		for _, name := range sortedMemberNames(shape) {
+			member := shape.Members[name]
+			if _, required := member.Traits[requiredTrait]; !required {
+				continue
+			}
+			if value := exampleValue(model, member.Target, name, depth+1, visiting); value != nil {
+				result[name] = value
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// placeholderString returns a recognizable string placeholder for a member
+func placeholderString(memberName string) string {
+	if memberName == "" {
+		return "example"
+	}
+	return "example-" + strings.ToLower(memberName)
+}
+
+// rangeMinimum returns the minimum of a range trait when it is above the default
+func rangeMinimum(traits map[string]json.RawMessage, defaultValue float64) float64 {
+	raw, ok := traits[rangeTrait]
+	if !ok {
+		return defaultValue
+	}
+	var value rangeTraitValue
+	if json.Unmarshal(raw, &value) != nil || value.Min == nil || *value.Min < defaultValue {
+		return defaultValue
+	}
+	return *value.Min
+}
+
+// firstEnumValue returns the value of the first enum member, alphabetically by member name
+func firstEnumValue(shape ServiceShape) interface{} {
+	for _, name := range sortedMemberNames(shape) {
+		raw, ok := shape.Members[name].Traits[enumValueTrait]
+		if !ok {
+			return name
+		}
+		var value interface{}
+		if json.Unmarshal(raw, &value) == nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// sortedMemberNames returns the member names of a shape in a stable order
+func sortedMemberNames(shape ServiceShape) []string {
+	names := make([]string, 0, len(shape.Members))
+	for name := range shape.Members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
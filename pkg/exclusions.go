@@ -0,0 +1,95 @@
+package extractor
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	internalTrait     = "smithy.api#internal"
+	optionalAuthTrait = "smithy.api#optionalAuth"
+	authTrait         = "smithy.api#auth"
+)
+
+//go:embed datasets/excluded_operations.yaml
+var exclusionDataset []byte
+
+// excludedOperations maps service names to operation names to the reason they are excluded
+var excludedOperations = mustParseExclusions(exclusionDataset)
+
+// internalDocumentationPattern matches documentation describing an operation as internal or console-only
+var internalDocumentationPattern = regexp.MustCompile(`(?i)(for internal (aws )?use only|internal use only|not (meant|intended) for (public|external|customer) use|console[- ]only|only (be )?used by the [\w ]*console)`)
+
+// mustParseExclusions parses the embedded exclusion dataset
+func mustParseExclusions(data []byte) map[string]map[string]string {
+	exclusions, err := parseExclusions(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid exclusion dataset: %v", err))
+	}
+	return exclusions
+}
+
+// parseExclusions reads an exclusion file mapping service names to operations and reasons
+func parseExclusions(data []byte) (map[string]map[string]string, error) {
+	exclusions := make(map[string]map[string]string)
+	if err := yaml.Unmarshal(data, &exclusions); err != nil {
+		return nil, err
+	}
+	return exclusions, nil
+}
+
+// LoadExclusions adds the operations of a YAML exclusion file to the embedded dataset:
+//
+//	sts:
+//	  GetCallerIdentity: callable without IAM permissions
+func LoadExclusions(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read exclusion file %s: %w", path, err)
+	}
+	exclusions, err := parseExclusions(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse exclusion file %s: %w", path, err)
+	}
+
+	for serviceName, operations := range exclusions {
+		if excludedOperations[serviceName] == nil {
+			excludedOperations[serviceName] = make(map[string]string)
+		}
+		for operationName, reason := range operations {
+			excludedOperations[serviceName][operationName] = reason
+		}
+	}
+	return nil
+}
+
+// exclusionReason returns why an operation is not an IAM action a controller could need, or "" when it is
+func exclusionReason(serviceName, operationName string, shape ServiceShape) string {
+	if reason, ok := excludedOperations[serviceName][operationName]; ok {
+		if reason == "" {
+			reason = "listed in the exclusion dataset"
+		}
+		return reason
+	}
+	if _, ok := shape.Traits[internalTrait]; ok {
+		return "marked internal in the model"
+	}
+	if _, ok := shape.Traits[optionalAuthTrait]; ok {
+		return "callable without authentication"
+	}
+	if raw, ok := shape.Traits[authTrait]; ok {
+		var schemes []string
+		if json.Unmarshal(raw, &schemes) == nil && len(schemes) == 0 {
+			return "callable without authentication"
+		}
+	}
+	if internalDocumentationPattern.MatchString(stringTrait(shape.Traits, documentationTrait)) {
+		return "documented as internal or console-only"
+	}
+	return ""
+}
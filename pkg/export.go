@@ -4,14 +4,150 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 )
 
 // WriteServiceOperationsJSON writes service operations to a JSON file
 func WriteServiceOperationsJSON(serviceOps *ServiceOperations, outputPath string) error {
+	serviceOps.SchemaVersion = SchemaVersion
 	data, err := json.MarshalIndent(serviceOps, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 	
-	return os.WriteFile(outputPath, data, 0644)
-}
\ No newline at end of file
+	return writeOutputFile(outputPath, data)
+}
+
+// NewCombinedOperations creates an empty combined operations document
+func NewCombinedOperations() *CombinedOperations {
+	return &CombinedOperations{SchemaVersion: SchemaVersion, Services: make(map[string]*ServiceOperations)}
+}
+
+// WriteCombinedOperationsJSON writes the operations of all services to a single JSON file
+func WriteCombinedOperationsJSON(combined *CombinedOperations, outputPath string) error {
+	combined.SchemaVersion = SchemaVersion
+	for _, serviceOps := range combined.Services {
+		serviceOps.SchemaVersion = SchemaVersion
+	}
+	data, err := json.MarshalIndent(combined, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	return writeOutputFile(outputPath, data)
+}
+
+// WriteServiceExamplesJSON writes example request payloads to a JSON file
+func WriteServiceExamplesJSON(examples *ServiceExamples, outputPath string) error {
+	data, err := json.MarshalIndent(examples, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal examples JSON: %w", err)
+	}
+
+	return writeOutputFile(outputPath, data)
+}
+
+// ReadServiceOperationsJSON reads service operations previously written by WriteServiceOperationsJSON
+func ReadServiceOperationsJSON(path string) (*ServiceOperations, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var serviceOps ServiceOperations
+	if err := decodeDocument(data, DocumentOperations, &serviceOps); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &serviceOps, nil
+}
+
+// ReadCombinedOperationsJSON reads a combined operations document previously written by WriteCombinedOperationsJSON
+func ReadCombinedOperationsJSON(path string) (*CombinedOperations, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	combined := NewCombinedOperations()
+	if err := decodeDocument(data, DocumentCombinedOperations, combined); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return combined, nil
+}
+
+// ReadOperationsFile reads the operations of every service in an output file, either a
+// <service>-operations.json file or a combined operations.json
+func ReadOperationsFile(path string) ([]*ServiceOperations, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	switch detectDocumentKind(document) {
+	case DocumentOperations:
+		serviceOps, err := ReadServiceOperationsJSON(path)
+		if err != nil {
+			return nil, err
+		}
+		return []*ServiceOperations{serviceOps}, nil
+	case DocumentCombinedOperations:
+		combined, err := ReadCombinedOperationsJSON(path)
+		if err != nil {
+			return nil, err
+		}
+		return combinedServices(combined), nil
+	}
+	return nil, fmt.Errorf("%s is not an operations file", path)
+}
+
+// ReadOperationsDir reads the operations of every service in an output directory, from the
+// combined operations.json when present and from the <service>-operations.json files otherwise
+func ReadOperationsDir(dir string) ([]*ServiceOperations, error) {
+	if combined, err := ReadCombinedOperationsJSON(filepath.Join(dir, "operations.json")); err == nil {
+		return combinedServices(combined), nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-operations.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operations files in %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no operations files found in %s", dir)
+	}
+	sort.Strings(files)
+
+	var services []*ServiceOperations
+	for _, file := range files {
+		if isDataPlaneOperationsFile(file) {
+			continue
+		}
+		serviceOps, err := ReadServiceOperationsJSON(file)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, serviceOps)
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no operations files found in %s", dir)
+	}
+	return services, nil
+}
+
+// combinedServices returns the services of a combined operations document sorted by name
+func combinedServices(combined *CombinedOperations) []*ServiceOperations {
+	names := make([]string, 0, len(combined.Services))
+	for name := range combined.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	services := make([]*ServiceOperations, 0, len(names))
+	for _, name := range names {
+		services = append(services, combined.Services[name])
+	}
+	return services
+}
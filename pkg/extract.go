@@ -1,75 +1,170 @@
 package extractor
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"runtime"
+	"sync"
+	"time"
 	"bufio"
 )
 
-// findControllerForService returns the path to the controller directory for a given service
+// controllersRoot is the directory containing the <service>-controller directories
+var controllersRoot = ".."
+
+// SetControllersDir sets the directory containing the <service>-controller directories.
+// Relative paths are resolved against the current working directory.
+func SetControllersDir(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve controllers directory %s: %w", dir, err)
+	}
+	controllersRoot = abs
+	return nil
+}
+
+// noController disables controller lookups for services without an ACK controller yet
+var noController bool
+
+// SetNoController extracts services as prospective controllers: controllers are neither looked up
+// nor scanned, and generated policies cover the operations a new controller would need
+func SetNoController(enabled bool) {
+	noController = enabled
+}
+
+// HasController reports whether a controller directory exists for a service
+func HasController(serviceName string) bool {
+	return findControllerForService(serviceName) != ""
+}
+
+// findControllerForService returns the path to the controller directory for a given service, the
+// first directory the controller path template matches, or the extracted release source when a
+// controller release was selected
 func findControllerForService(serviceName string) string {
-	controllerPath := filepath.Join("..", serviceName+"-controller")
-	if _, err := os.Stat(controllerPath); err == nil {
-		return controllerPath
+	if noController {
+		return ""
+	}
+	if release, ok := controllerReleases[serviceName]; ok {
+		return release.Path
+	}
+	for _, controllerPath := range globPathTemplate(ControllerPathTemplate(), controllersRoot, serviceName) {
+		if info, err := os.Stat(controllerPath); err == nil && info.IsDir() {
+			return controllerPath
+		}
 	}
 	return ""
 }
 
-// findOperationInController searches for an operation in the controller's pkg directory
-func findOperationInController(serviceName, operationName string) (string, int) {
+// scanControllerForOperations scans every Go file in the controller's pkg directory once,
+// matching each line against all operation names at the same time. Files are scanned in
+// parallel and the locations of each operation are returned in file walk order. Paths left
+// out by the scan limits, including files not reached before the scan timeout, are returned
+// as skipped.
+func scanControllerForOperations(serviceName string, operationNames []string) (map[string][]Location, []SkippedPath) {
+	result := make(map[string][]Location)
+
 	controllerPath := findControllerForService(serviceName)
 	if controllerPath == "" {
-		return "", 0
+		return result, nil
 	}
 
-	pkgPath := filepath.Join(controllerPath, "pkg")
-	if _, err := os.Stat(pkgPath); os.IsNotExist(err) {
-		return "", 0
+	files, skipped := listControllerFiles(controllerPath)
+	if len(files) == 0 {
+		return result, skipped
 	}
 
-	var foundFile string
-	var foundLine int
+	matcher := newPatternMatcher(operationNames)
+	custom := newCustomMatcher(serviceName, operationNames)
+	index := make(map[string]int, len(operationNames))
+	for i, name := range operationNames {
+		index[name] = i
+	}
+	fileMatches := make([]map[string][]Location, len(files))
+	scanned := make([]bool, len(files))
 
-	// Walk through all Go files in pkg directory
-	err := filepath.Walk(pkgPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	var deadline <-chan time.Time
+	if scanLimits.Timeout > 0 {
+		timer := time.NewTimer(scanLimits.Timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
 
-		// Only process .go files
-		if !strings.HasSuffix(path, ".go") {
-			return nil
+	var wg sync.WaitGroup
+	paths := make(chan int)
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range paths {
+				fileMatches[i] = scanFileForOperations(files[i], controllerRelPath(controllerPath, files[i]), operationNames, index, matcher, custom)
+				scanned[i] = true
+			}
+		}()
+	}
+dispatch:
+	for i := range files {
+		select {
+		case paths <- i:
+		case <-deadline:
+			break dispatch
 		}
+	}
+	close(paths)
+	wg.Wait()
 
-		// Open and scan the file
-		file, err := os.Open(path)
-		if err != nil {
-			return nil // Skip files we can't open
+	// Merge per-file results in walk order so locations stay deterministic
+	for i, matches := range fileMatches {
+		if !scanned[i] {
+			skipped = append(skipped, SkippedPath{Path: controllerRelPath(controllerPath, files[i]), Reason: SkipReasonTimeout})
+			continue
 		}
-		defer file.Close()
-
-		scanner := bufio.NewScanner(file)
-		lineNum := 0
-		for scanner.Scan() {
-			lineNum++
-			line := scanner.Text()
-
-			// Just search for the operation name
-			if strings.Contains(line, operationName) {
-				relPath, _ := filepath.Rel(controllerPath, path)
-				foundFile = relPath
-				foundLine = lineNum
-				return filepath.SkipAll
-			}
+		for _, name := range operationNames {
+			result[name] = append(result[name], matches[name]...)
 		}
-		return nil
-	})
+	}
+
+	return result, skipped
+}
 
+// scanFileForOperations returns the lines of a single file referencing each operation.
+// Lines matched by the custom patterns of the service count in addition to, or with
+// replace_default instead of, lines referencing the operation name. ack-operation comments
+// declare operations the surrounding code implements indirectly and are always honored.
+func scanFileForOperations(path, relPath string, operationNames []string, index map[string]int, matcher *patternMatcher, custom *customMatcher) map[string][]Location {
+	file, err := os.Open(path)
 	if err != nil {
-		return "", 0
+		return nil // Skip files we can't open
 	}
+	defer file.Close()
 
-	return foundFile, foundLine
+	matches := make(map[string][]Location)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		seen := make(map[int]bool)
+		found := func(pattern int) {
+			if seen[pattern] {
+				return
+			}
+			seen[pattern] = true
+			name := operationNames[pattern]
+			matches[name] = append(matches[name], Location{File: relPath, Line: lineNum})
+		}
+		for _, name := range parseOperationAnnotation(line) {
+			if pattern, ok := index[name]; ok && !seen[pattern] {
+				seen[pattern] = true
+				matches[name] = append(matches[name], Location{File: relPath, Line: lineNum, Annotation: true})
+			}
+		}
+		if custom == nil || !custom.replaceDefault {
+			matcher.match(line, found)
+		}
+		if custom != nil {
+			custom.match(line, found)
+		}
+	}
+	return matches
 }
-
@@ -0,0 +1,75 @@
+package extractor_test
+
+import (
+	"reflect"
+	"testing"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+	"github.com/aws-controllers-k8s/ack-api-extractor/pkg/extractortest"
+)
+
+func TestExtractDetailedOperationsFromService(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("foo").SigningName("foo").
+		Operation("CreateBar", extractortest.WithInput("Name!", "Description"), extractortest.WithHTTP("POST", "/bars")).
+		Operation("DescribeBar", extractortest.WithInput("Name!"), extractortest.WithOutput("Status"), extractortest.WithDocumentation("Describes a bar.")).
+		Operations("DeleteBar", "ListBars", "TagResource", "PutRecord"))
+	w.AddController(t, "foo", extractortest.NewController().
+		GeneratorConfig("foo", "ListBars").
+		SDKCall("bar", "CreateBar").
+		SDKCall("bar", "DescribeBar").
+		HookCall("bar", "DeleteBar").
+		FS())
+
+	serviceOps, err := extractor.ExtractDetailedOperationsFromService("foo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceOps.Timings = nil
+	extractortest.AssertGoldenJSON(t, "testdata/foo-operations.golden", serviceOps)
+}
+
+func TestExtractWithoutController(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("foo").Operations("CreateBar"))
+
+	serviceOps, err := extractor.ExtractDetailedOperationsFromService("foo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serviceOps.SupportedOperations != 0 || serviceOps.Operations[0].SupportStatus != extractor.SupportUnsupported {
+		t.Errorf("%d supported operation(s), CreateBar %s, want none supported", serviceOps.SupportedOperations, serviceOps.Operations[0].SupportStatus)
+	}
+
+	if _, err := extractor.ExtractDetailedOperationsFromService("missing", false); extractor.ErrorCategoryOf(err) != extractor.ErrorCategoryModelNotFound {
+		t.Errorf("extracting a service without a model returned %v, want a %s error", err, extractor.ErrorCategoryModelNotFound)
+	}
+}
+
+func TestExtractMatchesWholeOperationNames(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("foo").SigningName("foo").
+		Operations("CreateBar", "CreateBarAlias", "TagResource", "UntagResource", "ListTagsForResource"))
+	w.AddController(t, "foo", extractortest.NewController().
+		File("pkg/resource/bar/sdk.go", "package bar\n\n"+
+			"\tresp, err := rm.sdkapi.CreateBarWithContext(ctx, input)\n"+
+			"\tresp, err := rm.sdkapi.CreateBarAlias(ctx, input)\n"+
+			"\tinput := &svcsdk.UntagResourceInput{}\n"+
+			"\tresp, err := rm.sdkapi.ListTagsForResource(ctx, input)\n").
+		FS())
+
+	serviceOps, err := extractor.ExtractDetailedOperationsFromService("foo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := make(map[string][]int)
+	for _, op := range serviceOps.Operations {
+		for _, location := range op.Locations {
+			lines[op.Name] = append(lines[op.Name], location.Line)
+		}
+	}
+	want := map[string][]int{"CreateBar": {3}, "CreateBarAlias": {4}, "UntagResource": {5}, "ListTagsForResource": {6}}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("matched lines %v, want %v", lines, want)
+	}
+}
@@ -0,0 +1,66 @@
+package extractortest
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"testing/fstest"
+)
+
+// ControllerBuilder builds the files of a fake ACK controller repository on an fstest.MapFS
+type ControllerBuilder struct {
+	files fstest.MapFS
+}
+
+// NewController starts an empty controller repository
+func NewController() *ControllerBuilder {
+	return &ControllerBuilder{files: fstest.MapFS{}}
+}
+
+// File adds a file with the given slash-separated path and content
+func (c *ControllerBuilder) File(name, content string) *ControllerBuilder {
+	c.files[name] = &fstest.MapFile{Data: []byte(content), Mode: 0644}
+	return c
+}
+
+// SDKCall adds a generated rm.sdkapi call of an operation to pkg/resource/<resource>/sdk.go
+func (c *ControllerBuilder) SDKCall(resource, operation string) *ControllerBuilder {
+	return c.appendCall(path.Join("pkg", "resource", resource, "sdk.go"), resource,
+		fmt.Sprintf("\tresp, err := rm.sdkapi.%s(ctx, input)\n", operation))
+}
+
+// HookCall adds a hand-written rm.sdkapi call of an operation to pkg/resource/<resource>/hooks.go,
+// which the extractor reports as partially implemented
+func (c *ControllerBuilder) HookCall(resource, operation string) *ControllerBuilder {
+	return c.appendCall(path.Join("pkg", "resource", resource, "hooks.go"), resource,
+		fmt.Sprintf("\tresp, err := rm.sdkapi.%s(ctx, input)\n", operation))
+}
+
+// GeneratorConfig adds a generator.yaml with the model name and operations excluded from generation
+func (c *ControllerBuilder) GeneratorConfig(modelName string, ignoredOperations ...string) *ControllerBuilder {
+	var config strings.Builder
+	if modelName != "" {
+		fmt.Fprintf(&config, "sdk_names:\n  model_name: %s\n", modelName)
+	}
+	if len(ignoredOperations) > 0 {
+		config.WriteString("ignore:\n  operations:\n")
+		for _, operation := range ignoredOperations {
+			fmt.Fprintf(&config, "    - %s\n", operation)
+		}
+	}
+	return c.File("generator.yaml", config.String())
+}
+
+// FS returns the controller repository
+func (c *ControllerBuilder) FS() fstest.MapFS {
+	return c.files
+}
+
+// appendCall appends a line to a Go file of a resource package, creating the file when needed
+func (c *ControllerBuilder) appendCall(name, resource, line string) *ControllerBuilder {
+	content := fmt.Sprintf("package %s\n\n", resource)
+	if file, ok := c.files[name]; ok {
+		content = string(file.Data)
+	}
+	return c.File(name, content+line)
+}
@@ -0,0 +1,103 @@
+package extractortest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// recordingTB records the failures of golden-file assertions instead of failing the test
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "output.golden")
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertGolden(t, path, []byte("first\nsecond\n"))
+	t.Setenv(UpdateGoldenEnv, "")
+
+	AssertGolden(t, path, []byte("first\nsecond\n"))
+
+	recorder := &recordingTB{TB: t}
+	AssertGolden(recorder, path, []byte("first\nchanged\n"))
+	if len(recorder.failures) != 1 || !strings.Contains(recorder.failures[0], "at line 2") {
+		t.Errorf("failures = %q, want one at line 2", recorder.failures)
+	}
+
+	recorder = &recordingTB{TB: t}
+	AssertGolden(recorder, filepath.Join(t.TempDir(), "missing.golden"), nil)
+	if len(recorder.failures) == 0 || !strings.Contains(recorder.failures[0], UpdateGoldenEnv) {
+		t.Errorf("failures = %q, want a hint to set %s", recorder.failures, UpdateGoldenEnv)
+	}
+}
+
+func TestModelBuilder(t *testing.T) {
+	model := NewModel("foo-bar").SigningName("foobar").Version("2020-02-02").
+		Operation("CreateBaz", WithInput("Name!", "Tags"), WithHTTP("PUT", "/baz")).
+		Operations("DeleteBaz").
+		Build()
+
+	resolution := extractor.ResolveOperations(model)
+	if strings.Join(resolution.Operations, ",") != "CreateBaz,DeleteBaz" || len(resolution.Discrepancies) > 0 {
+		t.Errorf("operations %v with discrepancies %v", resolution.Operations, resolution.Discrepancies)
+	}
+	metadata := extractor.ExtractServiceMetadata(model)
+	if metadata.IAMServicePrefix() != "foobar" || metadata.APIVersion != "2020-02-02" {
+		t.Errorf("prefix %q, API version %q", metadata.IAMServicePrefix(), metadata.APIVersion)
+	}
+
+	input := model.Shapes["com.amazonaws.foobar#CreateBazRequest"]
+	if _, required := input.Members["Name"].Traits["smithy.api#required"]; !required {
+		t.Error("Name! is not a required member of CreateBazRequest")
+	}
+	if _, required := input.Members["Tags"].Traits["smithy.api#required"]; required {
+		t.Error("Tags is a required member of CreateBazRequest")
+	}
+	for _, diagnostic := range extractor.LintModel(model) {
+		if diagnostic.Rule == "missing-shape" {
+			t.Errorf("built model references a missing shape: %s", diagnostic.Message)
+		}
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	w := NewWorkspace(t)
+	w.AddModel(t, NewModel("foo").Operations("CreateBar", "DeleteBar"))
+	w.AddController(t, "foo", NewController().
+		GeneratorConfig("foo", "DeleteBar").
+		SDKCall("bar", "CreateBar").
+		SDKCall("bar", "DescribeBar").FS())
+
+	sdk, err := os.ReadFile(filepath.Join(w.ControllersDir, "foo-controller", "pkg", "resource", "bar", "sdk.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "package bar\n\n\tresp, err := rm.sdkapi.CreateBar(ctx, input)\n\tresp, err := rm.sdkapi.DescribeBar(ctx, input)\n"; string(sdk) != want {
+		t.Errorf("sdk.go = %q, want %q", sdk, want)
+	}
+
+	serviceOps, err := extractor.ExtractDetailedOperationsFromService("foo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make(map[string]extractor.SupportStatus)
+	for _, op := range serviceOps.Operations {
+		statuses[op.Name] = op.SupportStatus
+	}
+	if statuses["CreateBar"] != extractor.SupportImplemented || statuses["DeleteBar"] != extractor.SupportIntentionallyIgnored {
+		t.Errorf("support statuses = %v", statuses)
+	}
+}
@@ -0,0 +1,62 @@
+package extractortest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that rewrites golden files with the actual output
+// instead of comparing against them, e.g. UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// AssertGolden compares output with the golden file at path, conventionally under testdata/.
+// With UPDATE_GOLDEN set, the golden file is written instead.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s (run with %s=1 to create it): %v", path, UpdateGoldenEnv, err)
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+
+	gotLines := strings.Split(string(got), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var gotLine, wantLine string
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if gotLine != wantLine {
+			t.Fatalf("output differs from golden file %s at line %d:\n  got:  %q\n  want: %q\n(run with %s=1 to update it)", path, i+1, gotLine, wantLine, UpdateGoldenEnv)
+		}
+	}
+}
+
+// AssertGoldenJSON compares the indented JSON encoding of a value with the golden file at path
+func AssertGoldenJSON(t testing.TB, path string, value interface{}) {
+	t.Helper()
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal %T: %v", value, err)
+	}
+	AssertGolden(t, path, append(data, '\n'))
+}
@@ -0,0 +1,177 @@
+// Package extractortest provides fixtures for testing code built on the extractor: service model
+// builders, fake controllers on fstest.MapFS, a workspace wiring both into the extractor, and
+// golden-file comparison.
+package extractortest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// ModelBuilder builds a Smithy JSON AST service model
+type ModelBuilder struct {
+	serviceName  string
+	namespace    string
+	version      string
+	serviceTrait map[string]string
+	traits       map[string]interface{}
+	operations   []string
+	shapes       map[string]extractor.ServiceShape
+}
+
+// OperationOption customizes an operation added to a model
+type OperationOption func(b *ModelBuilder, name string, shape *extractor.ServiceShape)
+
+// NewModel starts a model for a service in the com.amazonaws.<service> namespace
+func NewModel(serviceName string) *ModelBuilder {
+	return &ModelBuilder{
+		serviceName:  serviceName,
+		namespace:    "com.amazonaws." + strings.ReplaceAll(serviceName, "-", ""),
+		version:      "2024-01-01",
+		serviceTrait: map[string]string{"sdkId": serviceName},
+		traits:       make(map[string]interface{}),
+		shapes:       make(map[string]extractor.ServiceShape),
+	}
+}
+
+// ServiceName returns the name of the service the model describes
+func (b *ModelBuilder) ServiceName() string {
+	return b.serviceName
+}
+
+// Version sets the API version of the service shape
+func (b *ModelBuilder) Version(version string) *ModelBuilder {
+	b.version = version
+	return b
+}
+
+// ARNNamespace sets the arnNamespace of the aws.api#service trait
+func (b *ModelBuilder) ARNNamespace(namespace string) *ModelBuilder {
+	b.serviceTrait["arnNamespace"] = namespace
+	return b
+}
+
+// SigningName sets the aws.auth#sigv4 signing name of the service
+func (b *ModelBuilder) SigningName(name string) *ModelBuilder {
+	return b.ServiceTrait("aws.auth#sigv4", map[string]string{"name": name})
+}
+
+// ServiceTrait sets a trait of the service shape
+func (b *ModelBuilder) ServiceTrait(name string, value interface{}) *ModelBuilder {
+	b.traits[name] = value
+	return b
+}
+
+// Operation adds an operation to the service
+func (b *ModelBuilder) Operation(name string, options ...OperationOption) *ModelBuilder {
+	shape := extractor.ServiceShape{Type: "operation", Traits: map[string]json.RawMessage{}}
+	for _, option := range options {
+		option(b, name, &shape)
+	}
+	b.operations = append(b.operations, name)
+	b.shapes[b.shapeID(name)] = shape
+	return b
+}
+
+// Operations adds several operations without options
+func (b *ModelBuilder) Operations(names ...string) *ModelBuilder {
+	for _, name := range names {
+		b.Operation(name)
+	}
+	return b
+}
+
+// WithInput gives an operation a <Operation>Request input structure with string members.
+// Members ending in "!" are marked required.
+func WithInput(members ...string) OperationOption {
+	return func(b *ModelBuilder, name string, shape *extractor.ServiceShape) {
+		shape.Input = &extractor.ShapeReference{Target: b.structure(name+"Request", members)}
+	}
+}
+
+// WithOutput gives an operation a <Operation>Response output structure with string members.
+// Members ending in "!" are marked required.
+func WithOutput(members ...string) OperationOption {
+	return func(b *ModelBuilder, name string, shape *extractor.ServiceShape) {
+		shape.Output = &extractor.ShapeReference{Target: b.structure(name+"Response", members)}
+	}
+}
+
+// WithHTTP binds an operation to an HTTP method and URI
+func WithHTTP(method, uri string) OperationOption {
+	return WithTrait("smithy.api#http", map[string]interface{}{"method": method, "uri": uri, "code": 200})
+}
+
+// WithDocumentation documents an operation
+func WithDocumentation(documentation string) OperationOption {
+	return WithTrait("smithy.api#documentation", documentation)
+}
+
+// WithTrait sets a trait of an operation
+func WithTrait(traitName string, value interface{}) OperationOption {
+	return func(b *ModelBuilder, name string, shape *extractor.ServiceShape) {
+		shape.Traits[traitName] = mustMarshal(value)
+	}
+}
+
+// Build returns the model
+func (b *ModelBuilder) Build() *extractor.AWSServiceModel {
+	model := &extractor.AWSServiceModel{Shapes: make(map[string]extractor.ServiceShape, len(b.shapes)+1)}
+	for id, shape := range b.shapes {
+		model.Shapes[id] = shape
+	}
+
+	service := extractor.ServiceShape{Type: "service", Version: b.version, Traits: map[string]json.RawMessage{}}
+	service.Traits["aws.api#service"] = mustMarshal(b.serviceTrait)
+	for name, value := range b.traits {
+		service.Traits[name] = mustMarshal(value)
+	}
+	for _, name := range b.operations {
+		service.Operations = append(service.Operations, extractor.OperationTarget{Target: b.shapeID(name)})
+	}
+	model.Shapes[b.shapeID(b.serviceName)] = service
+	return model
+}
+
+// JSON returns the model in the Smithy JSON AST format of the api-models-aws repository
+func (b *ModelBuilder) JSON() []byte {
+	document := map[string]interface{}{"smithy": "2.0", "shapes": b.Build().Shapes}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("extractortest: failed to marshal model: %v", err))
+	}
+	return data
+}
+
+// shapeID returns the absolute shape ID of a shape in the model's namespace
+func (b *ModelBuilder) shapeID(name string) string {
+	return b.namespace + "#" + name
+}
+
+// structure adds a structure shape with string members and returns its shape ID
+func (b *ModelBuilder) structure(name string, members []string) string {
+	shape := extractor.ServiceShape{Type: "structure", Members: make(map[string]extractor.ShapeReference, len(members))}
+	for _, member := range members {
+		reference := extractor.ShapeReference{Target: "smithy.api#String"}
+		if strings.HasSuffix(member, "!") {
+			member = strings.TrimSuffix(member, "!")
+			reference.Traits = map[string]json.RawMessage{"smithy.api#required": json.RawMessage("{}")}
+		}
+		shape.Members[member] = reference
+	}
+	id := b.shapeID(name)
+	b.shapes[id] = shape
+	return id
+}
+
+// mustMarshal encodes a trait value, which the builders only ever pass as plain data
+func mustMarshal(value interface{}) json.RawMessage {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Sprintf("extractortest: failed to marshal trait: %v", err))
+	}
+	return data
+}
@@ -0,0 +1,81 @@
+package extractortest
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// Workspace is a temporary directory holding service models and controllers the extractor is pointed at
+type Workspace struct {
+	ModelsDir      string
+	ControllersDir string
+}
+
+// NewWorkspace creates an empty workspace and points the extractor at it. The extractor's
+// package-level state is reset before and after the test.
+func NewWorkspace(t testing.TB) *Workspace {
+	t.Helper()
+	dir := t.TempDir()
+	w := &Workspace{
+		ModelsDir:      filepath.Join(dir, "models"),
+		ControllersDir: filepath.Join(dir, "controllers"),
+	}
+	for _, path := range []string{w.ModelsDir, w.ControllersDir} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+	}
+
+	extractor.ResetState()
+	t.Cleanup(extractor.ResetState)
+	if err := extractor.SetModelsDir(w.ModelsDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractor.SetControllersDir(w.ControllersDir); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// AddModel writes a service model to <models>/<service>/service/<version>/<service>.json
+func (w *Workspace) AddModel(t testing.TB, model *ModelBuilder) {
+	t.Helper()
+	dir := filepath.Join(w.ModelsDir, model.ServiceName(), "service", model.version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	file := filepath.Join(dir, model.ServiceName()+".json")
+	if err := os.WriteFile(file, model.JSON(), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", file, err)
+	}
+}
+
+// AddController copies a controller repository, e.g. ControllerBuilder.FS(), to <controllers>/<service>-controller
+func (w *Workspace) AddController(t testing.TB, serviceName string, controller fs.FS) {
+	t.Helper()
+	root := filepath.Join(w.ControllersDir, serviceName+"-controller")
+	err := fs.WalkDir(controller, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := fs.ReadFile(controller, name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+	if err != nil {
+		t.Fatalf("failed to write controller for %s: %v", serviceName, err)
+	}
+}
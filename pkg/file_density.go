@@ -0,0 +1,88 @@
+package extractor
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// minHotFileOperations is the smallest operation count for a custom code file to be reported as hot
+const minHotFileOperations = 5
+
+// ComputeFileDensity aggregates supported operations by the controller files they were found in.
+// Custom code files (hooks and other hand-written files) implementing at least
+// minHotFileOperations operations and twice the average of such files are marked hot.
+func ComputeFileDensity(operations []Operation) []FileDensity {
+	byFile := make(map[string]*FileDensity)
+	for _, op := range operations {
+		if !op.IsSupported() {
+			continue
+		}
+		locations := op.Locations
+		if len(locations) == 0 {
+			locations = []Location{{File: op.File, Line: op.Line}}
+		}
+		seen := make(map[string]bool)
+		for _, location := range locations {
+			if seen[location.File] {
+				continue
+			}
+			seen[location.File] = true
+
+			density, ok := byFile[location.File]
+			if !ok {
+				density = &FileDensity{File: location.File, Kind: classifyControllerFile(location.File)}
+				byFile[location.File] = density
+			}
+			density.Operations++
+			density.OperationNames = append(density.OperationNames, op.Name)
+		}
+	}
+
+	customFiles := 0
+	customOperations := 0
+	for _, density := range byFile {
+		if isCustomCodeFile(density.Kind) {
+			customFiles++
+			customOperations += density.Operations
+		}
+	}
+
+	result := make([]FileDensity, 0, len(byFile))
+	for _, density := range byFile {
+		if isCustomCodeFile(density.Kind) && density.Operations >= minHotFileOperations &&
+			density.Operations*customFiles >= 2*customOperations {
+			density.Hot = true
+		}
+		sort.Strings(density.OperationNames)
+		result = append(result, *density)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Operations != result[j].Operations {
+			return result[i].Operations > result[j].Operations
+		}
+		return result[i].File < result[j].File
+	})
+	return result
+}
+
+// classifyControllerFile categorizes a controller file as generated sdk code, hooks, manager or other
+func classifyControllerFile(path string) string {
+	base := filepath.Base(path)
+	switch {
+	case base == "sdk.go":
+		return "sdk"
+	case strings.Contains(base, "hook"):
+		return "hooks"
+	case base == "manager.go" || base == "manager_factory.go":
+		return "manager"
+	default:
+		return "other"
+	}
+}
+
+// isCustomCodeFile reports whether a file kind holds hand-written controller code
+func isCustomCodeFile(kind string) bool {
+	return kind == "hooks" || kind == "other"
+}
@@ -0,0 +1,106 @@
+package extractor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StringList is a generator.yaml value that may be written either as a single string or a list
+type StringList []string
+
+// UnmarshalYAML accepts both scalar and sequence nodes
+func (l *StringList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*l = StringList{node.Value}
+		return nil
+	case yaml.SequenceNode:
+		var values []string
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+		*l = values
+		return nil
+	default:
+		return fmt.Errorf("line %d: expected string or list of strings", node.Line)
+	}
+}
+
+// ParseGeneratorConfig parses generator.yaml content into a GeneratorConfig.
+// Values with the wrong type are an error; keys outside the known schema are
+// reported in SchemaWarnings rather than silently dropped.
+func ParseGeneratorConfig(data []byte) (*GeneratorConfig, error) {
+	var config GeneratorConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid generator.yaml: %w", err)
+	}
+
+	var strict GeneratorConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	// An empty or comment-only file holds no document and is an empty config
+	if err := decoder.Decode(&strict); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("invalid generator.yaml: %w", err)
+		}
+		config.SchemaWarnings = typeErr.Errors
+	}
+
+	return &config, nil
+}
+
+// LoadGeneratorConfig reads and parses a generator.yaml file
+func LoadGeneratorConfig(path string) (*GeneratorConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generator.yaml file %s: %w", path, err)
+	}
+
+	config, err := ParseGeneratorConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generator.yaml file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// LoadControllerGeneratorConfig loads the generator.yaml of the controller for a given service
+func LoadControllerGeneratorConfig(serviceName string) (*GeneratorConfig, error) {
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return nil, fmt.Errorf("controller directory not found for service %s", serviceName)
+	}
+
+	generatorFile := filepath.Join(controllerPath, "generator.yaml")
+	if _, err := os.Stat(generatorFile); os.IsNotExist(err) {
+		return nil, fmt.Errorf("generator.yaml not found in controller directory: %s", generatorFile)
+	}
+
+	return LoadGeneratorConfig(generatorFile)
+}
+
+// IsOperationIgnored reports whether generator.yaml excludes an operation from code generation
+func (c *GeneratorConfig) IsOperationIgnored(operationName string) bool {
+	for _, op := range c.Ignore.Operations {
+		if op == operationName {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceNames returns the names of all resources configured in generator.yaml
+func (c *GeneratorConfig) ResourceNames() []string {
+	names := make([]string, 0, len(c.Resources))
+	for name := range c.Resources {
+		names = append(names, name)
+	}
+	return names
+}
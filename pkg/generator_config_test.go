@@ -0,0 +1,31 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGeneratorConfig(t *testing.T) {
+	for _, content := range []string{"", "\n", "# nothing is ignored yet\n", "---\n"} {
+		config, err := ParseGeneratorConfig([]byte(content))
+		if err != nil {
+			t.Errorf("ParseGeneratorConfig(%q): %v", content, err)
+			continue
+		}
+		if len(config.Ignore.Operations) > 0 || len(config.SchemaWarnings) > 0 {
+			t.Errorf("ParseGeneratorConfig(%q) = %+v, want an empty config", content, config)
+		}
+	}
+
+	config, err := ParseGeneratorConfig([]byte("ignore:\n  operations: [DeleteBar]\n  unknown: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string(config.Ignore.Operations), []string{"DeleteBar"}) || len(config.SchemaWarnings) != 1 {
+		t.Errorf("ignored operations %v, schema warnings %v", config.Ignore.Operations, config.SchemaWarnings)
+	}
+
+	if _, err := ParseGeneratorConfig([]byte("ignore: [")); err == nil {
+		t.Error("ParseGeneratorConfig accepted malformed YAML")
+	}
+}
@@ -0,0 +1,349 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// githubCacheDir is the directory in the cache directory holding responses of GET requests by ETag
+const githubCacheDir = "github"
+
+// DefaultGitHubRateLimitWait is how long requests wait by default for an exhausted GitHub rate limit to reset
+const DefaultGitHubRateLimitWait = time.Minute
+
+// githubRateLimitWait is the longest time a request waits for an exhausted rate limit to reset
+var githubRateLimitWait = DefaultGitHubRateLimitWait
+
+// githubRateLimit is the API rate limit GitHub reported last, shared by every client so that
+// concurrent features stop sending requests once it is exhausted
+var githubRateLimit struct {
+	sync.Mutex
+	known     bool
+	remaining int
+	reset     time.Time
+}
+
+// githubHosts are the hosts the GitHub token is sent to; remote model sources on other hosts never see it
+var githubHosts = map[string]bool{
+	"api.github.com":            true,
+	"github.com":                true,
+	"raw.githubusercontent.com": true,
+}
+
+// SetGitHubRateLimitWait sets how long GitHub requests wait for an exhausted rate limit to reset
+// before failing; zero fails right away
+func SetGitHubRateLimitWait(wait time.Duration) error {
+	if wait < 0 {
+		return fmt.Errorf("invalid GitHub rate limit wait %s, must not be negative", wait)
+	}
+	githubRateLimitWait = wait
+	return nil
+}
+
+// GitHubRateLimitError is returned when the GitHub rate limit is exhausted for longer than requests wait
+type GitHubRateLimitError struct {
+	Reset         time.Time
+	Authenticated bool
+}
+
+func (e *GitHubRateLimitError) Error() string {
+	message := fmt.Sprintf("GitHub rate limit exhausted until %s", e.Reset.Local().Format("15:04:05"))
+	if !e.Authenticated {
+		message += "; set GITHUB_TOKEN to raise the limit"
+	}
+	return message
+}
+
+// githubAPIError is returned for API requests answered with a status other than 2xx
+type githubAPIError struct {
+	Method  string
+	Path    string
+	Status  int
+	Message string
+}
+
+func (e *githubAPIError) Error() string {
+	return fmt.Sprintf("GitHub request %s %s returned %d %s: %s", e.Method, e.Path, e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// githubClient sends the requests of every GitHub feature: API calls, release downloads and model
+// fetches. The token is only sent to GitHub hosts, GET responses are revalidated by ETag against
+// the cache directory, which doesn't count against the rate limit, and requests wait for an
+// exhausted rate limit to reset for at most the configured time.
+type githubClient struct {
+	token string
+}
+
+// newGitHubClient creates a client authenticating with token, or with GITHUB_TOKEN when token is empty
+func newGitHubClient(token string) *githubClient {
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	return &githubClient{token: token}
+}
+
+// do sends an API request with an optional JSON body and decodes the JSON response into out when non-nil
+func (c *githubClient) do(method, path string, body, out interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to marshal GitHub request: %w", err)
+		}
+	}
+
+	status, response, err := c.fetch(method, githubAPIURL+path, data, githubHTTPTimeout)
+	if err != nil {
+		return fmt.Errorf("GitHub request %s %s failed: %w", method, path, err)
+	}
+	if status < 200 || status >= 300 {
+		message := response
+		if len(message) > 1024 {
+			message = message[:1024]
+		}
+		return &githubAPIError{Method: method, Path: path, Status: status, Message: strings.TrimSpace(string(message))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response, out); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	return nil
+}
+
+// fetch sends a request and returns the status and body of the response. GET responses with an
+// ETag are cached and revalidated, a 304 answer returning the cached body with status 200.
+func (c *githubClient) fetch(method, endpoint string, body []byte, timeout time.Duration) (int, []byte, error) {
+	var cached *githubCachedResponse
+	cachePath := ""
+	header := make(http.Header)
+	if method == http.MethodGet {
+		cachePath = c.cachePath(endpoint)
+		if cached = readGitHubCache(cachePath); cached != nil {
+			header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
+	resp, err := c.send(method, endpoint, body, header, timeout)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return http.StatusOK, cached.Body, nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if etag := resp.Header.Get("ETag"); resp.StatusCode == http.StatusOK && etag != "" && cachePath != "" {
+		writeGitHubCache(cachePath, &githubCachedResponse{ETag: etag, Body: data})
+	}
+	return resp.StatusCode, data, nil
+}
+
+// open sends an uncached GET request and returns the response for streaming its body, e.g. a
+// release tarball. Each attempt is bounded by timeout, including reading the body.
+func (c *githubClient) open(endpoint string, timeout time.Duration) (*http.Response, error) {
+	return c.send(http.MethodGet, endpoint, nil, nil, timeout)
+}
+
+// send sends a request, waiting for an exhausted rate limit to reset first. A request answered
+// with a rate limit error is retried once after the wait GitHub asks for, unless that is longer
+// than the configured wait.
+func (c *githubClient) send(method, endpoint string, body []byte, header http.Header, timeout time.Duration) (*http.Response, error) {
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", endpoint, err)
+	}
+	isAPI := endpoint == githubAPIURL || strings.HasPrefix(endpoint, githubAPIURL+"/")
+
+	for attempt := 0; ; attempt++ {
+		if isAPI {
+			if err := c.waitForRateLimit(); err != nil {
+				return nil, err
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create request for %s: %w", endpoint, err)
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		req.Header.Set("User-Agent", "ack-api-extractor")
+		if isAPI {
+			req.Header.Set("Accept", "application/vnd.github+json")
+		}
+		if c.token != "" && githubHosts[target.Hostname()] {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		if isAPI {
+			recordGitHubRateLimit(resp.Header)
+		}
+
+		wait, limited := rateLimitWait(resp)
+		if !limited {
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		resp.Body.Close()
+		cancel()
+		if attempt > 0 || wait > githubRateLimitWait {
+			return nil, &GitHubRateLimitError{Reset: time.Now().Add(wait), Authenticated: c.token != ""}
+		}
+		reportWarning("", "GitHub rate limit reached, retrying %s in %s", target.Host+target.Path, wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+}
+
+// waitForRateLimit sleeps until the exhausted rate limit resets, or fails when that takes longer
+// than the configured wait
+func (c *githubClient) waitForRateLimit() error {
+	githubRateLimit.Lock()
+	exhausted := githubRateLimit.known && githubRateLimit.remaining == 0
+	reset := githubRateLimit.reset
+	githubRateLimit.Unlock()
+
+	wait := time.Until(reset)
+	if !exhausted || wait <= 0 {
+		return nil
+	}
+	if wait > githubRateLimitWait {
+		return &GitHubRateLimitError{Reset: reset, Authenticated: c.token != ""}
+	}
+	reportWarning("", "GitHub rate limit exhausted, waiting %s for it to reset", wait.Round(time.Second))
+	time.Sleep(wait)
+	return nil
+}
+
+// recordGitHubRateLimit remembers the rate limit reported by the X-RateLimit headers of a response
+func recordGitHubRateLimit(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	githubRateLimit.Lock()
+	defer githubRateLimit.Unlock()
+	githubRateLimit.known = true
+	githubRateLimit.remaining = remaining
+	githubRateLimit.reset = time.Unix(reset, 0)
+}
+
+// rateLimitWait reports whether a response is a rate limit error and how long to wait before
+// retrying, from its Retry-After header or else the reset time of an exhausted primary rate limit
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, resp.StatusCode == http.StatusTooManyRequests
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, true
+	}
+	return max(time.Until(time.Unix(reset, 0)), 0), true
+}
+
+// escapeRefPath escapes the segments of a slash-separated Git ref for use in a request path
+func escapeRefPath(ref string) string {
+	segments := strings.Split(ref, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// cancelOnClose releases the context of a streamed response when its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// githubCachedResponse is a GET response stored for revalidation by ETag
+type githubCachedResponse struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+// cachePath returns the cache file of a GET request. Responses may depend on who asks, so the
+// token is part of the key. It returns "" when the cache directory is unusable.
+func (c *githubClient) cachePath(endpoint string) string {
+	dir, err := CachePath(githubCacheDir)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(c.token + "\x00" + endpoint))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// readGitHubCache reads a cached response, nil when there is none or it is unreadable
+func readGitHubCache(path string) *githubCachedResponse {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cached githubCachedResponse
+	if json.Unmarshal(data, &cached) != nil || cached.ETag == "" {
+		return nil
+	}
+	return &cached
+}
+
+// writeGitHubCache stores a response; failures only cost a full response next time and are ignored
+func writeGitHubCache(path string, cached *githubCachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0755) != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".response-")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	if tmp.Close() != nil || writeErr != nil || os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,165 @@
+package extractor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeGitHubServer serves handler in place of every host requests are sent to until the test ends
+func fakeGitHubServer(t *testing.T, handler http.HandlerFunc) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := http.DefaultClient.Transport
+	t.Cleanup(func() { http.DefaultClient.Transport = transport })
+	http.DefaultClient.Transport = roundTripFunc(func(req *http.Request) *http.Response {
+		req = req.Clone(req.Context())
+		req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	})
+}
+
+// setupGitHubClientTest resets the package state and caches GitHub responses in a temporary directory
+func setupGitHubClientTest(t *testing.T) {
+	t.Cleanup(ResetState)
+	if err := SetCacheDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_TOKEN", "")
+}
+
+func TestGitHubClientRevalidatesCachedResponses(t *testing.T) {
+	setupGitHubClientTest(t)
+	var conditional []string
+	fakeGitHubServer(t, func(rw http.ResponseWriter, req *http.Request) {
+		conditional = append(conditional, req.Header.Get("If-None-Match"))
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.Header().Set("ETag", `"v1"`)
+		rw.Write([]byte(`{"name": "foo"}`))
+	})
+
+	client := newGitHubClient("")
+	for i := 0; i < 2; i++ {
+		var repository struct {
+			Name string `json:"name"`
+		}
+		if err := client.do(http.MethodGet, "/repos/aws-controllers-k8s/foo-controller", nil, &repository); err != nil {
+			t.Fatal(err)
+		}
+		if repository.Name != "foo" {
+			t.Errorf("request %d returned %q, want foo", i, repository.Name)
+		}
+	}
+	if want := []string{"", `"v1"`}; len(conditional) != 2 || conditional[0] != want[0] || conditional[1] != want[1] {
+		t.Errorf("If-None-Match headers %q, want %q", conditional, want)
+	}
+}
+
+func TestGitHubClientRetriesAfterRateLimit(t *testing.T) {
+	setupGitHubClientTest(t)
+	requests := 0
+	fakeGitHubServer(t, func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		if requests == 1 {
+			rw.Header().Set("Retry-After", "0")
+			rw.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		rw.Write([]byte(`{}`))
+	})
+	var warnings []string
+	SetProgressReporter(ProgressFunc(func(event ProgressEvent) {
+		if event.Kind == ProgressWarning {
+			warnings = append(warnings, event.Message)
+		}
+	}))
+
+	if err := newGitHubClient("").do(http.MethodGet, "/rate_limited", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || len(warnings) != 1 {
+		t.Errorf("%d request(s) with warnings %q, want a retry after one warning", requests, warnings)
+	}
+}
+
+func TestGitHubClientFailsOnLongRateLimitWaits(t *testing.T) {
+	setupGitHubClientTest(t)
+	if err := SetGitHubRateLimitWait(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	reset := time.Now().Add(time.Hour).Unix()
+	requests := 0
+	fakeGitHubServer(t, func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Path == "/retry_after" {
+			rw.Header().Set("Retry-After", "120")
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+		rw.Header().Set("X-RateLimit-Remaining", "0")
+		rw.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		rw.Write([]byte(`{}`))
+	})
+
+	client := newGitHubClient("")
+	var rateLimitErr *GitHubRateLimitError
+	if err := client.do(http.MethodGet, "/retry_after", nil, nil); !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Retry-After longer than the wait returned %v, want a GitHubRateLimitError", err)
+	}
+	if requests != 1 {
+		t.Errorf("%d requests, want no retry", requests)
+	}
+
+	// The last request exhausted the rate limit, so the next one fails without being sent
+	if err := client.do(http.MethodGet, "/exhausting", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	err := client.do(http.MethodGet, "/exhausted", nil, nil)
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.Reset.Unix() != reset || rateLimitErr.Authenticated {
+		t.Fatalf("request with an exhausted rate limit returned %v, want a GitHubRateLimitError until %d", err, reset)
+	}
+	if requests != 2 {
+		t.Errorf("%d requests, want the request with an exhausted rate limit not to be sent", requests)
+	}
+}
+
+func TestGitHubClientSendsTokenOnlyToGitHub(t *testing.T) {
+	setupGitHubClientTest(t)
+	authorization := make(map[string]string)
+	fakeGitHubServer(t, func(rw http.ResponseWriter, req *http.Request) {
+		authorization[req.Host] = req.Header.Get("Authorization")
+		rw.Write([]byte(`{}`))
+	})
+
+	client := newGitHubClient("secret")
+	for _, endpoint := range []string{githubAPIURL + "/user", "https://raw.githubusercontent.com/foo/bar/main/model.json", "https://models.example.com/foo/model.json"} {
+		if _, _, err := client.fetch(http.MethodGet, endpoint, nil, githubHTTPTimeout); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[string]string{
+		"api.github.com":            "Bearer secret",
+		"raw.githubusercontent.com": "Bearer secret",
+		"models.example.com":        "",
+	}
+	for host, header := range want {
+		if got, ok := authorization[host]; !ok || got != header {
+			t.Errorf("Authorization sent to %s = %q, want %q", host, got, header)
+		}
+	}
+}
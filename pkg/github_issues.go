@@ -0,0 +1,93 @@
+package extractor
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	githubAPIURL      = "https://api.github.com"
+	ackGitHubOrg      = "aws-controllers-k8s"
+	githubHTTPTimeout = 30 * time.Second
+
+	// githubSearchPageSize is the largest page the GitHub search API returns
+	githubSearchPageSize = 100
+	// githubSearchLimit is the number of results the GitHub search API returns at most for a query
+	githubSearchLimit = 1000
+)
+
+// GitHubIssue represents an issue returned by the GitHub search API
+type GitHubIssue struct {
+	Number        int    `json:"number"`
+	Title         string `json:"title"`
+	Body          string `json:"body"`
+	HTMLURL       string `json:"html_url"`
+	RepositoryURL string `json:"repository_url"`
+}
+
+// githubSearchResponse represents the GitHub issue search response
+type githubSearchResponse struct {
+	TotalCount int           `json:"total_count"`
+	Items      []GitHubIssue `json:"items"`
+}
+
+// FindTrackingIssues searches the ACK GitHub organization for open issues mentioning a service.
+// A GITHUB_TOKEN environment variable is used when set to raise the API rate limit.
+// Results are fetched page by page up to the search API's limit, with a warning when more issues
+// match than it returns.
+func FindTrackingIssues(serviceName string) ([]GitHubIssue, error) {
+	query := fmt.Sprintf("org:%s is:issue is:open %s", ackGitHubOrg, serviceName)
+	client := newGitHubClient("")
+
+	var issues []GitHubIssue
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/search/issues?q=%s&per_page=%d&page=%d", url.QueryEscape(query), githubSearchPageSize, page)
+		var result githubSearchResponse
+		if err := client.do(http.MethodGet, path, nil, &result); err != nil {
+			return nil, fmt.Errorf("failed to search GitHub issues: %w", err)
+		}
+		issues = append(issues, result.Items...)
+
+		if len(result.Items) < githubSearchPageSize || len(issues) >= result.TotalCount || len(issues) >= githubSearchLimit {
+			if result.TotalCount > len(issues) {
+				reportWarning(serviceName, "GitHub found %d open issues mentioning %s, only the first %d are linked", result.TotalCount, serviceName, len(issues))
+			}
+			return issues, nil
+		}
+	}
+}
+
+// LinkIssues attaches issues mentioning an operation by name to operations without controller code
+func LinkIssues(operations []Operation, issues []GitHubIssue) int {
+	linked := 0
+	for i := range operations {
+		if operations[i].IsSupported() {
+			continue
+		}
+		pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(operations[i].Name) + `\b`)
+		for _, issue := range issues {
+			if pattern.MatchString(issue.Title) || pattern.MatchString(issue.Body) {
+				operations[i].Issues = append(operations[i].Issues, IssueReference{
+					Repository: issueRepository(issue.RepositoryURL),
+					Number:     issue.Number,
+					Title:      issue.Title,
+					URL:        issue.HTMLURL,
+				})
+			}
+		}
+		if len(operations[i].Issues) > 0 {
+			linked++
+		}
+	}
+	return linked
+}
+
+// issueRepository returns the owner/name of a repository from its API URL
+// Example: "https://api.github.com/repos/aws-controllers-k8s/community" -> "aws-controllers-k8s/community"
+func issueRepository(repositoryURL string) string {
+	return strings.TrimPrefix(repositoryURL, githubAPIURL+"/repos/")
+}
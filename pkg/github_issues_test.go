@@ -0,0 +1,78 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc answers HTTP requests without a network
+type roundTripFunc func(*http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+// fakeIssueSearch answers issue searches with total matching issues, of which the search API
+// returns at most its limit
+func fakeIssueSearch(t *testing.T, total int, pages *[]int) {
+	transport := http.DefaultClient.Transport
+	t.Cleanup(func() { http.DefaultClient.Transport = transport })
+	http.DefaultClient.Transport = roundTripFunc(func(req *http.Request) *http.Response {
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(req.URL.Query().Get("per_page"))
+		*pages = append(*pages, page)
+
+		result := githubSearchResponse{TotalCount: total}
+		for n := (page-1)*perPage + 1; n <= page*perPage && n <= total && n <= githubSearchLimit; n++ {
+			result.Items = append(result.Items, GitHubIssue{Number: n, Title: fmt.Sprintf("Issue %d", n)})
+		}
+		body, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(string(body)))}
+	})
+}
+
+func TestFindTrackingIssuesPaginates(t *testing.T) {
+	t.Cleanup(ResetState)
+	if err := SetCacheDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_TOKEN", "")
+
+	tests := []struct {
+		total    int
+		pages    int
+		issues   int
+		warnings int
+	}{
+		{42, 1, 42, 0},
+		{250, 3, 250, 0},
+		{1500, 10, 1000, 1},
+	}
+	for _, test := range tests {
+		var pages []int
+		fakeIssueSearch(t, test.total, &pages)
+		var warnings []string
+		SetProgressReporter(ProgressFunc(func(event ProgressEvent) {
+			if event.Kind == ProgressWarning {
+				warnings = append(warnings, event.Message)
+			}
+		}))
+
+		issues, err := FindTrackingIssues("foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pages) != test.pages || len(issues) != test.issues || len(warnings) != test.warnings {
+			t.Errorf("%d matching issues: fetched %d page(s) and %d issue(s) with warnings %q, want %d, %d and %d warning(s)",
+				test.total, len(pages), len(issues), warnings, test.pages, test.issues, test.warnings)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package extractor
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// recommendedPolicyRepoPath is the path of the recommended inline policy in controller repositories
+const recommendedPolicyRepoPath = "config/iam/recommended-inline-policy"
+
+// ProposeOptions configures pull requests opened against controller repositories
+type ProposeOptions struct {
+	// Token is a GitHub token allowed to push branches and open pull requests
+	Token string
+	// Fork is the owner of a fork to push the branch to; the branch is pushed to the controller repository when empty
+	Fork string
+	// DryRun computes the change without pushing a branch or opening a pull request
+	DryRun bool
+}
+
+// ProposalResult describes the outcome of proposing a policy update for a service
+type ProposalResult struct {
+	Repository string   `json:"repository"`
+	Branch     string   `json:"branch,omitempty"`
+	Missing    []string `json:"missing_actions"`
+	PullURL    string   `json:"pull_request_url,omitempty"`
+	// Existing is true when an open pull request for the same change already existed
+	Existing bool `json:"existing,omitempty"`
+}
+
+// githubContent represents a file returned by the GitHub contents API
+type githubContent struct {
+	SHA      string `json:"sha"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// githubPull represents a pull request returned by the GitHub API
+type githubPull struct {
+	HTMLURL string `json:"html_url"`
+}
+
+// ProposePolicyUpdate opens a pull request against the <service>-controller repository adding the
+// actions of supported operations that its recommended inline policy lacks. The policy is read from
+// the head of the repository's default branch and patched without reformatting it, and the
+// supported operations are extracted from the controller source of the same commit. The branch name
+// is derived from the missing actions, so running the proposal again reuses an open pull request.
+func ProposePolicyUpdate(serviceName string, opts ProposeOptions) (*ProposalResult, error) {
+	if opts.Token == "" && !opts.DryRun {
+		return nil, fmt.Errorf("a GitHub token is required to open pull requests")
+	}
+
+	repo := fmt.Sprintf("%s/%s-controller", ackGitHubOrg, serviceName)
+	repoPath := githubRepoPath(ackGitHubOrg, serviceName+"-controller")
+	result := &ProposalResult{Repository: repo}
+	client := newGitHubClient(opts.Token)
+
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := client.do(http.MethodGet, repoPath, nil, &repository); err != nil {
+		return nil, err
+	}
+
+	var base struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := client.do(http.MethodGet, repoPath+"/git/ref/heads/"+escapeRefPath(repository.DefaultBranch), nil, &base); err != nil {
+		return nil, err
+	}
+
+	var file githubContent
+	contentsPath := repoPath + "/contents/" + recommendedPolicyRepoPath
+	if err := client.do(http.MethodGet, contentsPath+"?"+url.Values{"ref": {base.Object.SHA}}.Encode(), nil, &file); err != nil {
+		return nil, err
+	}
+	current, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", recommendedPolicyRepoPath, err)
+	}
+
+	// The local controller checkout may be at another commit than the policy
+	restore, err := useControllerCommit(client, serviceName, base.Object.SHA)
+	if err != nil {
+		return nil, err
+	}
+	required, err := RequiredPolicyActions(serviceName)
+	restore()
+	if err != nil {
+		return nil, err
+	}
+	patch, err := PatchPolicyDocument(current, required)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch %s of %s: %w", recommendedPolicyRepoPath, repo, err)
+	}
+	result.Missing = patch.Missing
+	if len(patch.Missing) == 0 {
+		return result, nil
+	}
+
+	digest := sha256.Sum256([]byte(strings.Join(patch.Missing, ",")))
+	result.Branch = fmt.Sprintf("ack-api-extractor/%s-iam-%s", serviceName, hex.EncodeToString(digest[:])[:8])
+	if opts.DryRun {
+		return result, nil
+	}
+
+	// Pull requests are listed by <owner>:<branch> of the repository the branch lives in
+	headRepoPath, headOwner := repoPath, ackGitHubOrg
+	if opts.Fork != "" {
+		headRepoPath = githubRepoPath(opts.Fork, serviceName+"-controller")
+		headOwner = opts.Fork
+	}
+	headRef := headOwner + ":" + result.Branch
+
+	// An open pull request from the same branch means this change was already proposed
+	var pulls []githubPull
+	query := url.Values{"state": {"open"}, "head": {headRef}}
+	if err := client.do(http.MethodGet, repoPath+"/pulls?"+query.Encode(), nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) > 0 {
+		result.PullURL = pulls[0].HTMLURL
+		result.Existing = true
+		return result, nil
+	}
+
+	// The branch of a closed pull request for the same change is moved back to the base commit
+	err = client.do(http.MethodPost, headRepoPath+"/git/refs", map[string]string{
+		"ref": "refs/heads/" + result.Branch,
+		"sha": base.Object.SHA,
+	}, nil)
+	var apiErr *githubAPIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusUnprocessableEntity {
+		err = client.do(http.MethodPatch, headRepoPath+"/git/refs/heads/"+escapeRefPath(result.Branch), map[string]interface{}{
+			"sha":   base.Object.SHA,
+			"force": true,
+		}, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create branch %s: %w", result.Branch, err)
+	}
+
+	message := fmt.Sprintf("Add %d missing IAM action(s) to the recommended inline policy", len(patch.Missing))
+	if err := client.do(http.MethodPut, headRepoPath+"/contents/"+recommendedPolicyRepoPath, map[string]string{
+		"message": message,
+		"content": base64.StdEncoding.EncodeToString(patch.Document),
+		"sha":     file.SHA,
+		"branch":  result.Branch,
+	}, nil); err != nil {
+		return nil, fmt.Errorf("failed to commit %s: %w", recommendedPolicyRepoPath, err)
+	}
+
+	var pull githubPull
+	if err := client.do(http.MethodPost, repoPath+"/pulls", map[string]string{
+		"title": message,
+		"head":  headRef,
+		"base":  repository.DefaultBranch,
+		"body":  proposalBody(patch.Missing),
+	}, &pull); err != nil {
+		return nil, fmt.Errorf("failed to open pull request: %w", err)
+	}
+	result.PullURL = pull.HTMLURL
+	return result, nil
+}
+
+// githubRepoPath returns the API path of a repository
+func githubRepoPath(owner, name string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+}
+
+// proposalBody describes the actions added by a proposed policy update
+func proposalBody(missing []string) string {
+	var b strings.Builder
+	b.WriteString("The controller calls operations whose IAM actions are not granted by `" + recommendedPolicyRepoPath + "`.\n\n")
+	b.WriteString("Added actions:\n\n")
+	for _, action := range missing {
+		fmt.Fprintf(&b, "- `%s`\n", action)
+	}
+	b.WriteString("\nGenerated by ack-api-extractor.\n")
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package extractor_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+	"github.com/aws-controllers-k8s/ack-api-extractor/pkg/extractortest"
+)
+
+// redirectTransport sends every request to a test server instead of its host
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (r redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = r.target.Scheme, r.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// fakeGitHub serves handler in place of GitHub until the test ends
+func fakeGitHub(t *testing.T, handler http.HandlerFunc) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := http.DefaultClient.Transport
+	t.Cleanup(func() { http.DefaultClient.Transport = transport })
+	http.DefaultClient.Transport = redirectTransport{target: target}
+}
+
+// sourceTarball builds a gzipped source tarball wrapping the files of a repository in a directory,
+// like the ones GitHub serves
+func sourceTarball(t *testing.T, files fs.FS) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	err := fs.WalkDir(files, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{Name: "aws-controllers-k8s-foo-controller-0123abc/" + name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = archive.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProposePolicyUpdate(t *testing.T) {
+	const baseSHA = "0123abcd0123abcd0123abcd0123abcd0123abcd"
+	w := extractortest.NewWorkspace(t)
+	if err := extractor.SetCacheDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_TOKEN", "")
+	w.AddModel(t, extractortest.NewModel("foo").Operations("CreateBar", "DescribeBar", "DeleteBar"))
+	// The local checkout is behind the default branch, which also calls DescribeBar
+	w.AddController(t, "foo", extractortest.NewController().SDKCall("bar", "CreateBar").FS())
+	tarball := sourceTarball(t, extractortest.NewController().
+		SDKCall("bar", "CreateBar").
+		SDKCall("bar", "DescribeBar").FS())
+	policy := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["foo:CreateBar"], "Resource": "*"}]}`
+
+	var requests []string
+	var branchReset map[string]interface{}
+	var committed string
+	fakeGitHub(t, func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.RequestURI())
+		repo := "/repos/aws-controllers-k8s/foo-controller"
+		switch req.Method + " " + req.URL.Path {
+		case "GET " + repo:
+			rw.Write([]byte(`{"default_branch": "main"}`))
+		case "GET " + repo + "/git/ref/heads/main":
+			rw.Write([]byte(`{"object": {"sha": "` + baseSHA + `"}}`))
+		case "GET " + repo + "/contents/config/iam/recommended-inline-policy":
+			if req.URL.Query().Get("ref") != baseSHA {
+				http.NotFound(rw, req)
+				return
+			}
+			json.NewEncoder(rw).Encode(map[string]string{"sha": "blob", "content": base64.StdEncoding.EncodeToString([]byte(policy)), "encoding": "base64"})
+		case "GET " + repo + "/tarball/" + baseSHA:
+			rw.Write(tarball)
+		case "GET " + repo + "/pulls":
+			rw.Write([]byte(`[]`))
+		case "POST " + repo + "/git/refs":
+			// The branch of an earlier, closed pull request still exists
+			http.Error(rw, `{"message": "Reference already exists"}`, http.StatusUnprocessableEntity)
+		case "PATCH " + repo + "/git/refs/heads/ack-api-extractor/foo-iam-56ccc7eb":
+			json.NewDecoder(req.Body).Decode(&branchReset)
+			rw.Write([]byte(`{}`))
+		case "PUT " + repo + "/contents/config/iam/recommended-inline-policy":
+			var body map[string]string
+			json.NewDecoder(req.Body).Decode(&body)
+			content, _ := base64.StdEncoding.DecodeString(body["content"])
+			committed = string(content)
+			rw.Write([]byte(`{}`))
+		case "POST " + repo + "/pulls":
+			rw.Write([]byte(`{"html_url": "https://github.com/aws-controllers-k8s/foo-controller/pull/1"}`))
+		default:
+			http.NotFound(rw, req)
+		}
+	})
+
+	result, err := extractor.ProposePolicyUpdate("foo", extractor.ProposeOptions{Token: "token"})
+	if err != nil {
+		t.Fatalf("%v, requests %q", err, requests)
+	}
+	if want := []string{"foo:DescribeBar"}; !reflect.DeepEqual(result.Missing, want) {
+		t.Errorf("missing %v, want %v", result.Missing, want)
+	}
+	if result.Branch != "ack-api-extractor/foo-iam-56ccc7eb" || result.PullURL != "https://github.com/aws-controllers-k8s/foo-controller/pull/1" {
+		t.Errorf("branch %s and pull request %s", result.Branch, result.PullURL)
+	}
+	if want := map[string]interface{}{"sha": baseSHA, "force": true}; !reflect.DeepEqual(branchReset, want) {
+		t.Errorf("existing branch reset with %v, want %v", branchReset, want)
+	}
+	if !strings.Contains(committed, `"foo:CreateBar", "foo:DescribeBar"`) {
+		t.Errorf("committed policy %s lacks foo:DescribeBar", committed)
+	}
+	wantQuery := "GET /repos/aws-controllers-k8s/foo-controller/pulls?head=aws-controllers-k8s%3Aack-api-extractor%2Ffoo-iam-56ccc7eb&state=open"
+	found := false
+	for _, request := range requests {
+		found = found || request == wantQuery
+	}
+	if !found {
+		t.Errorf("requests %q lack %s", requests, wantQuery)
+	}
+	// The commit is only scanned for the proposal
+	if tag := extractor.ControllerReleaseTag("foo"); tag != "" {
+		t.Errorf("controller release %s still selected", tag)
+	}
+}
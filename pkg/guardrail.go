@@ -0,0 +1,57 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagentruntime/types"
+)
+
+// guardrailVersionPattern matches the versions a guardrail can be invoked with
+var guardrailVersionPattern = regexp.MustCompile(`^([1-9][0-9]{0,7}|DRAFT)$`)
+
+// guardrail is the Bedrock Guardrail attached to every classification agent invocation, nil for none
+var guardrail *types.GuardrailConfigurationWithArn
+
+// SetGuardrail attaches a Bedrock Guardrail, given by ID or ARN and a version number or DRAFT, to
+// every classification agent invocation. An empty identifier detaches it.
+func SetGuardrail(identifier, version string) error {
+	if identifier == "" {
+		if version != "" {
+			return fmt.Errorf("a guardrail version requires a guardrail ID")
+		}
+		guardrail = nil
+		return nil
+	}
+	if version == "" {
+		return fmt.Errorf("guardrail %s requires a version", identifier)
+	}
+	if !guardrailVersionPattern.MatchString(version) {
+		return fmt.Errorf("invalid version %q for guardrail %s, expected a version number or DRAFT", version, identifier)
+	}
+	guardrail = &types.GuardrailConfigurationWithArn{
+		GuardrailIdentifier: aws.String(identifier),
+		GuardrailVersion:    aws.String(version),
+	}
+	return nil
+}
+
+// GuardrailDescription returns the attached guardrail as <identifier>:<version>, empty for none
+func GuardrailDescription() string {
+	if guardrail == nil {
+		return ""
+	}
+	return aws.ToString(guardrail.GuardrailIdentifier) + ":" + aws.ToString(guardrail.GuardrailVersion)
+}
+
+// guardrailIntervened reports whether an agent trace event records the guardrail blocking or
+// masking the input or the response
+func guardrailIntervened(event types.InlineAgentResponseStream) bool {
+	trace, ok := event.(*types.InlineAgentResponseStreamMemberTrace)
+	if !ok {
+		return false
+	}
+	guardrailTrace, ok := trace.Value.Trace.(*types.TraceMemberGuardrailTrace)
+	return ok && guardrailTrace.Value.Action == types.GuardrailActionIntervened
+}
@@ -0,0 +1,66 @@
+package extractor
+
+// ImplementationKind tells whether a supported operation is wired up by generated or hand-written controller code
+type ImplementationKind string
+
+const (
+	// ImplementationGenerated marks an operation only referenced from generated code such as sdk.go
+	ImplementationGenerated ImplementationKind = "generated"
+	// ImplementationCustom marks an operation only referenced from hooks or other hand-written files
+	ImplementationCustom ImplementationKind = "custom"
+	// ImplementationMixed marks an operation referenced from both generated and hand-written code
+	ImplementationMixed ImplementationKind = "mixed"
+)
+
+// ApplyImplementationKinds sets the implementation kind of supported operations from the kinds of
+// the controller files they were found in: sdk.go and the resource manager are generated, hooks
+// and other files are hand-written
+func ApplyImplementationKinds(operations []Operation) {
+	for i := range operations {
+		if !operations[i].IsSupported() {
+			continue
+		}
+		locations := operations[i].Locations
+		if len(locations) == 0 {
+			locations = []Location{{File: operations[i].File, Line: operations[i].Line}}
+		}
+
+		generated, custom := false, false
+		for _, location := range locations {
+			if isCustomCodeFile(classifyControllerFile(location.File)) {
+				custom = true
+			} else {
+				generated = true
+			}
+		}
+		switch {
+		case generated && custom:
+			operations[i].Implementation = ImplementationMixed
+		case custom:
+			operations[i].Implementation = ImplementationCustom
+		default:
+			operations[i].Implementation = ImplementationGenerated
+		}
+	}
+}
+
+// CountImplementationKinds returns the number of supported operations per implementation kind
+func CountImplementationKinds(operations []Operation) map[ImplementationKind]int {
+	counts := make(map[ImplementationKind]int)
+	for _, op := range operations {
+		if op.Implementation != "" {
+			counts[op.Implementation]++
+		}
+	}
+	return counts
+}
+
+// CustomImplementationShare returns the percentage of supported operations that need hand-written
+// code, alone or on top of generated code, as a proxy for a controller's maintenance burden
+func CustomImplementationShare(counts map[ImplementationKind]int) float64 {
+	total := counts[ImplementationGenerated] + counts[ImplementationCustom] + counts[ImplementationMixed]
+	if total == 0 {
+		return 0
+	}
+	return float64(counts[ImplementationCustom]+counts[ImplementationMixed]) / float64(total) * 100
+}
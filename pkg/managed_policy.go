@@ -0,0 +1,155 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// ManagedPolicyARN returns the ARN of an AWS managed policy given its name, e.g.
+// AmazonDynamoDBFullAccess or service-role/AWSGlueServiceRole. ARNs are returned unchanged.
+func ManagedPolicyARN(name, partition string) string {
+	if strings.HasPrefix(name, "arn:") {
+		return name
+	}
+	return fmt.Sprintf("arn:%s:iam::aws:policy/%s", partition, strings.TrimPrefix(name, "/"))
+}
+
+// FetchManagedPolicy downloads the default version of a managed policy and returns its document
+// and version ID
+func FetchManagedPolicy(policyARN string) ([]byte, string, error) {
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := iam.NewFromConfig(cfg)
+
+	policy, err := client.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: aws.String(policyARN)})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get policy %s: %w", policyARN, err)
+	}
+	versionID := aws.ToString(policy.Policy.DefaultVersionId)
+
+	version, err := client.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyARN),
+		VersionId: aws.String(versionID),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get version %s of policy %s: %w", versionID, policyARN, err)
+	}
+
+	// IAM returns policy documents URL-encoded
+	document, err := url.QueryUnescape(aws.ToString(version.PolicyVersion.Document))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode policy document: %w", err)
+	}
+	return []byte(document), versionID, nil
+}
+
+// CompareManagedPolicy diffs a managed policy document against the actions a controller needs.
+// Wildcards on the service's own prefix are expanded against the actions of its model, so
+// dynamodb:* counts as every DynamoDB action; actions and wildcards on other services cannot be
+// expanded and are reported as they appear in the managed policy.
+func CompareManagedPolicy(serviceName string, operations []Operation, required []string, document []byte) (*ManagedPolicyComparison, error) {
+	var policy struct {
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal(document, &policy); err != nil {
+		return nil, fmt.Errorf("invalid policy JSON: %w", err)
+	}
+	statements, err := decodeStatements(policy.Statement)
+	if err != nil {
+		return nil, err
+	}
+
+	var granted []string
+	comparison := &ManagedPolicyComparison{ServiceName: serviceName, ExcessActions: []string{}}
+	for i, stmt := range statements {
+		if stmt.Effect != "Allow" {
+			continue
+		}
+		if len(stmt.NotAction) > 0 {
+			comparison.Notes = append(comparison.Notes, fmt.Sprintf("statement %d uses NotAction and grants every action not listed", i))
+		}
+		actions, err := decodeActions(stmt.Action)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i, err)
+		}
+		granted = append(granted, actions...)
+	}
+
+	prefix := IAMServicePrefix(serviceName) + ":"
+	var serviceActions []string
+	for action := range ServiceActions(serviceName, operations) {
+		serviceActions = append(serviceActions, action)
+	}
+	sort.Strings(serviceActions)
+
+	neededSet := make(map[string]bool)
+	for _, action := range required {
+		neededSet[strings.ToLower(action)] = true
+	}
+
+	grantedSet := make(map[string]bool)
+	otherSet := make(map[string]bool)
+	for _, pattern := range granted {
+		if !strings.HasPrefix(strings.ToLower(pattern), strings.ToLower(prefix)) {
+			otherSet[pattern] = true
+		}
+	}
+	for _, action := range serviceActions {
+		if actionAllowed(action, granted) {
+			grantedSet[action] = true
+			if !neededSet[strings.ToLower(action)] {
+				comparison.ExcessActions = append(comparison.ExcessActions, action)
+			}
+		}
+	}
+	for _, action := range required {
+		if !actionAllowed(action, granted) {
+			comparison.MissingActions = append(comparison.MissingActions, action)
+		}
+	}
+
+	for pattern := range otherSet {
+		// Patterns covering auxiliary actions the controller needs are still broader than necessary
+		comparison.OtherServiceActions = append(comparison.OtherServiceActions, pattern)
+	}
+	sort.Strings(comparison.OtherServiceActions)
+	sort.Strings(comparison.MissingActions)
+
+	comparison.RequiredActions = len(required)
+	comparison.GrantedActions = len(grantedSet)
+	if comparison.GrantedActions > 0 {
+		comparison.ExcessShare = float64(len(comparison.ExcessActions)) * 100 / float64(comparison.GrantedActions)
+	}
+	return comparison, nil
+}
+
+// policyStatement holds the statement fields read when comparing policies
+type policyStatement struct {
+	Effect    string          `json:"Effect"`
+	Action    json.RawMessage `json:"Action"`
+	NotAction json.RawMessage `json:"NotAction"`
+}
+
+// decodeStatements reads a Statement value, which is either a single statement or an array
+func decodeStatements(raw json.RawMessage) ([]policyStatement, error) {
+	var statements []policyStatement
+	if err := json.Unmarshal(raw, &statements); err == nil {
+		return statements, nil
+	}
+	var single policyStatement
+	if err := json.Unmarshal(raw, &single); err != nil {
+		return nil, fmt.Errorf("Statement must be an object or an array of objects")
+	}
+	return []policyStatement{single}, nil
+}
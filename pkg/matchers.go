@@ -0,0 +1,127 @@
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// operationPlaceholder is replaced by the quoted operation name in custom match patterns
+const operationPlaceholder = "{operation}"
+
+// MatcherConfig defines extra controller match patterns for a service
+type MatcherConfig struct {
+	// Patterns are regular expressions; {operation} is replaced by the operation name
+	Patterns []string `yaml:"patterns"`
+	// ReplaceDefault only counts lines matched by Patterns instead of every line referencing the operation name
+	ReplaceDefault bool `yaml:"replace_default"`
+}
+
+// matcherConfigs maps a service name to its custom match patterns
+var matcherConfigs map[string]MatcherConfig
+
+// LoadMatcherConfig reads a YAML file defining extra match patterns per service:
+//
+//	dynamodb:
+//	  patterns:
+//	    - 'svc\.{operation}\('
+//	    - 'c\.api\.{operation}WithContext'
+//	  replace_default: true
+func LoadMatcherConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read matcher config %s: %w", path, err)
+	}
+
+	var configs map[string]MatcherConfig
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("failed to parse matcher config %s: %w", path, err)
+	}
+
+	for serviceName, config := range configs {
+		if config.ReplaceDefault && len(config.Patterns) == 0 {
+			return fmt.Errorf("matcher config for %s sets replace_default without patterns", serviceName)
+		}
+		for _, pattern := range config.Patterns {
+			for _, replacement := range []string{"Operation", unmatchable} {
+				if _, err := regexp.Compile(strings.ReplaceAll(pattern, operationPlaceholder, replacement)); err != nil {
+					return fmt.Errorf("invalid pattern %q for %s: %w", pattern, serviceName, err)
+				}
+			}
+		}
+	}
+
+	matcherConfigs = configs
+	return nil
+}
+
+// unmatchable replaces the placeholder in a pattern to find lines it matches without the operation name
+const unmatchable = `[^\x00-\x{10FFFF}]`
+
+// customMatcher matches controller lines against the custom patterns of a service. Only the
+// patterns of operations whose name occurs in a line can match it, so each line is first run
+// through an automaton of the operation names instead of through every pattern of every operation.
+type customMatcher struct {
+	replaceDefault bool
+	// names finds the operations whose name occurs anywhere in a line
+	names *patternMatcher
+	// patterns holds the compiled patterns of each operation, indexed like the operation names
+	patterns [][]*regexp.Regexp
+	// nameless holds each pattern with the placeholder made unmatchable; a line it matches, e.g.
+	// one matched by a pattern without the placeholder, is checked against every operation
+	nameless []*regexp.Regexp
+}
+
+// newCustomMatcher compiles the custom patterns of a service for the given operations.
+// It returns nil when the service has no custom patterns.
+func newCustomMatcher(serviceName string, operationNames []string) *customMatcher {
+	config, ok := matcherConfigs[serviceName]
+	if !ok || len(config.Patterns) == 0 {
+		return nil
+	}
+
+	m := &customMatcher{
+		replaceDefault: config.ReplaceDefault,
+		names:          newPatternMatcher(operationNames),
+		patterns:       make([][]*regexp.Regexp, len(operationNames)),
+	}
+	for _, pattern := range config.Patterns {
+		// Patterns were validated when the config was loaded
+		m.nameless = append(m.nameless, regexp.MustCompile(strings.ReplaceAll(pattern, operationPlaceholder, unmatchable)))
+	}
+	for i, name := range operationNames {
+		for _, pattern := range config.Patterns {
+			expr := strings.ReplaceAll(pattern, operationPlaceholder, regexp.QuoteMeta(name))
+			m.patterns[i] = append(m.patterns[i], regexp.MustCompile(expr))
+		}
+	}
+	return m
+}
+
+// match calls found once for every operation index whose patterns match text
+func (m *customMatcher) match(text string, found func(pattern int)) {
+	for _, pattern := range m.nameless {
+		if pattern.MatchString(text) {
+			for i := range m.patterns {
+				m.matchOperation(i, text, found)
+			}
+			return
+		}
+	}
+	m.names.contains(text, func(i int) {
+		m.matchOperation(i, text, found)
+	})
+}
+
+// matchOperation calls found when one of the patterns of an operation matches text
+func (m *customMatcher) matchOperation(i int, text string, found func(pattern int)) {
+	for _, pattern := range m.patterns[i] {
+		if pattern.MatchString(text) {
+			found(i)
+			return
+		}
+	}
+}
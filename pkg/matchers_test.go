@@ -0,0 +1,49 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCustomMatcher(t *testing.T) {
+	t.Cleanup(ResetState)
+	path := filepath.Join(t.TempDir(), "matchers.yaml")
+	config := `foo:
+  patterns:
+    - 'svc\.{operation}\('
+    - 'legacy(?:{operation})?Call'
+  replace_default: true
+`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadMatcherConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{"CreateBar", "CreateBarAlias", "DeleteBar"}
+	m := newCustomMatcher("foo", names)
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"resp, err := svc.CreateBar(ctx, input)", []string{"CreateBar"}},
+		{"resp, err := svc.CreateBarAlias(ctx, input)", []string{"CreateBarAlias"}},
+		{"// CreateBar and DeleteBar are called elsewhere", nil},
+		// The second pattern matches without an operation name, so it matches every operation
+		{"legacyCall()", names},
+		{"legacyDeleteBarCall()", []string{"DeleteBar"}},
+	}
+	for _, test := range tests {
+		var got []string
+		m.match(test.line, func(i int) {
+			got = append(got, names[i])
+		})
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q matched %v, want %v", test.line, got, test.want)
+		}
+	}
+
+}
@@ -0,0 +1,187 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// charactersPerToken approximates the tokens of prompts and responses from their characters
+	charactersPerToken = 4
+	// classificationInputPrice and classificationOutputPrice are the on-demand prices in USD per
+	// million input and output tokens of the classification model
+	classificationInputPrice  = 3.0
+	classificationOutputPrice = 15.0
+)
+
+// bedrockStats counts Bedrock activity and cache hits across all extractions of the process
+var bedrockStats = struct {
+	sync.Mutex
+	BedrockMetrics
+	classificationCacheHits int
+	checkpointBatchHits     int
+	classificationErrors    []string
+	batchSeconds            []float64
+}{}
+
+// recordInvocation counts a Bedrock call attempt and its duration
+func recordInvocation(duration time.Duration, err error, throttled bool) {
+	bedrockStats.Lock()
+	defer bedrockStats.Unlock()
+	bedrockStats.Invocations++
+	bedrockStats.Seconds += duration.Seconds()
+	if throttled {
+		bedrockStats.Throttled++
+	} else if err != nil {
+		bedrockStats.Failures++
+	}
+}
+
+// recordPrompt counts a classification prompt sent to Bedrock
+func recordPrompt(characters int, followUp bool) {
+	bedrockStats.Lock()
+	defer bedrockStats.Unlock()
+	bedrockStats.PromptCharacters += characters
+	if followUp {
+		bedrockStats.FollowUpPrompts++
+	}
+}
+
+// recordBatch counts a batch Bedrock classified, the time it took including retries, the
+// characters of the response and the tokens used
+func recordBatch(duration time.Duration, responseCharacters int, usage tokenUsage) {
+	bedrockStats.Lock()
+	defer bedrockStats.Unlock()
+	bedrockStats.Batches++
+	bedrockStats.ResponseCharacters += responseCharacters
+	bedrockStats.InputTokens += usage.input
+	bedrockStats.OutputTokens += usage.output
+	bedrockStats.batchSeconds = append(bedrockStats.batchSeconds, duration.Seconds())
+}
+
+// ClassificationMetrics returns the Bedrock activity of the process so far with the batch latency
+// percentiles and the estimated spend, e.g. to show running totals while classifying
+func ClassificationMetrics() BedrockMetrics {
+	bedrockStats.Lock()
+	defer bedrockStats.Unlock()
+	return currentBedrockMetrics()
+}
+
+// currentBedrockMetrics fills in the derived Bedrock metrics; bedrockStats must be locked
+func currentBedrockMetrics() BedrockMetrics {
+	metrics := bedrockStats.BedrockMetrics
+	sorted := append([]float64(nil), bedrockStats.batchSeconds...)
+	sort.Float64s(sorted)
+	metrics.BatchSecondsP50 = percentile(sorted, 50)
+	metrics.BatchSecondsP95 = percentile(sorted, 95)
+	inputTokens := float64(metrics.PromptCharacters) / charactersPerToken
+	outputTokens := float64(metrics.ResponseCharacters) / charactersPerToken
+	if metrics.InputTokens+metrics.OutputTokens > 0 {
+		inputTokens, outputTokens = float64(metrics.InputTokens), float64(metrics.OutputTokens)
+	}
+	metrics.EstimatedCostUSD = (inputTokens*classificationInputPrice + outputTokens*classificationOutputPrice) / 1e6
+	return metrics
+}
+
+// percentile returns the nearest-rank percentile of sorted values, 0 when there are none
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// recordClassificationFailure counts a service whose classification failed
+func recordClassificationFailure(serviceName string, err error) {
+	bedrockStats.Lock()
+	defer bedrockStats.Unlock()
+	bedrockStats.ClassificationFailures++
+	bedrockStats.classificationErrors = append(bedrockStats.classificationErrors, fmt.Sprintf("%s: classification failed: %v", serviceName, err))
+}
+
+// recordCacheHits counts operations classified from the shared classification cache
+func recordCacheHits(count int) {
+	bedrockStats.Lock()
+	defer bedrockStats.Unlock()
+	bedrockStats.classificationCacheHits += count
+}
+
+// recordCheckpointBatchHit counts a classification batch reused from the checkpoint
+func recordCheckpointBatchHit() {
+	bedrockStats.Lock()
+	defer bedrockStats.Unlock()
+	bedrockStats.checkpointBatchHits++
+}
+
+// NewRunMetrics starts collecting the metrics of a run
+func NewRunMetrics(requested int) *RunMetrics {
+	return &RunMetrics{
+		SchemaVersion: SchemaVersion,
+		StartedAt:     time.Now().UTC(),
+		Counts:        RunCounts{Requested: requested},
+		Services:      []ServiceMetrics{},
+		Errors:        []string{},
+	}
+}
+
+// RecordService adds the metrics of a processed service and updates the run counts
+func (m *RunMetrics) RecordService(serviceName string, serviceOps *ServiceOperations, status string, elapsed time.Duration, err error) {
+	service := ServiceMetrics{Name: serviceName, Status: status, Seconds: elapsed.Seconds()}
+	if err != nil {
+		service.Error = err.Error()
+		m.Counts.Failed++
+		m.Errors = append(m.Errors, fmt.Sprintf("%s: %v", serviceName, err))
+	} else {
+		m.Counts.Succeeded++
+	}
+	if serviceOps != nil {
+		service.Operations = len(serviceOps.Operations)
+		service.SupportedOperations = serviceOps.SupportedOperations
+		service.ControlPlaneOperations = serviceOps.ControlPlaneOps
+		m.Counts.Operations += len(serviceOps.Operations)
+		if serviceOps.Timings != nil {
+			service.ModelParseSeconds = serviceOps.Timings.ModelParse.Seconds()
+			service.ControllerScanSeconds = serviceOps.Timings.ControllerScan.Seconds()
+			service.ClassificationSeconds = serviceOps.Timings.Classification.Seconds()
+		}
+	}
+	switch status {
+	case ServiceStatusReused:
+		m.Cache.UnchangedServices++
+	case ServiceStatusResumed:
+		m.Cache.ResumedServices++
+	}
+	m.Services = append(m.Services, service)
+}
+
+// RecordError adds an error that is not tied to a failed service, e.g. a failed policy write
+func (m *RunMetrics) RecordError(format string, args ...interface{}) {
+	m.Errors = append(m.Errors, fmt.Sprintf(format, args...))
+}
+
+// Finish stamps the end of the run and copies the Bedrock and cache counters
+func (m *RunMetrics) Finish() {
+	m.FinishedAt = time.Now().UTC()
+	m.DurationSeconds = m.FinishedAt.Sub(m.StartedAt).Seconds()
+
+	bedrockStats.Lock()
+	defer bedrockStats.Unlock()
+	m.Bedrock = currentBedrockMetrics()
+	m.Cache.ClassificationHits = bedrockStats.classificationCacheHits
+	m.Cache.CheckpointBatchHits = bedrockStats.checkpointBatchHits
+	m.Errors = append(m.Errors, bedrockStats.classificationErrors...)
+}
+
+// WriteRunMetricsJSON writes run metrics to a JSON file
+func WriteRunMetricsJSON(metrics *RunMetrics, outputPath string) error {
+	data, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run metrics: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
@@ -0,0 +1,87 @@
+package extractor
+
+import (
+	"sort"
+	"strings"
+)
+
+// modelOperationActions maps every operation of a model to its IAM action, <prefix>:<Operation>
+// with the prefix read from the model's SigV4 signing name or ARN namespace
+func modelOperationActions(modelName string) (map[string]string, error) {
+	model, err := loadServiceModel(modelName)
+	if err != nil {
+		return nil, err
+	}
+	prefix := ExtractServiceMetadata(model).IAMServicePrefix()
+	if prefix == "" {
+		prefix = strings.ToLower(modelName)
+	}
+	actions := make(map[string]string)
+	for _, name := range ResolveOperations(model).Operations {
+		actions[name] = prefix + ":" + name
+	}
+	return actions, nil
+}
+
+// ModelActions returns the IAM actions of every operation of a service's model and of its sub-API
+// models. They are read from the models rather than through the action mapping of the policy
+// generator, so generated policies can be checked against them.
+func ModelActions(serviceName string) (map[string]bool, error) {
+	actions := make(map[string]bool)
+	for _, modelName := range append([]string{serviceName}, SubAPIs(serviceName)...) {
+		operations, err := modelOperationActions(modelName)
+		if err != nil {
+			return nil, err
+		}
+		for _, action := range operations {
+			actions[action] = true
+		}
+	}
+	return actions, nil
+}
+
+// ExpectedPolicyActions returns the model actions of the operations a generated policy should grant,
+// each looked up in the model the operation comes from, and the actions the generator grants
+// instead for operations whose action differs from the model's or which are not in the model
+func ExpectedPolicyActions(serviceName string, operations []Operation) (expected []string, mismatches []string, err error) {
+	models := make(map[string]map[string]string)
+	for _, op := range operations {
+		if !policyOperation(op) {
+			continue
+		}
+		modelName := serviceName
+		if op.Model != "" {
+			modelName = op.Model
+		}
+		if models[modelName] == nil {
+			if models[modelName], err = modelOperationActions(modelName); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		generated := operationIAMAction(serviceName, op)
+		action, ok := models[modelName][op.Name]
+		if ok {
+			expected = append(expected, action)
+		}
+		if action != generated {
+			mismatches = append(mismatches, generated)
+		}
+	}
+	sort.Strings(expected)
+	sort.Strings(mismatches)
+	return expected, mismatches, nil
+}
+
+// PolicyKnownActions returns the actions a service's permission policy may grant: the actions of the
+// service's models and the actions of the other services its controller calls
+func PolicyKnownActions(serviceName string, serviceOps *ServiceOperations) (map[string]bool, error) {
+	actions, err := ModelActions(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range CrossServiceActions(serviceOps.UnmodeledCalls) {
+		actions[action] = true
+	}
+	return actions, nil
+}
@@ -0,0 +1,106 @@
+package extractor_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+	"github.com/aws-controllers-k8s/ack-api-extractor/pkg/extractortest"
+)
+
+func TestExpectedPolicyActions(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("sfn").SigningName("states").
+		Operations("CreateStateMachine", "DescribeStateMachine", "StartExecution"))
+	w.AddController(t, "sfn", extractortest.NewController().
+		SDKCall("state_machine", "CreateStateMachine").
+		SDKCall("state_machine", "DescribeStateMachine").FS())
+
+	serviceOps, err := extractor.ExtractDetailedOperationsFromService("sfn", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, mismatches, err := extractor.ExpectedPolicyActions("sfn", serviceOps.Operations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"states:CreateStateMachine", "states:DescribeStateMachine"}; !reflect.DeepEqual(expected, want) {
+		t.Errorf("expected = %v, want %v", expected, want)
+	}
+	if len(mismatches) > 0 {
+		t.Errorf("mismatches = %v, want none", mismatches)
+	}
+
+	// An operation the model doesn't define is granted by the generator but not expected
+	operations := append(serviceOps.Operations, extractor.Operation{Name: "DeleteEverything", SupportStatus: extractor.SupportImplemented})
+	expected, mismatches, err = extractor.ExpectedPolicyActions("sfn", operations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expected) != 2 {
+		t.Errorf("expected = %v, want the 2 model actions", expected)
+	}
+	if want := []string{"states:DeleteEverything"}; !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("mismatches = %v, want %v", mismatches, want)
+	}
+}
+
+func TestModelActions(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("logs").ARNNamespace("logs").Operations("CreateLogGroup", "PutLogEvents"))
+
+	actions, err := extractor.ModelActions("logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"logs:CreateLogGroup": true, "logs:PutLogEvents": true}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+}
+
+func TestLintPolicyUnknownActions(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("sfn").SigningName("states").Operations("CreateStateMachine", "DeleteStateMachine"))
+	w.AddController(t, "sfn", extractortest.NewController().SDKCall("state_machine", "CreateStateMachine").FS())
+
+	serviceOps, err := extractor.ExtractDetailedOperationsFromService("sfn", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	knownActions, err := extractor.PolicyKnownActions("sfn", serviceOps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		actions []string
+		unknown []string
+	}{
+		{name: "model actions", actions: []string{"states:CreateStateMachine", "states:DeleteStateMachine"}},
+		{name: "wildcard", actions: []string{"states:Describe*"}},
+		{name: "action missing from the model", actions: []string{"states:CreateStateMachine", "states:CreateActivity"}, unknown: []string{"states:CreateActivity"}},
+		{name: "wrong service prefix", actions: []string{"sfn:CreateStateMachine"}, unknown: []string{"sfn:CreateStateMachine"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := extractor.IAMPolicy{Version: "2012-10-17", Statement: []extractor.PolicyStatement{
+				{Effect: "Allow", Action: test.actions, Resource: "*"},
+			}}
+			var unknown []string
+			for _, finding := range extractor.LintPolicy(policy, knownActions) {
+				if finding.Rule == "unknown-action" {
+					if finding.Severity != extractor.SeverityError {
+						t.Errorf("unknown-action finding has severity %s, want error", finding.Severity)
+					}
+					unknown = append(unknown, strings.Fields(finding.Message)[1])
+				}
+			}
+			if !reflect.DeepEqual(unknown, test.unknown) {
+				t.Errorf("unknown actions = %v, want %v", unknown, test.unknown)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package extractor
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// LoadParsedModel reads the model of a service from the models directory and model sources
+// and parses it. Nothing is written and no controller is scanned.
+func LoadParsedModel(serviceName string) (*ParsedModel, error) {
+	model, err := loadServiceModel(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return NewParsedModel(model), nil
+}
+
+// ParseModel parses a Smithy JSON AST model, for callers that read models themselves
+func ParseModel(data []byte) (*ParsedModel, error) {
+	model, err := parseServiceModel(data, "model")
+	if err != nil {
+		return nil, err
+	}
+	return NewParsedModel(model), nil
+}
+
+// NewParsedModel resolves the service, operations and traits of a decoded model with the same
+// implementation the extractor uses, so both tools agree on what a service's operations are
+func NewParsedModel(model *AWSServiceModel) *ParsedModel {
+	parsed := &ParsedModel{Metadata: ExtractServiceMetadata(model), Shapes: model.Shapes}
+	for id, shape := range model.Shapes {
+		if shape.Type == "service" {
+			parsed.ServiceID = id
+			parsed.ServiceTraits = shape.Traits
+			break
+		}
+	}
+
+	shapeIDs := make(map[string]string)
+	for id, shape := range model.Shapes {
+		if shape.Type == "operation" {
+			shapeIDs[extractOperationName(id)] = id
+		}
+	}
+	releases := ExtractReleaseStages(model)
+	waiters := ExtractWaiters(model)
+
+	// Operations bound to the service without an operation shape have nothing to describe
+	for _, name := range ResolveOperations(model).Operations {
+		id, ok := shapeIDs[name]
+		if !ok {
+			continue
+		}
+		shape := model.Shapes[id]
+		operation := ModelOperation{
+			Name:          name,
+			ShapeID:       id,
+			Documentation: stringTrait(shape.Traits, documentationTrait),
+			Release:       releases[name],
+			Waiters:       waiters[name],
+			Traits:        shape.Traits,
+		}
+		if shape.Input != nil {
+			operation.Input = shape.Input.Target
+		}
+		if shape.Output != nil {
+			operation.Output = shape.Output.Target
+		}
+		for _, ref := range shape.Errors {
+			operation.Errors = append(operation.Errors, ref.Target)
+		}
+		var http httpTraitValue
+		if raw, ok := shape.Traits[httpTrait]; ok && json.Unmarshal(raw, &http) == nil {
+			operation.HTTP = &HTTPBinding{Method: http.Method, URI: http.URI, Code: http.Code}
+		}
+		parsed.Operations = append(parsed.Operations, operation)
+	}
+	return parsed
+}
+
+// Operation returns the operation with the given name
+func (m *ParsedModel) Operation(name string) (ModelOperation, bool) {
+	for _, operation := range m.Operations {
+		if operation.Name == name {
+			return operation, true
+		}
+	}
+	return ModelOperation{}, false
+}
+
+// Shape returns a shape by absolute shape ID, or by name relative to the namespace of the
+// service shape, e.g. "Bucket" for com.amazonaws.s3#Bucket
+func (m *ParsedModel) Shape(id string) (ServiceShape, bool) {
+	if shape, ok := m.Shapes[id]; ok {
+		return shape, true
+	}
+	namespace, _, ok := strings.Cut(m.ServiceID, "#")
+	if !ok || strings.Contains(id, "#") {
+		return ServiceShape{}, false
+	}
+	shape, ok := m.Shapes[namespace+"#"+id]
+	return shape, ok
+}
@@ -0,0 +1,112 @@
+package extractor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// smithyPreludePrefix is the namespace of shapes built into Smithy, which are never defined in models
+const smithyPreludePrefix = "smithy.api#"
+
+// LintServiceModel loads the model of a service and reports anomalies in it
+func LintServiceModel(serviceName string) ([]ModelDiagnostic, error) {
+	model, err := loadServiceModel(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return LintModel(model), nil
+}
+
+// LintModel reports anomalies in a parsed model that can cause silent partial extraction:
+// a missing or duplicated service shape, operations without input or output, references
+// to shapes that are not defined, and operations only one resolution source finds.
+func LintModel(model *AWSServiceModel) []ModelDiagnostic {
+	var diagnostics []ModelDiagnostic
+	add := func(severity Severity, rule, shape, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, ModelDiagnostic{
+			Severity: severity,
+			Rule:     rule,
+			Shape:    shape,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	shapeNames := make([]string, 0, len(model.Shapes))
+	for name := range model.Shapes {
+		shapeNames = append(shapeNames, name)
+	}
+	sort.Strings(shapeNames)
+
+	var services []string
+	for _, name := range shapeNames {
+		shape := model.Shapes[name]
+		if shape.Type != "service" {
+			continue
+		}
+		services = append(services, name)
+		if len(shape.Operations) == 0 {
+			add(SeverityWarning, "service-without-operations", name, "service shape lists no operations")
+		}
+	}
+
+	switch len(services) {
+	case 0:
+		add(SeverityError, "missing-service", "", "model has no shape of type \"service\"")
+	case 1:
+	default:
+		add(SeverityWarning, "multiple-services", "", "model has %d service shapes: %s", len(services), strings.Join(services, ", "))
+	}
+
+	checkReference := func(from, role string, ref *ShapeReference) {
+		if ref == nil || ref.Target == "" || strings.HasPrefix(ref.Target, smithyPreludePrefix) {
+			return
+		}
+		if _, ok := model.Shapes[ref.Target]; !ok {
+			add(SeverityError, "missing-shape", from, "%s references undefined shape %s", role, ref.Target)
+		}
+	}
+
+	for _, name := range shapeNames {
+		shape := model.Shapes[name]
+
+		if shape.Type == "service" {
+			for _, op := range shape.Operations {
+				checkReference(name, "operation", &ShapeReference{Target: op.Target})
+			}
+		}
+
+		if shape.Type == "operation" {
+			if shape.Input == nil {
+				add(SeverityInfo, "operation-without-input", name, "operation has no input shape")
+			}
+			if shape.Output == nil {
+				add(SeverityInfo, "operation-without-output", name, "operation has no output shape")
+			}
+		}
+
+		checkReference(name, "input", shape.Input)
+		checkReference(name, "output", shape.Output)
+		checkReference(name, "member", shape.Member)
+		checkReference(name, "key", shape.Key)
+		checkReference(name, "value", shape.Value)
+		for i := range shape.Errors {
+			checkReference(name, "error", &shape.Errors[i])
+		}
+		memberNames := make([]string, 0, len(shape.Members))
+		for memberName := range shape.Members {
+			memberNames = append(memberNames, memberName)
+		}
+		sort.Strings(memberNames)
+		for _, memberName := range memberNames {
+			ref := shape.Members[memberName]
+			checkReference(name, "member "+memberName, &ref)
+		}
+	}
+
+	for _, discrepancy := range ResolveOperations(model).Discrepancies {
+		add(SeverityWarning, "resolution-discrepancy", discrepancy.Operation, "found only via %s: %s", discrepancy.FoundIn, discrepancy.Message)
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,280 @@
+package extractor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// modelSourceHTTPTimeout bounds fetching a model from a remote source
+const modelSourceHTTPTimeout = 30 * time.Second
+
+// modelSources are additional model sources consulted after the models directory, in precedence order
+var modelSources []string
+
+// errModelNotInSource reports that a model source doesn't have a model for the service
+var errModelNotInSource = errors.New("service not found in model source")
+
+// SetModelSources sets additional model sources consulted after the models directory, highest
+// precedence first. A source is either a directory laid out like api-models-aws/models, an
+// http(s) URL serving <url>/<service>.json, or go-sdk for the aws-sdk-go-v2 packages controllers
+// build against. Relative directories are resolved against the current working directory.
+func SetModelSources(sources []string) error {
+	resolved := make([]string, 0, len(sources))
+	for _, source := range sources {
+		if source == "" {
+			continue
+		}
+		if source == GoSDKModelSource {
+			resolved = append(resolved, source)
+			continue
+		}
+		if isRemoteModelSource(source) {
+			resolved = append(resolved, strings.TrimSuffix(source, "/"))
+			continue
+		}
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return fmt.Errorf("failed to resolve model source %s: %w", source, err)
+		}
+		resolved = append(resolved, abs)
+	}
+	modelSources = resolved
+	return nil
+}
+
+// ModelSources returns all model sources in precedence order, starting with the models directory.
+// When the models directory doesn't exist, go-sdk is consulted last even if not configured.
+func ModelSources() []string {
+	sources := append([]string{modelsDir()}, modelSources...)
+	if info, err := os.Stat(modelsDir()); (err != nil || !info.IsDir()) && !slices.Contains(modelSources, GoSDKModelSource) {
+		sources = append(sources, GoSDKModelSource)
+	}
+	return sources
+}
+
+// mergesModelSources reports whether models are read from other sources than the models directory
+func mergesModelSources() bool {
+	return len(ModelSources()) > 1
+}
+
+// isRemoteModelSource reports whether a model source is fetched over HTTP
+func isRemoteModelSource(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// readSourceModel reads the raw model of a service from a single source and returns where it was
+// read from. errModelNotInSource is returned when the source doesn't have the service.
+func readSourceModel(source, serviceName string) ([]byte, string, error) {
+	if source == GoSDKModelSource {
+		return readGoSDKModel(serviceName)
+	}
+	if isRemoteModelSource(source) {
+		return fetchRemoteModel(source, serviceName)
+	}
+
+	jsonFile, err := findModelJSONFileIn(source, serviceName)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", errModelNotInSource, err)
+	}
+	data, err := os.ReadFile(jsonFile)
+	if err != nil {
+		return nil, jsonFile, fmt.Errorf("failed to read JSON file %s: %w", jsonFile, err)
+	}
+	return data, jsonFile, nil
+}
+
+// fetchRemoteModel downloads <source>/<service>.json
+func fetchRemoteModel(source, serviceName string) ([]byte, string, error) {
+	endpoint := fmt.Sprintf("%s/%s.json", source, serviceName)
+
+	status, data, err := newGitHubClient("").fetch(http.MethodGet, endpoint, nil, modelSourceHTTPTimeout)
+	if err != nil {
+		return nil, endpoint, fmt.Errorf("failed to fetch %s: %w", endpoint, err)
+	}
+	if status == http.StatusNotFound {
+		return nil, endpoint, errModelNotInSource
+	}
+	if status != http.StatusOK {
+		return nil, endpoint, fmt.Errorf("fetching %s returned %d %s", endpoint, status, http.StatusText(status))
+	}
+	return data, endpoint, nil
+}
+
+// loadMergedServiceModel loads a service from every model source. The model of the highest
+// precedence source having the service is the base; operations only other sources define are
+// merged into it together with the shapes they reference. Sources failing for other reasons than
+// a missing service are skipped with a warning.
+func loadMergedServiceModel(serviceName string) (*AWSServiceModel, error) {
+	var merged *AWSServiceModel
+	for _, source := range ModelSources() {
+		data, location, err := readSourceModel(source, serviceName)
+		if errors.Is(err, errModelNotInSource) {
+			continue
+		}
+		if err != nil {
+			reportWarning(serviceName, "skipping model source %s: %v", source, err)
+			continue
+		}
+
+		model, err := parseServiceModel(data, location)
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = model
+			merged.operationSources = make(map[string]string)
+			for name := range operationShapes(model) {
+				merged.operationSources[name] = source
+			}
+			continue
+		}
+		mergeServiceModel(merged, model, source)
+	}
+
+	if merged == nil {
+		return nil, CategorizeError(ErrorCategoryModelNotFound, fmt.Errorf("service %s not found in any model source (%s)", serviceName, strings.Join(ModelSources(), ", ")))
+	}
+	return merged, nil
+}
+
+// mergeServiceModel adds the operations of model that base doesn't define, binding them to the
+// base service shape. Shapes base lacks are copied so their inputs and outputs resolve.
+func mergeServiceModel(base, model *AWSServiceModel, source string) {
+	known := operationShapes(base)
+	var added []string
+	for id, shape := range model.Shapes {
+		if shape.Type == "operation" && known[extractOperationName(id)].Type == "" {
+			added = append(added, id)
+			base.operationSources[extractOperationName(id)] = source
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	for id, shape := range model.Shapes {
+		if _, ok := base.Shapes[id]; !ok && shape.Type != "service" {
+			base.Shapes[id] = shape
+		}
+	}
+
+	sort.Strings(added)
+	for id, shape := range base.Shapes {
+		if shape.Type != "service" {
+			continue
+		}
+		for _, target := range added {
+			shape.Operations = append(shape.Operations, OperationTarget{Target: target})
+		}
+		base.Shapes[id] = shape
+		break
+	}
+}
+
+// ApplyModelSources records the model source each operation came from and returns the number of
+// operations per source. Nothing is recorded when only the models directory is configured.
+func ApplyModelSources(operations []Operation, model *AWSServiceModel) map[string]int {
+	if len(model.operationSources) == 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for i := range operations {
+		if source, ok := model.operationSources[operations[i].Name]; ok {
+			operations[i].ModelSource = source
+			counts[source]++
+		}
+	}
+	return counts
+}
+
+// CountModelSources returns the number of operations per model source, nil when no operation
+// records one
+func CountModelSources(operations []Operation) map[string]int {
+	var counts map[string]int
+	for _, op := range operations {
+		if op.ModelSource == "" {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[op.ModelSource]++
+	}
+	return counts
+}
+
+// hashServiceModels writes the models of a service from every source that has it to w
+func hashServiceModels(w io.Writer, serviceName string) error {
+	if !mergesModelSources() {
+		modelFile, err := findServiceModelJSONFile(serviceName)
+		if err != nil {
+			return err
+		}
+		return hashFile(w, modelFile, "model")
+	}
+
+	found := false
+	for _, source := range ModelSources() {
+		data, location, err := readSourceModel(source, serviceName)
+		if errors.Is(err, errModelNotInSource) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "model:%s\x00", location)
+		w.Write(data)
+		w.Write([]byte{0})
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("service %s not found in any model source", serviceName)
+	}
+	return nil
+}
+
+// listSourceServices returns the services of all local model sources, deduplicated and sorted.
+// The services of go-sdk are those with a controller.
+func listSourceServices() ([]string, error) {
+	seen := make(map[string]bool)
+	var services []string
+	add := func(serviceName string) {
+		if !seen[serviceName] {
+			seen[serviceName] = true
+			services = append(services, serviceName)
+		}
+	}
+	sources := ModelSources()
+	for i, source := range sources {
+		if source == GoSDKModelSource {
+			controllers, _ := ListControllers()
+			for _, serviceName := range controllers {
+				add(serviceName)
+			}
+			continue
+		}
+		if isRemoteModelSource(source) {
+			continue
+		}
+		sourceServices, err := listModelSourceServices(source)
+		if err != nil {
+			if i == 0 && !slices.Contains(sources, GoSDKModelSource) {
+				return nil, err
+			}
+			continue
+		}
+		for _, serviceName := range sourceServices {
+			add(serviceName)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
@@ -0,0 +1,72 @@
+package extractor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FindNameCollisions returns the operation names extracted from more than one service, sorted by
+// name, with the services and the IAM action each of them maps the operation to. Combined
+// artifacts keep such operations apart by service instead of letting one replace the other.
+func FindNameCollisions(services map[string]*ServiceOperations) []NameCollision {
+	names := make([]string, 0, len(services))
+	for serviceName := range services {
+		names = append(names, serviceName)
+	}
+	sort.Strings(names)
+
+	byOperation := make(map[string]*NameCollision)
+	for _, serviceName := range names {
+		seen := make(map[string]bool)
+		for _, op := range services[serviceName].Operations {
+			if seen[op.Name] {
+				continue
+			}
+			seen[op.Name] = true
+			collision := byOperation[op.Name]
+			if collision == nil {
+				collision = &NameCollision{Operation: op.Name}
+				byOperation[op.Name] = collision
+			}
+			action := operationIAMAction(serviceName, op)
+			if len(collision.Actions) > 0 && !strings.EqualFold(collision.Actions[0], action) {
+				collision.DistinctActions = true
+			}
+			collision.Services = append(collision.Services, serviceName)
+			collision.Actions = append(collision.Actions, action)
+		}
+	}
+
+	var collisions []NameCollision
+	for _, collision := range byOperation {
+		if len(collision.Services) > 1 {
+			collisions = append(collisions, *collision)
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].Operation < collisions[j].Operation })
+	return collisions
+}
+
+// DescribeNameCollisions summarizes collisions for a warning, naming at most limit of them, e.g.
+// "TagResource (dynamodb, s3), UntagResource (dynamodb, s3) and 3 more"
+func DescribeNameCollisions(collisions []NameCollision, limit int) string {
+	var described []string
+	for i, collision := range collisions {
+		if i == limit {
+			return fmt.Sprintf("%s and %d more", strings.Join(described, ", "), len(collisions)-limit)
+		}
+		described = append(described, fmt.Sprintf("%s (%s)", collision.Operation, strings.Join(collision.Services, ", ")))
+	}
+	return strings.Join(described, ", ")
+}
+
+// uniqueSid returns sid, or sid with the lowest numeric suffix from 2 not in used, and marks it used
+func uniqueSid(used map[string]bool, sid string) string {
+	unique := sid
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", sid, i)
+	}
+	used[unique] = true
+	return unique
+}
@@ -0,0 +1,142 @@
+package extractor
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ACK code generator operation types
+const (
+	ACKOpCreate        = "Create"
+	ACKOpReadOne       = "ReadOne"
+	ACKOpReadMany      = "ReadMany"
+	ACKOpUpdate        = "Update"
+	ACKOpDelete        = "Delete"
+	ACKOpGetAttributes = "GetAttributes"
+	ACKOpSetAttributes = "SetAttributes"
+)
+
+// verbPattern matches the leading verb of an operation name, e.g. Create in CreateDBInstance
+var verbPattern = regexp.MustCompile(`^[A-Z][a-z]+`)
+
+// AuditServiceNaming checks the operations of a service against ACK code generator naming conventions
+func AuditServiceNaming(serviceName string) (*NamingAudit, error) {
+	model, err := loadServiceModel(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	generatorConfig, _ := LoadControllerGeneratorConfig(serviceName)
+	return AuditNaming(serviceName, ResolveOperations(model).Operations, generatorConfig), nil
+}
+
+// AuditNaming maps operation names to the ACK operation types the code generator would infer
+// (Create/ReadOne/ReadMany/Update/Delete/GetAttributes/SetAttributes) and reports operations
+// with nonstandard verbs such as Register* or Provision* that need generator.yaml overrides,
+// as well as resources whose Create operation lacks the matching read or delete operation.
+// generatorConfig may be nil; when set, operation overrides and ignores are taken into account.
+func AuditNaming(serviceName string, operationNames []string, generatorConfig *GeneratorConfig) *NamingAudit {
+	audit := &NamingAudit{ServiceName: serviceName}
+	lifecycles := make(map[string]*ResourceLifecycle)
+
+	for _, name := range operationNames {
+		naming := classifyOperationName(name)
+		if generatorConfig != nil {
+			if override, ok := generatorConfig.Operations[name]; ok && len(override.OperationType) > 0 {
+				naming.Configured = true
+				naming.OperationType = override.OperationType[0]
+				if len(override.ResourceName) > 0 {
+					naming.Resource = override.ResourceName[0]
+				}
+			}
+			naming.Ignored = generatorConfig.IsOperationIgnored(name)
+		}
+		audit.Operations = append(audit.Operations, naming)
+
+		if !naming.Standard && !naming.Configured && !naming.Ignored {
+			audit.NonStandard++
+		}
+		if naming.OperationType == ACKOpCreate && !naming.Ignored {
+			lifecycles[naming.Resource] = &ResourceLifecycle{Resource: naming.Resource, Operations: make(map[string]string)}
+		}
+	}
+
+	for _, naming := range audit.Operations {
+		if lifecycle, ok := lifecycles[naming.Resource]; ok && naming.OperationType != "" && !naming.Ignored {
+			lifecycle.Operations[naming.OperationType] = naming.Operation
+		}
+	}
+
+	for _, lifecycle := range lifecycles {
+		if lifecycle.Operations[ACKOpReadOne] == "" && lifecycle.Operations[ACKOpReadMany] == "" && lifecycle.Operations[ACKOpGetAttributes] == "" {
+			lifecycle.Missing = append(lifecycle.Missing, ACKOpReadOne)
+		}
+		if lifecycle.Operations[ACKOpDelete] == "" {
+			lifecycle.Missing = append(lifecycle.Missing, ACKOpDelete)
+		}
+		if len(lifecycle.Missing) > 0 {
+			audit.IncompleteResources++
+		}
+		audit.CustomizationEstimate += len(lifecycle.Missing)
+		audit.Resources = append(audit.Resources, *lifecycle)
+	}
+	sort.Slice(audit.Resources, func(i, j int) bool { return audit.Resources[i].Resource < audit.Resources[j].Resource })
+	audit.CustomizationEstimate += audit.NonStandard
+
+	return audit
+}
+
+// classifyOperationName infers the ACK operation type and resource of an operation from its name
+func classifyOperationName(name string) OperationNaming {
+	verb := verbPattern.FindString(name)
+	resource := strings.TrimPrefix(name, verb)
+	naming := OperationNaming{Operation: name, Verb: verb, Resource: resource, Standard: true}
+
+	switch verb {
+	case "Create":
+		naming.OperationType = ACKOpCreate
+	case "Delete":
+		naming.OperationType = ACKOpDelete
+	case "Update", "Modify":
+		naming.OperationType = ACKOpUpdate
+	case "List":
+		naming.OperationType = ACKOpReadMany
+		naming.Resource = singularize(resource)
+	case "Describe", "Get":
+		switch {
+		case strings.HasSuffix(resource, "Attributes"):
+			naming.OperationType = ACKOpGetAttributes
+			naming.Resource = strings.TrimSuffix(resource, "Attributes")
+		case singularize(resource) != resource:
+			naming.OperationType = ACKOpReadMany
+			naming.Resource = singularize(resource)
+		default:
+			naming.OperationType = ACKOpReadOne
+		}
+	case "Set":
+		if strings.HasSuffix(resource, "Attributes") {
+			naming.OperationType = ACKOpSetAttributes
+			naming.Resource = strings.TrimSuffix(resource, "Attributes")
+		} else {
+			naming.Standard = false
+		}
+	default:
+		naming.Standard = false
+	}
+	return naming
+}
+
+// singularize returns the singular form of a plural resource name, or the name unchanged
+func singularize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ss"), strings.HasSuffix(name, "us"), strings.HasSuffix(name, "is"):
+		return name
+	case strings.HasSuffix(name, "s"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
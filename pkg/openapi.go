@@ -0,0 +1,331 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	httpTrait          = "smithy.api#http"
+	httpLabelTrait     = "smithy.api#httpLabel"
+	httpQueryTrait     = "smithy.api#httpQuery"
+	httpHeaderTrait    = "smithy.api#httpHeader"
+	httpPayloadTrait   = "smithy.api#httpPayload"
+	documentationTrait = "smithy.api#documentation"
+	titleTrait         = "smithy.api#title"
+	legacyEnumTrait    = "smithy.api#enum"
+
+	// openAPIVersion is the OpenAPI specification version of generated documents
+	openAPIVersion = "3.1.0"
+)
+
+// httpTraitValue represents the smithy.api#http trait
+type httpTraitValue struct {
+	Method string `json:"method"`
+	URI    string `json:"uri"`
+	Code   int    `json:"code"`
+}
+
+// simpleTypeSchemas maps the types of simple shapes, and of the Smithy prelude shapes, to JSON Schema types
+var simpleTypeSchemas = map[string]map[string]interface{}{
+	"string":     {"type": "string"},
+	"boolean":    {"type": "boolean"},
+	"byte":       {"type": "integer"},
+	"short":      {"type": "integer"},
+	"integer":    {"type": "integer", "format": "int32"},
+	"long":       {"type": "integer", "format": "int64"},
+	"float":      {"type": "number", "format": "float"},
+	"double":     {"type": "number", "format": "double"},
+	"bigInteger": {"type": "integer"},
+	"bigDecimal": {"type": "number"},
+	"timestamp":  {"type": "string", "format": "date-time"},
+	"blob":       {"type": "string", "contentEncoding": "base64"},
+	"document":   {},
+	"structure":  {"type": "object"},
+}
+
+// GenerateServiceOpenAPI loads the model of a service and renders the given operations as an
+// OpenAPI 3.1 document. Operations with a smithy.api#http trait use its method, URI and member
+// bindings; operations of RPC protocols without one are rendered as POST /<OperationName>.
+// The ACK support status and classification of each operation are recorded as x-ack-* extensions.
+func GenerateServiceOpenAPI(serviceName string, operations []Operation) (map[string]interface{}, error) {
+	model, err := loadServiceModel(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return renderOpenAPI(serviceName, model, operations)
+}
+
+// renderOpenAPI renders the operations of a service model as an OpenAPI 3.1 document, failing when two
+// operations are bound to the same method and path
+func renderOpenAPI(serviceName string, model *AWSServiceModel, operations []Operation) (map[string]interface{}, error) {
+	targets := make(map[string]string)
+	title, description := serviceName, ""
+	for shapeName, shape := range model.Shapes {
+		switch shape.Type {
+		case "operation":
+			targets[extractOperationName(shapeName)] = shapeName
+		case "service":
+			if value := stringTrait(shape.Traits, titleTrait); value != "" {
+				title = value
+			}
+			description = stringTrait(shape.Traits, documentationTrait)
+		}
+	}
+
+	g := &openAPIGenerator{model: model, schemas: make(map[string]interface{})}
+	var routes []openAPIRoute
+	bindings := make(map[string]int)
+	for _, op := range operations {
+		target, ok := targets[op.Name]
+		if !ok {
+			continue
+		}
+		route := g.operation(op, model.Shapes[target])
+		routes = append(routes, route)
+		bindings[route.method+" "+route.path]++
+	}
+
+	// Operations sharing a method and path, e.g. the S3 bucket subresources told apart by a query
+	// literal (PUT /{Bucket}?tagging), keep the literal in their path so none is overwritten
+	paths := make(map[string]map[string]interface{})
+	for _, route := range routes {
+		path := route.path
+		if bindings[route.method+" "+path] > 1 && route.query != "" {
+			path += "?" + route.query
+		}
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+		if existing, ok := paths[path][route.method]; ok {
+			return nil, fmt.Errorf("operations %s and %s are both bound to %s %s", existing.(map[string]interface{})["operationId"],
+				route.operation["operationId"], strings.ToUpper(route.method), path)
+		}
+		paths[path][route.method] = route.operation
+	}
+
+	info := map[string]interface{}{"title": title, "version": serviceVersion(model)}
+	if description != "" {
+		info["description"] = description
+	}
+	return map[string]interface{}{
+		"openapi":    openAPIVersion,
+		"info":       info,
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": g.schemas},
+	}, nil
+}
+
+// WriteOpenAPIJSON writes an OpenAPI document to a JSON file
+func WriteOpenAPIJSON(document map[string]interface{}, outputPath string) error {
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal OpenAPI JSON: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
+
+// openAPIGenerator converts model shapes into OpenAPI operations and component schemas
+type openAPIGenerator struct {
+	model   *AWSServiceModel
+	schemas map[string]interface{}
+}
+
+// openAPIRoute is a rendered operation with its lowercase HTTP method, path and the query literals
+// of its URI, e.g. tagging for /{Bucket}?tagging
+type openAPIRoute struct {
+	method    string
+	path      string
+	query     string
+	operation map[string]interface{}
+}
+
+// operation renders a single operation and returns it with its HTTP binding
+func (g *openAPIGenerator) operation(op Operation, shape ServiceShape) openAPIRoute {
+	binding := httpTraitValue{Method: "POST", URI: "/" + op.Name, Code: 200}
+	if raw, ok := shape.Traits[httpTrait]; ok {
+		json.Unmarshal(raw, &binding)
+		if binding.Code == 0 {
+			binding.Code = 200
+		}
+	}
+	// Query literals in the URI (e.g. /{Bucket}?tagging) are not part of OpenAPI paths
+	uri, query, _ := strings.Cut(binding.URI, "?")
+	uri = strings.ReplaceAll(uri, "+}", "}")
+
+	operation := map[string]interface{}{
+		"operationId":          op.Name,
+		"x-ack-support-status": op.SupportStatus.String(),
+		"x-ack-operation-type": op.Type.String(),
+	}
+	if doc := stringTrait(shape.Traits, documentationTrait); doc != "" {
+		operation["description"] = doc
+	}
+
+	var parameters []interface{}
+	if shape.Input != nil {
+		input := g.model.Shapes[shape.Input.Target]
+		body := map[string]interface{}{}
+		var required []string
+		for _, name := range sortedMemberNames(input) {
+			member := input.Members[name]
+			schema := g.schema(member.Target)
+			_, isRequired := member.Traits[requiredTrait]
+
+			switch {
+			case member.Traits[httpLabelTrait] != nil:
+				parameters = append(parameters, map[string]interface{}{"name": name, "in": "path", "required": true, "schema": schema})
+			case member.Traits[httpQueryTrait] != nil:
+				parameters = append(parameters, map[string]interface{}{"name": stringTrait(member.Traits, httpQueryTrait), "in": "query", "required": isRequired, "schema": schema})
+			case member.Traits[httpHeaderTrait] != nil:
+				parameters = append(parameters, map[string]interface{}{"name": stringTrait(member.Traits, httpHeaderTrait), "in": "header", "required": isRequired, "schema": schema})
+			case member.Traits[httpPayloadTrait] != nil:
+				operation["requestBody"] = jsonContent(schema, "required", isRequired)
+			default:
+				body[name] = schema
+				if isRequired {
+					required = append(required, name)
+				}
+			}
+		}
+		if _, ok := operation["requestBody"]; !ok && len(body) > 0 {
+			schema := map[string]interface{}{"type": "object", "properties": body}
+			if len(required) > 0 {
+				schema["required"] = required
+			}
+			operation["requestBody"] = jsonContent(schema, "required", len(required) > 0)
+		}
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	response := map[string]interface{}{"description": "Successful response"}
+	if shape.Output != nil {
+		response["content"] = jsonContent(g.schema(shape.Output.Target))["content"]
+	}
+	responses := map[string]interface{}{fmt.Sprint(binding.Code): response}
+	if len(shape.Errors) > 0 {
+		var errorNames []string
+		for _, errorRef := range shape.Errors {
+			errorNames = append(errorNames, extractOperationName(errorRef.Target))
+		}
+		responses["default"] = map[string]interface{}{"description": "Error response"}
+		operation["x-smithy-errors"] = errorNames
+	}
+	operation["responses"] = responses
+
+	return openAPIRoute{method: strings.ToLower(binding.Method), path: uri, query: query, operation: operation}
+}
+
+// schema returns the JSON Schema of a shape, registering named shapes as components
+func (g *openAPIGenerator) schema(target string) map[string]interface{} {
+	if shapeType, ok := smithyPrelude[target]; ok {
+		return simpleTypeSchemas[shapeType]
+	}
+	shape, ok := g.model.Shapes[target]
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	name := extractOperationName(target)
+	ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	if _, ok := g.schemas[name]; ok {
+		return ref
+	}
+	// Register a placeholder first so recursive shapes terminate
+	g.schemas[name] = map[string]interface{}{}
+
+	schema := map[string]interface{}{}
+	switch shape.Type {
+	case "structure", "union":
+		properties := map[string]interface{}{}
+		var required []string
+		for _, memberName := range sortedMemberNames(shape) {
+			member := shape.Members[memberName]
+			properties[memberName] = g.schema(member.Target)
+			if _, ok := member.Traits[requiredTrait]; ok {
+				required = append(required, memberName)
+			}
+		}
+		schema["type"] = "object"
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		if shape.Type == "union" {
+			schema["maxProperties"] = 1
+		}
+	case "list", "set":
+		schema["type"] = "array"
+		if shape.Member != nil {
+			schema["items"] = g.schema(shape.Member.Target)
+		}
+	case "map":
+		schema["type"] = "object"
+		if shape.Value != nil {
+			schema["additionalProperties"] = g.schema(shape.Value.Target)
+		}
+	case "enum":
+		var values []interface{}
+		for _, memberName := range sortedMemberNames(shape) {
+			value := memberName
+			json.Unmarshal(shape.Members[memberName].Traits[enumValueTrait], &value)
+			values = append(values, value)
+		}
+		schema["type"] = "string"
+		schema["enum"] = values
+	case "intEnum":
+		schema["type"] = "integer"
+	default:
+		for key, value := range simpleTypeSchemas[shape.Type] {
+			schema[key] = value
+		}
+		// Smithy 1.0 models declare string enums with the smithy.api#enum trait
+		var enumTrait []struct {
+			Value string `json:"value"`
+		}
+		if json.Unmarshal(shape.Traits[legacyEnumTrait], &enumTrait) == nil && len(enumTrait) > 0 {
+			var values []interface{}
+			for _, entry := range enumTrait {
+				values = append(values, entry.Value)
+			}
+			schema["enum"] = values
+		}
+	}
+	if doc := stringTrait(shape.Traits, documentationTrait); doc != "" {
+		schema["description"] = doc
+	}
+
+	g.schemas[name] = schema
+	return ref
+}
+
+// jsonContent wraps a schema in an application/json content object with extra key/value pairs
+func jsonContent(schema map[string]interface{}, extra ...interface{}) map[string]interface{} {
+	content := map[string]interface{}{
+		"content": map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}},
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		content[extra[i].(string)] = extra[i+1]
+	}
+	return content
+}
+
+// stringTrait returns the value of a trait holding a JSON string, or an empty string
+func stringTrait(traits map[string]json.RawMessage, name string) string {
+	var value string
+	json.Unmarshal(traits[name], &value)
+	return value
+}
+
+// serviceVersion returns the version of the service shape, which AWS models set to the API version
+func serviceVersion(model *AWSServiceModel) string {
+	for _, shape := range model.Shapes {
+		if shape.Type == "service" && shape.Version != "" {
+			return shape.Version
+		}
+	}
+	return "unknown"
+}
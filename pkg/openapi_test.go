@@ -0,0 +1,89 @@
+package extractor
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// httpOperation returns an operation shape bound to an HTTP method and URI
+func httpOperation(method, uri string) ServiceShape {
+	trait, _ := json.Marshal(httpTraitValue{Method: method, URI: uri})
+	return ServiceShape{Type: "operation", Traits: map[string]json.RawMessage{httpTrait: trait}}
+}
+
+func TestRenderOpenAPIPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		shapes map[string]ServiceShape
+		paths  []string
+		err    string
+	}{
+		{
+			name: "query literal dropped without collision",
+			shapes: map[string]ServiceShape{
+				"GetBucketTagging": httpOperation("GET", "/{Bucket}?tagging"),
+				"PutBucketTagging": httpOperation("PUT", "/{Bucket}?tagging"),
+				"GetObject":        httpOperation("GET", "/{Bucket}/{Key+}"),
+			},
+			paths: []string{"GET /{Bucket}", "GET /{Bucket}/{Key}", "PUT /{Bucket}"},
+		},
+		{
+			name: "query literal kept on collision",
+			shapes: map[string]ServiceShape{
+				"GetBucketTagging": httpOperation("GET", "/{Bucket}?tagging"),
+				"GetBucketPolicy":  httpOperation("GET", "/{Bucket}?policy"),
+				"PutBucketPolicy":  httpOperation("PUT", "/{Bucket}?policy"),
+			},
+			paths: []string{"GET /{Bucket}?policy", "GET /{Bucket}?tagging", "PUT /{Bucket}"},
+		},
+		{
+			name: "RPC operations",
+			shapes: map[string]ServiceShape{
+				"CreateTable": {Type: "operation"},
+				"DeleteTable": {Type: "operation"},
+			},
+			paths: []string{"POST /CreateTable", "POST /DeleteTable"},
+		},
+		{
+			name: "same binding",
+			shapes: map[string]ServiceShape{
+				"ListBuckets":   httpOperation("GET", "/"),
+				"ListBucketsV2": httpOperation("GET", "/"),
+			},
+			err: "operations ListBuckets and ListBucketsV2 are both bound to GET /",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var operations []Operation
+			for name := range test.shapes {
+				operations = append(operations, Operation{Name: name})
+			}
+			sort.Slice(operations, func(i, j int) bool { return operations[i].Name < operations[j].Name })
+
+			document, err := renderOpenAPI("s3", testModel(test.shapes), operations)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for path, methods := range document["paths"].(map[string]map[string]interface{}) {
+				for method := range methods {
+					paths = append(paths, strings.ToUpper(method)+" "+path)
+				}
+			}
+			sort.Strings(paths)
+			if strings.Join(paths, ", ") != strings.Join(test.paths, ", ") {
+				t.Errorf("paths = %v, want %v", paths, test.paths)
+			}
+		})
+	}
+}
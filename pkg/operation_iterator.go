@@ -0,0 +1,165 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sort"
+)
+
+// ErrStopIteration can be returned by a ForEachOperation callback to stop without an error
+var ErrStopIteration = errors.New("stop iteration")
+
+// serviceScan is what is read once per service to build its operations: the model, the operations
+// it resolves to along with those merged from sub-APIs, and the controller code referencing them
+type serviceScan struct {
+	serviceName     string
+	model           *AWSServiceModel
+	resolution      OperationResolution
+	subAPIModels    map[string]string
+	operations      []string
+	locations       map[string][]Location
+	skippedPaths    []SkippedPath
+	generatorConfig *GeneratorConfig
+	warnings        []string
+
+	shapes       map[string]ServiceShape
+	exists       map[string]bool
+	waiters      map[string][]Waiter
+	releases     map[string]OperationRelease
+	cfnResources []CloudFormationResource
+	cfnTypes     map[string]string
+}
+
+// scanService resolves the operations of a loaded service model and scans the controller for them
+func scanService(serviceName string, model *AWSServiceModel) *serviceScan {
+	s := &serviceScan{serviceName: serviceName, model: model, resolution: ResolveOperations(model)}
+
+	// Operations of sibling APIs the controller calls, e.g. s3-control for s3, are extracted with the service
+	s.subAPIModels, s.warnings = resolveSubAPIOperations(serviceName, s.resolution.Operations)
+	s.operations = append([]string{}, s.resolution.Operations...)
+	for name := range s.subAPIModels {
+		s.operations = append(s.operations, name)
+	}
+	sort.Strings(s.operations[len(s.resolution.Operations):])
+
+	s.locations, s.skippedPaths = scanControllerForOperations(serviceName, s.operations)
+	s.generatorConfig, _ = LoadControllerGeneratorConfig(serviceName)
+	s.shapes = operationShapes(model)
+	s.exists = make(map[string]bool, len(s.operations))
+	for _, name := range s.operations {
+		s.exists[name] = true
+	}
+	s.waiters = ExtractWaiters(model)
+	s.releases = ExtractReleaseStages(model)
+	s.cfnResources = MapCloudFormationResources(model)
+	s.cfnTypes = cloudFormationTypes(s.cfnResources)
+	return s
+}
+
+// forEach builds the operations of the scan one at a time in model order, ending with the
+// context's error when it is done
+func (s *serviceScan) forEach(ctx context.Context, fn func(Operation) error) error {
+	seen := make(map[string]bool)
+	for _, operationName := range s.operations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if operationName == "" || seen[operationName] {
+			continue
+		}
+		seen[operationName] = true
+		if err := fn(s.operation(operationName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// operation builds a single operation with everything known about it short of its classification:
+// unsupported operations keep the unclassified type unless they are excluded or a classification
+// override applies
+func (s *serviceScan) operation(operationName string) Operation {
+	single := []Operation{newOperation(operationName, s.locations[operationName])}
+	// Refine why unsupported operations have no controller code
+	assignUnsupportedStatus(s.serviceName, single, s.generatorConfig)
+	op := single[0]
+	op.Model = s.subAPIModels[operationName]
+
+	// Internal and console-only operations are never callable by a controller, and human review
+	// decisions take precedence over classification
+	shape := s.shapes[operationName]
+	if reason := exclusionReason(s.serviceName, op.Name, shape); reason != "" && !op.IsSupported() {
+		op.SupportStatus = SupportExcluded
+		op.ExclusionReason = reason
+	} else if opType, ok := classificationOverrides[s.serviceName][op.Name]; ok && !op.IsSupported() {
+		op.Type = opType
+	}
+
+	op.CloudFormationType = s.cfnTypes[op.Name]
+	op.Waiters = s.waiters[op.Name]
+	if release, ok := s.releases[op.Name]; ok {
+		op.APIVersion = release.APIVersion
+		op.ReleaseStage = release.Stage
+		op.DeprecatedSince = release.DeprecatedSince
+		op.DeprecationMessage = release.DeprecationMessage
+	}
+	op.Consistency, op.ConsistencySource = operationConsistency(s.serviceName, op.Name, shape, s.model)
+	op.SemanticGroup = semanticGroup(op.Name, inputMemberNames(s.model, shape))
+	op.AccessLevel, op.AccessLevelSource = ActionAccessLevel(operationIAMAction(s.serviceName, op))
+	op.Requires = operationRequires(op.Name, s.exists)
+	op.ModelSource = s.model.operationSources[op.Name]
+
+	single[0] = op
+	ApplyImplementationKinds(single)
+	ApplyRelevance(single)
+	return single[0]
+}
+
+// ForEachOperation streams the operations of a service to fn one at a time, in model order,
+// without building the operation slice, so large services can be filtered or searched and the
+// iteration stopped early. fn returning ErrStopIteration ends the iteration with a nil error;
+// any other error, or the context being done, ends it with that error.
+//
+// Operations are built the same way ExtractDetailedOperationsFromService builds them, short of
+// what needs the whole service: unsupported operations keep the unclassified type unless they are
+// excluded or a classification override applies, and coverage, file density, dependency gaps and
+// support predictions are left to ExtractDetailedOperationsFromService.
+func ForEachOperation(ctx context.Context, serviceName string, fn func(Operation) error) error {
+	model, err := loadServiceModel(serviceName)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err = scanService(serviceName, model).forEach(ctx, fn)
+	if errors.Is(err, ErrStopIteration) {
+		return nil
+	}
+	return err
+}
+
+// Operations returns an iterator over the operations of a service for use in range loops.
+// An error ends the iteration with a final pair holding the zero Operation and the error.
+//
+//	for op, err := range extractor.Operations(ctx, "dynamodb") {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func Operations(ctx context.Context, serviceName string) iter.Seq2[Operation, error] {
+	return func(yield func(Operation, error) bool) {
+		err := ForEachOperation(ctx, serviceName, func(op Operation) error {
+			if !yield(op, nil) {
+				return ErrStopIteration
+			}
+			return nil
+		})
+		if err != nil {
+			yield(Operation{}, err)
+		}
+	}
+}
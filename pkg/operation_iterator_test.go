@@ -0,0 +1,103 @@
+package extractor_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+	"github.com/aws-controllers-k8s/ack-api-extractor/pkg/extractortest"
+)
+
+// iteratorWorkspace writes a service with supported, hook-implemented, ignored and unsupported
+// operations and a sub-API
+func iteratorWorkspace(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("foo").SigningName("foo").
+		Operation("CreateBar", extractortest.WithInput("Name!", "Tags")).
+		Operation("DescribeBar", extractortest.WithInput("Name!"), extractortest.WithOutput("Status")).
+		Operations("DeleteBar", "TagResource", "PutRecord", "ListBars"))
+	w.AddModel(t, extractortest.NewModel("foo-streams").SigningName("foo").Operations("GetRecords"))
+	w.AddController(t, "foo", extractortest.NewController().
+		GeneratorConfig("foo", "ListBars").
+		SDKCall("bar", "CreateBar").
+		SDKCall("bar", "DescribeBar").
+		HookCall("bar", "DeleteBar").FS())
+	if err := extractor.SetSubAPIs([]string{"foo=foo-streams"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestForEachOperationMatchesExtraction(t *testing.T) {
+	iteratorWorkspace(t)
+
+	serviceOps, err := extractor.ExtractDetailedOperationsFromService("foo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extracted := make(map[string]extractor.Operation)
+	for _, op := range serviceOps.Operations {
+		// The support prediction needs every operation of the service
+		streamed := op
+		streamed.PredictedResource = ""
+		extracted[op.Name] = streamed
+	}
+
+	var names []string
+	err = extractor.ForEachOperation(context.Background(), "foo", func(op extractor.Operation) error {
+		names = append(names, op.Name)
+		if !reflect.DeepEqual(op, extracted[op.Name]) {
+			t.Errorf("streamed %s differs from the extracted operation:\n  got:  %+v\n  want: %+v", op.Name, op, extracted[op.Name])
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"CreateBar", "DeleteBar", "DescribeBar", "ListBars", "PutRecord", "TagResource", "GetRecords"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("streamed %v, want %v", names, want)
+	}
+
+	// Spot-check the metadata that used to be missing from streamed operations
+	if op := extracted["GetRecords"]; op.Model != "foo-streams" {
+		t.Errorf("GetRecords model = %q, want foo-streams", op.Model)
+	}
+	if op := extracted["DeleteBar"]; op.Implementation != extractor.ImplementationCustom || !reflect.DeepEqual(op.Requires, []string{"DescribeBar", "ListBars"}) {
+		t.Errorf("DeleteBar implementation = %q, requires %v", op.Implementation, op.Requires)
+	}
+	if op := extracted["ListBars"]; op.AccessLevel != extractor.AccessLevelList || op.SupportStatus != extractor.SupportIntentionallyIgnored {
+		t.Errorf("ListBars access level = %q, support status %q", op.AccessLevel, op.SupportStatus)
+	}
+}
+
+func TestForEachOperationStops(t *testing.T) {
+	iteratorWorkspace(t)
+
+	count := 0
+	err := extractor.ForEachOperation(context.Background(), "foo", func(op extractor.Operation) error {
+		count++
+		return extractor.ErrStopIteration
+	})
+	if err != nil || count != 1 {
+		t.Errorf("stopped after %d operation(s) with %v, want 1 and no error", count, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	count = 0
+	err = extractor.ForEachOperation(ctx, "foo", func(op extractor.Operation) error {
+		count++
+		if count == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || count != 2 {
+		t.Errorf("cancelled after %d operation(s) with %v, want 2 and context.Canceled", count, err)
+	}
+
+	if _, err := extractor.ExtractDetailedOperationsFromServiceContext(ctx, "foo", false); !errors.Is(err, context.Canceled) {
+		t.Errorf("extraction with a cancelled context returned %v, want context.Canceled", err)
+	}
+}
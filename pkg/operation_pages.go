@@ -0,0 +1,68 @@
+package extractor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OperationFilter selects operations by type, support and name prefix. Zero fields match every operation.
+type OperationFilter struct {
+	Type OperationType
+	// Supported selects supported operations when true and unsupported ones when false
+	Supported  *bool
+	NamePrefix string
+}
+
+// Matches reports whether an operation passes the filter
+func (f OperationFilter) Matches(op Operation) bool {
+	if f.Type != "" && op.Type != f.Type {
+		return false
+	}
+	if f.Supported != nil && op.IsSupported() != *f.Supported {
+		return false
+	}
+	return strings.HasPrefix(op.Name, f.NamePrefix)
+}
+
+// FilterOperations returns the operations passing the filter, in their original order
+func FilterOperations(operations []Operation, filter OperationFilter) []Operation {
+	var matched []Operation
+	for _, op := range operations {
+		if filter.Matches(op) {
+			matched = append(matched, op)
+		}
+	}
+	return matched
+}
+
+// PageOperations returns up to limit operations following the cursor, in name order, and the
+// cursor of the next page, empty on the last one. The cursor encodes the last operation name
+// of a page rather than an offset, so pages stay consistent when operations are added between
+// requests. A limit of 0 returns every operation following the cursor.
+func PageOperations(operations []Operation, limit int, cursor string) ([]Operation, string, error) {
+	if limit < 0 {
+		return nil, "", fmt.Errorf("limit must not be negative, got %d", limit)
+	}
+	after := ""
+	if cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(decoded) == 0 {
+			return nil, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+		after = string(decoded)
+	}
+
+	sorted := make([]Operation, len(operations))
+	copy(sorted, operations)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	start := sort.Search(len(sorted), func(i int) bool { return sorted[i].Name > after })
+	page := sorted[start:]
+	if limit == 0 || len(page) <= limit {
+		return page, "", nil
+	}
+	page = page[:limit]
+	return page, base64.RawURLEncoding.EncodeToString([]byte(page[limit-1].Name)), nil
+}
@@ -0,0 +1,82 @@
+package extractor
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SetOperation is an operation combining the operation sets of several services
+type SetOperation string
+
+const (
+	// SetIntersect keeps the members every service has
+	SetIntersect SetOperation = "intersect"
+	// SetUnion keeps the members any service has
+	SetUnion SetOperation = "union"
+	// SetDifference keeps the members of the first service none of the others has
+	SetDifference SetOperation = "difference"
+)
+
+// SetKey is what operations of different services are compared by
+type SetKey string
+
+const (
+	// SetKeyName compares operation names, e.g. DescribeTable in both dynamodb and dax
+	SetKeyName SetKey = "name"
+	// SetKeyAction compares IAM actions, which include the service prefix
+	SetKeyAction SetKey = "action"
+)
+
+// ParseSetKey parses the key operation sets are compared by
+func ParseSetKey(value string) (SetKey, error) {
+	switch SetKey(value) {
+	case SetKeyName, SetKeyAction:
+		return SetKey(value), nil
+	}
+	return "", fmt.Errorf("invalid set key %q, expected %s or %s", value, SetKeyName, SetKeyAction)
+}
+
+// CombineOperationSets applies a set operation to the operations of several services passing the
+// filter, each service being one set. Members are sorted and list the services that have them.
+func CombineOperationSets(operation SetOperation, key SetKey, services []*ServiceOperations, filter OperationFilter) (*OperationSetResult, error) {
+	if len(services) < 2 {
+		return nil, fmt.Errorf("%s needs at least two services, got %d", operation, len(services))
+	}
+
+	result := &OperationSetResult{Operation: operation, Key: key, Members: []OperationSetMember{}}
+	owners := make(map[string][]string)
+	for _, serviceOps := range services {
+		result.Services = append(result.Services, serviceOps.ServiceName)
+		seen := make(map[string]bool)
+		for _, op := range FilterOperations(serviceOps.Operations, filter) {
+			member := op.Name
+			if key == SetKeyAction {
+				member = operationIAMAction(serviceOps.ServiceName, op)
+			}
+			if !seen[member] {
+				seen[member] = true
+				owners[member] = append(owners[member], serviceOps.ServiceName)
+			}
+		}
+	}
+
+	first := services[0].ServiceName
+	for member, memberServices := range owners {
+		var keep bool
+		switch operation {
+		case SetIntersect:
+			keep = len(memberServices) == len(services)
+		case SetUnion:
+			keep = true
+		case SetDifference:
+			keep = len(memberServices) == 1 && memberServices[0] == first
+		default:
+			return nil, fmt.Errorf("unknown set operation %q", operation)
+		}
+		if keep {
+			result.Members = append(result.Members, OperationSetMember{Name: member, Services: memberServices})
+		}
+	}
+	sort.Slice(result.Members, func(i, j int) bool { return result.Members[i].Name < result.Members[j].Name })
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package extractor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OperationSubset names a subset of operations the operations output can be limited to
+type OperationSubset string
+
+const (
+	SubsetControlPlane OperationSubset = "control-plane"
+	SubsetDataPlane    OperationSubset = "data-plane"
+	SubsetSupported    OperationSubset = "supported"
+	SubsetUnsupported  OperationSubset = "unsupported"
+)
+
+// OperationSubsets are the subsets the operations output can be limited to
+var OperationSubsets = []OperationSubset{SubsetControlPlane, SubsetDataPlane, SubsetSupported, SubsetUnsupported}
+
+// ParseOperationSubsets builds the filter selecting the operations in every given subset, e.g.
+// control-plane and unsupported. A plane and a support subset combine; two of a kind conflict.
+func ParseOperationSubsets(values []string) (OperationFilter, error) {
+	var filter OperationFilter
+	for _, value := range values {
+		switch subset := OperationSubset(strings.TrimSpace(value)); subset {
+		case SubsetControlPlane, SubsetDataPlane:
+			opType := OperationTypeControlPlane
+			if subset == SubsetDataPlane {
+				opType = OperationTypeDataPlane
+			}
+			if filter.Type != "" && filter.Type != opType {
+				return filter, fmt.Errorf("subsets %s and %s exclude each other", SubsetControlPlane, SubsetDataPlane)
+			}
+			filter.Type = opType
+		case SubsetSupported, SubsetUnsupported:
+			supported := subset == SubsetSupported
+			if filter.Supported != nil && *filter.Supported != supported {
+				return filter, fmt.Errorf("subsets %s and %s exclude each other", SubsetSupported, SubsetUnsupported)
+			}
+			filter.Supported = &supported
+		default:
+			return filter, fmt.Errorf("invalid subset %q, must be one of %s", value, joinSubsets(OperationSubsets))
+		}
+	}
+	return filter, nil
+}
+
+// Subset returns a copy of the service operations listing only the operations passing the filter,
+// recording the subsets in Only. Counts and coverage still describe every operation of the service.
+func (s *ServiceOperations) Subset(only []string, filter OperationFilter) *ServiceOperations {
+	subset := *s
+	subset.Operations = FilterOperations(s.Operations, filter)
+	if subset.Operations == nil {
+		subset.Operations = []Operation{}
+	}
+	subset.Only = only
+	return &subset
+}
+
+// joinSubsets lists subsets for messages, e.g. "control-plane, data-plane"
+func joinSubsets(subsets []OperationSubset) string {
+	names := make([]string, len(subsets))
+	for i, subset := range subsets {
+		names[i] = string(subset)
+	}
+	return strings.Join(names, ", ")
+}
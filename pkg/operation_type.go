@@ -0,0 +1,94 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// OperationType describes whether an operation belongs to the control plane or the data plane
+type OperationType string
+
+const (
+	// OperationTypeControlPlane operations manage resources and are candidates for ACK controllers
+	OperationTypeControlPlane OperationType = "control_plane"
+	// OperationTypeDataPlane operations read or write data within existing resources
+	OperationTypeDataPlane OperationType = "data_plane"
+	// OperationTypeUnknown operations could not be classified because classification failed
+	OperationTypeUnknown OperationType = "unknown"
+	// OperationTypeUnclassified operations were not sent for classification
+	OperationTypeUnclassified OperationType = "unclassified"
+	// OperationTypeUnsampled operations were left out of a sampled classification run
+	OperationTypeUnsampled OperationType = "unsampled"
+)
+
+// ParseOperationType converts a string to an OperationType, accepting the legacy
+// "Unknown" and empty values written by earlier versions of the extractor
+func ParseOperationType(value string) (OperationType, error) {
+	switch value {
+	case string(OperationTypeControlPlane):
+		return OperationTypeControlPlane, nil
+	case string(OperationTypeDataPlane):
+		return OperationTypeDataPlane, nil
+	case string(OperationTypeUnknown), "Unknown":
+		return OperationTypeUnknown, nil
+	case string(OperationTypeUnclassified), "":
+		return OperationTypeUnclassified, nil
+	case string(OperationTypeUnsampled):
+		return OperationTypeUnsampled, nil
+	}
+	return "", fmt.Errorf("invalid operation type %q", value)
+}
+
+// Valid reports whether the type is one of the known operation types
+func (t OperationType) Valid() bool {
+	switch t {
+	case OperationTypeControlPlane, OperationTypeDataPlane, OperationTypeUnknown, OperationTypeUnclassified, OperationTypeUnsampled:
+		return true
+	}
+	return false
+}
+
+// IsControlPlane reports whether the operation is a control plane operation
+func (t OperationType) IsControlPlane() bool {
+	return t == OperationTypeControlPlane
+}
+
+// IsDataPlane reports whether the operation is a data plane operation
+func (t OperationType) IsDataPlane() bool {
+	return t == OperationTypeDataPlane
+}
+
+// IsClassified reports whether the operation was classified as control or data plane
+func (t OperationType) IsClassified() bool {
+	return t == OperationTypeControlPlane || t == OperationTypeDataPlane
+}
+
+// String returns the canonical name of the type, treating the zero value as unclassified
+func (t OperationType) String() string {
+	if t == "" {
+		return string(OperationTypeUnclassified)
+	}
+	return string(t)
+}
+
+// MarshalJSON writes the canonical name of the type and rejects unknown values
+func (t OperationType) MarshalJSON() ([]byte, error) {
+	if t != "" && !t.Valid() {
+		return nil, fmt.Errorf("invalid operation type %q", string(t))
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON reads an operation type, normalizing legacy values
+func (t *OperationType) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("operation type must be a string: %w", err)
+	}
+	parsed, err := ParseOperationType(value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
@@ -1,78 +1,94 @@
 package extractor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-	"gopkg.in/yaml.v3"
+	"time"
 )
 
-// processOperation processes a single operation and adds it to the appropriate slice
-func processOperation(operationName, serviceName string, operationNames map[string]bool, operations *[]Operation, unsupportedOperations *[]Operation, supportedCount *int) {
-	if operationName != "" && !operationNames[operationName] {
-		operationNames[operationName] = true
-		file, line := findOperationInController(serviceName, operationName)
-		operation := Operation{
-			Name: operationName,
-			Type: "",
-			File: file,
-			Line: line,
-		}
-		
-		if file != "" && line > 0 {
-			// Supported operation - mark as control_plane directly and add to main list
-			operation.Type = "control_plane"
-			*operations = append(*operations, operation)
-			(*supportedCount)++
-		} else {
-			// Unsupported operation - will need classification
-			*unsupportedOperations = append(*unsupportedOperations, operation)
-		}
+// newOperation creates an unclassified operation with the support status derived from its controller references
+func newOperation(operationName string, locations []Location) Operation {
+	operation := Operation{
+		Name:      operationName,
+		Type:      OperationTypeUnclassified,
+		Locations: locations,
+	}
+	if len(locations) > 0 {
+		// File and Line keep pointing at the first match for existing consumers
+		operation.File = locations[0].File
+		operation.Line = locations[0].Line
 	}
+	operation.SupportStatus = supportStatusForLocations(locations)
+	if operation.IsSupported() {
+		// Supported operations are control plane by nature
+		operation.Type = OperationTypeControlPlane
+	}
+	return operation
 }
 
 // ExtractDetailedOperationsFromService extracts operations with metadata structure
 func ExtractDetailedOperationsFromService(serviceName string, enableClassification bool) (*ServiceOperations, error) {
-	jsonFile, err := findServiceModelJSONFile(serviceName)
+	return ExtractDetailedOperationsFromServiceContext(context.Background(), serviceName, enableClassification)
+}
+
+// ExtractDetailedOperationsFromServiceContext extracts operations with metadata structure, building
+// them with the operation iterator and stopping with the context's error when it is done
+func ExtractDetailedOperationsFromServiceContext(ctx context.Context, serviceName string, enableClassification bool) (*ServiceOperations, error) {
+	timings := &PhaseTimings{}
+	phaseStart := time.Now()
+	reportProgress(ProgressEvent{Kind: ProgressServiceStarted, Service: serviceName, Message: "Extracting " + serviceName})
+
+	model, err := loadServiceModel(serviceName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find JSON file for service %s: %w", serviceName, err)
+		return nil, err
 	}
+	timings.ModelParse = time.Since(phaseStart)
+	phaseStart = time.Now()
 
-	data, err := os.ReadFile(jsonFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read JSON file %s: %w", jsonFile, err)
+	// Resolve operations from both the service shape and the operation shapes
+	scan := scanService(serviceName, model)
+	resolution := scan.resolution
+	reportProgress(ProgressEvent{Kind: ProgressModelParsed, Service: serviceName, Operations: len(resolution.Operations),
+		Message: fmt.Sprintf("Parsed the %s model: %d operations", serviceName, len(resolution.Operations))})
+	warnings := append([]string{}, scan.warnings...)
+	for _, warning := range scan.warnings {
+		reportWarning(serviceName, "%s", warning)
 	}
 
-	var model AWSServiceModel
-	if err := json.Unmarshal(data, &model); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON file %s: %w", jsonFile, err)
+	unmodeledCalls := findUnmodeledCalls(serviceName, scan.operations)
+	for _, warning := range findUnknownAnnotations(serviceName, scan.operations) {
+		reportWarning(serviceName, "%s", warning)
+		warnings = append(warnings, warning)
 	}
 
-	var operations []Operation
-	var unsupportedOperations []Operation
-	operationNames := make(map[string]bool) // Track seen operation names to avoid duplicates
+	// Supported operations are final; excluded and overridden ones need no classification
+	var operations, excluded, overridden, unsupportedOperations []Operation
 	supportedCount := 0
-	
-	// First, collect operations from service shapes
-	for _, shape := range model.Shapes {
-		if shape.Type == "service" && len(shape.Operations) > 0 {
-			for _, opTarget := range shape.Operations {
-				operationName := extractOperationName(opTarget.Target)
-				processOperation(operationName, serviceName, operationNames, &operations, &unsupportedOperations, &supportedCount)
-			}
-			break
-		}
-	}
-	
-	// Then, collect all operation shapes (shapes with type "operation") for models like lambda
-	for shapeName, shape := range model.Shapes {
-		if shape.Type == "operation" {
-			operationName := extractOperationName(shapeName)
-			processOperation(operationName, serviceName, operationNames, &operations, &unsupportedOperations, &supportedCount)
+	err = scan.forEach(ctx, func(op Operation) error {
+		switch {
+		case op.IsSupported():
+			operations = append(operations, op)
+			supportedCount++
+		case op.SupportStatus == SupportExcluded:
+			excluded = append(excluded, op)
+		case op.Type != OperationTypeUnclassified:
+			overridden = append(overridden, op)
+		default:
+			unsupportedOperations = append(unsupportedOperations, op)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	operations = append(operations, excluded...)
+	timings.ControllerScan = time.Since(phaseStart)
+	phaseStart = time.Now()
 
 	// Classification Logic:
 	// - All SUPPORTED operations (found in controller code) are automatically marked as "control_plane"
@@ -80,108 +96,239 @@ func ExtractDetailedOperationsFromService(serviceName string, enableClassificati
 	// - This reduces API costs and assumes implemented operations are control plane by nature
 	controlPlaneCount := 0
 	supportedControlPlaneCount := 0
-	
+
+	// Human review decisions take precedence over classification
+	operations = append(operations, overridden...)
+
 	if enableClassification && len(unsupportedOperations) > 0 {
-		classification, err := ClassifyOperations(serviceName, unsupportedOperations)
-		if err != nil {
-			fmt.Printf("Warning: Failed to classify operations for %s: %v\n", serviceName, err)
-			for _, op := range unsupportedOperations {
-				op.Type = "Unknown"
-				operations = append(operations, op)
+		// Tagging APIs behave the same in every service and never need the model
+		universal, remaining := applyUniversalClassification(unsupportedOperations)
+		operations = append(operations, universal...)
+
+		// Describe and List operations whose output shows what they read are decided by rules
+		described, remaining := applyDescribeRules(serviceName, remaining, model)
+		operations = append(operations, described...)
+
+		// Reuse classifications of identically named operations from sibling services
+		keys := classificationKeys(model, defaultClassificationPrompt())
+		reused, remaining := sharedClassificationCache.Reuse(serviceName, remaining, keys)
+		if len(reused) > 0 {
+			reportProgress(ProgressEvent{Kind: ProgressClassificationsReused, Service: serviceName, Operations: len(reused),
+				Message: fmt.Sprintf("Reused %d cached classification(s) for %s", len(reused), serviceName)})
+			recordCacheHits(len(reused))
+			operations = append(operations, reused...)
+		}
+
+		// Exploratory runs only pay for classifying a random sample
+		remaining, unsampled := sampleOperations(remaining)
+		if len(unsampled) > 0 {
+			reportProgress(ProgressEvent{Kind: ProgressSampleSelected, Service: serviceName, Operations: len(remaining),
+				Message: fmt.Sprintf("Classifying a sample of %d of %d operation(s) for %s", len(remaining), len(remaining)+len(unsampled), serviceName)})
+			operations = append(operations, unsampled...)
+		}
+
+		if len(remaining) > 0 {
+			classification, err := ClassifyOperations(serviceName, remaining)
+			if err != nil {
+				recordClassificationFailure(serviceName, err)
+				switch classificationFailurePolicy {
+				case ClassificationFailureFail:
+					return nil, CategorizeError(ErrorCategoryClassification, fmt.Errorf("failed to classify operations of %s: %w", serviceName, err))
+				case ClassificationFailureHeuristic:
+					reportWarning(serviceName, "failed to classify operations, classifying them heuristically: %v", err)
+					warnings = append(warnings, fmt.Sprintf("classification failed, classified heuristically: %v", err))
+					operations = append(operations, ClassifyHeuristically(serviceName, remaining)...)
+				default:
+					reportWarning(serviceName, "failed to classify operations: %v", err)
+					warnings = append(warnings, fmt.Sprintf("classification failed: %v", err))
+					for _, op := range remaining {
+						op.Type = OperationTypeUnknown
+						operations = append(operations, op)
+					}
+				}
+			} else {
+				classified, driftWarnings := applyModelClassification(serviceName, remaining, classification, keys)
+				for _, warning := range driftWarnings {
+					reportWarning(serviceName, "%s", warning)
+					warnings = append(warnings, warning)
+				}
+				operations = append(operations, classified...)
 			}
-		} else {
-			classified := ApplyClassification(unsupportedOperations, classification)
-			operations = append(operations, classified...)
 		}
 	} else if len(unsupportedOperations) > 0 {
 		// If classification is disabled, add unsupported operations with blank type
 		operations = append(operations, unsupportedOperations...)
 	}
 
+	timings.Classification = time.Since(phaseStart)
+
 	if len(operations) == 0 {
-		return nil, fmt.Errorf("no operations found for service %s", serviceName)
+		return nil, CategorizeError(ErrorCategoryNoOperations, fmt.Errorf("no operations found for service %s", serviceName))
 	}
 	
+	// The whole service is needed to mark the CloudFormation resources ACK supports and to find
+	// dependencies on read operations the controller doesn't support
+	cfnResources := scan.cfnResources
+	ApplyCloudFormationTypes(operations, cfnResources)
+	dependencyGaps := FindDependencyGaps(operations)
+	prediction := PredictSupport(operations, scan.generatorConfig)
+	sourceCounts := CountModelSources(operations)
+
 	controlPlaneCount, supportedControlPlaneCount = CountControlPlaneOperations(operations)
+	statusCounts := CountSupportStatus(operations)
+	implementationCounts := CountImplementationKinds(operations)
+
+	var sample *ClassificationSample
+	if enableClassification && classificationSamplePercent > 0 && classificationSamplePercent < 100 {
+		sample = EstimateClassificationSplit(operations)
+	}
 
 	return &ServiceOperations{
+		SchemaVersion:            SchemaVersion,
 		ServiceName:              serviceName,
 		TotalOperations:          len(operations),
 		SupportedOperations:      supportedCount,
 		ControlPlaneOps:          controlPlaneCount,
 		SupportedControlPlaneOps: supportedControlPlaneCount,
+		SupportStatusCounts:      statusCounts,
+		ReleaseStageCounts:       CountReleaseStages(operations),
+		SemanticGroups:           SummarizeSemanticGroups(operations),
+		AccessLevelCounts:        CountAccessLevels(operations),
+		SupportCoverage:          SupportCoverage(statusCounts),
+		RelevantCoverage:         RelevantCoverage(operations),
 		Operations:               operations,
+		Metadata:                 ExtractServiceMetadata(model),
+		FileDensity:              ComputeFileDensity(operations),
+		ImplementationCounts:     implementationCounts,
+		CustomImplementation:     CustomImplementationShare(implementationCounts),
+		ResolutionDiscrepancies:  resolution.Discrepancies,
+		CloudFormationResources:  cfnResources,
+		ClassificationSample:     sample,
+		SupportPrediction:        prediction,
+		ModelSources:             sourceCounts,
+		NoController:             noController,
+		ControllerRelease:        ControllerReleaseTag(serviceName),
+		UnmodeledCalls:           unmodeledCalls,
+		SubAPIs:                  mergedSubAPIs(scan.subAPIModels),
+		SkippedPaths:             scan.skippedPaths,
+		DependencyGaps:           dependencyGaps,
+		Warnings:                 warnings,
+		Timings:                  timings,
 	}, nil
 }
 
-// getModelNameFromController reads the generator.yaml file from a controller and extracts the model_name
-func getModelNameFromController(serviceName string) (string, error) {
-	controllerPath := findControllerForService(serviceName)
-	if controllerPath == "" {
-		return "", fmt.Errorf("controller directory not found for service %s", serviceName)
+// loadServiceModel locates and parses the API model JSON file for a service. With additional
+// model sources configured, the models of all sources are merged.
+func loadServiceModel(serviceName string) (*AWSServiceModel, error) {
+	if mergesModelSources() {
+		return loadMergedServiceModel(serviceName)
 	}
-	
-	generatorFile := filepath.Join(controllerPath, "generator.yaml")
-	if _, err := os.Stat(generatorFile); os.IsNotExist(err) {
-		return "", fmt.Errorf("generator.yaml not found in controller directory: %s", generatorFile)
+
+	jsonFile, err := findServiceModelJSONFile(serviceName)
+	if err != nil {
+		return nil, CategorizeError(ErrorCategoryModelNotFound, fmt.Errorf("failed to find JSON file for service %s: %w", serviceName, err))
 	}
-	
-	data, err := os.ReadFile(generatorFile)
+
+	data, err := os.ReadFile(jsonFile)
 	if err != nil {
-		return "", fmt.Errorf("failed to read generator.yaml file %s: %w", generatorFile, err)
+		return nil, CategorizeError(ErrorCategoryModelInvalid, fmt.Errorf("failed to read JSON file %s: %w", jsonFile, err))
 	}
-	
-	var config GeneratorConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return "", fmt.Errorf("failed to parse generator.yaml file %s: %w", generatorFile, err)
+
+	return parseServiceModel(data, jsonFile)
+}
+
+// parseServiceModel parses a Smithy JSON AST model read from location
+func parseServiceModel(data []byte, location string) (*AWSServiceModel, error) {
+	var model AWSServiceModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, CategorizeError(ErrorCategoryModelInvalid, fmt.Errorf("failed to parse JSON file %s: %w", location, err))
+	}
+
+	return &model, nil
+}
+
+// getModelNameFromController reads the generator.yaml file from a controller and extracts the model_name
+func getModelNameFromController(serviceName string) (string, error) {
+	config, err := LoadControllerGeneratorConfig(serviceName)
+	if err != nil {
+		return "", err
 	}
 	
 	if config.SDKNames.ModelName == "" {
-		return "", fmt.Errorf("model_name not found in generator.yaml file %s", generatorFile)
+		return "", fmt.Errorf("model_name not found in generator.yaml for service %s", serviceName)
 	}
 	
 	return config.SDKNames.ModelName, nil
 }
 
-// findServiceJSONFile locates the JSON file for a given service in the api-models-aws directory
-func findServiceModelJSONFile(serviceName string) (string, error) {
-	modelsPath := filepath.Join("..", "api-models-aws", "models", serviceName, "service")
-	
-	if _, err := os.Stat(modelsPath); os.IsNotExist(err) {
-		// Fallback: try to get the model name from the controller's generator.yaml file
-		modelName, fallbackErr := getModelNameFromController(serviceName)
-		if fallbackErr != nil {
-			return "", fmt.Errorf("service directory not found: %s, and fallback failed: %w", modelsPath, fallbackErr)
-		}
-		
-		// Try with the model name from generator.yaml
-		modelsPath = filepath.Join("..", "api-models-aws", "models", modelName, "service")
-		if _, err := os.Stat(modelsPath); os.IsNotExist(err) {
-			return "", fmt.Errorf("service directory not found for both service name (%s) and model name (%s)", serviceName, modelName)
-		}
+// modelsRoot is the directory containing the AWS service model directories
+var modelsRoot = filepath.Join("..", "api-models-aws", "models")
+
+// SetModelsDir sets the directory containing the AWS service model directories.
+// Relative paths are resolved against the current working directory.
+func SetModelsDir(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve models directory %s: %w", dir, err)
 	}
+	modelsRoot = abs
+	return nil
+}
 
-	var jsonFile string
-	err := filepath.Walk(modelsPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if strings.HasSuffix(path, ".json") {
-			jsonFile = path
-			return filepath.SkipDir // Stop after finding the first JSON file
-		}
-		return nil
-	})
+// modelsDir returns the directory containing the AWS service model directories
+func modelsDir() string {
+	return modelsRoot
+}
 
-	if err != nil {
-		return "", fmt.Errorf("error searching for JSON file: %w", err)
+// ListAvailableServices returns the names of all services the model path template matches in the
+// models directory, including those of additional local model sources
+func ListAvailableServices() ([]string, error) {
+	if mergesModelSources() {
+		return listSourceServices()
+	}
+
+	return listModelSourceServices(modelsDir())
+}
+
+// listModelSourceServices returns the services the model path template matches in a model source
+// directory, sorted
+func listModelSourceServices(root string) ([]string, error) {
+	if _, err := os.ReadDir(root); err != nil && strings.Contains(ModelPathTemplate(), "{root}") {
+		return nil, fmt.Errorf("failed to read models directory %s: %w", root, err)
 	}
 
-	if jsonFile == "" {
-		return "", fmt.Errorf("no JSON file found for service %s", serviceName)
+	var services []string
+	for serviceName := range listPathTemplateServices(ModelPathTemplate(), root) {
+		services = append(services, serviceName)
 	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// findServiceJSONFile locates the JSON file for a given service in the api-models-aws directory
+func findServiceModelJSONFile(serviceName string) (string, error) {
+	return findModelJSONFileIn(modelsDir(), serviceName)
+}
 
-	return jsonFile, nil
+// findModelJSONFileIn locates the JSON file for a given service in a model source directory,
+// laid out like api-models-aws/models unless a model path template is set
+func findModelJSONFileIn(root, serviceName string) (string, error) {
+	if jsonFile := findModelFileByTemplate(root, serviceName); jsonFile != "" {
+		return jsonFile, nil
+	}
+	modelsPath := expandPathTemplate(ModelPathTemplate(), root, serviceName)
+	
+	// Fallback: try to get the model name from the controller's generator.yaml file
+	modelName, fallbackErr := getModelNameFromController(serviceName)
+	if fallbackErr != nil {
+		return "", fmt.Errorf("no JSON file found for service %s in %s, and fallback failed: %w", serviceName, modelsPath, fallbackErr)
+	}
+	
+	// Try with the model name from generator.yaml
+	if jsonFile := findModelFileByTemplate(modelsDir(), modelName); jsonFile != "" {
+		return jsonFile, nil
+	}
+	return "", fmt.Errorf("no JSON file found for both service name (%s) and model name (%s) in %s", serviceName, modelName, modelsPath)
 }
 
 // extractOperationName extracts the operation name from a target string
@@ -0,0 +1,109 @@
+package extractor
+
+import "sort"
+
+// ComputeOrgStats aggregates the operations of every service into org-wide numbers and lists the
+// top least-covered services. A service's coverage is its control plane coverage when its operations
+// were classified and its support coverage otherwise.
+func ComputeOrgStats(services []*ServiceOperations, top int) *OrgStats {
+	stats := &OrgStats{LeastCovered: []ServiceCoverage{}}
+	statusCounts := make(map[SupportStatus]int)
+	var coverages []ServiceCoverage
+	for _, serviceOps := range services {
+		if serviceOps == nil || len(serviceOps.Operations) == 0 {
+			continue
+		}
+		stats.Services++
+		stats.TotalOperations += len(serviceOps.Operations)
+		controlPlane, supportedControlPlane := CountControlPlaneOperations(serviceOps.Operations)
+		stats.ControlPlaneOperations += controlPlane
+		stats.SupportedControlPlaneOps += supportedControlPlane
+
+		counts := CountSupportStatus(serviceOps.Operations)
+		for status, count := range counts {
+			statusCounts[status] += count
+		}
+		coverages = append(coverages, ServiceCoverage{
+			ServiceName:              serviceOps.ServiceName,
+			Operations:               len(serviceOps.Operations),
+			ControlPlaneOperations:   controlPlane,
+			SupportedControlPlaneOps: supportedControlPlane,
+			Coverage:                 OperationsCoverage(serviceOps.Operations),
+		})
+	}
+	stats.SupportedOperations = statusCounts[SupportImplemented] + statusCounts[SupportPartiallyImplemented]
+	stats.Coverage = SupportCoverage(statusCounts)
+	stats.ControlPlaneCoverage = percentage(stats.SupportedControlPlaneOps, stats.ControlPlaneOperations)
+
+	// The biggest gaps come first among equally covered services
+	sort.Slice(coverages, func(i, j int) bool {
+		if coverages[i].Coverage != coverages[j].Coverage {
+			return coverages[i].Coverage < coverages[j].Coverage
+		}
+		if coverages[i].ControlPlaneOperations != coverages[j].ControlPlaneOperations {
+			return coverages[i].ControlPlaneOperations > coverages[j].ControlPlaneOperations
+		}
+		return coverages[i].ServiceName < coverages[j].ServiceName
+	})
+	if top > 0 && len(coverages) > top {
+		coverages = coverages[:top]
+	}
+	stats.LeastCovered = append(stats.LeastCovered, coverages...)
+	return stats
+}
+
+// CompareOrgStats records the change of the org-wide numbers since a previous snapshot in current
+func CompareOrgStats(current, previous *OrgStats, currentServices, previousServices []*ServiceOperations) {
+	trend := &OrgTrend{
+		PreviousServices:           previous.Services,
+		Operations:                 current.TotalOperations - previous.TotalOperations,
+		ControlPlaneOperations:     current.ControlPlaneOperations - previous.ControlPlaneOperations,
+		SupportedOperations:        current.SupportedOperations - previous.SupportedOperations,
+		SupportedControlPlaneOps:   current.SupportedControlPlaneOps - previous.SupportedControlPlaneOps,
+		CoveragePoints:             current.Coverage - previous.Coverage,
+		ControlPlaneCoveragePoints: current.ControlPlaneCoverage - previous.ControlPlaneCoverage,
+		ServicesAdded:              []string{},
+		ServicesRemoved:            []string{},
+	}
+
+	names := func(services []*ServiceOperations) map[string]bool {
+		set := make(map[string]bool, len(services))
+		for _, serviceOps := range services {
+			if serviceOps != nil && len(serviceOps.Operations) > 0 {
+				set[serviceOps.ServiceName] = true
+			}
+		}
+		return set
+	}
+	currentNames, previousNames := names(currentServices), names(previousServices)
+	for name := range currentNames {
+		if !previousNames[name] {
+			trend.ServicesAdded = append(trend.ServicesAdded, name)
+		}
+	}
+	for name := range previousNames {
+		if !currentNames[name] {
+			trend.ServicesRemoved = append(trend.ServicesRemoved, name)
+		}
+	}
+	sort.Strings(trend.ServicesAdded)
+	sort.Strings(trend.ServicesRemoved)
+	current.Trend = trend
+}
+
+// OperationsCoverage returns the coverage of a service's operations: its control plane coverage when
+// operations were classified and its support coverage otherwise
+func OperationsCoverage(operations []Operation) float64 {
+	if controlPlane, supportedControlPlane := CountControlPlaneOperations(operations); controlPlane > 0 {
+		return percentage(supportedControlPlane, controlPlane)
+	}
+	return SupportCoverage(CountSupportStatus(operations))
+}
+
+// percentage returns part as a percentage of total, 0 for an empty total
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
@@ -0,0 +1,189 @@
+package extractor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// partialOutputSuffix names the staging directory of an output directory, kept when a run fails
+	partialOutputSuffix = ".partial"
+	// previousOutputSuffix names the output directory while it is moved aside for its replacement
+	// on platforms that cannot exchange two directories
+	previousOutputSuffix = ".previous"
+)
+
+// OutputBatch stages the files of a run in <output>.partial next to the output directory and
+// swaps them into place on Commit, so readers of the output directory never see half-written
+// files or files of two different runs. The staging directory starts as a copy of the output
+// directory, so files the run does not write, such as the output of other services, are kept.
+// A batch that is never committed stays as <output>.partial.
+type OutputBatch struct {
+	// Dir is the output directory the batch replaces
+	Dir string
+	// Staging is the directory the files of the batch are written to
+	Staging string
+}
+
+// BeginOutputBatch starts a batch replacing dir. With resume an earlier batch left in the staging
+// directory is continued, e.g. from its checkpoint; otherwise the staging directory starts as a
+// copy of dir. Directories containing the staging directory or the working directory are refused,
+// as they cannot be swapped.
+func BeginOutputBatch(dir string, resume bool) (*OutputBatch, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve output directory %s: %w", dir, err)
+	}
+	batch := &OutputBatch{Dir: abs, Staging: abs + partialOutputSuffix}
+	if err := checkSwappable(abs, batch.Staging); err != nil {
+		return nil, err
+	}
+
+	if resume {
+		if _, err := os.Stat(batch.Staging); err == nil {
+			return batch, nil
+		}
+	}
+	if err := os.RemoveAll(batch.Staging); err != nil {
+		return nil, fmt.Errorf("failed to remove staging directory %s: %w", batch.Staging, err)
+	}
+	if err := copyDirectory(abs, batch.Staging); err != nil {
+		os.RemoveAll(batch.Staging)
+		return nil, fmt.Errorf("failed to copy %s to staging directory %s: %w", abs, batch.Staging, err)
+	}
+	return batch, nil
+}
+
+// checkSwappable refuses an output directory that contains the staging directory or the working
+// directory, e.g. --output=. or a parent of the working directory
+func checkSwappable(dir, staging string) error {
+	if filepath.Dir(dir) == dir {
+		return fmt.Errorf("output directory %s is a filesystem root and cannot be replaced; use --in-place", dir)
+	}
+	if isWithin(staging, dir) {
+		return fmt.Errorf("output directory %s contains its staging directory %s; use --in-place", dir, staging)
+	}
+	if wd, err := os.Getwd(); err == nil && isWithin(wd, dir) {
+		return fmt.Errorf("output directory %s contains the working directory and cannot be replaced; use another directory or --in-place", dir)
+	}
+	return nil
+}
+
+// isWithin reports whether path is dir or below it; both are absolute and clean
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// copyDirectory copies the files of src into a new directory dst, creating only dst when src does
+// not exist. Symbolic links are copied as links.
+func copyDirectory(src, dst string) error {
+	if _, err := os.Lstat(src); os.IsNotExist(err) {
+		return os.MkdirAll(dst, 0755)
+	}
+	return filepath.WalkDir(src, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// copyFile copies a regular file
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Commit replaces the output directory with the staging directory. Where the platform supports
+// it, both are exchanged in a single rename, so the output directory always exists and holds
+// either every old file or every new one; elsewhere the old directory is renamed aside first.
+// The old files are removed afterwards.
+func (b *OutputBatch) Commit() error {
+	info, err := os.Lstat(b.Dir)
+	if os.IsNotExist(err) {
+		if err := os.Rename(b.Staging, b.Dir); err != nil {
+			return fmt.Errorf("failed to move %s to %s: %w", b.Staging, b.Dir, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect output directory %s: %w", b.Dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("output %s is not a directory", b.Dir)
+	}
+
+	exchanged, err := exchangeDirectories(b.Staging, b.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to swap %s and %s: %w", b.Staging, b.Dir, err)
+	}
+	if exchanged {
+		// The staging directory now holds the old output
+		if err := os.RemoveAll(b.Staging); err != nil {
+			reportWarning("", "failed to remove the previous output %s: %v", b.Staging, err)
+		}
+		return nil
+	}
+
+	previous := b.Dir + previousOutputSuffix
+	if err := os.RemoveAll(previous); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", previous, err)
+	}
+	if err := os.Rename(b.Dir, previous); err != nil {
+		return fmt.Errorf("failed to move %s aside: %w", b.Dir, err)
+	}
+	if err := os.Rename(b.Staging, b.Dir); err != nil {
+		if restoreErr := os.Rename(previous, b.Dir); restoreErr != nil {
+			return fmt.Errorf("failed to move %s to %s: %w; the previous output is left in %s", b.Staging, b.Dir, err, previous)
+		}
+		return fmt.Errorf("failed to move %s to %s: %w", b.Staging, b.Dir, err)
+	}
+	if err := os.RemoveAll(previous); err != nil {
+		reportWarning("", "failed to remove the previous output %s: %v", previous, err)
+	}
+	return nil
+}
+
+// Abandon leaves the output directory untouched and reports whether the staging directory is kept
+// for a rerun with resume. A staging directory nothing was written to is removed.
+func (b *OutputBatch) Abandon() bool {
+	entries, err := os.ReadDir(b.Staging)
+	if err != nil || len(entries) > 0 {
+		return err == nil
+	}
+	return os.Remove(b.Staging) != nil
+}
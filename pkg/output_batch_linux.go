@@ -0,0 +1,17 @@
+package extractor
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// exchangeDirectories atomically swaps two directories with renameat2(RENAME_EXCHANGE). It
+// returns false without an error when the filesystem does not support exchanging.
+func exchangeDirectories(a, b string) (bool, error) {
+	err := unix.Renameat2(unix.AT_FDCWD, a, unix.AT_FDCWD, b, unix.RENAME_EXCHANGE)
+	if errors.Is(err, unix.EINVAL) || errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.ENOTSUP) {
+		return false, nil
+	}
+	return err == nil, err
+}
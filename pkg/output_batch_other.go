@@ -0,0 +1,8 @@
+//go:build !linux
+
+package extractor
+
+// exchangeDirectories reports that directories cannot be swapped atomically on this platform
+func exchangeDirectories(a, b string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,122 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readOutput returns the content of a file in an output directory, "" when it is missing
+func readOutput(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestOutputBatchCommit(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"foo-operations.json": "old foo", "bar-operations.json": "old bar"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	batch, err := BeginOutputBatch(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(batch.Staging, "foo-operations.json"), []byte("new foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := readOutput(t, dir, "foo-operations.json"); got != "old foo" {
+		t.Errorf("output directory changed before Commit: %q", got)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readOutput(t, dir, "foo-operations.json"); got != "new foo" {
+		t.Errorf("foo-operations.json = %q after Commit, want the new output", got)
+	}
+	if got := readOutput(t, dir, "bar-operations.json"); got != "old bar" {
+		t.Errorf("bar-operations.json = %q after Commit, want the untouched old output", got)
+	}
+	for _, leftover := range []string{batch.Staging, dir + previousOutputSuffix} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Errorf("%s is left after Commit", leftover)
+		}
+	}
+}
+
+func TestOutputBatchAbandonAndResume(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+
+	// A batch nothing was written to leaves nothing behind
+	batch, err := BeginOutputBatch(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.Abandon() {
+		t.Error("Abandon kept an empty staging directory")
+	}
+	if _, err := os.Stat(batch.Staging); !os.IsNotExist(err) {
+		t.Error("the empty staging directory was not removed")
+	}
+
+	batch, err = BeginOutputBatch(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(batch.Staging, "foo-operations.json"), []byte("partial foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !batch.Abandon() {
+		t.Fatal("Abandon removed a staging directory holding output")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("Abandon created the output directory")
+	}
+
+	// Resuming continues the staging directory, starting over discards it
+	resumed, err := BeginOutputBatch(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readOutput(t, resumed.Staging, "foo-operations.json"); got != "partial foo" {
+		t.Errorf("resumed staging directory holds %q, want the partial output", got)
+	}
+	restarted, err := BeginOutputBatch(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readOutput(t, restarted.Staging, "foo-operations.json"); got != "" {
+		t.Errorf("restarted staging directory holds %q, want it empty", got)
+	}
+	if err := restarted.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("Commit of a new output directory: %v", err)
+	}
+}
+
+func TestBeginOutputBatchRefusesUnswappableDirectories(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{".", filepath.Dir(wd), string(filepath.Separator)} {
+		if _, err := BeginOutputBatch(dir, false); err == nil {
+			t.Errorf("BeginOutputBatch accepted %s", dir)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// OutputSink publishes the files of a finished run written to a local staging directory
+type OutputSink interface {
+	// Publish uploads the files of dir and returns where each one was published
+	Publish(ctx context.Context, dir string) ([]PublishedFile, error)
+	// String returns the destination as given on the command line
+	String() string
+}
+
+// PublishedFile describes one output file published to a sink
+type PublishedFile struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	SHA256   string `json:"sha256"`
+	Size     int    `json:"size"`
+	// Uploaded is false when identical content was already published
+	Uploaded bool `json:"uploaded"`
+}
+
+// PublishManifest maps the output file names of a run to their content-addressed locations
+type PublishManifest struct {
+	SchemaVersion int             `json:"schema_version"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+	Files         []PublishedFile `json:"files"`
+}
+
+// ParseOutputSink returns the sink for a remote --output destination: s3://<bucket>[/<prefix>]
+// or gist://[<id>]. Local paths yield a nil sink.
+func ParseOutputSink(output string) (OutputSink, error) {
+	scheme, rest, ok := strings.Cut(output, "://")
+	if !ok {
+		return nil, nil
+	}
+	switch scheme {
+	case "s3":
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid S3 output %q, expected s3://<bucket>[/<prefix>]", output)
+		}
+		return &S3Sink{Bucket: bucket, Prefix: strings.Trim(prefix, "/")}, nil
+	case "gist":
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("publishing to a gist requires GITHUB_TOKEN")
+		}
+		return &GistSink{ID: strings.Trim(rest, "/"), token: token}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output sink %q, expected a directory, s3:// or gist://", scheme)
+	}
+}
+
+// outputFile is a file read from the staging directory
+type outputFile struct {
+	name   string
+	data   []byte
+	sha256 string
+}
+
+// readOutputFiles reads the files of a staging directory in name order. Hidden files such as the
+// checkpoint are run bookkeeping and not published.
+func readOutputFiles(dir string) ([]outputFile, error) {
+	var files []outputFile
+	err := filepath.WalkDir(dir, func(file string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && file != dir {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel(dir, file)
+		sum := sha256.Sum256(data)
+		files = append(files, outputFile{name: filepath.ToSlash(name), data: data, sha256: hex.EncodeToString(sum[:])})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output files from %s: %w", dir, err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+// S3Sink publishes output files to S3 under content-addressed keys, <prefix>/objects/<sha256>/<file>,
+// so unchanged files are not uploaded again. Each run writes a manifest mapping file names to
+// keys to <prefix>/runs/<timestamp>.json and <prefix>/latest.json.
+type S3Sink struct {
+	Bucket string
+	Prefix string
+}
+
+// String returns the s3:// URL of the sink
+func (s *S3Sink) String() string {
+	return "s3://" + path.Join(s.Bucket, s.Prefix)
+}
+
+// key joins a path below the sink's prefix
+func (s *S3Sink) key(elements ...string) string {
+	return path.Join(append([]string{s.Prefix}, elements...)...)
+}
+
+// Publish uploads the files of dir and the run manifest
+func (s *S3Sink) Publish(ctx context.Context, dir string) ([]PublishedFile, error) {
+	files, err := readOutputFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := s3.NewFromConfig(cfg)
+
+	manifest := PublishManifest{SchemaVersion: SchemaVersion, GeneratedAt: time.Now().UTC(), Files: []PublishedFile{}}
+	for _, file := range files {
+		key := s.key("objects", file.sha256, path.Base(file.name))
+		published := PublishedFile{Name: file.name, Location: "s3://" + s.Bucket + "/" + key, SHA256: file.sha256, Size: len(file.data)}
+
+		_, headErr := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(s.Bucket), Key: aws.String(key)})
+		var notFound *s3types.NotFound
+		switch {
+		case headErr == nil:
+		case errors.As(headErr, &notFound):
+			if err := s.put(ctx, client, key, file.data); err != nil {
+				return nil, err
+			}
+			published.Uploaded = true
+		default:
+			return nil, fmt.Errorf("failed to check s3://%s/%s: %w", s.Bucket, key, headErr)
+		}
+		manifest.Files = append(manifest.Files, published)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal publish manifest: %w", err)
+	}
+	runKey := s.key("runs", manifest.GeneratedAt.Format("20060102T150405Z")+".json")
+	for _, key := range []string{runKey, s.key("latest.json")} {
+		if err := s.put(ctx, client, key, data); err != nil {
+			return nil, err
+		}
+	}
+	return manifest.Files, nil
+}
+
+// put uploads a single object
+func (s *S3Sink) put(ctx context.Context, client *s3.Client, key string, data []byte) error {
+	contentType := "application/octet-stream"
+	if strings.HasSuffix(key, ".json") {
+		contentType = "application/json"
+	}
+	_, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload s3://%s/%s: %w", s.Bucket, key, err)
+	}
+	return nil
+}
+
+// GistSink publishes output files to a secret GitHub gist, creating one when no ID is given and
+// replacing the files of an existing gist otherwise. Gists are flat, so nested file names have
+// their slashes replaced.
+type GistSink struct {
+	ID    string
+	token string
+}
+
+// String returns the gist:// URL of the sink
+func (s *GistSink) String() string {
+	return "gist://" + s.ID
+}
+
+// gistFile is the content of one file in a gist request
+type gistFile struct {
+	Content string `json:"content"`
+}
+
+// gistRequest creates or updates a gist
+type gistRequest struct {
+	Description string              `json:"description"`
+	Public      bool                `json:"public"`
+	Files       map[string]gistFile `json:"files"`
+}
+
+// gistResponse holds the fields of a created or updated gist
+type gistResponse struct {
+	ID      string `json:"id"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Publish creates or updates the gist with the files of dir
+func (s *GistSink) Publish(ctx context.Context, dir string) ([]PublishedFile, error) {
+	files, err := readOutputFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no output files to publish")
+	}
+
+	request := gistRequest{
+		Description: fmt.Sprintf("ack-api-extractor output %s", time.Now().UTC().Format(time.RFC3339)),
+		Files:       make(map[string]gistFile, len(files)),
+	}
+	for _, file := range files {
+		request.Files[gistFileName(file.name)] = gistFile{Content: string(file.data)}
+	}
+
+	client := newGitHubClient(s.token)
+	var response gistResponse
+	if s.ID == "" {
+		err = client.do("POST", "/gists", request, &response)
+	} else {
+		err = client.do("PATCH", "/gists/"+s.ID, request, &response)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.ID = response.ID
+
+	published := make([]PublishedFile, 0, len(files))
+	for _, file := range files {
+		published = append(published, PublishedFile{
+			Name:     file.name,
+			Location: response.HTMLURL + "#file-" + strings.ReplaceAll(gistFileName(file.name), ".", "-"),
+			SHA256:   file.sha256,
+			Size:     len(file.data),
+			Uploaded: true,
+		})
+	}
+	return published, nil
+}
+
+// gistFileName flattens a relative output path into a gist file name
+func gistFileName(name string) string {
+	return strings.ReplaceAll(name, "/", "__")
+}
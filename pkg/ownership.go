@@ -0,0 +1,176 @@
+package extractor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// codeownersPaths are the locations GitHub reads a CODEOWNERS file from, in order of precedence
+var codeownersPaths = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// codeownersRule assigns owners to the paths matching a CODEOWNERS pattern
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// ResolveOwnership attributes the supported operations of a service to the owners of the controller
+// code implementing them: the CODEOWNERS entry of the file and the author of the matched line
+// according to git blame. It returns the number of operations with an owner. Blame errors, e.g.
+// for a controller that is not a git checkout, are returned after CODEOWNERS entries were applied.
+func ResolveOwnership(serviceName string, operations []Operation) (int, error) {
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return 0, fmt.Errorf("controller directory not found for service %s", serviceName)
+	}
+
+	rules, err := loadCodeowners(controllerPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var blameErr error
+	resolved := 0
+	for i := range operations {
+		op := &operations[i]
+		if !op.IsSupported() || op.File == "" {
+			continue
+		}
+
+		owner := &OperationOwner{CodeOwners: matchCodeowners(rules, op.File)}
+		if blameErr == nil {
+			blameErr = blameLine(controllerPath, op.File, op.Line, owner)
+		}
+		if len(owner.CodeOwners) == 0 && owner.LastAuthor == "" {
+			continue
+		}
+		op.Owner = owner
+		resolved++
+	}
+
+	if blameErr != nil {
+		return resolved, fmt.Errorf("failed to blame controller code of %s: %w", serviceName, blameErr)
+	}
+	return resolved, nil
+}
+
+// loadCodeowners parses the first CODEOWNERS file found in the controller repository.
+// A repository without a CODEOWNERS file has no rules.
+func loadCodeowners(controllerPath string) ([]codeownersRule, error) {
+	for _, path := range codeownersPaths {
+		file, err := os.Open(filepath.Join(controllerPath, path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		defer file.Close()
+
+		var rules []codeownersRule
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			pattern, err := codeownersPattern(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q in %s: %w", fields[0], path, err)
+			}
+			var owners []string
+			for _, owner := range fields[1:] {
+				if strings.HasPrefix(owner, "#") {
+					break
+				}
+				owners = append(owners, owner)
+			}
+			rules = append(rules, codeownersRule{pattern: pattern, owners: owners})
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return rules, nil
+	}
+	return nil, nil
+}
+
+// codeownersPattern converts a gitignore-style CODEOWNERS pattern to a regular expression.
+// Patterns containing a slash are anchored at the repository root, others match at any depth,
+// and every pattern also matches the contents of a matching directory.
+func codeownersPattern(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var expr strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+
+	prefix := "^(.*/)?"
+	if anchored {
+		prefix = "^"
+	}
+	return regexp.Compile(prefix + expr.String() + "(/.*)?$")
+}
+
+// matchCodeowners returns the owners of a file; the last matching rule takes precedence
+func matchCodeowners(rules []codeownersRule, file string) []string {
+	var owners []string
+	for _, rule := range rules {
+		if rule.pattern.MatchString(file) {
+			owners = rule.owners
+		}
+	}
+	return owners
+}
+
+// blameLine fills in the commit, author and date that last modified a line of a controller file
+func blameLine(controllerPath, file string, line int, owner *OperationOwner) error {
+	if line <= 0 {
+		return nil
+	}
+	lineRange := fmt.Sprintf("%d,%d", line, line)
+	out, err := exec.Command("git", "-C", controllerPath, "blame", "--porcelain", "-L", lineRange, "--", file).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git blame %s:%d: %v: %s", file, line, err, strings.TrimSpace(string(out)))
+	}
+
+	for i, text := range strings.Split(string(out), "\n") {
+		key, value, _ := strings.Cut(text, " ")
+		switch {
+		case i == 0:
+			owner.Commit = key
+		case key == "author":
+			owner.LastAuthor = value
+		case key == "author-mail":
+			owner.LastAuthorEmail = strings.Trim(value, "<>")
+		case key == "author-time":
+			if seconds, parseErr := strconv.ParseInt(value, 10, 64); parseErr == nil {
+				owner.LastModified = time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+			}
+		}
+	}
+	return nil
+}
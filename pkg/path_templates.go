@@ -0,0 +1,222 @@
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultModelPathTemplate locates the model of a service in a directory laid out like api-models-aws/models
+	DefaultModelPathTemplate = "{root}/{service}/service"
+	// DefaultControllerPathTemplate locates the controller of a service in the controllers directory
+	DefaultControllerPathTemplate = "{root}/{service}-controller"
+)
+
+// pathTemplatePlaceholder matches the placeholders of a path template
+var pathTemplatePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// modelPathTemplate and controllerPathTemplate override the default layouts when set
+var modelPathTemplate, controllerPathTemplate string
+
+// SetModelPathTemplate sets where models are found in the models directory and other local model
+// sources, e.g. {root}/models/{service}/service/*.json. {root} is the directory of the source and
+// {service} the service name; the rest is a glob pattern. Matching directories are searched for
+// their first JSON file. Relative templates not starting with {root} are resolved against the
+// current working directory, and an empty template restores the default.
+func SetModelPathTemplate(template string) error {
+	resolved, err := resolvePathTemplate(template)
+	if err != nil {
+		return fmt.Errorf("invalid model path template: %w", err)
+	}
+	modelPathTemplate = resolved
+	return nil
+}
+
+// SetControllerPathTemplate sets where the controller of a service is found, e.g.
+// {root}/src/{service}-controller/src. {root} is the controllers directory and {service} the
+// service name; the rest is a glob pattern whose first matching directory is the controller.
+// Relative templates not starting with {root} are resolved against the current working directory,
+// and an empty template restores the default.
+func SetControllerPathTemplate(template string) error {
+	resolved, err := resolvePathTemplate(template)
+	if err != nil {
+		return fmt.Errorf("invalid controller path template: %w", err)
+	}
+	controllerPathTemplate = resolved
+	return nil
+}
+
+// ModelPathTemplate returns the template models are found with
+func ModelPathTemplate() string {
+	if modelPathTemplate == "" {
+		return DefaultModelPathTemplate
+	}
+	return modelPathTemplate
+}
+
+// ControllerPathTemplate returns the template controllers are found with
+func ControllerPathTemplate() string {
+	if controllerPathTemplate == "" {
+		return DefaultControllerPathTemplate
+	}
+	return controllerPathTemplate
+}
+
+// resolvePathTemplate validates the placeholders of a template and makes it absolute
+func resolvePathTemplate(template string) (string, error) {
+	if template == "" {
+		return "", nil
+	}
+	if _, err := filepath.Match(pathTemplatePlaceholder.ReplaceAllString(template, "x"), ""); err != nil {
+		return "", fmt.Errorf("%s: %w", template, err)
+	}
+	hasService := false
+	for _, placeholder := range pathTemplatePlaceholder.FindAllString(template, -1) {
+		switch placeholder {
+		case "{service}":
+			hasService = true
+		case "{root}":
+		default:
+			return "", fmt.Errorf("%s: unknown placeholder %s, expected {root} or {service}", template, placeholder)
+		}
+	}
+	if !hasService {
+		return "", fmt.Errorf("%s: {service} is missing", template)
+	}
+	if strings.HasPrefix(template, "{root}") || filepath.IsAbs(template) {
+		return filepath.ToSlash(template), nil
+	}
+	abs, err := filepath.Abs(template)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", template, err)
+	}
+	return filepath.ToSlash(abs), nil
+}
+
+// expandPathTemplate returns the glob pattern of a template for a root directory and service
+func expandPathTemplate(template, root, serviceName string) string {
+	return expandPathTemplatePattern(template, root, escapeGlob(serviceName))
+}
+
+// expandPathTemplatePattern returns the glob pattern of a template for a root directory, with
+// {service} replaced by a glob pattern
+func expandPathTemplatePattern(template, root, servicePattern string) string {
+	pattern := pathTemplatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if placeholder == "{root}" {
+			return escapeGlob(filepath.ToSlash(root))
+		}
+		return servicePattern
+	})
+	return filepath.FromSlash(pattern)
+}
+
+// globPathTemplate returns the paths matching a template for a root directory and service, sorted
+func globPathTemplate(template, root, serviceName string) []string {
+	matches, _ := filepath.Glob(expandPathTemplate(template, root, serviceName))
+	sort.Strings(matches)
+	return matches
+}
+
+// listPathTemplateServices returns the services having a path matching a template below a root
+// directory, mapped to their first matching path
+func listPathTemplateServices(template, root string) map[string]string {
+	matches, _ := filepath.Glob(expandPathTemplatePattern(template, root, "*"))
+	sort.Strings(matches)
+
+	var expression strings.Builder
+	expression.WriteString("^")
+	rest := template
+	for {
+		location := pathTemplatePlaceholder.FindStringIndex(rest)
+		if location == nil {
+			break
+		}
+		expression.WriteString(globExpression(rest[:location[0]]))
+		if rest[location[0]:location[1]] == "{root}" {
+			expression.WriteString(regexp.QuoteMeta(filepath.ToSlash(root)))
+		} else {
+			expression.WriteString("([^/]+)")
+		}
+		rest = rest[location[1]:]
+	}
+	expression.WriteString(globExpression(rest) + "$")
+	matcher := regexp.MustCompile(expression.String())
+
+	services := make(map[string]string)
+	for _, match := range matches {
+		groups := matcher.FindStringSubmatch(filepath.ToSlash(match))
+		if len(groups) < 2 || services[groups[1]] != "" {
+			continue
+		}
+		// A template naming the service more than once only matches when every occurrence agrees
+		if expanded, _ := filepath.Match(expandPathTemplate(template, root, groups[1]), match); expanded {
+			services[groups[1]] = match
+		}
+	}
+	return services
+}
+
+// globExpression converts the * and ? wildcards of a glob pattern to a regular expression matching
+// within one path element; other characters match literally
+func globExpression(pattern string) string {
+	var expression strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expression.WriteString("[^/]*")
+		case '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return expression.String()
+}
+
+// escapeGlob escapes the glob metacharacters of a literal path
+func escapeGlob(path string) string {
+	var escaped strings.Builder
+	for _, r := range path {
+		if strings.ContainsRune(`*?[\`, r) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// findModelFileByTemplate returns the first JSON file the model path template matches for a
+// service in a model source directory, searching matching directories for their first JSON file
+func findModelFileByTemplate(root, serviceName string) string {
+	for _, match := range globPathTemplate(ModelPathTemplate(), root, serviceName) {
+		info, err := os.Stat(match)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			if strings.HasSuffix(match, ".json") {
+				return match
+			}
+			continue
+		}
+		jsonFile := ""
+		filepath.Walk(match, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(path, ".json") {
+				jsonFile = path
+				return filepath.SkipDir // Stop after finding the first JSON file
+			}
+			return nil
+		})
+		if jsonFile != "" {
+			return jsonFile
+		}
+	}
+	return ""
+}
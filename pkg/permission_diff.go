@@ -0,0 +1,84 @@
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DiffControllerPermissions extracts the operations supported by a controller at two git
+// refs and reports the IAM actions added and removed between them. Each ref is checked
+// out into a temporary git worktree of the controller repository.
+func DiffControllerPermissions(serviceName, fromRef, toRef string) (*PermissionDiff, error) {
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return nil, fmt.Errorf("controller directory not found for service %s", serviceName)
+	}
+
+	fromActions, err := actionsAtRef(serviceName, controllerPath, fromRef)
+	if err != nil {
+		return nil, err
+	}
+	toActions, err := actionsAtRef(serviceName, controllerPath, toRef)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &PermissionDiff{
+		ServiceName: serviceName,
+		From:        fromRef,
+		To:          toRef,
+		Added:       []string{},
+		Removed:     []string{},
+	}
+	for action := range toActions {
+		if !fromActions[action] {
+			diff.Added = append(diff.Added, action)
+		}
+	}
+	for action := range fromActions {
+		if !toActions[action] {
+			diff.Removed = append(diff.Removed, action)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+
+	return diff, nil
+}
+
+// actionsAtRef returns the IAM actions of the operations a controller supports at a git ref
+func actionsAtRef(serviceName, controllerPath, ref string) (map[string]bool, error) {
+	tmpRoot, err := os.MkdirTemp("", "ack-api-extractor-diff-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpRoot)
+
+	worktree := filepath.Join(tmpRoot, serviceName+"-controller")
+	if out, err := exec.Command("git", "-C", controllerPath, "worktree", "add", "--detach", worktree, ref).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to check out %s at %s: %v: %s", serviceName+"-controller", ref, err, strings.TrimSpace(string(out)))
+	}
+	defer exec.Command("git", "-C", controllerPath, "worktree", "remove", "--force", worktree).Run()
+
+	// Point controller lookups at the worktree while extracting
+	previousRoot, previousTemplate := controllersRoot, controllerPathTemplate
+	controllersRoot, controllerPathTemplate = tmpRoot, ""
+	defer func() { controllersRoot, controllerPathTemplate = previousRoot, previousTemplate }()
+
+	serviceOps, err := ExtractDetailedOperationsFromService(serviceName, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract operations at %s: %w", ref, err)
+	}
+
+	actions := make(map[string]bool)
+	for _, op := range serviceOps.Operations {
+		if op.IsSupported() {
+			actions[operationIAMAction(serviceName, op)] = true
+		}
+	}
+	return actions, nil
+}
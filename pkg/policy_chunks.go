@@ -0,0 +1,118 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// maxRoleManagedPolicies is the default IAM quota of managed policies attached to a single role
+const maxRoleManagedPolicies = 10
+
+// SplitPolicy splits a policy exceeding the managed policy size limit into policies that each fit
+// within it. Statements are kept whole where possible; a statement that is too large on its own is
+// split by action, keeping its Sid, Effect, Resource and Condition. It fails instead of truncating
+// when the policies would not fit within the managed policy quota of the controller role.
+func SplitPolicy(policy *IAMPolicy) ([]*IAMPolicy, error) {
+	if compactPolicySize(*policy) <= maxManagedPolicySize {
+		return []*IAMPolicy{policy}, nil
+	}
+
+	var chunks []*IAMPolicy
+	current := &IAMPolicy{Version: policy.Version}
+	fits := func(stmt PolicyStatement) bool {
+		candidate := IAMPolicy{Version: current.Version, Statement: append(current.Statement[:len(current.Statement):len(current.Statement)], stmt)}
+		return compactPolicySize(candidate) <= maxManagedPolicySize
+	}
+	flush := func() {
+		if len(current.Statement) > 0 {
+			chunks = append(chunks, current)
+			current = &IAMPolicy{Version: policy.Version}
+		}
+	}
+
+	for _, stmt := range policy.Statement {
+		if fits(stmt) {
+			current.Statement = append(current.Statement, stmt)
+			continue
+		}
+		flush()
+		if fits(stmt) {
+			current.Statement = append(current.Statement, stmt)
+			continue
+		}
+
+		// Splitting a NotAction statement would grant what the other pieces deny
+		if len(stmt.Action) == 0 {
+			return nil, fmt.Errorf("statement %q does not fit within the %d character managed policy limit and has no actions to split it by", stmt.Sid, maxManagedPolicySize)
+		}
+		piece := stmt
+		piece.Action = nil
+		for _, action := range stmt.Action {
+			candidate := piece
+			candidate.Action = append(piece.Action[:len(piece.Action):len(piece.Action)], action)
+			if fits(candidate) {
+				piece = candidate
+				continue
+			}
+			if len(piece.Action) > 0 {
+				current.Statement = append(current.Statement, piece)
+				flush()
+				candidate.Action = []string{action}
+			}
+			if !fits(candidate) {
+				return nil, fmt.Errorf("statement %q does not fit within the %d character managed policy limit even with the single action %s", stmt.Sid, maxManagedPolicySize, action)
+			}
+			piece = candidate
+		}
+		current.Statement = append(current.Statement, piece)
+	}
+	flush()
+
+	if len(chunks) > maxRoleManagedPolicies {
+		return nil, fmt.Errorf("policy needs %d managed policies, more than the %d that can be attached to a role", len(chunks), maxRoleManagedPolicies)
+	}
+	return chunks, nil
+}
+
+// NewAttachmentPlan creates an empty attachment plan
+func NewAttachmentPlan() *AttachmentPlan {
+	return &AttachmentPlan{SchemaVersion: SchemaVersion, MaxManagedPoliciesPerRole: maxRoleManagedPolicies, Roles: []RoleAttachment{}}
+}
+
+// Add records the policy files written for the controller role of a service in one partition.
+// Files are listed relative to the output directory.
+func (p *AttachmentPlan) Add(serviceName, partition string, policies []*IAMPolicy, files []string) {
+	role := RoleAttachment{Service: serviceName, Partition: partition}
+	for i, policy := range policies {
+		actions := 0
+		for _, stmt := range policy.Statement {
+			actions += len(stmt.Action)
+		}
+		role.Policies = append(role.Policies, PlannedPolicy{
+			Name:    ManagedPolicyName(serviceName, i, len(policies)),
+			File:    filepath.Base(files[i]),
+			Actions: actions,
+			Size:    compactPolicySize(*policy),
+		})
+	}
+	p.Roles = append(p.Roles, role)
+}
+
+// ManagedPolicyName returns the suggested name of the index-th of count managed policies of a controller
+func ManagedPolicyName(serviceName string, index, count int) string {
+	name := fmt.Sprintf("ack-%s-controller", serviceName)
+	if count > 1 {
+		name = fmt.Sprintf("%s-%d", name, index+1)
+	}
+	return name
+}
+
+// WriteAttachmentPlanJSON writes an attachment plan to a JSON file
+func WriteAttachmentPlanJSON(plan *AttachmentPlan, outputPath string) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal attachment plan: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
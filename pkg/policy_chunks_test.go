@@ -0,0 +1,120 @@
+package extractor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// paddedActions returns count actions of the foo service, each padded to width characters
+func paddedActions(name string, count, width int) []string {
+	actions := make([]string, count)
+	for i := range actions {
+		action := fmt.Sprintf("foo:%s%d", name, i)
+		actions[i] = action + strings.Repeat("X", max(width-len(action), 0))
+	}
+	return actions
+}
+
+// allowStatement returns an Allow statement of actions on every resource
+func allowStatement(sid string, actions []string) PolicyStatement {
+	return PolicyStatement{Sid: sid, Effect: "Allow", Action: actions, Resource: "*"}
+}
+
+func TestSplitPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		statements []PolicyStatement
+		// chunks holds the Sid of the statements of every chunk, one entry per piece
+		chunks  [][]string
+		wantErr string
+	}{
+		{
+			name:       "policy within the limit",
+			statements: []PolicyStatement{allowStatement("A", paddedActions("Read", 3, 20))},
+			chunks:     [][]string{{"A"}},
+		},
+		{
+			name: "whole statements packed",
+			statements: []PolicyStatement{
+				allowStatement("A", paddedActions("Read", 50, 50)),
+				allowStatement("B", paddedActions("Write", 50, 50)),
+				allowStatement("C", paddedActions("List", 50, 50)),
+			},
+			chunks: [][]string{{"A", "B"}, {"C"}},
+		},
+		{
+			name:       "statement split by action",
+			statements: []PolicyStatement{allowStatement("A", paddedActions("Read", 300, 50))},
+			chunks:     [][]string{{"A"}, {"A"}, {"A"}},
+		},
+		{
+			name:       "single action that cannot fit",
+			statements: []PolicyStatement{allowStatement("A", paddedActions("Read", 1, maxManagedPolicySize))},
+			wantErr:    "even with the single action foo:Read0",
+		},
+		{
+			name:       "single action that cannot fit after a flushed piece",
+			statements: []PolicyStatement{allowStatement("A", append(paddedActions("Read", 200, 50), paddedActions("Write", 1, maxManagedPolicySize)...))},
+			wantErr:    "even with the single action foo:Write0",
+		},
+		{
+			name:       "NotAction statement",
+			statements: []PolicyStatement{{Sid: "A", Effect: "Allow", NotAction: paddedActions("Read", 300, 50), Resource: "*"}},
+			wantErr:    "no actions to split it by",
+		},
+		{
+			name: "more policies than a role can attach",
+			statements: func() []PolicyStatement {
+				var statements []PolicyStatement
+				for i := 0; i <= maxRoleManagedPolicies; i++ {
+					statements = append(statements, allowStatement(fmt.Sprintf("S%d", i), paddedActions("Read", 80, 50)))
+				}
+				return statements
+			}(),
+			wantErr: "needs 11 managed policies",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &IAMPolicy{Version: "2012-10-17", Statement: tt.statements}
+			chunks, err := SplitPolicy(policy)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sids [][]string
+			actions := make(map[string][]string)
+			for _, chunk := range chunks {
+				if size := compactPolicySize(*chunk); size > maxManagedPolicySize {
+					t.Errorf("chunk of %d characters exceeds the limit", size)
+				}
+				var chunkSids []string
+				for _, stmt := range chunk.Statement {
+					chunkSids = append(chunkSids, stmt.Sid)
+					actions[stmt.Sid] = append(actions[stmt.Sid], stmt.Action...)
+					if stmt.Effect != "Allow" || stmt.Resource != "*" {
+						t.Errorf("statement %s lost its Effect or Resource", stmt.Sid)
+					}
+				}
+				sids = append(sids, chunkSids)
+			}
+			if !reflect.DeepEqual(sids, tt.chunks) {
+				t.Errorf("chunks %v, want %v", sids, tt.chunks)
+			}
+			for _, stmt := range tt.statements {
+				if !reflect.DeepEqual(actions[stmt.Sid], stmt.Action) {
+					t.Errorf("statement %s split into %d actions, want the %d actions in order", stmt.Sid, len(actions[stmt.Sid]), len(stmt.Action))
+				}
+			}
+		})
+	}
+}
@@ -3,61 +3,250 @@ package extractor
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
+	"sync"
 )
 
-// GenerateSinglePolicy creates a single IAM policy for supported operations only
-func GenerateSinglePolicy(serviceName string, operations []Operation) (*IAMPolicy, error) {
-	var supportedActions []string
+// DefaultPartition is the partition of commercial AWS regions
+const DefaultPartition = "aws"
+
+// knownPartitions lists the AWS partitions policies can be generated for
+var knownPartitions = map[string]bool{
+	"aws":        true,
+	"aws-cn":     true,
+	"aws-us-gov": true,
+	"aws-iso":    true,
+	"aws-iso-b":  true,
+	"aws-iso-e":  true,
+	"aws-iso-f":  true,
+	"aws-eusc":   true,
+}
+
+// ValidatePartition checks that a partition name is a known AWS partition
+func ValidatePartition(partition string) error {
+	if !knownPartitions[partition] {
+		return fmt.Errorf("unknown partition %q", partition)
+	}
+	return nil
+}
+
+// policyOperation reports whether a policy grants an operation: supported operations, or in
+// --no-controller mode the control plane operations a prospective controller would call.
+// Without classification, declarative operations stand in for the control plane.
+func policyOperation(op Operation) bool {
+	if op.IsSupported() {
+		return true
+	}
+	if !noController || !op.SupportStatus.Countable() {
+		return false
+	}
+	return op.Type.IsControlPlane() || (!op.Type.IsClassified() && op.Declarative)
+}
+
+// PolicyActions returns the IAM actions a generated policy grants for a service's operations
+func PolicyActions(serviceName string, operations []Operation) []string {
+	var actions []string
 	for _, op := range operations {
-		if op.File != "" && op.Line > 0 {
-			action := mapOperationToIAMAction(serviceName, op.Name)
-			supportedActions = append(supportedActions, action)
+		if policyOperation(op) {
+			actions = append(actions, operationIAMAction(serviceName, op))
 		}
 	}
+	return actions
+}
+
+// GenerateSinglePolicy creates a single IAM policy for supported operations only
+func GenerateSinglePolicy(serviceName string, operations []Operation) (*IAMPolicy, error) {
+	return GeneratePartitionPolicy(serviceName, operations, DefaultPartition)
+}
+
+// GeneratePartitionPolicy creates a single IAM policy for supported operations with
+// resource ARNs in the given partition (e.g. aws-us-gov or aws-cn)
+func GeneratePartitionPolicy(serviceName string, operations []Operation, partition string) (*IAMPolicy, error) {
+	if err := ValidatePartition(partition); err != nil {
+		return nil, err
+	}
 
+	supportedActions := PolicyActions(serviceName, operations)
 	if len(supportedActions) == 0 {
+		if noController {
+			return nil, fmt.Errorf("no control plane operations found for service %s", serviceName)
+		}
 		return nil, fmt.Errorf("no supported operations found for service %s", serviceName)
 	}
 
-	resourcePattern := generateSimpleResourcePattern(serviceName)
-	policy := createPolicy(supportedActions, resourcePattern)
+	resourcePattern := serviceResourcePattern(serviceName, cachedServiceMetadata(serviceName), partition)
+	if groupPolicyByAccessLevel {
+		scoped, wildcardOnly := splitByResourceLevelSupport(supportedActions)
+		policy := IAMPolicy{Version: "2012-10-17", Statement: accessLevelStatements(scoped, resourcePattern)}
+		if len(wildcardOnly) > 0 {
+			policy.Statement = append(policy.Statement, PolicyStatement{
+				Sid:      wildcardOnlyStatementSid,
+				Effect:   "Allow",
+				Action:   wildcardOnly,
+				Resource: "*",
+			})
+		}
+		return &policy, nil
+	}
+
+	if groupPolicyByARNType {
+		scoped, wildcardOnly := splitByResourceLevelSupport(supportedActions)
+		statements, unmapped := arnTypeStatements(scoped, partition)
+		policy := IAMPolicy{Version: "2012-10-17", Statement: statements}
+		if len(unmapped) > 0 {
+			policy.Statement = append(policy.Statement, PolicyStatement{
+				Sid:      otherARNTypesStatementSid,
+				Effect:   "Allow",
+				Action:   unmapped,
+				Resource: resourcePattern,
+			})
+		}
+		if len(wildcardOnly) > 0 {
+			policy.Statement = append(policy.Statement, PolicyStatement{
+				Sid:      wildcardOnlyStatementSid,
+				Effect:   "Allow",
+				Action:   wildcardOnly,
+				Resource: "*",
+			})
+		}
+		return &policy, nil
+	}
+
+	// Tagging actions get a dedicated statement so they are easy to find, share and review
+	var actions, taggingActions []string
+	for _, action := range supportedActions {
+		if _, operationName, _ := strings.Cut(action, ":"); IsTaggingOperation(operationName) {
+			taggingActions = append(taggingActions, action)
+		} else {
+			actions = append(actions, action)
+		}
+	}
+
+	// Actions without resource-level permissions are only granted by Resource "*"
+	actions, wildcardOnly := splitByResourceLevelSupport(actions)
+	taggingActions, wildcardOnlyTagging := splitByResourceLevelSupport(taggingActions)
+	wildcardOnly = append(wildcardOnly, wildcardOnlyTagging...)
+
+	policy := createPolicy(actions, resourcePattern)
+	if len(taggingActions) > 0 {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Sid:      taggingStatementSid,
+			Effect:   "Allow",
+			Action:   taggingActions,
+			Resource: resourcePattern,
+		})
+	}
+	if len(wildcardOnly) > 0 {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Sid:      wildcardOnlyStatementSid,
+			Effect:   "Allow",
+			Action:   wildcardOnly,
+			Resource: "*",
+		})
+	}
 
 	return &policy, nil
 }
 
+// ServiceActions returns the set of IAM actions for all operations of a service
+func ServiceActions(serviceName string, operations []Operation) map[string]bool {
+	actions := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		actions[operationIAMAction(serviceName, op)] = true
+	}
+	return actions
+}
+
+// servicePrefixes caches the IAM service prefix per service so the model is read only once
+var servicePrefixes = struct {
+	sync.Mutex
+	prefixes map[string]string
+}{prefixes: make(map[string]string)}
+
+// serviceMetadataCache caches the metadata of each service's model so policies for several
+// partitions, or of several kinds, read the model only once
+var serviceMetadataCache = struct {
+	sync.Mutex
+	metadata map[string]*ServiceMetadata
+}{metadata: make(map[string]*ServiceMetadata)}
+
+// cachedServiceMetadata returns the metadata of a service's model, empty when the model can't be read
+func cachedServiceMetadata(serviceName string) *ServiceMetadata {
+	serviceMetadataCache.Lock()
+	metadata, ok := serviceMetadataCache.metadata[serviceName]
+	serviceMetadataCache.Unlock()
+	if ok {
+		return metadata
+	}
+
+	// Models are parsed without holding the lock, so concurrent extractions of other services
+	// don't wait for this one
+	metadata, err := LoadServiceMetadata(serviceName)
+	if err != nil {
+		metadata = &ServiceMetadata{}
+	}
+
+	serviceMetadataCache.Lock()
+	defer serviceMetadataCache.Unlock()
+	// Keep the metadata of whichever caller stored it first, so every caller shares it
+	if cached, ok := serviceMetadataCache.metadata[serviceName]; ok {
+		return cached
+	}
+	serviceMetadataCache.metadata[serviceName] = metadata
+	return metadata
+}
+
 // mapOperationToIAMAction converts an AWS operation to IAM action format
 func mapOperationToIAMAction(serviceName, operationName string) string {
-	modelName, err := getModelNameFromController(serviceName)
-	if err != nil {
-		modelName = serviceName
+	return fmt.Sprintf("%s:%s", IAMServicePrefix(serviceName), operationName)
+}
+
+// IAMServicePrefix returns the IAM action prefix of a service, read from the model's SigV4
+// signing name or ARN namespace and falling back to the lowercased model name
+func IAMServicePrefix(serviceName string) string {
+	servicePrefixes.Lock()
+	prefix, ok := servicePrefixes.prefixes[serviceName]
+	servicePrefixes.Unlock()
+	if ok {
+		return prefix
 	}
-	
-	servicePrefix := strings.ToLower(modelName)
-	return fmt.Sprintf("%s:%s", servicePrefix, operationName)
+
+	// The model and generator.yaml are read without holding the lock
+	prefix = cachedServiceMetadata(serviceName).IAMServicePrefix()
+	if prefix == "" {
+		modelName, err := getModelNameFromController(serviceName)
+		if err != nil {
+			modelName = serviceName
+		}
+		prefix = strings.ToLower(modelName)
+	}
+
+	servicePrefixes.Lock()
+	defer servicePrefixes.Unlock()
+	if cached, ok := servicePrefixes.prefixes[serviceName]; ok {
+		return cached
+	}
+	servicePrefixes.prefixes[serviceName] = prefix
+	return prefix
 }
 
-// generateSimpleResourcePattern creates a simple wildcard resource ARN pattern for the service
-func generateSimpleResourcePattern(serviceName string) string {
-	modelName, err := getModelNameFromController(serviceName)
-	if err != nil {
-		modelName = serviceName
+// serviceResourcePattern creates a simple wildcard resource ARN pattern for the service from the
+// metadata of its loaded model: the ARN namespace, falling back to the model name when the model
+// has none, and whether the service is global (no region in its ARNs)
+func serviceResourcePattern(serviceName string, metadata *ServiceMetadata, partition string) string {
+	serviceForARN := metadata.ARNNamespace
+	if serviceForARN == "" {
+		modelName, err := getModelNameFromController(serviceName)
+		if err != nil {
+			modelName = serviceName
+		}
+		serviceForARN = strings.ToLower(modelName)
 	}
-	
-	serviceForARN := strings.ToLower(modelName)
-
-	// TODO -> this is a hack
-	switch serviceForARN {
-	case "s3":
-		// S3 has global ARNs
-		return "*"
-	case "iam":
-		// IAM is global service (no region)
-		return "arn:aws:iam::*:*"
-	default:
-		return fmt.Sprintf("arn:aws:%s:*:*:*", serviceForARN)
+
+	if metadata.Global {
+		return fmt.Sprintf("arn:%s:%s::*:*", partition, serviceForARN)
 	}
+	return fmt.Sprintf("arn:%s:%s:*:*:*", partition, serviceForARN)
 }
 
 // createPolicy creates an IAM policy with the given actions and resources
@@ -102,8 +291,8 @@ func ValidatePolicyJSON(policy IAMPolicy) error {
 			return fmt.Errorf("statement %d: Effect must be 'Allow' or 'Deny'", i)
 		}
 		
-		if len(stmt.Action) == 0 {
-			return fmt.Errorf("statement %d: Action is required", i)
+		if len(stmt.Action) == 0 && len(stmt.NotAction) == 0 {
+			return fmt.Errorf("statement %d: Action or NotAction is required", i)
 		}
 		
 		if stmt.Resource == nil {
@@ -121,5 +310,5 @@ func WritePolicyJSON(policy *IAMPolicy, outputPath string) error {
 		return fmt.Errorf("failed to marshal policy JSON: %w", err)
 	}
 	
-	return os.WriteFile(outputPath, data, 0644)
+	return writeOutputFile(outputPath, data)
 }
\ No newline at end of file
@@ -0,0 +1,157 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Severity ranks policy lint findings
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+const (
+	// maxManagedPolicySize is the IAM limit for customer managed policies, excluding whitespace
+	maxManagedPolicySize = 6144
+	// maxStatementActions is the best-practice upper bound of actions in a single statement
+	maxStatementActions = 100
+)
+
+// actionPattern matches a well-formed IAM action such as dynamodb:CreateTable
+var actionPattern = regexp.MustCompile(`^[a-z0-9-]+:[A-Za-z0-9*]+$`)
+
+// mutatingVerbs are operation name prefixes of actions that change resources
+var mutatingVerbs = map[string]bool{
+	"Create": true, "Delete": true, "Put": true, "Update": true, "Modify": true,
+	"Attach": true, "Detach": true, "Associate": true, "Disassociate": true,
+	"Tag": true, "Untag": true, "Add": true, "Remove": true, "Set": true,
+	"Enable": true, "Disable": true, "Start": true, "Stop": true, "Register": true,
+	"Deregister": true, "Authorize": true, "Revoke": true, "Reset": true, "Restore": true,
+}
+
+// PolicyFinding represents a single lint rule violation in a policy
+type PolicyFinding struct {
+	Severity  Severity `json:"severity"`
+	Rule      string   `json:"rule"`
+	Statement int      `json:"statement"`
+	Message   string   `json:"message"`
+}
+
+// LintPolicy checks a permission policy against best-practice rules.
+// knownActions, when non-nil, is the set of actions that exist for the service;
+// actions outside of it are reported as errors.
+func LintPolicy(policy IAMPolicy, knownActions map[string]bool) []PolicyFinding {
+	var findings []PolicyFinding
+	add := func(severity Severity, rule string, statement int, format string, args ...interface{}) {
+		findings = append(findings, PolicyFinding{
+			Severity:  severity,
+			Rule:      rule,
+			Statement: statement,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if err := ValidatePolicyJSON(policy); err != nil {
+		add(SeverityError, "invalid-policy", -1, "%v", err)
+	}
+
+	if size := compactPolicySize(policy); size > maxManagedPolicySize {
+		add(SeverityError, "policy-size", -1, "policy is %d characters, exceeding the %d character managed policy limit", size, maxManagedPolicySize)
+	}
+
+	for i, stmt := range policy.Statement {
+		seen := make(map[string]bool)
+		var mutating, tagging []string
+		for _, action := range stmt.Action {
+			if seen[action] {
+				add(SeverityWarning, "duplicate-action", i, "action %s is listed more than once", action)
+				continue
+			}
+			seen[action] = true
+
+			if !actionPattern.MatchString(action) {
+				add(SeverityError, "malformed-action", i, "action %s is not of the form <service>:<Action>", action)
+				continue
+			}
+			if knownActions != nil && !strings.Contains(action, "*") && !knownActions[action] {
+				add(SeverityError, "unknown-action", i, "action %s does not exist in the service model", action)
+			}
+
+			verb := operationVerb(action[strings.Index(action, ":")+1:])
+			// Actions without resource-level permissions can't be scoped, so "*" is expected for them
+			if mutatingVerbs[verb] && SupportsResourceLevelPermissions(action) {
+				mutating = append(mutating, action)
+			}
+			if verb == "Tag" || verb == "Untag" {
+				tagging = append(tagging, action)
+			}
+		}
+
+		if len(stmt.Action) > maxStatementActions {
+			add(SeverityWarning, "statement-size", i, "statement has %d actions, more than the recommended %d", len(stmt.Action), maxStatementActions)
+		}
+
+		if resourceIsWildcard(stmt.Resource) && len(mutating) > 0 {
+			add(SeverityWarning, "wildcard-resource", i, "Resource \"*\" is granted to %d mutating action(s), e.g. %s", len(mutating), mutating[0])
+		}
+
+		if len(tagging) > 0 && stmt.Condition == nil {
+			add(SeverityInfo, "tag-condition", i, "tagging actions (%s) have no aws:RequestTag or aws:TagKeys condition", strings.Join(tagging, ", "))
+		}
+	}
+
+	return findings
+}
+
+// HasBlockingFindings reports whether findings should fail the policy.
+// Errors always block; in strict mode warnings block as well.
+func HasBlockingFindings(findings []PolicyFinding, strict bool) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError || (strict && finding.Severity == SeverityWarning) {
+			return true
+		}
+	}
+	return false
+}
+
+// operationVerb returns the leading capitalized word of an operation name.
+// Example: "CreateTable" -> "Create"
+func operationVerb(operationName string) string {
+	for i, r := range operationName {
+		if i > 0 && unicode.IsUpper(r) {
+			return operationName[:i]
+		}
+	}
+	return operationName
+}
+
+// resourceIsWildcard reports whether a statement resource is or contains "*"
+func resourceIsWildcard(resource interface{}) bool {
+	switch value := resource.(type) {
+	case string:
+		return value == "*"
+	case []string:
+		for _, r := range value {
+			if r == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compactPolicySize returns the policy size the way IAM counts it, without whitespace
+func compactPolicySize(policy IAMPolicy) int {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,381 @@
+package extractor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RecommendedPolicyPath returns the path of a controller's recommended inline policy file
+func RecommendedPolicyPath(serviceName string) (string, error) {
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return "", fmt.Errorf("controller not found for service %s", serviceName)
+	}
+	return filepath.Join(controllerPath, "config", "iam", "recommended-inline-policy"), nil
+}
+
+// PlanRecommendedPolicyPatch compares a controller's recommended inline policy with the actions
+// of the operations the controller supports and returns the actions it lacks, as a JSON Patch
+// (RFC 6902) against the policy and as the policy file rewritten with the actions inserted.
+func PlanRecommendedPolicyPatch(serviceName string) (*PolicyPatch, error) {
+	policyFile, err := RecommendedPolicyPath(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(policyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recommended policy: %w", err)
+	}
+
+	required, err := RequiredPolicyActions(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := PatchPolicyDocument(data, required)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch %s: %w", policyFile, err)
+	}
+	patch.File = policyFile
+	return patch, nil
+}
+
+// RequiredPolicyActions returns the IAM actions of the operations a controller supports
+func RequiredPolicyActions(serviceName string) ([]string, error) {
+	serviceOps, err := ExtractDetailedOperationsFromService(serviceName, false)
+	if err != nil {
+		return nil, err
+	}
+	var required []string
+	for _, op := range serviceOps.Operations {
+		if op.IsSupported() {
+			required = append(required, operationIAMAction(serviceName, op))
+		}
+	}
+	return required, nil
+}
+
+// PatchPolicyDocument adds the required actions not yet allowed by a policy document to the
+// Action array of its first Allow statement with one. The rewritten document keeps the original
+// formatting and ordering; new actions are appended in sorted order. Allow statements using
+// NotAction grant every action they don't list; when they are the only Allow statements, the
+// missing actions are added in a new statement.
+func PatchPolicyDocument(data []byte, required []string) (*PolicyPatch, error) {
+	var document struct {
+		Statement []struct {
+			Effect    string          `json:"Effect"`
+			Action    json.RawMessage `json:"Action"`
+			NotAction json.RawMessage `json:"NotAction"`
+		} `json:"Statement"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid policy JSON: %w", err)
+	}
+
+	target := -1
+	hasAllow := false
+	var allowed []string
+	var notActions [][]string
+	for i, stmt := range document.Statement {
+		if stmt.Effect != "Allow" {
+			continue
+		}
+		hasAllow = true
+		if len(stmt.NotAction) > 0 {
+			excluded, err := decodeActions(stmt.NotAction)
+			if err != nil {
+				return nil, fmt.Errorf("statement %d: %w", i, err)
+			}
+			notActions = append(notActions, excluded)
+			continue
+		}
+		actions, err := decodeActions(stmt.Action)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i, err)
+		}
+		allowed = append(allowed, actions...)
+		if target == -1 {
+			target = i
+		}
+	}
+	if !hasAllow {
+		return nil, fmt.Errorf("policy has no Allow statement")
+	}
+
+	missingSet := make(map[string]bool)
+	for _, action := range required {
+		if !actionAllowed(action, allowed) && !grantedByNotAction(action, notActions) {
+			missingSet[action] = true
+		}
+	}
+	missing := make([]string, 0, len(missingSet))
+	for action := range missingSet {
+		missing = append(missing, action)
+	}
+	sort.Strings(missing)
+
+	patch := &PolicyPatch{Missing: missing, Document: data}
+	if len(missing) == 0 {
+		return patch, nil
+	}
+
+	if target == -1 {
+		statement := PolicyStatement{Effect: "Allow", Action: missing, Resource: "*"}
+		patch.Operations = append(patch.Operations, JSONPatchOperation{Op: "add", Path: "/Statement/-", Value: statement})
+		rewritten, err := appendStatement(data, statement)
+		if err != nil {
+			return nil, err
+		}
+		patch.Document = rewritten
+		return patch, nil
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(document.Statement[target].Action), []byte("[")) {
+		for _, action := range missing {
+			patch.Operations = append(patch.Operations, JSONPatchOperation{
+				Op:    "add",
+				Path:  fmt.Sprintf("/Statement/%d/Action/-", target),
+				Value: action,
+			})
+		}
+	} else {
+		// A single string Action has to become an array
+		existing, _ := decodeActions(document.Statement[target].Action)
+		patch.Operations = append(patch.Operations, JSONPatchOperation{
+			Op:    "replace",
+			Path:  fmt.Sprintf("/Statement/%d/Action", target),
+			Value: append(existing, missing...),
+		})
+	}
+
+	rewritten, err := insertActions(data, target, missing)
+	if err != nil {
+		return nil, err
+	}
+	patch.Document = rewritten
+	return patch, nil
+}
+
+// decodeActions reads an Action value, which is either a string or an array of strings
+func decodeActions(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+	var actions []string
+	if err := json.Unmarshal(raw, &actions); err != nil {
+		return nil, fmt.Errorf("Action must be a string or an array of strings")
+	}
+	return actions, nil
+}
+
+// actionAllowed reports whether an action is granted by any of the allowed actions, including wildcards
+func actionAllowed(action string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(action)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// grantedByNotAction reports whether an Allow statement with one of the NotAction lists grants an
+// action, which it does for every action the list doesn't match
+func grantedByNotAction(action string, notActions [][]string) bool {
+	for _, excluded := range notActions {
+		if !actionAllowed(action, excluded) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendStatement adds a statement to the end of the Statement array without reformatting the rest
+// of the document, indented like the last existing statement
+func appendStatement(data []byte, statement PolicyStatement) ([]byte, error) {
+	lastStart, lastEnd, closing, err := locateStatements(data)
+	if err != nil {
+		return nil, err
+	}
+	if lastStart == -1 {
+		encoded, _ := json.Marshal(statement)
+		return splice(data, closing, closing, string(encoded)), nil
+	}
+
+	last := data[lastStart:lastEnd]
+	lineStart := bytes.LastIndexByte(data[:lastStart], '\n') + 1
+	if !bytes.Contains(last, []byte("\n")) || strings.TrimSpace(string(data[lineStart:lastStart])) != "" {
+		encoded, _ := json.Marshal(statement)
+		return splice(data, lastEnd, lastEnd, ", "+string(encoded)), nil
+	}
+	// Indent the new statement like the last one and its fields like the last one's first field
+	indent := string(data[lineStart:lastStart])
+	fieldLine := last[bytes.IndexByte(last, '\n')+1:]
+	unit := strings.TrimPrefix(string(fieldLine[:len(fieldLine)-len(bytes.TrimLeft(fieldLine, " \t"))]), indent)
+	if unit == "" {
+		unit = "  "
+	}
+	encoded, err := json.MarshalIndent(statement, indent, unit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal statement: %w", err)
+	}
+	return splice(data, lastEnd, lastEnd, ",\n"+indent+string(encoded)), nil
+}
+
+// locateStatements returns the byte range of the last statement, -1 when there is none, and the
+// offset of the bracket closing the Statement array
+func locateStatements(data []byte) (int, int, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := expectDelim(dec, '{'); err != nil {
+		return 0, 0, 0, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if key != "Statement" {
+			if err := dec.Decode(&json.RawMessage{}); err != nil {
+				return 0, 0, 0, err
+			}
+			continue
+		}
+		if err := expectDelim(dec, '['); err != nil {
+			return 0, 0, 0, err
+		}
+		lastStart, lastEnd := -1, -1
+		for dec.More() {
+			before := int(dec.InputOffset())
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return 0, 0, 0, err
+			}
+			lastStart = before + bytes.IndexByte(data[before:], raw[0])
+			lastEnd = lastStart + len(raw)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return 0, 0, 0, err
+		}
+		return lastStart, lastEnd, int(dec.InputOffset()) - 1, nil
+	}
+	return 0, 0, 0, fmt.Errorf("policy has no Statement array")
+}
+
+// insertActions inserts actions at the end of the Action value of a statement without
+// reformatting the rest of the document. Multi-line arrays get one action per line using
+// the indentation of the last existing action.
+func insertActions(data []byte, statementIndex int, actions []string) ([]byte, error) {
+	start, end, err := locateStatementAction(data, statementIndex)
+	if err != nil {
+		return nil, err
+	}
+	value := data[start:end]
+
+	quoted := make([]string, len(actions))
+	for i, action := range actions {
+		encoded, _ := json.Marshal(action)
+		quoted[i] = string(encoded)
+	}
+
+	if value[0] != '[' {
+		// Turn "Action": "s3:GetObject" into an array on the same line
+		return splice(data, start, end, "["+string(value)+", "+strings.Join(quoted, ", ")+"]"), nil
+	}
+
+	closing := start + bytes.LastIndexByte(value, ']')
+	body := bytes.TrimSpace(data[start+1 : closing])
+	if len(body) == 0 {
+		return splice(data, start+1, closing, strings.Join(quoted, ", ")), nil
+	}
+	// lastEnd is the offset just past the last existing action
+	lastEnd := start + 1 + bytes.LastIndexByte(data[start+1:closing], body[len(body)-1]) + 1
+
+	if !bytes.Contains(data[start:lastEnd], []byte("\n")) {
+		return splice(data, lastEnd, lastEnd, ", "+strings.Join(quoted, ", ")), nil
+	}
+	lineStart := bytes.LastIndexByte(data[:lastEnd], '\n') + 1
+	line := data[lineStart:lastEnd]
+	indent := string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+	return splice(data, lastEnd, lastEnd, ",\n"+indent+strings.Join(quoted, ",\n"+indent)), nil
+}
+
+// locateStatementAction returns the byte range of the Action value of a statement
+func locateStatementAction(data []byte, statementIndex int) (int, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := expectDelim(dec, '{'); err != nil {
+		return 0, 0, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return 0, 0, err
+		}
+		if key != "Statement" {
+			if err := dec.Decode(&json.RawMessage{}); err != nil {
+				return 0, 0, err
+			}
+			continue
+		}
+		if err := expectDelim(dec, '['); err != nil {
+			return 0, 0, err
+		}
+		for index := 0; dec.More(); index++ {
+			if index != statementIndex {
+				if err := dec.Decode(&json.RawMessage{}); err != nil {
+					return 0, 0, err
+				}
+				continue
+			}
+			if err := expectDelim(dec, '{'); err != nil {
+				return 0, 0, err
+			}
+			for dec.More() {
+				field, err := dec.Token()
+				if err != nil {
+					return 0, 0, err
+				}
+				before := int(dec.InputOffset())
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return 0, 0, err
+				}
+				if field == "Action" {
+					// InputOffset points after the key; skip the colon and whitespace before the value
+					start := before + bytes.Index(data[before:], raw[:1])
+					return start, start + len(raw), nil
+				}
+			}
+			return 0, 0, fmt.Errorf("statement %d has no Action", statementIndex)
+		}
+	}
+	return 0, 0, fmt.Errorf("statement %d not found", statementIndex)
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q, found %v", delim, token)
+	}
+	return nil
+}
+
+// splice replaces data[start:end] with the insertion
+func splice(data []byte, start, end int, insertion string) []byte {
+	result := make([]byte, 0, len(data)+len(insertion))
+	result = append(result, data[:start]...)
+	result = append(result, insertion...)
+	return append(result, data[end:]...)
+}
@@ -0,0 +1,110 @@
+package extractor
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPatchPolicyDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		missing  []string
+		want     string
+	}{
+		{
+			name: "appends to the first Allow statement",
+			document: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "foo:Describe*"
+      ],
+      "Resource": "*"
+    }
+  ]
+}`,
+			missing: []string{"foo:CreateBar", "foo:DeleteBar"},
+			want: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "foo:Describe*",
+        "foo:CreateBar",
+        "foo:DeleteBar"
+      ],
+      "Resource": "*"
+    }
+  ]
+}`,
+		},
+		{
+			name: "skips a NotAction statement",
+			document: `{"Version": "2012-10-17", "Statement": [
+  {"Effect": "Allow", "NotAction": ["foo:Create*"], "Resource": "*"},
+  {"Effect": "Allow", "Action": ["foo:DescribeBar"], "Resource": "*"}
+]}`,
+			missing: []string{"foo:CreateBar"},
+			want: `{"Version": "2012-10-17", "Statement": [
+  {"Effect": "Allow", "NotAction": ["foo:Create*"], "Resource": "*"},
+  {"Effect": "Allow", "Action": ["foo:DescribeBar", "foo:CreateBar"], "Resource": "*"}
+]}`,
+		},
+		{
+			name: "adds a statement next to NotAction statements",
+			document: `{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "NotAction": "foo:Create*",
+            "Resource": "*"
+        }
+    ]
+}`,
+			missing: []string{"foo:CreateBar"},
+			want: `{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "NotAction": "foo:Create*",
+            "Resource": "*"
+        },
+        {
+            "Effect": "Allow",
+            "Action": [
+                "foo:CreateBar"
+            ],
+            "Resource": "*"
+        }
+    ]
+}`,
+		},
+	}
+	for _, test := range tests {
+		patch, err := PatchPolicyDocument([]byte(test.document), []string{"foo:CreateBar", "foo:DescribeBar", "foo:DeleteBar"})
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(patch.Missing, test.missing) {
+			t.Errorf("%s: missing %v, want %v", test.name, patch.Missing, test.missing)
+		}
+		if string(patch.Document) != test.want {
+			t.Errorf("%s: document\n%s\nwant\n%s", test.name, patch.Document, test.want)
+		}
+		if !json.Valid(patch.Document) {
+			t.Errorf("%s: patched document is not valid JSON", test.name)
+		}
+	}
+
+	if _, err := PatchPolicyDocument([]byte(`{"Statement": [{"Effect": "Deny", "Action": "*", "Resource": "*"}]}`), []string{"foo:CreateBar"}); err == nil {
+		t.Error("PatchPolicyDocument accepted a policy without Allow statements")
+	}
+}
@@ -0,0 +1,71 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// Decisions of the IAM policy simulator
+const (
+	SimulationAllowed      = "allowed"
+	SimulationImplicitDeny = "implicitDeny"
+	SimulationExplicitDeny = "explicitDeny"
+)
+
+// SimulatePolicy runs a policy document through the IAM SimulateCustomPolicy API for every action, so
+// actions the policy does not actually allow (e.g. because of a wrong service prefix or action
+// name) are caught empirically. Resources default to "*" when resourceARNs is empty.
+func SimulatePolicy(document []byte, actions []string, resourceARNs []string) (*PolicySimulation, error) {
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("no actions to simulate")
+	}
+	if !json.Valid(document) {
+		return nil, fmt.Errorf("policy is not valid JSON")
+	}
+
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := iam.NewFromConfig(cfg)
+
+	input := &iam.SimulateCustomPolicyInput{
+		PolicyInputList: []string{string(document)},
+		ActionNames:     actions,
+	}
+	if len(resourceARNs) > 0 {
+		input.ResourceArns = resourceARNs
+	}
+
+	simulation := &PolicySimulation{Results: []ActionSimulation{}}
+	paginator := iam.NewSimulateCustomPolicyPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate policy: %w", err)
+		}
+		for _, result := range page.EvaluationResults {
+			action := ActionSimulation{
+				Action:   aws.ToString(result.EvalActionName),
+				Resource: aws.ToString(result.EvalResourceName),
+				Decision: string(result.EvalDecision),
+			}
+			for _, statement := range result.MatchedStatements {
+				action.MatchedStatements = append(action.MatchedStatements, aws.ToString(statement.SourcePolicyId))
+			}
+			if action.Decision == SimulationAllowed {
+				simulation.Allowed++
+			} else {
+				simulation.Denied++
+			}
+			simulation.Results = append(simulation.Results, action)
+		}
+	}
+	return simulation, nil
+}
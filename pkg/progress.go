@@ -0,0 +1,139 @@
+package extractor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ProgressEventKind identifies what happened in a progress event
+type ProgressEventKind string
+
+const (
+	// ProgressServiceStarted is reported when the extraction of a service starts
+	ProgressServiceStarted ProgressEventKind = "service_started"
+	// ProgressModelParsed is reported once the model of a service is parsed, with its operation count
+	ProgressModelParsed ProgressEventKind = "model_parsed"
+	// ProgressBatchStarted is reported before a classification batch is sent to Bedrock
+	ProgressBatchStarted ProgressEventKind = "batch_started"
+	// ProgressBatchClassified is reported when Bedrock classified a batch
+	ProgressBatchClassified ProgressEventKind = "batch_classified"
+	// ProgressBatchReused is reported when a batch is taken from the checkpoint instead of Bedrock
+	ProgressBatchReused ProgressEventKind = "batch_reused"
+	// ProgressThrottled is reported when Bedrock throttled an invocation that is retried
+	ProgressThrottled ProgressEventKind = "throttled"
+	// ProgressClassificationsReused is reported when classifications of sibling services are reused
+	ProgressClassificationsReused ProgressEventKind = "classifications_reused"
+	// ProgressSampleSelected is reported when only a sample of the operations is classified
+	ProgressSampleSelected ProgressEventKind = "sample_selected"
+	// ProgressFileWritten is reported after an output file was written
+	ProgressFileWritten ProgressEventKind = "file_written"
+	// ProgressWarning is reported for problems that don't stop the extraction
+	ProgressWarning ProgressEventKind = "warning"
+)
+
+// ProgressEvent is a step of an extraction. Fields that don't apply to the kind are zero.
+type ProgressEvent struct {
+	Kind    ProgressEventKind
+	Service string
+	// Message describes the event for people, e.g. "Processing batch 2/3 (100 operations)"
+	Message string
+	// Batch and Batches are the 1-based number of a classification batch and the number of batches
+	Batch      int
+	Batches    int
+	Operations int
+	File       string
+	// InputTokens and OutputTokens are the tokens Bedrock reported for a classified batch
+	InputTokens  int
+	OutputTokens int
+}
+
+// ProgressReporter receives the progress events of extractions. Services may be extracted
+// concurrently, so implementations must be safe for concurrent use.
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+}
+
+// ProgressFunc adapts a function to a ProgressReporter
+type ProgressFunc func(event ProgressEvent)
+
+// Report calls f
+func (f ProgressFunc) Report(event ProgressEvent) {
+	f(event)
+}
+
+// ProgressChannel sends progress events to a channel, which must be drained while extracting
+type ProgressChannel chan<- ProgressEvent
+
+// Report sends the event to the channel
+func (c ProgressChannel) Report(event ProgressEvent) {
+	c <- event
+}
+
+// textProgressReporter writes progress messages as lines, as the command line shows them
+type textProgressReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewTextProgressReporter creates a reporter writing the message of every event to w, warnings
+// prefixed with "Warning:". Service starts, parsed models and written files are left out, since
+// callers announce those in their own words.
+func NewTextProgressReporter(w io.Writer) ProgressReporter {
+	return &textProgressReporter{w: w}
+}
+
+func (r *textProgressReporter) Report(event ProgressEvent) {
+	switch event.Kind {
+	case ProgressServiceStarted, ProgressModelParsed, ProgressFileWritten:
+		return
+	}
+	line := event.Message
+	if event.Kind == ProgressWarning {
+		line = "Warning: " + line
+		if event.Service != "" {
+			line = fmt.Sprintf("Warning: %s: %s", event.Service, event.Message)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintln(r.w, line)
+}
+
+var progress struct {
+	sync.RWMutex
+	reporter ProgressReporter
+}
+
+// SetProgressReporter sets the reporter receiving the progress events of extractions;
+// nil discards them, which is the default
+func SetProgressReporter(reporter ProgressReporter) {
+	progress.Lock()
+	defer progress.Unlock()
+	progress.reporter = reporter
+}
+
+// reportProgress passes an event to the configured reporter
+func reportProgress(event ProgressEvent) {
+	progress.RLock()
+	reporter := progress.reporter
+	progress.RUnlock()
+	if reporter != nil {
+		reporter.Report(event)
+	}
+}
+
+// reportWarning reports a warning about a service, or about the run when serviceName is empty
+func reportWarning(serviceName, format string, args ...interface{}) {
+	reportProgress(ProgressEvent{Kind: ProgressWarning, Service: serviceName, Message: fmt.Sprintf(format, args...)})
+}
+
+// writeOutputFile writes an output file and reports it as written
+func writeOutputFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	reportProgress(ProgressEvent{Kind: ProgressFileWritten, File: path, Message: "Wrote " + path})
+	return nil
+}
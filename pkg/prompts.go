@@ -0,0 +1,144 @@
+package extractor
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+//go:embed prompts/*
+var promptFiles embed.FS
+
+// agentInstruction is the system instruction given to the inline classification agent
+var agentInstruction = embeddedAgentInstruction()
+
+// classificationTemplate renders the per-batch classification prompt
+var classificationTemplate = embeddedClassificationTemplate()
+
+// PromptData holds the variables available to classification prompt templates
+type PromptData struct {
+	ServiceName   string
+	Operations    []string
+	OperationList string
+}
+
+// LoadPromptTemplate replaces the embedded classification prompt with a template read from disk.
+// Templates use Go text/template syntax with the fields of PromptData.
+func LoadPromptTemplate(path string) error {
+	tmpl, err := readPromptTemplate(path)
+	if err != nil {
+		return err
+	}
+	classificationTemplate = tmpl
+	return nil
+}
+
+// LoadAgentInstruction replaces the embedded system instruction of the classification agent with
+// the contents of a file
+func LoadAgentInstruction(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read agent instruction %s: %w", path, err)
+	}
+	instruction := strings.TrimSpace(string(data))
+	if instruction == "" {
+		return fmt.Errorf("agent instruction %s is empty", path)
+	}
+	agentInstruction = instruction
+	return nil
+}
+
+// readPromptTemplate reads, parses and validates a classification prompt template
+func readPromptTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt template %s: %w", path, err)
+	}
+
+	tmpl, err := parsePromptTemplate(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// parsePromptTemplate parses a classification prompt template and renders it, along with its
+// follow-up prompt, for an empty batch, so references to fields PromptData lacks fail when the
+// template is loaded rather than at the first batch sent to Bedrock
+func parsePromptTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("classification").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range []*template.Template{tmpl, tmpl.Lookup(followUpTemplateName)} {
+		if t == nil {
+			continue
+		}
+		if err := t.Execute(io.Discard, PromptData{}); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// followUpTemplateName names the template rendering the prompt of later batches of a service. They
+// are sent in the agent session that already received the full prompt, so the classification rules
+// are sent once per service. Prompt templates without it send the full prompt with every batch.
+const followUpTemplateName = "followup"
+
+// buildClassificationInput creates the input text for operation classification
+func buildClassificationInput(tmpl *template.Template, serviceName string, operations []string) (string, error) {
+	return renderPrompt(tmpl, serviceName, operations)
+}
+
+// buildFollowUpInput creates the input text for a later batch of a service, reporting false when the
+// classification template defines no follow-up prompt
+func buildFollowUpInput(tmpl *template.Template, serviceName string, operations []string) (string, bool, error) {
+	followUp := tmpl.Lookup(followUpTemplateName)
+	if followUp == nil {
+		return "", false, nil
+	}
+	prompt, err := renderPrompt(followUp, serviceName, operations)
+	return prompt, err == nil, err
+}
+
+// renderPrompt renders a classification prompt template for a batch of operations
+func renderPrompt(tmpl *template.Template, serviceName string, operations []string) (string, error) {
+	var prompt strings.Builder
+	err := tmpl.Execute(&prompt, PromptData{
+		ServiceName:   serviceName,
+		Operations:    operations,
+		OperationList: strings.Join(operations, ", "),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render classification prompt: %w", err)
+	}
+
+	return strings.TrimSpace(prompt.String()), nil
+}
+
+// embeddedAgentInstruction returns the system instruction embedded into the binary
+func embeddedAgentInstruction() string {
+	return strings.TrimSpace(mustReadPrompt("prompts/instruction.txt"))
+}
+
+// embeddedClassificationTemplate returns the classification prompt template embedded into the binary
+func embeddedClassificationTemplate() *template.Template {
+	tmpl, err := parsePromptTemplate(mustReadPrompt("prompts/classification.tmpl"))
+	if err != nil {
+		panic(fmt.Sprintf("embedded classification prompt is invalid: %v", err))
+	}
+	return tmpl
+}
+
+// mustReadPrompt reads an embedded prompt file, panicking if it is missing from the build
+func mustReadPrompt(name string) string {
+	data, err := promptFiles.ReadFile(name)
+	if err != nil {
+		panic(fmt.Sprintf("embedded prompt %s not found: %v", name, err))
+	}
+	return string(data)
+}
@@ -0,0 +1,60 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadPromptTemplateValidatesFields(t *testing.T) {
+	tests := []struct {
+		content string
+		err     string
+	}{
+		{`Classify {{.OperationList}} of {{.ServiceName}}{{define "followup"}}{{range .Operations}}{{.}}{{end}}{{end}}`, ""},
+		{`Classify {{.OperationList}} of {{.Service}}`, "can't evaluate field Service"},
+		{`Classify {{.OperationList}}{{define "followup"}}Also {{.Batch}}{{end}}`, "can't evaluate field Batch"},
+		{`Classify {{.OperationList`, "unclosed action"},
+	}
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "prompt.tmpl")
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := readPromptTemplate(path)
+		if test.err == "" && err != nil {
+			t.Errorf("readPromptTemplate(%q): %v", test.content, err)
+		} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("readPromptTemplate(%q) = %v, want an error containing %q", test.content, err, test.err)
+		}
+	}
+}
+
+func TestLoadAgentInstruction(t *testing.T) {
+	t.Cleanup(ResetState)
+	embedded := agentInstruction
+
+	path := filepath.Join(t.TempDir(), "instruction.txt")
+	if err := os.WriteFile(path, []byte("  Classify operations as control plane or data plane.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadAgentInstruction(path); err != nil {
+		t.Fatal(err)
+	}
+	if prompt := defaultClassificationPrompt(); prompt.instruction != "Classify operations as control plane or data plane." {
+		t.Errorf("instruction = %q", prompt.instruction)
+	}
+
+	if err := os.WriteFile(path, []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadAgentInstruction(path); err == nil {
+		t.Error("LoadAgentInstruction accepted an empty instruction")
+	}
+
+	ResetState()
+	if agentInstruction != embedded {
+		t.Error("ResetState kept the loaded agent instruction")
+	}
+}
@@ -0,0 +1,236 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ackAPIGroupSuffix follows the service name in the API group of a controller's CRDs
+	ackAPIGroupSuffix = ".services.k8s.aws"
+	// controllerClusterRoleFile is the ClusterRole of a controller's service account, relative to the controller
+	controllerClusterRoleFile = "config/rbac/cluster-role-controller.yaml"
+	// rbacDefaultsSource is the RBAC source of pairings for controllers without a ClusterRole file
+	rbacDefaultsSource = "defaults"
+)
+
+var (
+	// ackResourceVerbs are the verbs ACK controllers are granted on their CRDs by default
+	ackResourceVerbs = []string{"create", "delete", "get", "list", "patch", "update", "watch"}
+	// ackStatusVerbs are the verbs ACK controllers are granted on the status of their CRDs by default
+	ackStatusVerbs = []string{"get", "patch", "update"}
+)
+
+// PairRBACAndIAM pairs every resource of a service's controller, one per directory in
+// pkg/resource, with the RBAC rules its CRD needs and the IAM actions of the supported operations
+// referenced in that directory. RBAC rules are read from the controller's
+// config/rbac/cluster-role-controller.yaml, falling back to the rules ACK generates. Rules and
+// actions not tied to one resource are listed as shared.
+func PairRBACAndIAM(serviceOps *ServiceOperations) (*RBACPairing, error) {
+	serviceName := serviceOps.ServiceName
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return nil, fmt.Errorf("controller not found for service %s", serviceName)
+	}
+	entries, err := os.ReadDir(filepath.Join(controllerPath, "pkg", "resource"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the resources of the %s controller: %w", serviceName, err)
+	}
+
+	group := serviceName + ackAPIGroupSuffix
+	pairing := &RBACPairing{
+		SchemaVersion:    SchemaVersion,
+		ServiceName:      serviceName,
+		APIGroup:         group,
+		RBACSource:       rbacDefaultsSource,
+		Resources:        []ResourcePairing{},
+		SharedRBACRules:  []RBACRule{},
+		SharedIAMActions: []string{},
+	}
+
+	kinds := resourceKinds(serviceName, serviceOps.Operations)
+	resourceIndex := make(map[string]int)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		kind := kinds[normalizeKind(entry.Name())]
+		if kind == "" {
+			kind = kindFromDirectory(entry.Name())
+		}
+		resourceIndex[entry.Name()] = len(pairing.Resources)
+		pairing.Resources = append(pairing.Resources, ResourcePairing{
+			Kind:       kind,
+			Resource:   strings.ToLower(pluralize(kind)),
+			RBACRules:  []RBACRule{},
+			IAMActions: []string{},
+			Operations: []string{},
+		})
+	}
+
+	shared := make(map[string]bool)
+	for _, op := range serviceOps.Operations {
+		if !op.IsSupported() {
+			continue
+		}
+		action := operationIAMAction(serviceName, op)
+		attributed := false
+		for _, location := range op.Locations {
+			index, ok := resourceIndex[resourceDirectory(location.File)]
+			if !ok {
+				continue
+			}
+			resource := &pairing.Resources[index]
+			if !slices.Contains(resource.Operations, op.Name) {
+				resource.Operations = append(resource.Operations, op.Name)
+				resource.IAMActions = append(resource.IAMActions, action)
+			}
+			attributed = true
+		}
+		if !attributed {
+			shared[action] = true
+		}
+	}
+	for i := range pairing.Resources {
+		sort.Strings(pairing.Resources[i].Operations)
+		pairing.Resources[i].IAMActions = sortedUnique(pairing.Resources[i].IAMActions)
+	}
+	for action := range shared {
+		pairing.SharedIAMActions = append(pairing.SharedIAMActions, action)
+	}
+	sort.Strings(pairing.SharedIAMActions)
+
+	rules, err := loadControllerRBACRules(filepath.Join(controllerPath, controllerClusterRoleFile))
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		for i := range pairing.Resources {
+			resource := &pairing.Resources[i]
+			resource.RBACRules = []RBACRule{
+				{APIGroups: []string{group}, Resources: []string{resource.Resource}, Verbs: ackResourceVerbs},
+				{APIGroups: []string{group}, Resources: []string{resource.Resource + "/status"}, Verbs: ackStatusVerbs},
+			}
+		}
+		return pairing, nil
+	}
+
+	pairing.RBACSource = controllerClusterRoleFile
+	pluralIndex := make(map[string]int, len(pairing.Resources))
+	for i, resource := range pairing.Resources {
+		pluralIndex[resource.Resource] = i
+	}
+	for _, rule := range rules {
+		if !slices.Contains(rule.APIGroups, group) {
+			pairing.SharedRBACRules = append(pairing.SharedRBACRules, rule)
+			continue
+		}
+		var leftover []string
+		for _, name := range rule.Resources {
+			plural, _, _ := strings.Cut(name, "/")
+			index, ok := pluralIndex[plural]
+			if !ok {
+				leftover = append(leftover, name)
+				continue
+			}
+			resource := &pairing.Resources[index]
+			resource.RBACRules = append(resource.RBACRules, RBACRule{APIGroups: []string{group}, Resources: []string{name}, Verbs: rule.Verbs})
+		}
+		if len(leftover) > 0 {
+			pairing.SharedRBACRules = append(pairing.SharedRBACRules, RBACRule{APIGroups: rule.APIGroups, Resources: leftover, Verbs: rule.Verbs})
+		}
+	}
+	return pairing, nil
+}
+
+// loadControllerRBACRules reads the rules of a controller's ClusterRole, nil when the file doesn't exist
+func loadControllerRBACRules(path string) ([]RBACRule, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var role struct {
+		Rules []RBACRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &role); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if role.Rules == nil {
+		role.Rules = []RBACRule{}
+	}
+	return role.Rules, nil
+}
+
+// resourceKinds maps normalized names to the resource kinds of a service known from generator.yaml
+// and the resources predicted from its operations
+func resourceKinds(serviceName string, operations []Operation) map[string]string {
+	kinds := make(map[string]string)
+	if generatorConfig, err := LoadControllerGeneratorConfig(serviceName); err == nil {
+		for _, name := range generatorConfig.ResourceNames() {
+			kinds[normalizeKind(name)] = name
+		}
+	}
+	for _, op := range operations {
+		if op.PredictedResource != "" && kinds[normalizeKind(op.PredictedResource)] == "" {
+			kinds[normalizeKind(op.PredictedResource)] = op.PredictedResource
+		}
+	}
+	return kinds
+}
+
+// resourceDirectory returns the resource package directory of a controller file, e.g. db_instance
+// for pkg/resource/db_instance/hooks.go, or "" for files outside pkg/resource
+func resourceDirectory(file string) string {
+	rest, ok := strings.CutPrefix(filepath.ToSlash(file), "pkg/resource/")
+	if !ok {
+		return ""
+	}
+	dir, _, ok := strings.Cut(rest, "/")
+	if !ok {
+		return ""
+	}
+	return dir
+}
+
+// normalizeKind lowercases a kind or resource directory and drops underscores so the two compare equal
+func normalizeKind(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// kindFromDirectory derives a kind from a resource directory, e.g. DbInstance from db_instance
+func kindFromDirectory(dir string) string {
+	var kind strings.Builder
+	for _, part := range strings.Split(dir, "_") {
+		if part != "" {
+			kind.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return kind.String()
+}
+
+// sortedUnique returns the sorted distinct values
+func sortedUnique(values []string) []string {
+	sorted := slices.Clone(values)
+	sort.Strings(sorted)
+	return slices.Compact(sorted)
+}
+
+// WriteRBACPairingJSON writes an RBAC and IAM pairing to a JSON file
+func WriteRBACPairingJSON(pairing *RBACPairing, outputPath string) error {
+	pairing.SchemaVersion = SchemaVersion
+	data, err := json.MarshalIndent(pairing, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal RBAC pairing JSON: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
@@ -0,0 +1,91 @@
+package extractor
+
+import "encoding/json"
+
+const (
+	unstableTrait   = "smithy.api#unstable"
+	deprecatedTrait = "smithy.api#deprecated"
+)
+
+// ReleaseStage describes how settled an operation's API is
+type ReleaseStage string
+
+const (
+	// ReleaseStageGA marks an operation without any stability trait
+	ReleaseStageGA ReleaseStage = "ga"
+	// ReleaseStagePreview marks an operation whose model is still subject to change
+	ReleaseStagePreview ReleaseStage = "preview"
+	// ReleaseStageDeprecated marks an operation that should no longer be implemented
+	ReleaseStageDeprecated ReleaseStage = "deprecated"
+)
+
+// deprecatedTraitValue represents the optional fields of the smithy.api#deprecated trait
+type deprecatedTraitValue struct {
+	Message string `json:"message"`
+	Since   string `json:"since"`
+}
+
+// ExtractReleaseStages returns the API version and release stage of each operation of a model,
+// keyed by operation name. The version is the service shape's API version date; the stage
+// comes from the unstable and deprecated traits, where a trait on the service applies to
+// every operation.
+func ExtractReleaseStages(model *AWSServiceModel) map[string]OperationRelease {
+	var apiVersion string
+	serviceStage := ReleaseStageGA
+	for _, shape := range model.Shapes {
+		if shape.Type != "service" {
+			continue
+		}
+		apiVersion = shape.Version
+		if _, ok := shape.Traits[unstableTrait]; ok {
+			serviceStage = ReleaseStagePreview
+		}
+		break
+	}
+
+	releases := make(map[string]OperationRelease)
+	for shapeName, shape := range model.Shapes {
+		if shape.Type != "operation" {
+			continue
+		}
+		release := OperationRelease{APIVersion: apiVersion, Stage: serviceStage}
+		if _, ok := shape.Traits[unstableTrait]; ok {
+			release.Stage = ReleaseStagePreview
+		}
+		if raw, ok := shape.Traits[deprecatedTrait]; ok {
+			release.Stage = ReleaseStageDeprecated
+			var deprecated deprecatedTraitValue
+			if json.Unmarshal(raw, &deprecated) == nil {
+				release.DeprecatedSince = deprecated.Since
+				release.DeprecationMessage = deprecated.Message
+			}
+		}
+		releases[extractOperationName(shapeName)] = release
+	}
+	return releases
+}
+
+// ApplyReleaseStages attaches the API version and release stage of each operation
+func ApplyReleaseStages(operations []Operation, releases map[string]OperationRelease) {
+	for i := range operations {
+		release, ok := releases[operations[i].Name]
+		if !ok {
+			continue
+		}
+		operations[i].APIVersion = release.APIVersion
+		operations[i].ReleaseStage = release.Stage
+		operations[i].DeprecatedSince = release.DeprecatedSince
+		operations[i].DeprecationMessage = release.DeprecationMessage
+	}
+}
+
+// CountReleaseStages counts the operations in each release stage
+func CountReleaseStages(operations []Operation) map[ReleaseStage]int {
+	counts := make(map[ReleaseStage]int)
+	for _, op := range operations {
+		if op.ReleaseStage != "" {
+			counts[op.ReleaseStage]++
+		}
+	}
+	return counts
+}
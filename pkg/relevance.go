@@ -0,0 +1,82 @@
+package extractor
+
+// Relevance scores for operations by verb. A declarative controller reconciles desired
+// state, so lifecycle and configuration operations are relevant while one-off actions
+// (RebootInstance, TestConnection, SendMessage, ...) are not.
+const (
+	relevanceDeclarative = 1.0
+	relevanceRead        = 0.8
+	relevanceUnknown     = 0.5
+	relevanceImperative  = 0.1
+
+	// minDeclarativeRelevance is the score from which an operation counts as declarative
+	minDeclarativeRelevance = 0.5
+)
+
+// declarativeVerbs change the desired state of a resource
+var declarativeVerbs = map[string]bool{
+	"Create": true, "Delete": true, "Update": true, "Put": true, "Modify": true,
+	"Tag": true, "Untag": true, "Associate": true, "Disassociate": true,
+	"Attach": true, "Detach": true, "Enable": true, "Disable": true,
+	"Add": true, "Remove": true, "Set": true, "Register": true, "Deregister": true,
+	"Authorize": true, "Revoke": true, "Allocate": true, "Release": true,
+}
+
+// readVerbs observe resource state and back a controller's read path
+var readVerbs = map[string]bool{
+	"Describe": true, "Get": true, "List": true,
+}
+
+// imperativeVerbs perform one-off actions with no declarative desired state
+var imperativeVerbs = map[string]bool{
+	"Reboot": true, "Test": true, "Invoke": true, "Send": true, "Publish": true,
+	"Execute": true, "Cancel": true, "Retry": true, "Simulate": true, "Validate": true,
+	"Preview": true, "Estimate": true, "Failover": true, "Poll": true, "Receive": true,
+	"Query": true, "Scan": true, "Search": true, "Select": true, "Download": true,
+	"Upload": true, "Decrypt": true, "Encrypt": true, "Sign": true, "Verify": true,
+	"Generate": true, "Detect": true, "Check": true, "Run": true, "Start": true, "Stop": true,
+}
+
+// ScoreRelevance estimates how relevant an operation is to a declarative ACK controller
+func ScoreRelevance(operationName string) float64 {
+	verb := operationVerb(operationName)
+	switch {
+	case declarativeVerbs[verb]:
+		return relevanceDeclarative
+	case readVerbs[verb]:
+		return relevanceRead
+	case imperativeVerbs[verb]:
+		return relevanceImperative
+	default:
+		return relevanceUnknown
+	}
+}
+
+// ApplyRelevance scores every operation and flags the declarative ones.
+// Operations the controller already supports are always considered declarative.
+func ApplyRelevance(operations []Operation) {
+	for i := range operations {
+		operations[i].Relevance = ScoreRelevance(operations[i].Name)
+		operations[i].Declarative = operations[i].Relevance >= minDeclarativeRelevance || operations[i].IsSupported()
+	}
+}
+
+// RelevantCoverage returns the percentage of declarative, non-ignored, non-excluded operations with controller
+// code, so one-off actions a declarative controller would never implement don't lower coverage
+func RelevantCoverage(operations []Operation) float64 {
+	total := 0
+	supported := 0
+	for _, op := range operations {
+		if !op.Declarative || !op.SupportStatus.Countable() {
+			continue
+		}
+		total++
+		if op.IsSupported() {
+			supported++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(supported) * 100 / float64(total)
+}
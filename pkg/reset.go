@@ -0,0 +1,77 @@
+package extractor
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ResetState restores the package-level configuration and caches to their defaults: the models and
+// controllers directories and their path templates, loaded configuration files, the progress
+// reporter, the classification cache, cached service prefixes and metadata and run counters. Tests and
+// long-running callers use it to extract with a clean slate.
+func ResetState() {
+	modelsRoot = filepath.Join("..", "api-models-aws", "models")
+	modelSources = nil
+	controllersRoot = ".."
+	modelPathTemplate = ""
+	controllerPathTemplate = ""
+	controllerReleases = make(map[string]*ControllerRelease)
+	cacheRoot = ""
+	configFile = ""
+	cacheFallbackWarning = sync.Once{}
+	noController = false
+	subAPIs = nil
+	detectSubAPIs = false
+	guardrail = nil
+	acceptClassificationDrift = false
+	classificationFailurePolicy = ClassificationFailureContinue
+	classificationSamplePercent = 0
+	matcherConfigs = nil
+	scanLimits = DefaultScanLimits()
+	roadmap = nil
+	excludedOperations = mustParseExclusions(exclusionDataset)
+	wildcardOnlyActions = mustParseResourceLevelSupport(resourceLevelDataset)
+	curatedAccessLevels = mustParseAccessLevels(accessLevelDataset)
+	groupPolicyByAccessLevel = false
+	arnTypes = mustParseARNTypes(arnTypeDataset)
+	groupPolicyByARNType = false
+	classificationOverrides = nil
+	classificationRules = mustParseClassificationRules(classificationRulesDataset)
+	describeRules = mustParseDescribeRules(describeRulesDataset)
+	activeCheckpoint = nil
+	sharedClassificationCache = NewClassificationCache()
+	classificationTemplate = embeddedClassificationTemplate()
+	agentInstruction = embeddedAgentInstruction()
+
+	githubRateLimitWait = DefaultGitHubRateLimitWait
+	githubRateLimit.Lock()
+	githubRateLimit.known = false
+	githubRateLimit.remaining = 0
+	githubRateLimit.reset = time.Time{}
+	githubRateLimit.Unlock()
+
+	goSDKModels.Lock()
+	goSDKModels.models = nil
+	goSDKModels.Unlock()
+
+	servicePrefixes.Lock()
+	servicePrefixes.prefixes = make(map[string]string)
+	servicePrefixes.Unlock()
+
+	serviceMetadataCache.Lock()
+	serviceMetadataCache.metadata = make(map[string]*ServiceMetadata)
+	serviceMetadataCache.Unlock()
+
+	progress.Lock()
+	progress.reporter = nil
+	progress.Unlock()
+
+	bedrockStats.Lock()
+	bedrockStats.BedrockMetrics = BedrockMetrics{}
+	bedrockStats.classificationCacheHits = 0
+	bedrockStats.checkpointBatchHits = 0
+	bedrockStats.classificationErrors = nil
+	bedrockStats.batchSeconds = nil
+	bedrockStats.Unlock()
+}
@@ -0,0 +1,196 @@
+package extractor
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// readOnlyGlobals are the package-level variables ResetState leaves alone because nothing assigns
+// them after initialization: embedded datasets, patterns and lookup tables
+var readOnlyGlobals = map[string]bool{
+	"ErrStopIteration": true, "OperationSubsets": true, "accessLevelDataset": true, "accessLevelOrder": true,
+	"ackResourceVerbs": true, "ackStatusVerbs": true, "actionPattern": true,
+	"arnTypeDataset": true, "arnVariable": true, "badgeColors": true, "bedrockProblemDescriptions": true,
+	"classificationRulesDataset": true, "clientCallPattern": true, "clientConstructorPattern": true,
+	"clientFieldPattern": true, "codeownersPaths": true, "consistencyDataset": true,
+	"containerControllersMounts": true, "containerModelsMounts": true, "curatedConsistency": true,
+	"declarativeVerbs": true, "dependencyReasons": true, "describeRulesDataset": true, "documentKinds": true,
+	"errModelNotInSource": true, "eventualConsistencyPattern": true, "exclusionDataset": true,
+	"githubHosts": true, "goSDKPrelude": true, "goldenDataset": true, "guardrailVersionPattern": true,
+	"imperativeVerbs": true, "inputCallPattern": true, "integrationPermissions": true,
+	"internalDocumentationPattern": true, "invalidTokenErrorCodes": true, "knownPartitions": true,
+	"lifecycleVerbs": true, "mutatingVerbs": true, "operationAnnotationPattern": true,
+	"pathTemplatePlaceholder": true, "permissionsNamePattern": true, "promptFiles": true,
+	"readAccessVerbs": true, "readVerbs": true, "recordAPICallPattern": true, "resourceLevelDataset": true,
+	"reviewHeader": true, "runSessionID": true, "schemaMigrations": true, "scpBaselineActions": true,
+	"sdkAPICallPattern": true, "sdkIdentifierSuffixes": true, "sdkImportPattern": true, "sdkPackagePrefixes": true,
+	"semanticGroupOrder": true, "semanticGroupRules": true, "simpleTypeSchemas": true, "smithyPrelude": true,
+	"taggingNamePattern": true, "taggingOperations": true, "throttlingErrorCodes": true, "verbPattern": true,
+}
+
+// packageGlobals parses the non-test files of the package and returns them with the top-level
+// variable declarations they hold
+func packageGlobals(t *testing.T) ([]*ast.File, map[*ast.ValueSpec]bool, map[string]bool) {
+	t.Helper()
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	specs := make(map[*ast.ValueSpec]bool)
+	names := make(map[string]bool)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+				for _, spec := range gen.Specs {
+					specs[spec.(*ast.ValueSpec)] = true
+					for _, name := range spec.(*ast.ValueSpec).Names {
+						names[name.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return files, specs, names
+}
+
+// assignedGlobal returns the package-level variable an assignment target or method receiver is
+// rooted at, e.g. bedrockStats for bedrockStats.Metrics or arnTypes for arnTypes[prefix]
+func assignedGlobal(expr ast.Expr, specs map[*ast.ValueSpec]bool, globals map[string]bool) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			// Identifiers resolved to a local declaration shadow the global of the same name
+			if e.Obj != nil {
+				if spec, ok := e.Obj.Decl.(*ast.ValueSpec); !ok || !specs[spec] {
+					return ""
+				}
+			}
+			if globals[e.Name] {
+				return e.Name
+			}
+			return ""
+		default:
+			return ""
+		}
+	}
+}
+
+func TestResetStateCoversMutableGlobals(t *testing.T) {
+	files, specs, globals := packageGlobals(t)
+
+	reset := make(map[string]bool)
+	assigned := make(map[string][]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			ast.Inspect(fn.Body, func(node ast.Node) bool {
+				var targets []ast.Expr
+				switch n := node.(type) {
+				case *ast.AssignStmt:
+					targets = n.Lhs
+				case *ast.IncDecStmt:
+					targets = []ast.Expr{n.X}
+				case *ast.CallExpr:
+					// Locking or clearing guarded state counts as resetting it
+					if fn.Name.Name == "ResetState" && fn.Recv == nil {
+						if selector, ok := n.Fun.(*ast.SelectorExpr); ok {
+							targets = []ast.Expr{selector.X}
+						}
+					}
+				}
+				for _, target := range targets {
+					name := assignedGlobal(target, specs, globals)
+					if name == "" {
+						continue
+					}
+					if fn.Name.Name == "ResetState" && fn.Recv == nil {
+						reset[name] = true
+					} else if _, isCall := node.(*ast.CallExpr); !isCall {
+						assigned[name] = append(assigned[name], fn.Name.Name)
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	var missing []string
+	for name := range globals {
+		if !reset[name] && !readOnlyGlobals[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		t.Errorf("package variable %s is not reset by ResetState; reset it there or, if nothing assigns it after initialization, add it to readOnlyGlobals", name)
+	}
+
+	for name := range readOnlyGlobals {
+		if !globals[name] {
+			t.Errorf("readOnlyGlobals lists %s, which is not a package variable", name)
+		}
+		if writers := assigned[name]; len(writers) > 0 {
+			t.Errorf("%s is listed in readOnlyGlobals but assigned by %s; reset it in ResetState instead", name, strings.Join(writers, ", "))
+		}
+	}
+}
+
+func TestResetStateRestoresDefaults(t *testing.T) {
+	ResetState()
+	t.Cleanup(ResetState)
+
+	SetNoController(true)
+	SetGroupPolicyByAccessLevel(true)
+	SetGroupPolicyByARNType(true)
+	SetDetectSubAPIs(true)
+	if err := SetSubAPIs([]string{"foo=foo-streams"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetModelsDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	SetProgressReporter(NewTextProgressReporter(nil))
+	arnTypes["foo"] = []ARNType{{Type: "bar"}}
+	ResetState()
+
+	if noController || groupPolicyByAccessLevel || groupPolicyByARNType || detectSubAPIs || subAPIs != nil {
+		t.Error("ResetState left a setting enabled")
+	}
+	if modelsRoot != filepath.Join("..", "api-models-aws", "models") {
+		t.Errorf("models directory = %s after ResetState", modelsRoot)
+	}
+	if _, ok := arnTypes["foo"]; ok {
+		t.Error("ResetState kept a loaded ARN type")
+	}
+	progress.Lock()
+	defer progress.Unlock()
+	if progress.reporter != nil {
+		t.Error("ResetState kept the progress reporter")
+	}
+}
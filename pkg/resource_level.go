@@ -0,0 +1,88 @@
+package extractor
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// wildcardOnlyStatementSid names the policy statement granting actions without resource-level permissions
+const wildcardOnlyStatementSid = "NonResourceLevelActions"
+
+//go:embed datasets/resource_level_support.yaml
+var resourceLevelDataset []byte
+
+// wildcardOnlyActions maps IAM service prefixes to the actions that only support Resource "*"
+var wildcardOnlyActions = mustParseResourceLevelSupport(resourceLevelDataset)
+
+// mustParseResourceLevelSupport parses the embedded resource-level support dataset
+func mustParseResourceLevelSupport(data []byte) map[string][]string {
+	actions, err := parseResourceLevelSupport(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid resource-level support dataset: %v", err))
+	}
+	return actions
+}
+
+// parseResourceLevelSupport reads a file mapping IAM service prefixes to wildcard-only actions
+func parseResourceLevelSupport(data []byte) (map[string][]string, error) {
+	actions := make(map[string][]string)
+	if err := yaml.Unmarshal(data, &actions); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// LoadResourceLevelSupport adds the actions of a YAML file to the embedded dataset of actions
+// without resource-level permissions:
+//
+//	dynamodb:
+//	  - ListTables
+func LoadResourceLevelSupport(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read resource-level support file %s: %w", path, err)
+	}
+	actions, err := parseResourceLevelSupport(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse resource-level support file %s: %w", path, err)
+	}
+
+	for prefix, names := range actions {
+		wildcardOnlyActions[prefix] = append(wildcardOnlyActions[prefix], names...)
+	}
+	return nil
+}
+
+// SupportsResourceLevelPermissions reports whether an IAM action (<prefix>:<Action>) can be scoped
+// to resource ARNs. Actions the dataset doesn't list are assumed to support it.
+func SupportsResourceLevelPermissions(action string) bool {
+	prefix, name, ok := strings.Cut(action, ":")
+	if !ok {
+		return true
+	}
+	for _, pattern := range wildcardOnlyActions[prefix] {
+		if base, isPrefix := strings.CutSuffix(pattern, "*"); isPrefix && strings.HasPrefix(name, base) {
+			return false
+		}
+		if pattern == name {
+			return false
+		}
+	}
+	return true
+}
+
+// splitByResourceLevelSupport separates actions that can be scoped to resource ARNs from those that only support Resource "*"
+func splitByResourceLevelSupport(actions []string) (scoped, wildcardOnly []string) {
+	for _, action := range actions {
+		if SupportsResourceLevelPermissions(action) {
+			scoped = append(scoped, action)
+		} else {
+			wildcardOnly = append(wildcardOnly, action)
+		}
+	}
+	return scoped, wildcardOnly
+}
@@ -0,0 +1,170 @@
+package extractor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DetectResponseDrift compares the output shapes of each controller resource's read operation
+// with the resource's generated Spec and Status types in apis/v1alpha1, and reports response
+// fields the CRD does not have. Missing fields usually mean the controller's generated code
+// is older than the model and needs to be regenerated.
+func DetectResponseDrift(serviceName string) ([]ResponseDrift, error) {
+	model, err := loadServiceModel(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return nil, fmt.Errorf("controller not found for service %s", serviceName)
+	}
+	crdFields, err := loadCRDFields(filepath.Join(controllerPath, "apis", "v1alpha1"))
+	if err != nil {
+		return nil, err
+	}
+
+	operationNames := ResolveOperations(model).Operations
+	readOps := make(map[string]OperationNaming)
+	for _, name := range operationNames {
+		naming := classifyOperationName(name)
+		switch naming.OperationType {
+		case ACKOpReadOne:
+			readOps[naming.Resource] = naming
+		case ACKOpReadMany:
+			// Prefer a ReadOne operation when the resource has both
+			if existing, ok := readOps[naming.Resource]; !ok || existing.OperationType != ACKOpReadOne {
+				readOps[naming.Resource] = naming
+			}
+		}
+	}
+
+	var drifts []ResponseDrift
+	for resource, fields := range crdFields {
+		readOp, ok := readOps[resource]
+		if !ok {
+			continue
+		}
+		members := responseMembers(model, readOp.Operation)
+		var missing []string
+		for _, member := range members {
+			// ACK stores the primary ARN of a resource in status.ackResourceMetadata
+			if fields[normalizeFieldName("ACKResourceMetadata")] && (member == "Arn" || member == resource+"Arn") {
+				continue
+			}
+			if !fields[normalizeFieldName(member)] {
+				missing = append(missing, member)
+			}
+		}
+		if len(missing) > 0 {
+			drifts = append(drifts, ResponseDrift{
+				Resource:      resource,
+				Operation:     readOp.Operation,
+				MissingFields: missing,
+			})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Resource < drifts[j].Resource })
+	return drifts, nil
+}
+
+// responseMembers returns the sorted member names of an operation's response. Outputs wrapping
+// a single structure (DescribeTable returns Table) or a list of structures (DescribeDBInstances
+// returns DBInstances) are unwrapped the way the ACK code generator reads them.
+func responseMembers(model *AWSServiceModel, operationName string) []string {
+	var operation ServiceShape
+	found := false
+	for id, shape := range model.Shapes {
+		if shape.Type == "operation" && extractOperationName(id) == operationName {
+			operation, found = shape, true
+			break
+		}
+	}
+	if !found || operation.Output == nil {
+		return nil
+	}
+
+	output := model.Shapes[operation.Output.Target]
+	if wrapped := unwrapResponse(model, output); wrapped != nil {
+		output = *wrapped
+	}
+	return sortedMemberNames(output)
+}
+
+// unwrapResponse returns the structure wrapped by an output with a single structure or list member
+func unwrapResponse(model *AWSServiceModel, output ServiceShape) *ServiceShape {
+	var structures []ServiceShape
+	for name, member := range output.Members {
+		if name == "NextToken" || name == "Marker" {
+			continue
+		}
+		target := model.Shapes[member.Target]
+		if target.Type == "list" && target.Member != nil {
+			target = model.Shapes[target.Member.Target]
+		}
+		if target.Type == "structure" {
+			structures = append(structures, target)
+		}
+	}
+	if len(structures) != 1 || len(output.Members) > 2 {
+		return nil
+	}
+	return &structures[0]
+}
+
+// loadCRDFields parses the Go files of a controller's API package and returns the normalized
+// field names of each resource's Spec and Status structs, keyed by resource name
+func loadCRDFields(apisPath string) (map[string]map[string]bool, error) {
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(apisPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API types in %s: %w", apisPath, err)
+	}
+
+	fields := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(apisPath, entry.Name()), nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			structType, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			resource := strings.TrimSuffix(strings.TrimSuffix(spec.Name.Name, "Spec"), "Status")
+			if resource == spec.Name.Name {
+				return false
+			}
+			if fields[resource] == nil {
+				fields[resource] = make(map[string]bool)
+			}
+			for _, field := range structType.Fields.List {
+				for _, name := range field.Names {
+					fields[resource][normalizeFieldName(name.Name)] = true
+				}
+			}
+			return false
+		})
+	}
+	return fields, nil
+}
+
+// normalizeFieldName folds the casing differences between model member names and
+// generated Go field names, e.g. TableArn and TableARN
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
@@ -0,0 +1,277 @@
+package extractor
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Reasons an operation's classification needs human review
+const (
+	ReviewReasonUnknown       = "unknown"
+	ReviewReasonConflicting   = "conflicting"
+	ReviewReasonLowConfidence = "low-confidence"
+	ReviewReasonDrift         = "drift"
+)
+
+// Reviewer decisions in the review CSV
+const (
+	ReviewAccept = "accept"
+	ReviewReject = "reject"
+)
+
+// reviewHeader is the header row of the review CSV
+var reviewHeader = []string{"service", "operation", "type", "support_status", "reason", "decision", "corrected_type"}
+
+// ReviewItem is a classification presented to a human reviewer
+type ReviewItem struct {
+	Service       string
+	Operation     string
+	Type          OperationType
+	SupportStatus SupportStatus
+	Reason        string
+	// Decision is accept or reject, filled in by the reviewer
+	Decision string
+	// CorrectedType is the type to use for a rejected classification; the opposite plane when empty
+	CorrectedType OperationType
+}
+
+// ClassificationOverrides maps a service name to human-decided operation types
+type ClassificationOverrides map[string]map[string]OperationType
+
+// classificationOverrides take precedence over automatic and Bedrock classification
+var classificationOverrides ClassificationOverrides
+
+// ReviewCandidates returns the classifications that need review: operations whose classification
+// failed, operations whose classification flipped since an earlier run, operations sibling
+// services classified differently according to the shared
+// classification cache, and classifications contradicting the operation's verb (e.g. a Create*
+// operation classified as data plane), which are the model's least confident answers.
+func ReviewCandidates(services []*ServiceOperations) []ReviewItem {
+	var items []ReviewItem
+	for _, serviceOps := range services {
+		for _, op := range serviceOps.Operations {
+			if override := classificationOverrides[serviceOps.ServiceName][op.Name]; override != "" {
+				continue
+			}
+			reason := reviewReason(op)
+			if reason == "" {
+				continue
+			}
+			items = append(items, ReviewItem{
+				Service:       serviceOps.ServiceName,
+				Operation:     op.Name,
+				Type:          op.Type,
+				SupportStatus: op.SupportStatus,
+				Reason:        reason,
+			})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Service != items[j].Service {
+			return items[i].Service < items[j].Service
+		}
+		return items[i].Operation < items[j].Operation
+	})
+	return items
+}
+
+// reviewReason returns why an operation's classification needs review, or an empty string
+func reviewReason(op Operation) string {
+	switch {
+	case op.Type == OperationTypeUnknown:
+		return ReviewReasonUnknown
+	case !op.Type.IsClassified():
+		return ""
+	case op.ClassificationDrift != nil && !op.ClassificationDrift.Accepted:
+		return ReviewReasonDrift
+	case sharedClassificationCache.conflicting(op.Name):
+		return ReviewReasonConflicting
+	case op.Type.IsDataPlane() && ScoreRelevance(op.Name) == relevanceDeclarative,
+		op.Type.IsControlPlane() && !op.IsSupported() && ScoreRelevance(op.Name) == relevanceImperative:
+		return ReviewReasonLowConfidence
+	}
+	return ""
+}
+
+// WriteReviewCSV writes review items to a CSV file with empty decision columns for the reviewer
+func WriteReviewCSV(items []ReviewItem, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write(reviewHeader)
+	for _, item := range items {
+		w.Write([]string{item.Service, item.Operation, item.Type.String(), item.SupportStatus.String(), item.Reason, item.Decision, string(item.CorrectedType)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadReviewCSV reads a review CSV filled in by a reviewer. Columns are matched by header name,
+// so spreadsheets that reorder or add columns can be imported.
+func ReadReviewCSV(path string) ([]ReviewItem, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header of %s: %w", path, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"service", "operation", "type", "decision"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%s has no %s column", path, name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var items []ReviewItem
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		opType, err := ParseOperationType(field(record, "type"))
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, line, err)
+		}
+		item := ReviewItem{
+			Service:   field(record, "service"),
+			Operation: field(record, "operation"),
+			Type:      opType,
+			Reason:    field(record, "reason"),
+			Decision:  strings.ToLower(field(record, "decision")),
+		}
+		if corrected := field(record, "corrected_type"); corrected != "" {
+			item.CorrectedType, err = ParseOperationType(corrected)
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d: %w", path, line, err)
+			}
+		}
+		if item.Decision != "" && item.Decision != ReviewAccept && item.Decision != ReviewReject {
+			return nil, fmt.Errorf("%s line %d: decision must be %s or %s, got %q", path, line, ReviewAccept, ReviewReject, item.Decision)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// MergeReviewDecisions folds reviewed items into overrides. Accepted items keep their type and
+// rejected items take the corrected type, or the opposite plane when none is given. Items
+// without a decision are left out. It returns the number of merged items and messages for
+// items that could not be merged.
+func MergeReviewDecisions(overrides ClassificationOverrides, items []ReviewItem) (int, []string) {
+	merged := 0
+	var skipped []string
+	for _, item := range items {
+		var decided OperationType
+		switch item.Decision {
+		case "":
+			continue
+		case ReviewAccept:
+			decided = item.Type
+		case ReviewReject:
+			decided = item.CorrectedType
+			if decided == "" {
+				switch item.Type {
+				case OperationTypeControlPlane:
+					decided = OperationTypeDataPlane
+				case OperationTypeDataPlane:
+					decided = OperationTypeControlPlane
+				}
+			}
+		}
+		if !decided.IsClassified() {
+			skipped = append(skipped, fmt.Sprintf("%s %s: %s decision needs a corrected_type of control_plane or data_plane", item.Service, item.Operation, item.Decision))
+			continue
+		}
+		if overrides[item.Service] == nil {
+			overrides[item.Service] = make(map[string]OperationType)
+		}
+		overrides[item.Service][item.Operation] = decided
+		merged++
+	}
+	return merged, skipped
+}
+
+// LoadClassificationOverrides reads a YAML overrides file and applies it to later extractions:
+//
+//	s3:
+//	  PutBucketPolicy: control_plane
+//	  SelectObjectContent: data_plane
+//
+// A missing file yields empty overrides so import-review can create it.
+func LoadClassificationOverrides(path string) (ClassificationOverrides, error) {
+	overrides := make(ClassificationOverrides)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		classificationOverrides = overrides
+		return overrides, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read classification overrides %s: %w", path, err)
+	}
+
+	var raw map[string]map[string]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse classification overrides %s: %w", path, err)
+	}
+	for serviceName, operations := range raw {
+		overrides[serviceName] = make(map[string]OperationType)
+		for name, value := range operations {
+			opType, err := ParseOperationType(value)
+			if err != nil || !opType.IsClassified() {
+				return nil, fmt.Errorf("classification override %s %s must be control_plane or data_plane, got %q", serviceName, name, value)
+			}
+			overrides[serviceName][name] = opType
+		}
+	}
+
+	classificationOverrides = overrides
+	return overrides, nil
+}
+
+// SaveClassificationOverrides writes overrides to a YAML file
+func SaveClassificationOverrides(overrides ClassificationOverrides, path string) error {
+	raw := make(map[string]map[string]string, len(overrides))
+	for serviceName, operations := range overrides {
+		raw[serviceName] = make(map[string]string, len(operations))
+		for name, opType := range operations {
+			raw[serviceName][name] = string(opType)
+		}
+	}
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal classification overrides: %w", err)
+	}
+	return writeOutputFile(path, data)
+}
@@ -0,0 +1,181 @@
+package extractor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RunState records content hashes of the inputs of every service extracted by previous runs,
+// so services whose model and controller did not change can be skipped. The state of every
+// output directory is kept in one global state file in the cache directory.
+type RunState struct {
+	path      string
+	outputDir string
+
+	Services map[string]string
+}
+
+// runStateFile is the format of the global state file, keyed by absolute output directory
+type runStateFile struct {
+	Outputs map[string]map[string]string `json:"outputs"`
+}
+
+// LoadRunState reads the state of an output directory from a state file. A missing file or
+// output directory yields an empty state.
+func LoadRunState(path, outputDir string) (*RunState, error) {
+	abs, err := filepath.Abs(outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve output directory %s: %w", outputDir, err)
+	}
+	state := &RunState{path: path, outputDir: abs, Services: make(map[string]string)}
+
+	file, err := readRunStateFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for serviceName, hash := range file.Outputs[abs] {
+		state.Services[serviceName] = hash
+	}
+	return state, nil
+}
+
+// LoadLegacyRunState reads the per-output-directory state file written by earlier versions
+func LoadLegacyRunState(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var legacy struct {
+		Services map[string]string `json:"services"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return legacy.Services, nil
+}
+
+// readRunStateFile reads the global state file. A missing file yields an empty state.
+func readRunStateFile(path string) (*runStateFile, error) {
+	file := &runStateFile{Outputs: make(map[string]map[string]string)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if file.Outputs == nil {
+		file.Outputs = make(map[string]map[string]string)
+	}
+	return file, nil
+}
+
+// Unchanged reports whether a service was extracted before from inputs with the same hash
+func (s *RunState) Unchanged(serviceName, hash string) bool {
+	return s.Services[serviceName] == hash
+}
+
+// Record stores the input hash of a successfully extracted service
+func (s *RunState) Record(serviceName, hash string) {
+	s.Services[serviceName] = hash
+}
+
+// Save writes the state of the output directory to the state file. The file is read again
+// first so the state of other output directories, possibly saved meanwhile, is kept.
+func (s *RunState) Save() error {
+	file, err := readRunStateFile(s.path)
+	if err != nil {
+		return err
+	}
+	file.Outputs[s.outputDir] = s.Services
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// ServiceInputHash returns a content hash of everything an extraction of the service reads:
+// the model files including those of sub-APIs, the controller's pkg tree outside excluded
+// directories and generator.yaml, and the given settings (e.g. flags or configuration files
+// that change the output)
+func ServiceInputHash(serviceName string, settings ...string) (string, error) {
+	hasher := sha256.New()
+
+	if err := hashServiceModels(hasher, serviceName); err != nil {
+		return "", err
+	}
+	for _, subAPI := range SubAPIs(serviceName) {
+		// Sub-API models that can't be read are reported when extracting
+		_ = hashServiceModels(hasher, subAPI)
+	}
+
+	if controllerPath := findControllerForService(serviceName); controllerPath != "" {
+		var files []string
+		walkErr := filepath.Walk(filepath.Join(controllerPath, "pkg"), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() && scanLimits.skipsDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			if !info.IsDir() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if walkErr != nil {
+			return "", fmt.Errorf("failed to walk controller for %s: %w", serviceName, walkErr)
+		}
+		if generatorFile := filepath.Join(controllerPath, "generator.yaml"); fileExists(generatorFile) {
+			files = append(files, generatorFile)
+		}
+
+		sort.Strings(files)
+		for _, file := range files {
+			relPath, _ := filepath.Rel(controllerPath, file)
+			if err := hashFile(hasher, file, filepath.ToSlash(relPath)); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	fmt.Fprintf(hasher, "settings\x00%s\x00", strings.Join(settings, "\x00"))
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// hashFile writes a file's name and content into the hash
+func hashFile(w io.Writer, path, name string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	fmt.Fprintf(w, "%s\x00", name)
+	if _, err := io.Copy(w, file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	w.Write([]byte{0})
+	return nil
+}
+
+// fileExists reports whether a regular file exists at path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
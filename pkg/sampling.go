@@ -0,0 +1,90 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// z95 is the z-score of a 95% confidence interval
+const z95 = 1.96
+
+// classificationSamplePercent is the share of unsupported operations sent for classification.
+// Zero classifies every operation.
+var classificationSamplePercent float64
+
+// ParseSamplePercent parses a sample size such as "10%" or "10" into a percentage
+func ParseSamplePercent(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample size %q: %w", value, err)
+	}
+	if percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("sample size %q must be greater than 0%% and at most 100%%", value)
+	}
+	return percent, nil
+}
+
+// SetClassificationSample limits classification to a random sample of the unsupported operations
+func SetClassificationSample(percent float64) {
+	classificationSamplePercent = percent
+}
+
+// sampleOperations splits operations into a random sample of the configured size and the
+// operations left out of it, which are marked as unsampled
+func sampleOperations(operations []Operation) (sampled []Operation, unsampled []Operation) {
+	if classificationSamplePercent <= 0 || classificationSamplePercent >= 100 {
+		return operations, nil
+	}
+
+	size := int(math.Ceil(float64(len(operations)) * classificationSamplePercent / 100))
+	shuffled := make([]Operation, len(operations))
+	copy(shuffled, operations)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	for i := range shuffled[size:] {
+		shuffled[size+i].Type = OperationTypeUnsampled
+	}
+	return shuffled[:size], shuffled[size:]
+}
+
+// EstimateClassificationSplit estimates the control plane share of the unsupported operations
+// from the ones classified in a sampled run, with a 95% margin of error
+func EstimateClassificationSplit(operations []Operation) *ClassificationSample {
+	sample := &ClassificationSample{SamplePercent: classificationSamplePercent}
+	for _, op := range operations {
+		if op.IsSupported() {
+			continue
+		}
+		switch {
+		case op.Type == OperationTypeUnsampled:
+			sample.UnsampledOperations++
+		case op.Type.IsControlPlane():
+			sample.ControlPlaneOps++
+		case op.Type.IsDataPlane():
+			sample.DataPlaneOps++
+		}
+	}
+
+	classified := sample.ControlPlaneOps + sample.DataPlaneOps
+	sample.SampledOperations = classified
+	if classified == 0 {
+		return sample
+	}
+
+	share := float64(sample.ControlPlaneOps) / float64(classified)
+	population := float64(classified + sample.UnsampledOperations)
+	// The finite population correction shrinks the margin as the sample covers more of the service
+	correction := 1.0
+	if population > 1 {
+		correction = math.Sqrt((population - float64(classified)) / (population - 1))
+	}
+	margin := z95 * math.Sqrt(share*(1-share)/float64(classified)) * correction
+
+	sample.EstimatedControlPlaneShare = share * 100
+	sample.MarginOfError = margin * 100
+	sample.EstimatedControlPlaneOps = int(math.Round(share * population))
+	return sample
+}
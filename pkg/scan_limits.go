@@ -0,0 +1,89 @@
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Reasons controller paths are skipped while scanning
+const (
+	SkipReasonExcludedDir = "excluded directory"
+	SkipReasonTooLarge    = "file too large"
+	SkipReasonTimeout     = "scan timeout"
+)
+
+// ScanLimits guards controller scanning against pathological repositories
+type ScanLimits struct {
+	// MaxFileSize is the size in bytes above which files are not scanned, 0 for no limit
+	MaxFileSize int64
+	// SkipDirs lists directory names that are never descended into, at any depth
+	SkipDirs []string
+	// Timeout bounds the scan of a single service's controller, 0 for no limit
+	Timeout time.Duration
+}
+
+// DefaultScanLimits skips vendored dependencies and test fixtures, generated files larger than
+// any hand-maintained controller file, and scans taking longer than a few minutes
+func DefaultScanLimits() ScanLimits {
+	return ScanLimits{MaxFileSize: 2 << 20, SkipDirs: []string{"vendor", "testdata"}, Timeout: 5 * time.Minute}
+}
+
+// scanLimits holds the limits applied to every controller scan
+var scanLimits = DefaultScanLimits()
+
+// SetScanLimits sets the limits applied to every controller scan
+func SetScanLimits(limits ScanLimits) error {
+	if limits.MaxFileSize < 0 {
+		return fmt.Errorf("maximum file size must not be negative, got %d", limits.MaxFileSize)
+	}
+	if limits.Timeout < 0 {
+		return fmt.Errorf("scan timeout must not be negative, got %s", limits.Timeout)
+	}
+	scanLimits = limits
+	return nil
+}
+
+// skipsDir reports whether a directory name is excluded from scanning
+func (l ScanLimits) skipsDir(name string) bool {
+	return slices.Contains(l.SkipDirs, name)
+}
+
+// listControllerFiles returns the Go files of a controller's pkg tree in walk order, leaving out
+// excluded directories and files over the size limit, which are returned as skipped paths
+func listControllerFiles(controllerPath string) ([]string, []SkippedPath) {
+	pkgPath := filepath.Join(controllerPath, "pkg")
+	var files []string
+	var skipped []SkippedPath
+	filepath.Walk(pkgPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != pkgPath && scanLimits.skipsDir(info.Name()) {
+				skipped = append(skipped, SkippedPath{Path: controllerRelPath(controllerPath, path), Reason: SkipReasonExcludedDir})
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+		if scanLimits.MaxFileSize > 0 && info.Size() > scanLimits.MaxFileSize {
+			skipped = append(skipped, SkippedPath{Path: controllerRelPath(controllerPath, path), Reason: SkipReasonTooLarge})
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	return files, skipped
+}
+
+// controllerRelPath returns the slash-separated path of a file relative to the controller,
+// so output is identical on every OS
+func controllerRelPath(controllerPath, path string) string {
+	relPath, _ := filepath.Rel(controllerPath, path)
+	return filepath.ToSlash(relPath)
+}
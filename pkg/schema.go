@@ -0,0 +1,228 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the version of the JSON documents the extractor writes. Documents without a
+// schema_version field were written before versioning and are schema version 1.
+const SchemaVersion = 2
+
+// DocumentKind identifies the kind of a JSON document written by the extractor
+type DocumentKind string
+
+const (
+	DocumentOperations         DocumentKind = "operations"
+	DocumentCombinedOperations DocumentKind = "combined_operations"
+	DocumentExamples           DocumentKind = "examples"
+	DocumentDataPlane          DocumentKind = "data_plane_operations"
+	DocumentControllerCalls    DocumentKind = "controller_calls"
+	DocumentStatusReport       DocumentKind = "status_report"
+	DocumentRunMetrics         DocumentKind = "run_metrics"
+	DocumentAttachmentPlan     DocumentKind = "attachment_plan"
+	DocumentPublishManifest    DocumentKind = "publish_manifest"
+	DocumentRBACPairing        DocumentKind = "rbac_pairing"
+	DocumentClassificationEval DocumentKind = "classification_evaluation"
+)
+
+// documentKinds lists the fields identifying each kind of document and the type it is decoded into
+var documentKinds = []struct {
+	kind     DocumentKind
+	fields   []string
+	document func() interface{}
+}{
+	{DocumentCombinedOperations, []string{"services", "total_operations"}, func() interface{} { return NewCombinedOperations() }},
+	{DocumentDataPlane, []string{"service_name", "data_plane_operations"}, func() interface{} { return &DataPlaneOperations{} }},
+	{DocumentRBACPairing, []string{"service_name", "api_group", "resources"}, func() interface{} { return &RBACPairing{} }},
+	{DocumentOperations, []string{"service_name", "operations"}, func() interface{} { return &ServiceOperations{} }},
+	{DocumentExamples, []string{"service_name", "examples"}, func() interface{} { return &ServiceExamples{} }},
+	{DocumentControllerCalls, []string{"service_name", "calls"}, func() interface{} { return &ControllerCalls{} }},
+	{DocumentStatusReport, []string{"summary", "services"}, func() interface{} { return &StatusReport{} }},
+	{DocumentRunMetrics, []string{"counts", "bedrock"}, func() interface{} { return &RunMetrics{} }},
+	{DocumentAttachmentPlan, []string{"roles", "max_managed_policies_per_role"}, func() interface{} { return &AttachmentPlan{} }},
+	{DocumentPublishManifest, []string{"files", "generated_at"}, func() interface{} { return &PublishManifest{} }},
+	{DocumentClassificationEval, []string{"golden", "classifiers"}, func() interface{} { return &ClassificationEvaluation{} }},
+}
+
+// schemaMigration upgrades a decoded document from one schema version to the next
+type schemaMigration struct {
+	from    int
+	migrate func(kind DocumentKind, document map[string]interface{})
+}
+
+// schemaMigrations holds the migrations in version order. Every schema change that renames,
+// removes or reinterprets a field adds one, so older files keep working.
+var schemaMigrations = []schemaMigration{
+	{from: 1, migrate: migrateUnversioned},
+}
+
+// MigrationResult is the outcome of migrating a single document
+type MigrationResult struct {
+	Kind        DocumentKind
+	FromVersion int
+	// Data is the document in the current schema, formatted like the extractor writes it
+	Data []byte
+}
+
+// MigrateDocument upgrades a JSON document written by any earlier version to the current schema.
+// Documents the extractor does not recognize, such as IAM policies, are returned with an empty
+// kind; documents written by a newer version are rejected.
+func MigrateDocument(data []byte) (*MigrationResult, error) {
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	result := &MigrationResult{Kind: detectDocumentKind(document), FromVersion: documentVersion(document), Data: data}
+	if result.Kind == "" {
+		return result, nil
+	}
+	if err := migrateDocument(result.Kind, document); err != nil {
+		return nil, err
+	}
+
+	typed, err := decodeMigrated(result.Kind, document)
+	if err != nil {
+		return nil, err
+	}
+	result.Data, err = json.MarshalIndent(typed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal migrated document: %w", err)
+	}
+	return result, nil
+}
+
+// decodeDocument decodes a document of the given kind into out, migrating it first when it was
+// written with an older schema
+func decodeDocument(data []byte, kind DocumentKind, out interface{}) error {
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	if documentVersion(document) == SchemaVersion {
+		return json.Unmarshal(data, out)
+	}
+	if err := migrateDocument(kind, document); err != nil {
+		return err
+	}
+	migrated, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(migrated, out)
+}
+
+// migrateDocument applies the migrations from the document's version up to the current schema
+func migrateDocument(kind DocumentKind, document map[string]interface{}) error {
+	version := documentVersion(document)
+	if version > SchemaVersion {
+		return fmt.Errorf("document has schema version %d, newer than the supported version %d; upgrade ack-api-extractor", version, SchemaVersion)
+	}
+	for _, migration := range schemaMigrations {
+		if migration.from >= version {
+			migration.migrate(kind, document)
+		}
+	}
+	document["schema_version"] = SchemaVersion
+	return nil
+}
+
+// decodeMigrated decodes a migrated document into its type so it is written in the current field order
+func decodeMigrated(kind DocumentKind, document map[string]interface{}) (interface{}, error) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal migrated document: %w", err)
+	}
+	for _, candidate := range documentKinds {
+		if candidate.kind != kind {
+			continue
+		}
+		typed := candidate.document()
+		if err := json.Unmarshal(data, typed); err != nil {
+			return nil, fmt.Errorf("failed to decode migrated %s document: %w", kind, err)
+		}
+		return typed, nil
+	}
+	return nil, fmt.Errorf("unknown document kind %s", kind)
+}
+
+// detectDocumentKind recognizes a document by its identifying fields
+func detectDocumentKind(document map[string]interface{}) DocumentKind {
+	for _, candidate := range documentKinds {
+		matched := true
+		for _, field := range candidate.fields {
+			if _, ok := document[field]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return candidate.kind
+		}
+	}
+	return ""
+}
+
+// documentVersion returns the schema version of a decoded document, 1 when it has none
+func documentVersion(document map[string]interface{}) int {
+	if version, ok := document["schema_version"].(float64); ok {
+		return int(version)
+	}
+	if version, ok := document["schema_version"].(int); ok {
+		return version
+	}
+	return 1
+}
+
+// migrateUnversioned upgrades documents written before schema versioning: operations without a
+// support_status get one from whether the controller referenced them, legacy type values are
+// normalized and the first reference is copied into locations
+func migrateUnversioned(kind DocumentKind, document map[string]interface{}) {
+	switch kind {
+	case DocumentOperations:
+		migrateUnversionedOperations(document)
+	case DocumentCombinedOperations:
+		services, _ := document["services"].(map[string]interface{})
+		for _, service := range services {
+			if serviceDocument, ok := service.(map[string]interface{}); ok {
+				migrateUnversionedOperations(serviceDocument)
+				serviceDocument["schema_version"] = SchemaVersion
+			}
+		}
+	}
+}
+
+// migrateUnversionedOperations upgrades the operations of a single unversioned service document
+func migrateUnversionedOperations(document map[string]interface{}) {
+	operations, _ := document["operations"].([]interface{})
+	statusCounts := make(map[string]int)
+	for _, entry := range operations {
+		operation, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		file, _ := operation["file"].(string)
+		if _, ok := operation["support_status"]; !ok {
+			if file != "" {
+				operation["support_status"] = string(SupportImplemented)
+			} else {
+				operation["support_status"] = string(SupportUnsupported)
+			}
+		}
+		if value, ok := operation["type"].(string); ok || operation["type"] == nil {
+			if parsed, err := ParseOperationType(value); err == nil {
+				operation["type"] = string(parsed)
+			}
+		}
+		if _, ok := operation["locations"]; !ok && file != "" {
+			operation["locations"] = []interface{}{map[string]interface{}{"file": file, "line": operation["line"]}}
+		}
+		if status, ok := operation["support_status"].(string); ok {
+			statusCounts[status]++
+		}
+	}
+	if _, ok := document["support_status_counts"]; !ok {
+		document["support_status_counts"] = statusCounts
+	}
+}
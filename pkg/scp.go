@@ -0,0 +1,74 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// maxServiceControlPolicySize is the AWS Organizations limit for SCPs, excluding whitespace
+const maxServiceControlPolicySize = 5120
+
+// scpBaselineActions are needed by every controller to obtain credentials through IRSA or Pod Identity
+var scpBaselineActions = []string{
+	"sts:AssumeRole",
+	"sts:AssumeRoleWithWebIdentity",
+	"sts:TagSession",
+}
+
+// GenerateServiceControlPolicy creates an AWS Organizations Service Control Policy that denies every
+// action except the ones the generated IAM policies of the given services grant, so the SCP never
+// denies what a controller's policy allows. The deny only applies to principals matching
+// principalARNs, e.g. arn:aws:iam::*:role/ack-*-controller: without them it would lock every other
+// principal of the member account out of all other services, so they are required.
+func GenerateServiceControlPolicy(services map[string]*ServiceOperations, principalARNs []string) (*IAMPolicy, error) {
+	if len(principalARNs) == 0 {
+		return nil, fmt.Errorf("a service control policy needs principal ARNs; without them it denies every other action to every principal of the account")
+	}
+
+	allowed := make(map[string]bool)
+	for serviceName, serviceOps := range services {
+		for _, action := range PolicyActions(serviceName, serviceOps.Operations) {
+			allowed[action] = true
+		}
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("no actions granted by the generated policies")
+	}
+	for _, action := range scpBaselineActions {
+		allowed[action] = true
+	}
+
+	actions := make([]string, 0, len(allowed))
+	for action := range allowed {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	statement := PolicyStatement{
+		Sid:       "DenyActionsNotUsedByACKControllers",
+		Effect:    "Deny",
+		NotAction: actions,
+		Resource:  "*",
+		Condition: map[string]map[string][]string{
+			"ArnLike": {"aws:PrincipalArn": principalARNs},
+		},
+	}
+
+	return &IAMPolicy{
+		Version:   "2012-10-17",
+		Statement: []PolicyStatement{statement},
+	}, nil
+}
+
+// CheckServiceControlPolicySize returns an error when the minified policy exceeds the SCP size limit
+func CheckServiceControlPolicySize(policy *IAMPolicy) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal service control policy: %w", err)
+	}
+	if len(data) > maxServiceControlPolicySize {
+		return fmt.Errorf("service control policy is %d characters, more than the %d allowed by AWS Organizations", len(data), maxServiceControlPolicySize)
+	}
+	return nil
+}
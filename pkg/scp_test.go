@@ -0,0 +1,74 @@
+package extractor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateServiceControlPolicy(t *testing.T) {
+	t.Cleanup(ResetState)
+	principals := []string{"arn:aws:iam::*:role/ack-*"}
+	services := map[string]*ServiceOperations{
+		"foo": {ServiceName: "foo", Operations: []Operation{
+			{Name: "CreateBar", Type: OperationTypeControlPlane, SupportStatus: SupportImplemented},
+			{Name: "DescribeBar", Type: OperationTypeControlPlane, SupportStatus: SupportPartiallyImplemented},
+			{Name: "DeleteBar", Type: OperationTypeControlPlane, SupportStatus: SupportUnsupported},
+			{Name: "PutRecord", Type: OperationTypeDataPlane, SupportStatus: SupportUnsupported},
+		}},
+	}
+
+	policy, err := GenerateServiceControlPolicy(services, principals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := policy.Statement[0]
+	want := []string{"foo:CreateBar", "foo:DescribeBar", "sts:AssumeRole", "sts:AssumeRoleWithWebIdentity", "sts:TagSession"}
+	if statement.Effect != "Deny" || !reflect.DeepEqual(statement.NotAction, want) {
+		t.Errorf("%s NotAction %v, want Deny NotAction %v", statement.Effect, statement.NotAction, want)
+	}
+	wantCondition := map[string]map[string][]string{"ArnLike": {"aws:PrincipalArn": principals}}
+	if !reflect.DeepEqual(statement.Condition, wantCondition) {
+		t.Errorf("condition %v, want %v", statement.Condition, wantCondition)
+	}
+	if err := CheckServiceControlPolicySize(policy); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := GenerateServiceControlPolicy(services, nil); err == nil {
+		t.Error("a service control policy without principal ARNs was generated")
+	}
+
+	// Without a controller the SCP allows what the prospective policies grant
+	SetNoController(true)
+	policy, err = GenerateServiceControlPolicy(services, principals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(policy.Statement[0].NotAction, "foo:DeleteBar") || contains(policy.Statement[0].NotAction, "foo:PutRecord") {
+		t.Errorf("NotAction %v without a controller, want DeleteBar but not PutRecord", policy.Statement[0].NotAction)
+	}
+	SetNoController(false)
+
+	var large []Operation
+	for i := 0; i < 400; i++ {
+		large = append(large, Operation{Name: fmt.Sprintf("CreateBarWithALongName%d", i), Type: OperationTypeControlPlane, SupportStatus: SupportImplemented})
+	}
+	policy, err = GenerateServiceControlPolicy(map[string]*ServiceOperations{"foo": {ServiceName: "foo", Operations: large}}, principals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckServiceControlPolicySize(policy); err == nil || !strings.Contains(err.Error(), "5120") {
+		t.Errorf("oversized policy check returned %v", err)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
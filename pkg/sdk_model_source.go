@@ -0,0 +1,304 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// GoSDKModelSource is the model source deriving service models from the aws-sdk-go-v2 service
+// packages controllers build against, vendored or in the module cache
+const GoSDKModelSource = "go-sdk"
+
+// goSDKServicePackagePrefix is the import path prefix of the aws-sdk-go-v2 service packages
+const goSDKServicePackagePrefix = "github.com/aws/aws-sdk-go-v2/service/"
+
+// goSDKModels caches the models derived from SDK packages by service, since loading a package
+// runs the go command
+var goSDKModels struct {
+	sync.Mutex
+	models map[string]goSDKModel
+}
+
+// goSDKModel is a model derived from an SDK package and the package it was derived from
+type goSDKModel struct {
+	data     []byte
+	location string
+	err      error
+}
+
+// goSDKPrelude maps the Go types of SDK input and output fields to Smithy prelude shapes
+var goSDKPrelude = map[string]string{
+	"string":    "smithy.api#String",
+	"bool":      "smithy.api#Boolean",
+	"int32":     "smithy.api#Integer",
+	"int64":     "smithy.api#Long",
+	"int16":     "smithy.api#Short",
+	"int8":      "smithy.api#Byte",
+	"float32":   "smithy.api#Float",
+	"float64":   "smithy.api#Double",
+	"time.Time": "smithy.api#Timestamp",
+	"[]byte":    "smithy.api#Blob",
+}
+
+// readGoSDKModel derives the model of a service from the aws-sdk-go-v2 package of its model name,
+// as resolved by the module of the service's controller. errModelNotInSource is returned for
+// services without a controller or whose controller doesn't depend on the package.
+func readGoSDKModel(serviceName string) ([]byte, string, error) {
+	goSDKModels.Lock()
+	defer goSDKModels.Unlock()
+	if goSDKModels.models == nil {
+		goSDKModels.models = make(map[string]goSDKModel)
+	}
+	if cached, ok := goSDKModels.models[serviceName]; ok {
+		return cached.data, cached.location, cached.err
+	}
+
+	data, location, err := loadGoSDKModel(serviceName)
+	goSDKModels.models[serviceName] = goSDKModel{data: data, location: location, err: err}
+	return data, location, err
+}
+
+// loadGoSDKModel loads the SDK package of a service with go/packages from its controller's
+// directory and converts its client methods to a Smithy JSON AST model
+func loadGoSDKModel(serviceName string) ([]byte, string, error) {
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return nil, "", fmt.Errorf("%w: no controller to resolve the aws-sdk-go-v2 package of %s from", errModelNotInSource, serviceName)
+	}
+	modelName, err := getModelNameFromController(serviceName)
+	if err != nil {
+		modelName = serviceName
+	}
+	importPath := goSDKServicePackagePrefix + strings.ToLower(strings.ReplaceAll(modelName, "-", ""))
+
+	config := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedSyntax | packages.NeedModule,
+		Dir:  controllerPath,
+	}
+	loaded, err := packages.Load(config, importPath)
+	if err != nil {
+		return nil, importPath, fmt.Errorf("failed to load %s: %w", importPath, err)
+	}
+	if len(loaded) != 1 || len(loaded[0].Errors) > 0 || len(loaded[0].Syntax) == 0 {
+		return nil, importPath, fmt.Errorf("%w: %s is not a dependency of %s", errModelNotInSource, importPath, controllerPath)
+	}
+
+	pkg := loaded[0]
+	location := importPath
+	if pkg.Module != nil && pkg.Module.Version != "" {
+		location += "@" + pkg.Module.Version
+	}
+	model := goSDKPackageModel(pkg.Name, pkg.Syntax)
+	if len(model.Shapes) <= 1 {
+		return nil, location, fmt.Errorf("%w: %s has no client operations", errModelNotInSource, location)
+	}
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, location, fmt.Errorf("failed to marshal model derived from %s: %w", location, err)
+	}
+	return data, location, nil
+}
+
+// goSDKPackageModel builds a model from the syntax of an SDK package: a service shape from the
+// ServiceID and ServiceAPIVersion constants and the signing name, and an operation shape for every
+// client method taking an <Operation>Input and returning an <Operation>Output. Input and output
+// structures get a member per exported field, with its documentation and whether it is required.
+func goSDKPackageModel(packageName string, files []*ast.File) *AWSServiceModel {
+	namespace := "com.amazonaws." + packageName
+	constants := make(map[string]string)
+	structs := make(map[string]*ast.StructType)
+	var operations []*ast.FuncDecl
+	signingName := ""
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if goSDKClientOperation(decl) {
+					operations = append(operations, decl)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						if decl.Tok == token.CONST && len(spec.Names) == 1 && len(spec.Values) == 1 {
+							if value, ok := stringLiteral(spec.Values[0]); ok {
+								constants[spec.Names[0].Name] = value
+							}
+						}
+					case *ast.TypeSpec:
+						if structType, ok := spec.Type.(*ast.StructType); ok {
+							structs[spec.Name.Name] = structType
+						}
+					}
+				}
+			}
+		}
+		if signingName == "" {
+			signingName = goSDKSigningName(file)
+		}
+	}
+
+	sdkID := constants["ServiceID"]
+	serviceTraits := map[string]json.RawMessage{}
+	if sdkID != "" {
+		serviceTraits[awsServiceTrait], _ = json.Marshal(map[string]string{"sdkId": sdkID})
+	}
+	if signingName != "" {
+		serviceTraits[sigv4Trait], _ = json.Marshal(map[string]string{"name": signingName})
+	}
+	service := ServiceShape{Type: "service", Version: constants["ServiceAPIVersion"], Traits: serviceTraits}
+
+	model := &AWSServiceModel{Shapes: make(map[string]ServiceShape)}
+	sort.Slice(operations, func(i, j int) bool { return operations[i].Name.Name < operations[j].Name.Name })
+	for _, decl := range operations {
+		name := decl.Name.Name
+		operation := ServiceShape{
+			Type:   "operation",
+			Input:  &ShapeReference{Target: namespace + "#" + name + "Request"},
+			Output: &ShapeReference{Target: namespace + "#" + name + "Response"},
+		}
+		if doc := docText(decl.Doc); doc != "" {
+			operation.Traits = map[string]json.RawMessage{documentationTrait: jsonString(doc)}
+		}
+		model.Shapes[namespace+"#"+name] = operation
+		model.Shapes[operation.Input.Target] = goSDKStructure(structs[name+"Input"])
+		model.Shapes[operation.Output.Target] = goSDKStructure(structs[name+"Output"])
+		service.Operations = append(service.Operations, OperationTarget{Target: namespace + "#" + name})
+	}
+
+	serviceShapeName := strings.ReplaceAll(sdkID, " ", "")
+	if serviceShapeName == "" {
+		serviceShapeName = packageName
+	}
+	model.Shapes[namespace+"#"+serviceShapeName] = service
+	return model
+}
+
+// goSDKClientOperation reports whether a function is an operation method of an SDK client:
+// func (c *Client) <Operation>(ctx context.Context, params *<Operation>Input, optFns ...func(*Options)) (*<Operation>Output, error)
+func goSDKClientOperation(decl *ast.FuncDecl) bool {
+	if decl.Recv == nil || len(decl.Recv.List) != 1 || !decl.Name.IsExported() {
+		return false
+	}
+	receiver, ok := decl.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok || identName(receiver.X) != "Client" {
+		return false
+	}
+	params, results := decl.Type.Params.List, decl.Type.Results
+	if len(params) != 3 || results == nil || len(results.List) != 2 {
+		return false
+	}
+	input, ok := params[1].Type.(*ast.StarExpr)
+	if !ok || identName(input.X) != decl.Name.Name+"Input" {
+		return false
+	}
+	output, ok := results.List[0].Type.(*ast.StarExpr)
+	return ok && identName(output.X) == decl.Name.Name+"Output"
+}
+
+// goSDKSigningName returns the SigV4 signing name a file of an SDK package sets, e.g. "sts" from
+// smithyhttp.SetSigV4SigningName(&props, "sts") in auth.go
+func goSDKSigningName(file *ast.File) string {
+	signingName := ""
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || signingName != "" || len(call.Args) != 2 {
+			return signingName == ""
+		}
+		if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "SetSigV4SigningName" {
+			signingName, _ = stringLiteral(call.Args[1])
+		}
+		return signingName == ""
+	})
+	return signingName
+}
+
+// goSDKStructure converts an SDK input or output struct to a structure shape
+func goSDKStructure(structType *ast.StructType) ServiceShape {
+	shape := ServiceShape{Type: "structure", Members: make(map[string]ShapeReference)}
+	if structType == nil {
+		return shape
+	}
+	for _, field := range structType.Fields.List {
+		for _, name := range field.Names {
+			if !name.IsExported() || name.Name == "ResultMetadata" {
+				continue
+			}
+			member := ShapeReference{Target: goSDKFieldTarget(field.Type), Traits: map[string]json.RawMessage{}}
+			doc := docText(field.Doc)
+			if strings.Contains(doc, "This member is required.") {
+				member.Traits[requiredTrait] = json.RawMessage("{}")
+				doc = strings.TrimSpace(strings.ReplaceAll(doc, "This member is required.", ""))
+			}
+			if doc != "" {
+				member.Traits[documentationTrait] = jsonString(doc)
+			}
+			shape.Members[name.Name] = member
+		}
+	}
+	return shape
+}
+
+// goSDKFieldTarget maps the Go type of a field to a prelude shape; lists, maps, structures and
+// enums of the package become documents, since their shapes are not derived
+func goSDKFieldTarget(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	name := ""
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		name = expr.Name
+	case *ast.SelectorExpr:
+		name = identName(expr.X) + "." + expr.Sel.Name
+	case *ast.ArrayType:
+		if expr.Len == nil && identName(expr.Elt) == "byte" {
+			name = "[]byte"
+		}
+	}
+	if target, ok := goSDKPrelude[name]; ok {
+		return target
+	}
+	return "smithy.api#Document"
+}
+
+// identName returns the name of an identifier expression, "" for other expressions
+func identName(expr ast.Expr) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// stringLiteral returns the value of a string literal expression
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	return value, err == nil
+}
+
+// docText returns the text of a doc comment with its lines joined
+func docText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+// jsonString marshals a string trait value
+func jsonString(value string) json.RawMessage {
+	data, _ := json.Marshal(value)
+	return data
+}
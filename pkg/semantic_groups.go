@@ -0,0 +1,136 @@
+package extractor
+
+import "regexp"
+
+// SemanticGroup buckets operations by what they manage, so reports can summarize them
+type SemanticGroup string
+
+const (
+	SemanticGroupLifecycle  SemanticGroup = "lifecycle"
+	SemanticGroupTagging    SemanticGroup = "tagging"
+	SemanticGroupNetworking SemanticGroup = "networking"
+	SemanticGroupEncryption SemanticGroup = "encryption"
+	SemanticGroupAccess     SemanticGroup = "access"
+	SemanticGroupMonitoring SemanticGroup = "monitoring"
+	SemanticGroupOther      SemanticGroup = "other"
+)
+
+// semanticGroupOrder is the order groups are reported in
+var semanticGroupOrder = []SemanticGroup{
+	SemanticGroupLifecycle, SemanticGroupTagging, SemanticGroupNetworking, SemanticGroupEncryption,
+	SemanticGroupAccess, SemanticGroupMonitoring, SemanticGroupOther,
+}
+
+// semanticGroupRules match operation names and input member names to groups, in precedence order
+var semanticGroupRules = []struct {
+	group   SemanticGroup
+	name    *regexp.Regexp
+	members *regexp.Regexp
+}{
+	{
+		group:   SemanticGroupEncryption,
+		name:    regexp.MustCompile(`Encrypt|Decrypt|Kms|KMS|Certificate|Sse|SSE|Keys?($|[A-Z])`),
+		members: regexp.MustCompile(`^(KmsKeyId|KMSKeyId|KmsKeyArn|KMSKeyArn|SSESpecification|SseSpecification|Encryption\w*|ServerSideEncryption\w*)$`),
+	},
+	{
+		group:   SemanticGroupNetworking,
+		name:    regexp.MustCompile(`Vpc|VPC|Subnet|SecurityGroup|NetworkInterface|Network|Endpoint|Route|Gateway|Dns|DNS|Address|Peering|Listener|LoadBalancer|Ip($|[A-Z])|IP($|[A-Z])`),
+		members: regexp.MustCompile(`^(VpcId|VpcConfig|VpcConfiguration|SubnetIds?|Subnets|SecurityGroupIds?|SecurityGroups|VpcSecurityGroupIds|NetworkConfiguration|PrivateIpAddress\w*)$`),
+	},
+	{
+		group:   SemanticGroupAccess,
+		name:    regexp.MustCompile(`Polic(y|ies)|Permission|Role|Grant|Acl|ACL|Authoriz|Access|Principal`),
+		members: regexp.MustCompile(`^(Policy|PolicyDocument|Principal|RoleArn|ExecutionRoleArn|Permissions?)$`),
+	},
+	{
+		group:   SemanticGroupMonitoring,
+		name:    regexp.MustCompile(`Metric|Logs?($|[A-Z])|Logging|Alarm|Insight|Trace|Monitor`),
+		members: regexp.MustCompile(`^(LoggingConfig|LoggingConfiguration|LogConfig|MonitoringConfiguration|MetricsConfiguration|CloudWatchLogs\w*|LogDestination\w*)$`),
+	},
+}
+
+// lifecycleVerbs are the verbs of operations managing a resource's lifecycle
+var lifecycleVerbs = map[string]bool{
+	"Create": true, "Delete": true, "Update": true, "Describe": true, "Get": true, "List": true,
+	"Modify": true, "Put": true, "Restore": true, "Copy": true,
+}
+
+// ApplySemanticGroups assigns each operation a semantic group. Tagging operations come first;
+// then the operation name is matched against the encryption, networking, access and monitoring
+// patterns, then the input members, where a group applies when at least half of the members
+// match it. Remaining operations with a lifecycle verb are lifecycle operations.
+func ApplySemanticGroups(operations []Operation, model *AWSServiceModel) {
+	shapes := operationShapes(model)
+	for i := range operations {
+		operations[i].SemanticGroup = semanticGroup(operations[i].Name, inputMemberNames(model, shapes[operations[i].Name]))
+	}
+}
+
+// semanticGroup returns the group of an operation from its name and input member names
+func semanticGroup(operationName string, members []string) SemanticGroup {
+	if IsTaggingOperation(operationName) {
+		return SemanticGroupTagging
+	}
+	for _, rule := range semanticGroupRules {
+		if rule.name.MatchString(operationName) {
+			return rule.group
+		}
+	}
+	if len(members) > 0 {
+		for _, rule := range semanticGroupRules {
+			matched := 0
+			for _, member := range members {
+				if rule.members.MatchString(member) {
+					matched++
+				}
+			}
+			if matched > 0 && 2*matched >= len(members) {
+				return rule.group
+			}
+		}
+	}
+	if lifecycleVerbs[operationVerb(operationName)] {
+		return SemanticGroupLifecycle
+	}
+	return SemanticGroupOther
+}
+
+// inputMemberNames returns the member names of an operation's input structure
+func inputMemberNames(model *AWSServiceModel, shape ServiceShape) []string {
+	if shape.Input == nil {
+		return nil
+	}
+	var names []string
+	for name := range model.Shapes[shape.Input.Target].Members {
+		names = append(names, name)
+	}
+	return names
+}
+
+// SummarizeSemanticGroups counts the operations and supported operations of each semantic group,
+// in report order and leaving out empty groups
+func SummarizeSemanticGroups(operations []Operation) []SemanticGroupSummary {
+	counts := make(map[SemanticGroup]*SemanticGroupSummary)
+	for _, op := range operations {
+		if op.SemanticGroup == "" {
+			continue
+		}
+		summary, ok := counts[op.SemanticGroup]
+		if !ok {
+			summary = &SemanticGroupSummary{Group: op.SemanticGroup}
+			counts[op.SemanticGroup] = summary
+		}
+		summary.Operations++
+		if op.IsSupported() {
+			summary.Supported++
+		}
+	}
+
+	var summaries []SemanticGroupSummary
+	for _, group := range semanticGroupOrder {
+		if summary, ok := counts[group]; ok {
+			summaries = append(summaries, *summary)
+		}
+	}
+	return summaries
+}
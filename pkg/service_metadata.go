@@ -0,0 +1,162 @@
+package extractor
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	awsServiceTrait      = "aws.api#service"
+	sigv4Trait           = "aws.auth#sigv4"
+	endpointRuleSetTrait = "smithy.rules#endpointRuleSet"
+	arnTrait             = "aws.api#arn"
+)
+
+// arnTraitValue represents the fields of the aws.api#arn trait of a resource shape used by the extractor
+type arnTraitValue struct {
+	Template string `json:"template"`
+	Absolute bool   `json:"absolute"`
+	NoRegion bool   `json:"noRegion"`
+}
+
+// awsServiceTraitValue represents the fields of the aws.api#service trait used by the extractor
+type awsServiceTraitValue struct {
+	SDKID              string `json:"sdkId"`
+	ARNNamespace       string `json:"arnNamespace"`
+	EndpointPrefix     string `json:"endpointPrefix"`
+	CloudFormationName string `json:"cloudFormationName"`
+}
+
+// sigv4TraitValue represents the aws.auth#sigv4 trait naming the service in request signatures
+type sigv4TraitValue struct {
+	Name string `json:"name"`
+}
+
+// IAMServicePrefix returns the prefix of the service's IAM actions. The SigV4 signing name is the
+// namespace IAM authorizes requests against, with the ARN namespace as fallback; both differ from
+// the model name for services such as Step Functions (states) or CloudWatch Logs (logs).
+func (m *ServiceMetadata) IAMServicePrefix() string {
+	if m.SigningName != "" {
+		return m.SigningName
+	}
+	return m.ARNNamespace
+}
+
+// LoadServiceMetadata reads the service model and extracts its service-level metadata
+func LoadServiceMetadata(serviceName string) (*ServiceMetadata, error) {
+	model, err := loadServiceModel(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractServiceMetadata(model), nil
+}
+
+// ExtractServiceMetadata reads the aws.api#service and endpoint rule set traits of the service shape
+func ExtractServiceMetadata(model *AWSServiceModel) *ServiceMetadata {
+	metadata := &ServiceMetadata{}
+	for _, shape := range model.Shapes {
+		if shape.Type != "service" {
+			continue
+		}
+
+		metadata.APIVersion = shape.Version
+
+		var serviceTrait awsServiceTraitValue
+		if raw, ok := shape.Traits[awsServiceTrait]; ok && json.Unmarshal(raw, &serviceTrait) == nil {
+			metadata.SDKID = serviceTrait.SDKID
+			metadata.ARNNamespace = serviceTrait.ARNNamespace
+			metadata.EndpointPrefix = serviceTrait.EndpointPrefix
+			metadata.CloudFormationName = serviceTrait.CloudFormationName
+		}
+
+		var sigv4 sigv4TraitValue
+		if raw, ok := shape.Traits[sigv4Trait]; ok && json.Unmarshal(raw, &sigv4) == nil {
+			metadata.SigningName = sigv4.Name
+		}
+
+		// The ARN templates of the service's resources are authoritative; services whose model
+		// has none fall back to their endpoint rule set
+		if global, ok := regionlessResourceARNs(model); ok {
+			metadata.Global = global
+		} else if raw, ok := shape.Traits[endpointRuleSetTrait]; ok {
+			var ruleSet interface{}
+			if json.Unmarshal(raw, &ruleSet) == nil {
+				metadata.Global = hasGlobalEndpoint(ruleSet, globalEndpointHosts(metadata))
+			}
+		}
+		break
+	}
+	return metadata
+}
+
+// regionlessResourceARNs reports whether the resource shapes of the model leave the region out of
+// their ARNs, and false for ok when none of them has a relative ARN template
+func regionlessResourceARNs(model *AWSServiceModel) (global, ok bool) {
+	global = true
+	for _, shape := range model.Shapes {
+		if shape.Type != "resource" {
+			continue
+		}
+		var arn arnTraitValue
+		if raw, found := shape.Traits[arnTrait]; !found || json.Unmarshal(raw, &arn) != nil || arn.Absolute {
+			continue
+		}
+		ok = true
+		global = global && arn.NoRegion
+	}
+	return global && ok, ok
+}
+
+// globalEndpointHosts returns the hosts a global endpoint of the service can have: its own
+// endpoint prefix, signing name or ARN namespace under amazonaws.com
+func globalEndpointHosts(metadata *ServiceMetadata) map[string]bool {
+	hosts := make(map[string]bool)
+	for _, name := range []string{metadata.EndpointPrefix, metadata.SigningName, metadata.ARNNamespace} {
+		if name != "" {
+			hosts[name+".amazonaws.com"] = true
+		}
+	}
+	return hosts
+}
+
+// hasGlobalEndpoint walks an endpoint rule set looking for an endpoint of the service with a fixed,
+// region-less URL on one of its hosts signed against a fixed region. Services such as IAM,
+// CloudFront and Route53 resolve the aws partition to an endpoint of this form.
+func hasGlobalEndpoint(node interface{}, hosts map[string]bool) bool {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if endpoint, ok := value["endpoint"].(map[string]interface{}); ok && isGlobalEndpoint(endpoint, hosts) {
+			return true
+		}
+		for _, child := range value {
+			if hasGlobalEndpoint(child, hosts) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			if hasGlobalEndpoint(child, hosts) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isGlobalEndpoint reports whether a single rule set endpoint is a global endpoint on one of the hosts
+func isGlobalEndpoint(endpoint map[string]interface{}, hosts map[string]bool) bool {
+	url, ok := endpoint["url"].(string)
+	if !ok || strings.Contains(url, "{") || !hosts[strings.TrimSuffix(strings.TrimPrefix(url, "https://"), "/")] {
+		return false
+	}
+
+	properties, _ := endpoint["properties"].(map[string]interface{})
+	authSchemes, _ := properties["authSchemes"].([]interface{})
+	for _, scheme := range authSchemes {
+		schemeMap, _ := scheme.(map[string]interface{})
+		if region, ok := schemeMap["signingRegion"].(string); ok && region != "" && !strings.Contains(region, "{") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// globalEndpointRuleSet is an endpoint rule set resolving the aws partition to a fixed URL signed in us-east-1
+func globalEndpointRuleSet(url string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"rules": [{"conditions": [], "endpoint": {"url": %q,
+		"properties": {"authSchemes": [{"name": "sigv4", "signingRegion": "us-east-1"}]}}, "type": "endpoint"}]}`, url))
+}
+
+func TestExtractServiceMetadataGlobal(t *testing.T) {
+	service := func(ruleSet json.RawMessage) ServiceShape {
+		return ServiceShape{Type: "service", Traits: map[string]json.RawMessage{
+			awsServiceTrait:      json.RawMessage(`{"arnNamespace": "foo", "endpointPrefix": "foo"}`),
+			endpointRuleSetTrait: ruleSet,
+		}}
+	}
+	resource := func(arn string) ServiceShape {
+		return ServiceShape{Type: "resource", Traits: map[string]json.RawMessage{arnTrait: json.RawMessage(arn)}}
+	}
+
+	tests := []struct {
+		name   string
+		shapes map[string]ServiceShape
+		global bool
+	}{
+		{"own global endpoint", map[string]ServiceShape{"Foo": service(globalEndpointRuleSet("https://foo.amazonaws.com"))}, true},
+		{"global endpoint of another service", map[string]ServiceShape{"Foo": service(globalEndpointRuleSet("https://sts.amazonaws.com"))}, false},
+		{"regional endpoint", map[string]ServiceShape{"Foo": service(globalEndpointRuleSet("https://foo.{Region}.amazonaws.com"))}, false},
+		{"region-less resource ARNs", map[string]ServiceShape{
+			"Foo": service(globalEndpointRuleSet("https://foo.{Region}.amazonaws.com")),
+			"Bar": resource(`{"template": "bar/{BarName}", "noRegion": true}`),
+			"Baz": resource(`{"template": "arn:aws:iam::aws:policy/{Name}", "absolute": true}`),
+		}, true},
+		{"regional resource ARNs", map[string]ServiceShape{
+			"Foo": service(globalEndpointRuleSet("https://foo.amazonaws.com")),
+			"Bar": resource(`{"template": "bar/{BarName}", "noRegion": true}`),
+			"Baz": resource(`{"template": "baz/{BazName}"}`),
+		}, false},
+	}
+	for _, test := range tests {
+		if global := ExtractServiceMetadata(testModel(test.shapes)).Global; global != test.global {
+			t.Errorf("%s: global = %t, want %t", test.name, global, test.global)
+		}
+	}
+}
+
+func TestServiceResourcePattern(t *testing.T) {
+	ResetState()
+	t.Cleanup(ResetState)
+
+	tests := []struct {
+		metadata ServiceMetadata
+		want     string
+	}{
+		{ServiceMetadata{ARNNamespace: "iam", Global: true}, "arn:aws-cn:iam::*:*"},
+		{ServiceMetadata{ARNNamespace: "states"}, "arn:aws-cn:states:*:*:*"},
+		{ServiceMetadata{}, "arn:aws-cn:foo:*:*:*"},
+	}
+	for _, test := range tests {
+		if pattern := serviceResourcePattern("foo", &test.metadata, "aws-cn"); pattern != test.want {
+			t.Errorf("serviceResourcePattern(%+v) = %s, want %s", test.metadata, pattern, test.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package extractor_test
+
+import (
+	"sync"
+	"testing"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+	"github.com/aws-controllers-k8s/ack-api-extractor/pkg/extractortest"
+)
+
+func TestIAMServicePrefixConcurrent(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("foo").SigningName("foosign").Operations("CreateBar"))
+	w.AddModel(t, extractortest.NewModel("bar").Operations("CreateBaz"))
+
+	var wg sync.WaitGroup
+	prefixes := make([]string, 32)
+	for i := range prefixes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			service := []string{"foo", "bar"}[i%2]
+			prefixes[i] = extractor.IAMServicePrefix(service)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, prefix := range prefixes {
+		if want := []string{"foosign", "bar"}[i%2]; prefix != want {
+			t.Errorf("prefix %d = %q, want %q", i, prefix, want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package extractor
+
+import (
+	"sort"
+)
+
+// Operation sources reported in resolution discrepancies
+const (
+	SourceServiceShape   = "service"
+	SourceOperationShape = "operation_shape"
+)
+
+// smithyPrelude maps the simple shapes of the Smithy prelude, which models reference without
+// defining them, to their shape type. Unit is the empty structure of operations without input or
+// output.
+var smithyPrelude = map[string]string{
+	"smithy.api#String":           "string",
+	"smithy.api#Blob":             "blob",
+	"smithy.api#Boolean":          "boolean",
+	"smithy.api#PrimitiveBoolean": "boolean",
+	"smithy.api#Byte":             "byte",
+	"smithy.api#PrimitiveByte":    "byte",
+	"smithy.api#Short":            "short",
+	"smithy.api#PrimitiveShort":   "short",
+	"smithy.api#Integer":          "integer",
+	"smithy.api#PrimitiveInteger": "integer",
+	"smithy.api#Long":             "long",
+	"smithy.api#PrimitiveLong":    "long",
+	"smithy.api#Float":            "float",
+	"smithy.api#PrimitiveFloat":   "float",
+	"smithy.api#Double":           "double",
+	"smithy.api#PrimitiveDouble":  "double",
+	"smithy.api#BigInteger":       "bigInteger",
+	"smithy.api#BigDecimal":       "bigDecimal",
+	"smithy.api#Timestamp":        "timestamp",
+	"smithy.api#Document":         "document",
+	"smithy.api#Unit":             "structure",
+}
+
+// resolveShape returns the shape a target refers to: a shape of the model, or for prelude shapes a
+// shape of their type without members or traits. ok is false for targets that are not defined.
+func resolveShape(model *AWSServiceModel, target string) (shape ServiceShape, ok bool) {
+	if shapeType, prelude := smithyPrelude[target]; prelude {
+		return ServiceShape{Type: shapeType}, true
+	}
+	shape, ok = model.Shapes[target]
+	return shape, ok
+}
+
+// OperationResolution is the result of resolving the operations of a model
+type OperationResolution struct {
+	// Operations holds the operation names found by either source, sorted by name
+	Operations []string
+	// Discrepancies lists operations only one of the sources found
+	Discrepancies []ResolutionDiscrepancy
+}
+
+// ResolveOperations resolves the operations of a model with a single algorithm.
+// Operations bound to the service are collected by following the service shape's
+// operations and resources (including resource lifecycle and nested resources); all
+// shapes of type "operation" are collected independently. The result is the union of
+// both sources, and operations found by only one of them are reported as discrepancies.
+func ResolveOperations(model *AWSServiceModel) OperationResolution {
+	bound := make(map[string]bool)
+	for _, shape := range model.Shapes {
+		if shape.Type == "service" {
+			collectBoundOperations(model, shape, bound, make(map[string]bool))
+			break
+		}
+	}
+
+	declared := make(map[string]bool)
+	for shapeName, shape := range model.Shapes {
+		if shape.Type == "operation" {
+			declared[shapeName] = true
+		}
+	}
+
+	names := make(map[string]bool)
+	var resolution OperationResolution
+	for target := range bound {
+		if !declared[target] {
+			resolution.Discrepancies = append(resolution.Discrepancies, ResolutionDiscrepancy{
+				Operation: extractOperationName(target),
+				FoundIn:   SourceServiceShape,
+				Message:   "bound to the service but no operation shape is defined",
+			})
+		}
+		names[extractOperationName(target)] = true
+	}
+	for target := range declared {
+		if len(bound) > 0 && !bound[target] {
+			resolution.Discrepancies = append(resolution.Discrepancies, ResolutionDiscrepancy{
+				Operation: extractOperationName(target),
+				FoundIn:   SourceOperationShape,
+				Message:   "operation shape is not bound to the service or any of its resources",
+			})
+		}
+		names[extractOperationName(target)] = true
+	}
+
+	for name := range names {
+		if name != "" {
+			resolution.Operations = append(resolution.Operations, name)
+		}
+	}
+	sort.Strings(resolution.Operations)
+	sort.Slice(resolution.Discrepancies, func(i, j int) bool {
+		return resolution.Discrepancies[i].Operation < resolution.Discrepancies[j].Operation
+	})
+
+	return resolution
+}
+
+// collectBoundOperations adds the operation targets bound to a service or resource shape,
+// recursing into the resources it declares
+func collectBoundOperations(model *AWSServiceModel, shape ServiceShape, bound map[string]bool, visited map[string]bool) {
+	for _, target := range resourceOperationTargets(shape) {
+		bound[target] = true
+	}
+
+	for _, resource := range shape.Resources {
+		if visited[resource.Target] {
+			continue
+		}
+		visited[resource.Target] = true
+		if resourceShape, ok := model.Shapes[resource.Target]; ok {
+			collectBoundOperations(model, resourceShape, bound, visited)
+		}
+	}
+}
+
+// operationShapes returns the operation shapes of a model keyed by operation name
+func operationShapes(model *AWSServiceModel) map[string]ServiceShape {
+	shapes := make(map[string]ServiceShape)
+	for id, shape := range model.Shapes {
+		if shape.Type == "operation" {
+			shapes[extractOperationName(id)] = shape
+		}
+	}
+	return shapes
+}
@@ -0,0 +1,202 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testModel builds a model from shapes keyed by their name in the com.amazonaws.test namespace
+func testModel(shapes map[string]ServiceShape) *AWSServiceModel {
+	model := &AWSServiceModel{Shapes: make(map[string]ServiceShape, len(shapes))}
+	for name, shape := range shapes {
+		model.Shapes["com.amazonaws.test#"+name] = shape
+	}
+	return model
+}
+
+// targets returns the operation targets of names in the com.amazonaws.test namespace
+func targets(names ...string) []OperationTarget {
+	var result []OperationTarget
+	for _, name := range names {
+		result = append(result, OperationTarget{Target: "com.amazonaws.test#" + name})
+	}
+	return result
+}
+
+// ref returns a reference to a shape of the com.amazonaws.test namespace, or to target as is when it
+// has a namespace
+func ref(target string) *ShapeReference {
+	for _, r := range target {
+		if r == '#' {
+			return &ShapeReference{Target: target}
+		}
+	}
+	return &ShapeReference{Target: "com.amazonaws.test#" + target}
+}
+
+func TestResolveOperations(t *testing.T) {
+	tests := []struct {
+		name          string
+		shapes        map[string]ServiceShape
+		operations    []string
+		discrepancies []ResolutionDiscrepancy
+	}{
+		{
+			name: "bound and declared",
+			shapes: map[string]ServiceShape{
+				"Service":   {Type: "service", Operations: targets("CreateBar", "GetBar")},
+				"CreateBar": {Type: "operation"},
+				"GetBar":    {Type: "operation"},
+			},
+			operations: []string{"CreateBar", "GetBar"},
+		},
+		{
+			name: "bound through nested resources",
+			shapes: map[string]ServiceShape{
+				"Service":    {Type: "service", Resources: targets("Bar")},
+				"Bar":        {Type: "resource", Create: ref("CreateBar"), Read: ref("GetBar"), Resources: targets("Baz")},
+				"Baz":        {Type: "resource", List: ref("ListBazs"), Operations: targets("StartBaz")},
+				"CreateBar":  {Type: "operation"},
+				"GetBar":     {Type: "operation"},
+				"ListBazs":   {Type: "operation"},
+				"StartBaz":   {Type: "operation"},
+				"UnusedBaz2": {Type: "structure"},
+			},
+			operations: []string{"CreateBar", "GetBar", "ListBazs", "StartBaz"},
+		},
+		{
+			name: "operation shapes only",
+			shapes: map[string]ServiceShape{
+				"Invoke":       {Type: "operation"},
+				"ListFunction": {Type: "operation"},
+			},
+			operations: []string{"Invoke", "ListFunction"},
+		},
+		{
+			name: "bound without an operation shape",
+			shapes: map[string]ServiceShape{
+				"Service":   {Type: "service", Operations: targets("CreateBar", "DeleteBar")},
+				"CreateBar": {Type: "operation"},
+			},
+			operations: []string{"CreateBar", "DeleteBar"},
+			discrepancies: []ResolutionDiscrepancy{
+				{Operation: "DeleteBar", FoundIn: SourceServiceShape, Message: "bound to the service but no operation shape is defined"},
+			},
+		},
+		{
+			name: "operation shape not bound",
+			shapes: map[string]ServiceShape{
+				"Service":   {Type: "service", Operations: targets("CreateBar")},
+				"CreateBar": {Type: "operation"},
+				"Orphan":    {Type: "operation"},
+			},
+			operations: []string{"CreateBar", "Orphan"},
+			discrepancies: []ResolutionDiscrepancy{
+				{Operation: "Orphan", FoundIn: SourceOperationShape, Message: "operation shape is not bound to the service or any of its resources"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolution := ResolveOperations(testModel(test.shapes))
+			if !reflect.DeepEqual(resolution.Operations, test.operations) {
+				t.Errorf("operations = %v, want %v", resolution.Operations, test.operations)
+			}
+			if !reflect.DeepEqual(resolution.Discrepancies, test.discrepancies) {
+				t.Errorf("discrepancies = %+v, want %+v", resolution.Discrepancies, test.discrepancies)
+			}
+		})
+	}
+}
+
+func TestResolveShape(t *testing.T) {
+	model := testModel(map[string]ServiceShape{
+		"BarRequest": {Type: "structure", Members: map[string]ShapeReference{"Name": {Target: "smithy.api#String"}}},
+	})
+
+	tests := []struct {
+		target    string
+		shapeType string
+		ok        bool
+	}{
+		{"com.amazonaws.test#BarRequest", "structure", true},
+		{"com.amazonaws.test#MissingRequest", "", false},
+		{"smithy.api#Unit", "structure", true},
+		{"smithy.api#String", "string", true},
+		{"smithy.api#PrimitiveInteger", "integer", true},
+		{"smithy.api#PrimitiveShort", "short", true},
+		{"smithy.api#PrimitiveByte", "byte", true},
+		{"smithy.api#PrimitiveFloat", "float", true},
+		{"smithy.api#PrimitiveDouble", "double", true},
+		{"smithy.api#BigInteger", "bigInteger", true},
+		{"smithy.api#Undefined", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target, func(t *testing.T) {
+			shape, ok := resolveShape(model, test.target)
+			if ok != test.ok || shape.Type != test.shapeType {
+				t.Errorf("resolveShape(%s) = %q, %t, want %q, %t", test.target, shape.Type, ok, test.shapeType, test.ok)
+			}
+		})
+	}
+}
+
+func TestLintModelOperationShapes(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation ServiceShape
+		rules     []string
+	}{
+		{
+			name:      "resolved input and output",
+			operation: ServiceShape{Type: "operation", Input: ref("BarRequest"), Output: ref("BarResponse")},
+		},
+		{
+			name:      "missing input shape",
+			operation: ServiceShape{Type: "operation", Input: ref("MissingRequest"), Output: ref("BarResponse")},
+			rules:     []string{"missing-shape"},
+		},
+		{
+			name:      "missing output shape",
+			operation: ServiceShape{Type: "operation", Input: ref("BarRequest"), Output: ref("MissingResponse")},
+			rules:     []string{"missing-shape"},
+		},
+		{
+			name:      "no input or output",
+			operation: ServiceShape{Type: "operation"},
+			rules:     []string{"operation-without-input", "operation-without-output"},
+		},
+		{
+			name:      "Unit input and output",
+			operation: ServiceShape{Type: "operation", Input: ref("smithy.api#Unit"), Output: ref("smithy.api#Unit")},
+		},
+		{
+			name:      "prelude members",
+			operation: ServiceShape{Type: "operation", Input: ref("PrimitiveRequest"), Output: ref("BarResponse")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := testModel(map[string]ServiceShape{
+				"Service":     {Type: "service", Operations: targets("GetBar")},
+				"GetBar":      test.operation,
+				"BarRequest":  {Type: "structure"},
+				"BarResponse": {Type: "structure", Members: map[string]ShapeReference{"Name": {Target: "smithy.api#String"}}},
+				"PrimitiveRequest": {Type: "structure", Members: map[string]ShapeReference{
+					"Count": {Target: "smithy.api#PrimitiveInteger"},
+					"Ratio": {Target: "smithy.api#PrimitiveDouble"},
+				}},
+			})
+			var rules []string
+			for _, diagnostic := range LintModel(model) {
+				rules = append(rules, diagnostic.Rule)
+			}
+			if !reflect.DeepEqual(rules, test.rules) {
+				t.Errorf("rules = %v, want %v", rules, test.rules)
+			}
+		})
+	}
+}
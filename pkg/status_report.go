@@ -0,0 +1,145 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrorCategory groups service failures so orchestration can react to them without parsing messages
+type ErrorCategory string
+
+const (
+	// ErrorCategoryModelNotFound means no API model was found for the service
+	ErrorCategoryModelNotFound ErrorCategory = "model_not_found"
+	// ErrorCategoryModelInvalid means the API model could not be read or parsed
+	ErrorCategoryModelInvalid ErrorCategory = "model_invalid"
+	// ErrorCategoryNoOperations means the API model defines no operations
+	ErrorCategoryNoOperations ErrorCategory = "no_operations"
+	// ErrorCategoryClassification means classification failed and the failure policy is to fail the service
+	ErrorCategoryClassification ErrorCategory = "classification"
+	// ErrorCategoryOutput means an output file could not be written
+	ErrorCategoryOutput ErrorCategory = "output"
+	// ErrorCategoryUnknown covers every other failure
+	ErrorCategoryUnknown ErrorCategory = "unknown"
+)
+
+// categorizedError attaches an ErrorCategory to an error without changing its message
+type categorizedError struct {
+	category ErrorCategory
+	err      error
+}
+
+func (e *categorizedError) Error() string { return e.err.Error() }
+
+func (e *categorizedError) Unwrap() error { return e.err }
+
+// CategorizeError tags an error with a category for the status report
+func CategorizeError(category ErrorCategory, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &categorizedError{category: category, err: err}
+}
+
+// ErrorCategoryOf returns the category of an error, or ErrorCategoryUnknown when it has none
+func ErrorCategoryOf(err error) ErrorCategory {
+	var categorized *categorizedError
+	if errors.As(err, &categorized) {
+		return categorized.category
+	}
+	return ErrorCategoryUnknown
+}
+
+// NewStatusReport starts the status report of a run
+func NewStatusReport(requested int) *StatusReport {
+	return &StatusReport{
+		SchemaVersion: SchemaVersion,
+		StartedAt:     time.Now().UTC(),
+		Summary:       StatusSummary{Requested: requested},
+		Services:      []ServiceStatusReport{},
+		Warnings:      []string{},
+	}
+}
+
+// Warn records a warning for a service, or for the whole run when serviceName is empty.
+// Warnings of services that are not recorded yet are attached once they are.
+func (r *StatusReport) Warn(serviceName, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	r.Summary.Warnings++
+	if serviceName == "" {
+		r.Warnings = append(r.Warnings, message)
+		return
+	}
+	if r.pendingWarnings == nil {
+		r.pendingWarnings = make(map[string][]string)
+	}
+	r.pendingWarnings[serviceName] = append(r.pendingWarnings[serviceName], message)
+}
+
+// RecordService adds the outcome of a processed service. A nil error marks the service as succeeded.
+func (r *StatusReport) RecordService(serviceName string, serviceOps *ServiceOperations, source string, elapsed time.Duration, err error) {
+	service := ServiceStatusReport{
+		Service:         serviceName,
+		Status:          ServiceSucceeded,
+		Source:          source,
+		DurationSeconds: elapsed.Seconds(),
+		Warnings:        r.pendingWarnings[serviceName],
+	}
+	delete(r.pendingWarnings, serviceName)
+	if service.Warnings == nil {
+		service.Warnings = []string{}
+	}
+
+	if err != nil {
+		service.Status = ServiceFailed
+		service.ErrorCategory = ErrorCategoryOf(err)
+		service.Error = err.Error()
+		r.Summary.Failed++
+	} else {
+		r.Summary.Succeeded++
+	}
+
+	if serviceOps != nil {
+		service.Operations = len(serviceOps.Operations)
+		if serviceOps.Timings != nil {
+			service.Timings = &ServiceTimings{
+				ModelParseSeconds:     serviceOps.Timings.ModelParse.Seconds(),
+				ControllerScanSeconds: serviceOps.Timings.ControllerScan.Seconds(),
+				ClassificationSeconds: serviceOps.Timings.Classification.Seconds(),
+			}
+		}
+		if err == nil {
+			r.Summary.Operations += len(serviceOps.Operations)
+		}
+	}
+	r.Services = append(r.Services, service)
+}
+
+// Finish stamps the end of the run and decides whether it succeeded as a whole
+func (r *StatusReport) Finish() {
+	r.FinishedAt = time.Now().UTC()
+	r.DurationSeconds = r.FinishedAt.Sub(r.StartedAt).Seconds()
+	r.Success = r.Summary.Failed == 0 && r.Summary.Succeeded == r.Summary.Requested
+}
+
+// FailedServices returns the services that failed during the run
+func (r *StatusReport) FailedServices() []ServiceStatusReport {
+	var failed []ServiceStatusReport
+	for _, service := range r.Services {
+		if service.Status == ServiceFailed {
+			failed = append(failed, service)
+		}
+	}
+	return failed
+}
+
+// WriteStatusReportJSON writes a status report to a JSON file
+func WriteStatusReportJSON(report *StatusReport, outputPath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status report: %w", err)
+	}
+	return writeOutputFile(outputPath, data)
+}
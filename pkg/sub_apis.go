@@ -0,0 +1,163 @@
+package extractor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// subAPIs maps a service to the models of sibling APIs its controller calls, e.g. s3-control for s3
+var subAPIs map[string][]string
+
+// detectSubAPIs enables detecting sub-APIs from the SDK packages a controller imports
+var detectSubAPIs bool
+
+// SetSubAPIs declares the sub-API models whose operations are merged into a service's operations,
+// given as service=model entries, e.g. s3=s3-control or dynamodb=dynamodb-streams
+func SetSubAPIs(declarations []string) error {
+	declared := make(map[string][]string)
+	for _, declaration := range declarations {
+		serviceName, model, ok := strings.Cut(declaration, "=")
+		serviceName, model = strings.TrimSpace(serviceName), strings.TrimSpace(model)
+		if !ok || serviceName == "" || model == "" {
+			return fmt.Errorf("invalid sub-API %q, expected <service>=<model>", declaration)
+		}
+		if model == serviceName {
+			return fmt.Errorf("invalid sub-API %q, a service is not its own sub-API", declaration)
+		}
+		declared[serviceName] = append(declared[serviceName], model)
+	}
+	subAPIs = declared
+	return nil
+}
+
+// SetDetectSubAPIs enables detecting the sub-APIs of a service from its controller's imports: AWS SDK
+// packages of another model sharing the service's IAM prefix, e.g. dynamodbstreams for dynamodb
+func SetDetectSubAPIs(enabled bool) {
+	detectSubAPIs = enabled
+}
+
+// SubAPIs returns the sorted sub-API models of a service, declared or detected
+func SubAPIs(serviceName string) []string {
+	seen := make(map[string]bool)
+	var models []string
+	add := func(model string) {
+		if !seen[model] {
+			seen[model] = true
+			models = append(models, model)
+		}
+	}
+	for _, model := range subAPIs[serviceName] {
+		add(model)
+	}
+	if detectSubAPIs {
+		for _, model := range detectedSubAPIs(serviceName) {
+			add(model)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
+// detectedSubAPIs returns the models of the SDK packages a controller imports that share the
+// service's IAM prefix without being the service's own model
+func detectedSubAPIs(serviceName string) []string {
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return nil
+	}
+	available, err := ListAvailableServices()
+	if err != nil {
+		return nil
+	}
+	modelsByPackage := make(map[string]string, len(available))
+	for _, model := range available {
+		modelsByPackage[strings.ReplaceAll(model, "-", "")] = model
+	}
+
+	ownPrefix := IAMServicePrefix(serviceName)
+	ownModel, _ := getModelNameFromController(serviceName)
+	packages := make(map[string]bool)
+	files, _ := listControllerFiles(controllerPath)
+	for _, path := range files {
+		for _, packageName := range sdkImportAliases(path) {
+			packages[packageName] = true
+		}
+	}
+
+	var models []string
+	for packageName := range packages {
+		model, ok := modelsByPackage[packageName]
+		if !ok || model == serviceName || strings.EqualFold(model, ownModel) || packageName == serviceName {
+			continue
+		}
+		if IAMServicePrefix(model) == ownPrefix {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// subAPIPackages returns the AWS SDK package names of a service's sub-APIs
+func subAPIPackages(serviceName string) []string {
+	var packages []string
+	for _, model := range SubAPIs(serviceName) {
+		packages = append(packages, strings.ReplaceAll(model, "-", ""))
+	}
+	return packages
+}
+
+// resolveSubAPIOperations returns the operations of a service's sub-API models mapped to their model.
+// Operations named like one of the service's own operations or of an earlier sub-API are left out,
+// since operations are identified by name, and reported as warnings like unloadable models.
+func resolveSubAPIOperations(serviceName string, ownOperations []string) (map[string]string, []string) {
+	models := make(map[string]string)
+	own := make(map[string]bool, len(ownOperations))
+	for _, name := range ownOperations {
+		own[name] = true
+	}
+
+	var warnings []string
+	for _, subAPI := range SubAPIs(serviceName) {
+		model, err := loadServiceModel(subAPI)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("sub-API %s not merged: %v", subAPI, err))
+			continue
+		}
+		var collisions []string
+		for _, name := range ResolveOperations(model).Operations {
+			if own[name] || models[name] != "" {
+				collisions = append(collisions, name)
+				continue
+			}
+			models[name] = subAPI
+		}
+		if len(collisions) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d operation(s) of sub-API %s share a name with an operation already extracted and are left out: %s",
+				len(collisions), subAPI, strings.Join(collisions, ", ")))
+		}
+	}
+	return models, warnings
+}
+
+// operationIAMAction returns the IAM action of an operation, using the prefix of the sub-API it comes from
+func operationIAMAction(serviceName string, op Operation) string {
+	if op.Model != "" {
+		return mapOperationToIAMAction(op.Model, op.Name)
+	}
+	return mapOperationToIAMAction(serviceName, op.Name)
+}
+
+// mergedSubAPIs returns the sorted sub-API models at least one operation was merged from
+func mergedSubAPIs(models map[string]string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, model := range models {
+		if !seen[model] {
+			seen[model] = true
+			merged = append(merged, model)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
@@ -0,0 +1,120 @@
+package extractor
+
+import (
+	"sort"
+	"strings"
+)
+
+// PredictSupport predicts which operations the ACK code generator wires up from the resources of
+// a service, before anything is implemented, and compares the prediction with actual support.
+// Like the code generator, it derives a resource from every Create<Resource> operation not
+// listed in generator.yaml ignore.resource_names and maps its CRUD operations by naming
+// convention: Delete<Resource>, Describe<Resource> or Get<Resource> to read one, Describe or
+// List of the plural to read many when there is no read-one operation, and Update<Resource> or
+// Modify<Resource>. Operations assigned to a resource in generator.yaml operations are predicted
+// as well; ignored operations never are. Predicted operations get their predicted_resource set.
+func PredictSupport(operations []Operation, generatorConfig *GeneratorConfig) *SupportPrediction {
+	exists := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		exists[op.Name] = true
+	}
+
+	ignoredResources := make(map[string]bool)
+	if generatorConfig != nil {
+		for _, name := range generatorConfig.Ignore.ResourceNames {
+			ignoredResources[name] = true
+		}
+	}
+
+	predicted := make(map[string]string)
+	predict := func(operationName, resource string) {
+		if exists[operationName] && predicted[operationName] == "" {
+			if generatorConfig == nil || !generatorConfig.IsOperationIgnored(operationName) {
+				predicted[operationName] = resource
+			}
+		}
+	}
+	firstExisting := func(names ...string) string {
+		for _, name := range names {
+			if exists[name] {
+				return name
+			}
+		}
+		return ""
+	}
+
+	resourceSet := make(map[string]bool)
+	for _, op := range operations {
+		resource, ok := strings.CutPrefix(op.Name, "Create")
+		if !ok || resource == "" || ignoredResources[resource] {
+			continue
+		}
+		resourceSet[resource] = true
+
+		predict(op.Name, resource)
+		predict("Delete"+resource, resource)
+		if readOne := firstExisting("Describe"+resource, "Get"+resource); readOne != "" {
+			predict(readOne, resource)
+		} else {
+			plural := pluralize(resource)
+			predict(firstExisting("Describe"+plural, "List"+plural), resource)
+		}
+		predict(firstExisting("Update"+resource, "Modify"+resource), resource)
+	}
+
+	if generatorConfig != nil {
+		for operationName, config := range generatorConfig.Operations {
+			for _, resource := range config.ResourceName {
+				if !ignoredResources[resource] {
+					resourceSet[resource] = true
+					predict(operationName, resource)
+				}
+			}
+		}
+	}
+
+	prediction := &SupportPrediction{Resources: make([]string, 0, len(resourceSet)), MissingOperations: []string{}, UnpredictedOperations: []string{}}
+	for resource := range resourceSet {
+		prediction.Resources = append(prediction.Resources, resource)
+	}
+	sort.Strings(prediction.Resources)
+
+	supported := 0
+	for i := range operations {
+		op := &operations[i]
+		op.PredictedResource = predicted[op.Name]
+		if op.IsSupported() {
+			supported++
+		}
+		switch {
+		case op.PredictedResource != "" && op.IsSupported():
+			prediction.ConfirmedOperations++
+		case op.PredictedResource != "":
+			prediction.MissingOperations = append(prediction.MissingOperations, op.Name)
+		case op.IsSupported():
+			prediction.UnpredictedOperations = append(prediction.UnpredictedOperations, op.Name)
+		}
+	}
+	sort.Strings(prediction.MissingOperations)
+	sort.Strings(prediction.UnpredictedOperations)
+
+	prediction.PredictedOperations = len(predicted)
+	if prediction.PredictedOperations > 0 {
+		prediction.Precision = float64(prediction.ConfirmedOperations) * 100 / float64(prediction.PredictedOperations)
+	}
+	if supported > 0 {
+		prediction.Recall = float64(prediction.ConfirmedOperations) * 100 / float64(supported)
+	}
+	return prediction
+}
+
+// pluralize returns the English plural of a resource name the way AWS names list operations
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
@@ -0,0 +1,178 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SupportStatus describes how far an ACK controller implements an operation
+type SupportStatus string
+
+const (
+	// SupportImplemented operations are referenced from generated sdk code
+	SupportImplemented SupportStatus = "implemented"
+	// SupportPartiallyImplemented operations are only referenced from hooks or other custom code
+	SupportPartiallyImplemented SupportStatus = "partially-implemented"
+	// SupportIntentionallyIgnored operations are listed in generator.yaml ignore.operations
+	SupportIntentionallyIgnored SupportStatus = "intentionally-ignored"
+	// SupportExcluded operations are internal, console-only or need no IAM permissions
+	SupportExcluded SupportStatus = "excluded"
+	// SupportPlanned operations are listed in the roadmap file
+	SupportPlanned SupportStatus = "planned"
+	// SupportUnsupported operations are not referenced by the controller at all
+	SupportUnsupported SupportStatus = "unsupported"
+)
+
+// roadmap maps a service name to the operations planned for its controller
+var roadmap map[string][]string
+
+// LoadRoadmap reads a YAML roadmap file mapping service names to planned operations:
+//
+//	dynamodb:
+//	  - CreateBackup
+//	  - RestoreTableFromBackup
+func LoadRoadmap(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read roadmap file %s: %w", path, err)
+	}
+
+	var planned map[string][]string
+	if err := yaml.Unmarshal(data, &planned); err != nil {
+		return fmt.Errorf("failed to parse roadmap file %s: %w", path, err)
+	}
+
+	roadmap = planned
+	return nil
+}
+
+// ParseSupportStatus converts a string to a SupportStatus
+func ParseSupportStatus(value string) (SupportStatus, error) {
+	status := SupportStatus(value)
+	if value == "" {
+		return SupportUnsupported, nil
+	}
+	if !status.Valid() {
+		return "", fmt.Errorf("invalid support status %q", value)
+	}
+	return status, nil
+}
+
+// Valid reports whether the status is one of the known support statuses
+func (s SupportStatus) Valid() bool {
+	switch s {
+	case SupportImplemented, SupportPartiallyImplemented, SupportIntentionallyIgnored, SupportExcluded, SupportPlanned, SupportUnsupported:
+		return true
+	}
+	return false
+}
+
+// IsSupported reports whether the status means the controller has code calling the operation
+func (s SupportStatus) IsSupported() bool {
+	return s == SupportImplemented || s == SupportPartiallyImplemented
+}
+
+// Countable reports whether operations with the status count towards coverage
+func (s SupportStatus) Countable() bool {
+	return s != SupportIntentionallyIgnored && s != SupportExcluded
+}
+
+// String returns the canonical name of the status, treating the zero value as unsupported
+func (s SupportStatus) String() string {
+	if s == "" {
+		return string(SupportUnsupported)
+	}
+	return string(s)
+}
+
+// MarshalJSON writes the canonical name of the status and rejects unknown values
+func (s SupportStatus) MarshalJSON() ([]byte, error) {
+	if s != "" && !s.Valid() {
+		return nil, fmt.Errorf("invalid support status %q", string(s))
+	}
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON reads a support status and rejects unknown values
+func (s *SupportStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("support status must be a string: %w", err)
+	}
+	parsed, err := ParseSupportStatus(value)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// IsSupported reports whether the controller has code calling the operation
+func (o Operation) IsSupported() bool {
+	return o.SupportStatus.IsSupported()
+}
+
+// supportStatusForLocations derives the status of an operation from where it is referenced.
+// Operations declared with an ack-operation comment count as implemented wherever the comment is.
+func supportStatusForLocations(locations []Location) SupportStatus {
+	if len(locations) == 0 {
+		return SupportUnsupported
+	}
+	for _, location := range locations {
+		if location.Annotation || classifyControllerFile(location.File) == "sdk" {
+			return SupportImplemented
+		}
+	}
+	return SupportPartiallyImplemented
+}
+
+// assignUnsupportedStatus refines the status of operations without controller code
+// using generator.yaml ignores and the roadmap
+func assignUnsupportedStatus(serviceName string, operations []Operation, generatorConfig *GeneratorConfig) {
+	planned := make(map[string]bool)
+	for _, name := range roadmap[serviceName] {
+		planned[name] = true
+	}
+
+	for i := range operations {
+		if operations[i].IsSupported() {
+			continue
+		}
+		switch {
+		case generatorConfig != nil && generatorConfig.IsOperationIgnored(operations[i].Name):
+			operations[i].SupportStatus = SupportIntentionallyIgnored
+		case planned[operations[i].Name]:
+			operations[i].SupportStatus = SupportPlanned
+		default:
+			operations[i].SupportStatus = SupportUnsupported
+		}
+	}
+}
+
+// CountSupportStatus counts operations per support status
+func CountSupportStatus(operations []Operation) map[SupportStatus]int {
+	counts := make(map[SupportStatus]int)
+	for _, op := range operations {
+		counts[op.SupportStatus]++
+	}
+	return counts
+}
+
+// SupportCoverage returns the percentage of operations with controller code.
+// Intentionally ignored and excluded operations are left out of the denominator.
+func SupportCoverage(counts map[SupportStatus]int) float64 {
+	total := 0
+	for status, count := range counts {
+		if status.Countable() {
+			total += count
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	supported := counts[SupportImplemented] + counts[SupportPartiallyImplemented]
+	return float64(supported) * 100 / float64(total)
+}
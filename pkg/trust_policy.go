@@ -0,0 +1,133 @@
+package extractor
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultControllerNamespace  = "ack-system"
+	podIdentityServicePrincipal = "pods.eks.amazonaws.com"
+)
+
+// DefaultServiceAccountName returns the service account name used by the ACK controller Helm charts
+func DefaultServiceAccountName(serviceName string) string {
+	return fmt.Sprintf("ack-%s-controller", serviceName)
+}
+
+// GenerateIRSATrustPolicy creates the assume-role trust policy for IAM Roles for Service Accounts
+// in the given partition. The OIDC provider condition restricts the role to the controller's
+// service account.
+func GenerateIRSATrustPolicy(serviceName string, cfg TrustPolicyConfig, partition string) (*IAMPolicy, error) {
+	if err := ValidatePartition(partition); err != nil {
+		return nil, err
+	}
+	if cfg.AccountID == "" {
+		return nil, fmt.Errorf("account ID is required for IRSA trust policy")
+	}
+	if cfg.OIDCProvider == "" {
+		return nil, fmt.Errorf("OIDC provider is required for IRSA trust policy")
+	}
+
+	// The provider is referenced without its scheme, e.g. oidc.eks.us-west-2.amazonaws.com/id/EXAMPLE
+	provider := cfg.OIDCProvider
+	if _, rest, found := strings.Cut(provider, "://"); found {
+		provider = rest
+	}
+	provider = strings.TrimSuffix(provider, "/")
+	namespace, serviceAccount := trustPolicySubject(serviceName, cfg)
+
+	return &IAMPolicy{
+		Version: "2012-10-17",
+		Statement: []PolicyStatement{
+			{
+				Effect: "Allow",
+				Principal: map[string]string{
+					"Federated": fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", partition, cfg.AccountID, provider),
+				},
+				Action: []string{"sts:AssumeRoleWithWebIdentity"},
+				Condition: map[string]map[string]string{
+					"StringEquals": {
+						provider + ":sub": fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount),
+						provider + ":aud": "sts.amazonaws.com",
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// GeneratePodIdentityTrustPolicy creates the assume-role trust policy for EKS Pod Identity in the
+// given partition. When account and cluster inputs are provided the policy is scoped to that cluster.
+func GeneratePodIdentityTrustPolicy(cfg TrustPolicyConfig, partition string) (*IAMPolicy, error) {
+	if err := ValidatePartition(partition); err != nil {
+		return nil, err
+	}
+	statement := PolicyStatement{
+		Effect: "Allow",
+		Principal: map[string]string{
+			"Service": podIdentityServicePrincipal,
+		},
+		Action: []string{"sts:AssumeRole", "sts:TagSession"},
+	}
+
+	if cfg.AccountID != "" {
+		condition := map[string]map[string]string{
+			"StringEquals": {"aws:SourceAccount": cfg.AccountID},
+		}
+		if cfg.ClusterName != "" {
+			if cfg.Region == "" {
+				return nil, fmt.Errorf("region is required to scope Pod Identity trust policy to cluster %s", cfg.ClusterName)
+			}
+			condition["ArnEquals"] = map[string]string{
+				"aws:SourceArn": fmt.Sprintf("arn:%s:eks:%s:%s:cluster/%s", partition, cfg.Region, cfg.AccountID, cfg.ClusterName),
+			}
+		}
+		statement.Condition = condition
+	}
+
+	return &IAMPolicy{
+		Version:   "2012-10-17",
+		Statement: []PolicyStatement{statement},
+	}, nil
+}
+
+// ValidateTrustPolicyJSON validates that a trust policy has the principal and actions it needs
+func ValidateTrustPolicyJSON(policy IAMPolicy) error {
+	if policy.Version == "" {
+		return fmt.Errorf("policy Version is required")
+	}
+
+	if len(policy.Statement) == 0 {
+		return fmt.Errorf("policy must have at least one statement")
+	}
+
+	for i, stmt := range policy.Statement {
+		if stmt.Principal == nil {
+			return fmt.Errorf("statement %d: Principal is required", i)
+		}
+
+		if len(stmt.Action) == 0 {
+			return fmt.Errorf("statement %d: Action is required", i)
+		}
+
+		if stmt.Resource != nil {
+			return fmt.Errorf("statement %d: Resource is not allowed in a trust policy", i)
+		}
+	}
+
+	return nil
+}
+
+// trustPolicySubject resolves the namespace and service account, falling back to ACK defaults
+func trustPolicySubject(serviceName string, cfg TrustPolicyConfig) (string, string) {
+	namespace := cfg.Namespace
+	if namespace == "" {
+		namespace = defaultControllerNamespace
+	}
+	serviceAccount := cfg.ServiceAccount
+	if serviceAccount == "" {
+		serviceAccount = DefaultServiceAccountName(serviceName)
+	}
+	return namespace, serviceAccount
+}
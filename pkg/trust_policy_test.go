@@ -0,0 +1,133 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerateIRSATrustPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           TrustPolicyConfig
+		partition     string
+		wantPrincipal string
+		wantSubject   string
+	}{
+		{
+			name:          "https provider in the aws partition",
+			cfg:           TrustPolicyConfig{AccountID: "111122223333", OIDCProvider: "https://oidc.eks.us-west-2.amazonaws.com/id/EXAMPLE"},
+			partition:     "aws",
+			wantPrincipal: "arn:aws:iam::111122223333:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLE",
+			wantSubject:   "system:serviceaccount:ack-system:ack-foo-controller",
+		},
+		{
+			name:          "other scheme and trailing slash in the aws-us-gov partition",
+			cfg:           TrustPolicyConfig{AccountID: "111122223333", OIDCProvider: "HTTP://oidc.eks.us-gov-west-1.amazonaws.com/id/EXAMPLE/"},
+			partition:     "aws-us-gov",
+			wantPrincipal: "arn:aws-us-gov:iam::111122223333:oidc-provider/oidc.eks.us-gov-west-1.amazonaws.com/id/EXAMPLE",
+			wantSubject:   "system:serviceaccount:ack-system:ack-foo-controller",
+		},
+		{
+			name:          "custom namespace and service account in the aws-cn partition",
+			cfg:           TrustPolicyConfig{AccountID: "111122223333", OIDCProvider: "oidc.eks.cn-north-1.amazonaws.com.cn/id/EXAMPLE", Namespace: "controllers", ServiceAccount: "foo"},
+			partition:     "aws-cn",
+			wantPrincipal: "arn:aws-cn:iam::111122223333:oidc-provider/oidc.eks.cn-north-1.amazonaws.com.cn/id/EXAMPLE",
+			wantSubject:   "system:serviceaccount:controllers:foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := GenerateIRSATrustPolicy("foo", tt.cfg, tt.partition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateTrustPolicyJSON(*policy); err != nil {
+				t.Fatal(err)
+			}
+			statement := policy.Statement[0]
+			if principal := statement.Principal.(map[string]string)["Federated"]; principal != tt.wantPrincipal {
+				t.Errorf("principal %s, want %s", principal, tt.wantPrincipal)
+			}
+			provider := tt.wantPrincipal[len("arn:"+tt.partition+":iam::111122223333:oidc-provider/"):]
+			wantCondition := map[string]map[string]string{"StringEquals": {
+				provider + ":sub": tt.wantSubject,
+				provider + ":aud": "sts.amazonaws.com",
+			}}
+			if !reflect.DeepEqual(statement.Condition, wantCondition) {
+				t.Errorf("condition %v, want %v", statement.Condition, wantCondition)
+			}
+		})
+	}
+
+	for name, cfg := range map[string]TrustPolicyConfig{
+		"no account ID":    {OIDCProvider: "oidc.eks.us-west-2.amazonaws.com/id/EXAMPLE"},
+		"no OIDC provider": {AccountID: "111122223333"},
+	} {
+		if _, err := GenerateIRSATrustPolicy("foo", cfg, "aws"); err == nil {
+			t.Errorf("%s: trust policy generated", name)
+		}
+	}
+	valid := TrustPolicyConfig{AccountID: "111122223333", OIDCProvider: "oidc.eks.us-west-2.amazonaws.com/id/EXAMPLE"}
+	if _, err := GenerateIRSATrustPolicy("foo", valid, "aws-unknown"); err == nil {
+		t.Error("trust policy generated for an unknown partition")
+	}
+}
+
+func TestGeneratePodIdentityTrustPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           TrustPolicyConfig
+		partition     string
+		wantCondition interface{}
+	}{
+		{
+			name:          "unscoped",
+			partition:     "aws",
+			wantCondition: nil,
+		},
+		{
+			name:          "scoped to the account",
+			cfg:           TrustPolicyConfig{AccountID: "111122223333"},
+			partition:     "aws",
+			wantCondition: map[string]map[string]string{"StringEquals": {"aws:SourceAccount": "111122223333"}},
+		},
+		{
+			name:      "scoped to a cluster in the aws-cn partition",
+			cfg:       TrustPolicyConfig{AccountID: "111122223333", ClusterName: "prod", Region: "cn-north-1"},
+			partition: "aws-cn",
+			wantCondition: map[string]map[string]string{
+				"StringEquals": {"aws:SourceAccount": "111122223333"},
+				"ArnEquals":    {"aws:SourceArn": "arn:aws-cn:eks:cn-north-1:111122223333:cluster/prod"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := GeneratePodIdentityTrustPolicy(tt.cfg, tt.partition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateTrustPolicyJSON(*policy); err != nil {
+				t.Fatal(err)
+			}
+			statement := policy.Statement[0]
+			if !reflect.DeepEqual(statement.Action, []string{"sts:AssumeRole", "sts:TagSession"}) {
+				t.Errorf("actions %v", statement.Action)
+			}
+			if tt.wantCondition == nil {
+				if statement.Condition != nil {
+					t.Errorf("condition %v, want none", statement.Condition)
+				}
+			} else if !reflect.DeepEqual(statement.Condition, tt.wantCondition) {
+				t.Errorf("condition %v, want %v", statement.Condition, tt.wantCondition)
+			}
+		})
+	}
+
+	if _, err := GeneratePodIdentityTrustPolicy(TrustPolicyConfig{AccountID: "111122223333", ClusterName: "prod"}, "aws"); err == nil {
+		t.Error("trust policy scoped to a cluster without a region was generated")
+	}
+	if _, err := GeneratePodIdentityTrustPolicy(TrustPolicyConfig{}, "aws-unknown"); err == nil {
+		t.Error("trust policy generated for an unknown partition")
+	}
+}
@@ -1,32 +1,385 @@
 package extractor
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Operation represents a detailed AWS API operation with metadata
 type Operation struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Name                string                    `json:"name"`
+	Type                OperationType             `json:"type"`
+	ClassificationDrift *ClassificationDrift      `json:"classification_drift,omitempty"`
+	Correction          *ClassificationCorrection `json:"classification_correction,omitempty"`
+	File                string                    `json:"file"`
+	Line                int                       `json:"line"`
+	Locations           []Location                `json:"locations,omitempty"`
+	SupportStatus       SupportStatus             `json:"support_status"`
+	Implementation      ImplementationKind        `json:"implementation,omitempty"`
+	SemanticGroup       SemanticGroup             `json:"semantic_group,omitempty"`
+	ExclusionReason     string                    `json:"exclusion_reason,omitempty"`
+	PredictedResource   string                    `json:"predicted_resource,omitempty"`
+	ModelSource         string                    `json:"model_source,omitempty"`
+	Issues              []IssueReference          `json:"issues,omitempty"`
+	Owner               *OperationOwner           `json:"owner,omitempty"`
+	CloudFormationType  string                    `json:"cloudformation_type,omitempty"`
+	Waiters             []Waiter                  `json:"waiters,omitempty"`
+	Requires            []string                  `json:"requires,omitempty"`
+	Relevance           float64                   `json:"relevance"`
+	Declarative         bool                      `json:"declarative"`
+	APIVersion          string                    `json:"api_version,omitempty"`
+	ReleaseStage        ReleaseStage              `json:"release_stage,omitempty"`
+	DeprecatedSince     string                    `json:"deprecated_since,omitempty"`
+	DeprecationMessage  string                    `json:"deprecation_message,omitempty"`
+	Consistency         ConsistencyModel          `json:"consistency,omitempty"`
+	ConsistencySource   string                    `json:"consistency_source,omitempty"`
+	AccessLevel         AccessLevel               `json:"access_level,omitempty"`
+	AccessLevelSource   string                    `json:"access_level_source,omitempty"`
+	// Model is the sub-API model the operation comes from, empty for the service's own model
+	Model string `json:"model,omitempty"`
+	// HeuristicClassification marks types guessed from the operation name after classification failed
+	HeuristicClassification bool `json:"heuristic_classification,omitempty"`
+	// DescribeRule names the rule that classified a Describe or List operation from its output without Bedrock
+	DescribeRule string `json:"describe_rule,omitempty"`
+}
+
+// Waiter represents a Smithy waiter polling an operation until a resource reaches a state
+type Waiter struct {
+	Name           string   `json:"name"`
+	MinDelay       int      `json:"min_delay"`
+	MaxDelay       int      `json:"max_delay"`
+	AcceptorStates []string `json:"acceptor_states"`
+	Deprecated     bool     `json:"deprecated,omitempty"`
+}
+
+// ClassificationDrift records a classification that flipped an operation between planes since an earlier run
+type ClassificationDrift struct {
+	Previous OperationType `json:"previous"`
+	Proposed OperationType `json:"proposed"`
+	Accepted bool          `json:"accepted"`
+}
+
+// OperationOwner attributes a supported operation to the maintainers of the code implementing it
+type OperationOwner struct {
+	CodeOwners      []string `json:"codeowners,omitempty"`
+	LastAuthor      string   `json:"last_author,omitempty"`
+	LastAuthorEmail string   `json:"last_author_email,omitempty"`
+	LastModified    string   `json:"last_modified,omitempty"`
+	Commit          string   `json:"commit,omitempty"`
+}
+
+// IssueReference links an operation to a GitHub issue tracking it
+type IssueReference struct {
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+}
+
+// Location represents a single reference to an operation in controller code
+type Location struct {
 	File string `json:"file"`
 	Line int    `json:"line"`
+	// Annotation marks locations of ack-operation comments rather than code referencing the operation
+	Annotation bool `json:"annotation,omitempty"`
+}
+
+// SkippedPath is a controller path left out of the scan and why
+type SkippedPath struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
 }
 
 // ServiceOperations represents all operations for a service
 type ServiceOperations struct {
-	ServiceName                    string      `json:"service_name"`
-	TotalOperations                int         `json:"total_operations"`
-	SupportedOperations            int         `json:"supported_operations"`
-	ControlPlaneOps                int         `json:"control_plane_operations"`
-	SupportedControlPlaneOps       int         `json:"supported_control_plane_operations"`
-	Operations                     []Operation `json:"operations"`
+	SchemaVersion            int                        `json:"schema_version"`
+	ServiceName              string                     `json:"service_name"`
+	TotalOperations          int                        `json:"total_operations"`
+	SupportedOperations      int                        `json:"supported_operations"`
+	ControlPlaneOps          int                        `json:"control_plane_operations"`
+	SupportedControlPlaneOps int                        `json:"supported_control_plane_operations"`
+	SupportStatusCounts      map[SupportStatus]int      `json:"support_status_counts"`
+	ReleaseStageCounts       map[ReleaseStage]int       `json:"release_stage_counts,omitempty"`
+	SemanticGroups           []SemanticGroupSummary     `json:"semantic_groups,omitempty"`
+	AccessLevelCounts        map[AccessLevel]int        `json:"access_level_counts,omitempty"`
+	SupportCoverage          float64                    `json:"support_coverage"`
+	RelevantCoverage         float64                    `json:"relevant_coverage"`
+	Operations               []Operation                `json:"operations"`
+	Metadata                 *ServiceMetadata           `json:"service_metadata,omitempty"`
+	FileDensity              []FileDensity              `json:"file_density,omitempty"`
+	ImplementationCounts     map[ImplementationKind]int `json:"implementation_counts,omitempty"`
+	CustomImplementation     float64                    `json:"custom_implementation_share"`
+	ResolutionDiscrepancies  []ResolutionDiscrepancy    `json:"resolution_discrepancies,omitempty"`
+	CloudFormationResources  []CloudFormationResource   `json:"cloudformation_resources,omitempty"`
+	ClassificationSample     *ClassificationSample      `json:"classification_sample,omitempty"`
+	SupportPrediction        *SupportPrediction         `json:"support_prediction,omitempty"`
+	ModelSources             map[string]int             `json:"model_sources,omitempty"`
+	NoController             bool                       `json:"no_controller,omitempty"`
+	ControllerRelease        string                     `json:"controller_release,omitempty"`
+	UnmodeledCalls           []UnmodeledCall            `json:"unmodeled_calls,omitempty"`
+	SubAPIs                  []string                   `json:"sub_apis,omitempty"`
+	SkippedPaths             []SkippedPath              `json:"skipped_paths,omitempty"`
+	DependencyGaps           []DependencyGap            `json:"dependency_gaps,omitempty"`
+	Only                     []string                   `json:"only,omitempty"`
+	Warnings                 []string                   `json:"-"`
+	Timings                  *PhaseTimings              `json:"-"`
+}
+
+// SupportPrediction compares the operations the ACK code generator is predicted to wire up
+// with the operations the controller actually supports
+type SupportPrediction struct {
+	Resources             []string `json:"resources"`
+	PredictedOperations   int      `json:"predicted_operations"`
+	ConfirmedOperations   int      `json:"confirmed_operations"`
+	MissingOperations     []string `json:"missing_operations"`
+	UnpredictedOperations []string `json:"unpredicted_operations"`
+	Precision             float64  `json:"precision"`
+	Recall                float64  `json:"recall"`
+}
+
+// ClassificationSample estimates the control/data plane split of a service's unsupported
+// operations from a random sample of classified operations
+type ClassificationSample struct {
+	SamplePercent              float64 `json:"sample_percent"`
+	SampledOperations          int     `json:"sampled_operations"`
+	UnsampledOperations        int     `json:"unsampled_operations"`
+	ControlPlaneOps            int     `json:"control_plane_operations"`
+	DataPlaneOps               int     `json:"data_plane_operations"`
+	EstimatedControlPlaneShare float64 `json:"estimated_control_plane_share"`
+	MarginOfError              float64 `json:"margin_of_error"`
+	EstimatedControlPlaneOps   int     `json:"estimated_control_plane_operations"`
+}
+
+// UnmodeledCall is an API call in controller code to an operation the service model does not
+// define, such as a deprecated operation or an operation of another service. Controller call
+// audits use it for every call.
+type UnmodeledCall struct {
+	Service      string     `json:"service"`
+	Operation    string     `json:"operation"`
+	Action       string     `json:"action"`
+	CrossService bool       `json:"cross_service"`
+	Locations    []Location `json:"locations"`
+}
+
+// ControllerCalls lists the AWS API calls of a controller, found without the service model
+type ControllerCalls struct {
+	SchemaVersion     int             `json:"schema_version"`
+	ServiceName       string          `json:"service_name"`
+	ControllerRelease string          `json:"controller_release,omitempty"`
+	Actions           []string        `json:"actions"`
+	Calls             []UnmodeledCall `json:"calls"`
+}
+
+// OperationSetResult is the outcome of a set operation on the operations of several services
+type OperationSetResult struct {
+	Operation SetOperation         `json:"operation"`
+	Key       SetKey               `json:"key"`
+	Services  []string             `json:"services"`
+	Members   []OperationSetMember `json:"members"`
+}
+
+// OperationSetMember is an operation name or IAM action in the result of a set operation
+type OperationSetMember struct {
+	Name string `json:"name"`
+	// Services lists the services having the member, in input order
+	Services []string `json:"services"`
+}
+
+// DependencyGap is a supported operation whose controller implements none of the read
+// operations it depends on, a frequent source of reconcile bugs
+type DependencyGap struct {
+	Operation string   `json:"operation"`
+	Requires  []string `json:"requires"`
+	Reason    string   `json:"reason"`
+}
+
+// PhaseTimings records how long each extraction phase took for a service
+type PhaseTimings struct {
+	ModelParse     time.Duration
+	ControllerScan time.Duration
+	Classification time.Duration
+}
+
+// CombinedOperations holds the operations of several services in a single document
+type CombinedOperations struct {
+	SchemaVersion   int                           `json:"schema_version"`
+	TotalOperations int                           `json:"total_operations"`
+	Services        map[string]*ServiceOperations `json:"services"`
+	NameCollisions  []NameCollision               `json:"name_collisions,omitempty"`
+}
+
+// NameCollision is an operation name extracted from several services
+type NameCollision struct {
+	Operation string   `json:"operation"`
+	Services  []string `json:"services"`
+	// Actions are the IAM actions of the operation in the order of Services
+	Actions []string `json:"actions"`
+	// DistinctActions is set when the services map the operation to different IAM actions; otherwise
+	// they share an IAM prefix and the action is granted once by combined policies
+	DistinctActions bool `json:"distinct_actions"`
+}
+
+// FileDensity represents how many supported operations a single controller file implements
+type FileDensity struct {
+	File           string   `json:"file"`
+	Kind           string   `json:"kind"`
+	Operations     int      `json:"operations"`
+	OperationNames []string `json:"operation_names"`
+	Hot            bool     `json:"hot"`
 }
 
 // AWSServiceModel represents the top-level structure of AWS API model JSON files
 type AWSServiceModel struct {
 	Shapes map[string]ServiceShape `json:"shapes"`
+
+	// operationSources maps operation names to the model source defining them when several are merged
+	operationSources map[string]string
 }
 
 // ServiceShape represents a shape in the AWS API model
 type ServiceShape struct {
-	Type       string            `json:"type"`
-	Operations []OperationTarget `json:"operations,omitempty"`
+	Type       string                     `json:"type"`
+	Version    string                     `json:"version,omitempty"`
+	Operations []OperationTarget          `json:"operations,omitempty"`
+	Traits     map[string]json.RawMessage `json:"traits,omitempty"`
+	Input      *ShapeReference            `json:"input,omitempty"`
+	Output     *ShapeReference            `json:"output,omitempty"`
+	Errors     []ShapeReference           `json:"errors,omitempty"`
+	Members    map[string]ShapeReference  `json:"members,omitempty"`
+	Member     *ShapeReference            `json:"member,omitempty"`
+	Key        *ShapeReference            `json:"key,omitempty"`
+	Value      *ShapeReference            `json:"value,omitempty"`
+
+	// Resource shape bindings
+	Resources            []OperationTarget `json:"resources,omitempty"`
+	CollectionOperations []OperationTarget `json:"collectionOperations,omitempty"`
+	Create               *ShapeReference   `json:"create,omitempty"`
+	Put                  *ShapeReference   `json:"put,omitempty"`
+	Read                 *ShapeReference   `json:"read,omitempty"`
+	Update               *ShapeReference   `json:"update,omitempty"`
+	Delete               *ShapeReference   `json:"delete,omitempty"`
+	List                 *ShapeReference   `json:"list,omitempty"`
+}
+
+// ResolutionDiscrepancy represents an operation found by only one resolution source
+type ResolutionDiscrepancy struct {
+	Operation string `json:"operation"`
+	FoundIn   string `json:"found_in"`
+	Message   string `json:"message"`
+}
+
+// ShapeReference represents a reference from one shape to another
+type ShapeReference struct {
+	Target string                     `json:"target"`
+	Traits map[string]json.RawMessage `json:"traits,omitempty"`
+}
+
+// PermissionDiff represents the IAM actions added and removed between two controller versions
+type PermissionDiff struct {
+	ServiceName string   `json:"service_name"`
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+	Added       []string `json:"added"`
+	Removed     []string `json:"removed"`
+}
+
+// ManagedPolicyComparison reports what an AWS managed policy grants beyond the actions a controller needs
+type ManagedPolicyComparison struct {
+	ServiceName         string   `json:"service_name"`
+	ManagedPolicy       string   `json:"managed_policy"`
+	VersionID           string   `json:"version_id,omitempty"`
+	RequiredActions     int      `json:"required_actions"`
+	GrantedActions      int      `json:"granted_actions"`
+	ExcessShare         float64  `json:"excess_share"`
+	ExcessActions       []string `json:"excess_actions"`
+	OtherServiceActions []string `json:"other_service_actions,omitempty"`
+	MissingActions      []string `json:"missing_actions,omitempty"`
+	Notes               []string `json:"notes,omitempty"`
+}
+
+// ServiceExamples holds example request payloads for the operations of a service
+type ServiceExamples struct {
+	SchemaVersion int                    `json:"schema_version"`
+	ServiceName   string                 `json:"service_name"`
+	Examples      map[string]interface{} `json:"examples"`
+}
+
+// DataPlaneOperations holds the data plane operations of a service, the basis of the IAM
+// policies of applications using ACK-provisioned resources
+type DataPlaneOperations struct {
+	SchemaVersion       int         `json:"schema_version"`
+	ServiceName         string      `json:"service_name"`
+	DataPlaneOperations int         `json:"data_plane_operations"`
+	Operations          []Operation `json:"operations"`
+}
+
+// ModelDiagnostic represents an anomaly found while linting a service model
+type ModelDiagnostic struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Shape    string   `json:"shape,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// ServiceMetadata holds service-level identifiers and endpoint properties read from model traits
+type ServiceMetadata struct {
+	SDKID              string `json:"sdk_id,omitempty"`
+	APIVersion         string `json:"api_version,omitempty"`
+	ARNNamespace       string `json:"arn_namespace,omitempty"`
+	SigningName        string `json:"signing_name,omitempty"`
+	EndpointPrefix     string `json:"endpoint_prefix,omitempty"`
+	CloudFormationName string `json:"cloudformation_name,omitempty"`
+	Global             bool   `json:"global"`
+}
+
+// OperationRelease holds the API version and release stage of an operation
+type OperationRelease struct {
+	APIVersion         string
+	Stage              ReleaseStage
+	DeprecatedSince    string
+	DeprecationMessage string
+}
+
+// ParsedModel is a service model parsed into the structures the ACK code-generator consumes,
+// built without writing any files
+type ParsedModel struct {
+	// ServiceID is the absolute shape ID of the service shape, e.g. com.amazonaws.s3#AmazonS3
+	ServiceID     string
+	Metadata      *ServiceMetadata
+	ServiceTraits map[string]json.RawMessage
+	// Operations holds the resolved operations of the service, sorted by name
+	Operations []ModelOperation
+	// Shapes holds every shape of the model keyed by absolute shape ID
+	Shapes map[string]ServiceShape
+}
+
+// ModelOperation is an operation of a parsed service model. Shape references are absolute shape IDs.
+type ModelOperation struct {
+	Name          string
+	ShapeID       string
+	Input         string
+	Output        string
+	Errors        []string
+	Documentation string
+	// HTTP is the smithy.api#http binding, nil for operations of RPC protocols without one
+	HTTP    *HTTPBinding
+	Release OperationRelease
+	Waiters []Waiter
+	Traits  map[string]json.RawMessage
+}
+
+// HTTPBinding is the HTTP method, URI pattern and success code an operation is bound to
+type HTTPBinding struct {
+	Method string
+	URI    string
+	Code   int
+}
+
+// CloudFormationResource represents a CloudFormation resource type and the operations bound to it
+type CloudFormationResource struct {
+	Type         string   `json:"type"`
+	Operations   []string `json:"operations"`
+	ACKSupported bool     `json:"ack_supported"`
 }
 
 // OperationTarget represents an operation reference in the service
@@ -36,16 +389,25 @@ type OperationTarget struct {
 
 // ClassificationResult represents the result of operation classification
 type ClassificationResult struct {
-	ControlPlane []string `json:"control_plane"`
-	DataPlane    []string `json:"data_plane"`
+	ControlPlane []string                   `json:"control_plane"`
+	DataPlane    []string                   `json:"data_plane"`
+	Corrections  []ClassificationCorrection `json:"corrections,omitempty"`
+}
+
+// ClassificationCorrection records a classification rule overriding the type Bedrock classified an operation as
+type ClassificationCorrection struct {
+	Operation string        `json:"operation"`
+	Rule      string        `json:"rule"`
+	Original  OperationType `json:"original"`
+	Corrected OperationType `json:"corrected"`
 }
 
 // InlineAgentConfig represents the configuration for an inline agent
 type InlineAgentConfig struct {
-	FoundationModel string                `json:"foundation_model"`
-	Instruction     string                `json:"instruction"`
-	AgentName       string                `json:"agent_name"`
-	ActionGroups    []InlineActionGroup   `json:"action_groups"`
+	FoundationModel string              `json:"foundation_model"`
+	Instruction     string              `json:"instruction"`
+	AgentName       string              `json:"agent_name"`
+	ActionGroups    []InlineActionGroup `json:"action_groups"`
 }
 
 // InlineActionGroup represents an action group for inline agent
@@ -63,12 +425,210 @@ type AgentResponse struct {
 
 // GeneratorConfig represents the structure of generator.yaml files
 type GeneratorConfig struct {
-	SDKNames SDKNames `yaml:"sdk_names"`
+	Ignore      IgnoreSpec                 `yaml:"ignore"`
+	SDKNames    SDKNames                   `yaml:"sdk_names"`
+	Operations  map[string]OperationConfig `yaml:"operations"`
+	Resources   map[string]ResourceConfig  `yaml:"resources"`
+	EmptyShapes []string                   `yaml:"empty_shapes"`
+
+	// SchemaWarnings lists keys present in the file that are not part of the known schema
+	SchemaWarnings []string `yaml:"-"`
+}
+
+// IgnoreSpec represents the ignore section of generator.yaml
+type IgnoreSpec struct {
+	ResourceNames []string `yaml:"resource_names"`
+	FieldPaths    []string `yaml:"field_paths"`
+	Operations    []string `yaml:"operations"`
+	ShapeNames    []string `yaml:"shape_names"`
 }
 
 // SDKNames represents the SDK names configuration
 type SDKNames struct {
-	ModelName string `yaml:"model_name"`
+	ModelName       string `yaml:"model_name"`
+	PackageName     string `yaml:"package_name"`
+	ClientInterface string `yaml:"client_interface"`
+	ClientStruct    string `yaml:"client_struct"`
+}
+
+// OperationConfig represents per-operation overrides in generator.yaml
+type OperationConfig struct {
+	OperationType             StringList        `yaml:"operation_type"`
+	ResourceName              StringList        `yaml:"resource_name"`
+	OutputWrapperFieldPath    string            `yaml:"output_wrapper_field_path"`
+	SetOutputCustomMethodName string            `yaml:"set_output_custom_method_name"`
+	CustomImplementation      string            `yaml:"custom_implementation"`
+	OverrideValues            map[string]string `yaml:"override_values"`
+}
+
+// ResourceConfig represents the configuration of a single resource in generator.yaml
+type ResourceConfig struct {
+	Renames          *RenamesConfig          `yaml:"renames"`
+	Hooks            map[string]HookConfig   `yaml:"hooks"`
+	Tags             *TagConfig              `yaml:"tags"`
+	Fields           map[string]FieldConfig  `yaml:"fields"`
+	Exceptions       *ExceptionsConfig       `yaml:"exceptions"`
+	Reconcile        *ReconcileConfig        `yaml:"reconcile"`
+	UpdateOperation  *CustomOperationConfig  `yaml:"update_operation"`
+	FindOperation    *CustomOperationConfig  `yaml:"find_operation"`
+	ListOperation    *ListOperationConfig    `yaml:"list_operation"`
+	Synced           *SyncedConfig           `yaml:"synced"`
+	Print            *PrintConfig            `yaml:"print"`
+	IsAdoptable      *bool                   `yaml:"is_adoptable"`
+	IsARNPrimaryKey  bool                    `yaml:"is_arn_primary_key"`
+	ShortNames       []string                `yaml:"short_names"`
+	UnpackAttributes *UnpackAttributesConfig `yaml:"unpack_attributes_map"`
+}
+
+// RenamesConfig represents field renames applied to a resource's operations
+type RenamesConfig struct {
+	Operations map[string]OperationRenames `yaml:"operations"`
+}
+
+// OperationRenames maps original shape member names to renamed field names
+type OperationRenames struct {
+	InputFields  map[string]string `yaml:"input_fields"`
+	OutputFields map[string]string `yaml:"output_fields"`
+}
+
+// HookConfig represents a custom code hook, given either inline or as a template path
+type HookConfig struct {
+	Code         *string `yaml:"code"`
+	TemplatePath *string `yaml:"template_path"`
+}
+
+// TagConfig represents how a resource exposes its tags
+type TagConfig struct {
+	Ignore    bool    `yaml:"ignore"`
+	Path      *string `yaml:"path"`
+	KeyName   *string `yaml:"key_name"`
+	ValueName *string `yaml:"value_name"`
+}
+
+// FieldConfig represents per-field overrides for a resource
+type FieldConfig struct {
+	IsPrimaryKey     bool                  `yaml:"is_primary_key"`
+	IsOwnerAccountID bool                  `yaml:"is_owner_account_id"`
+	IsRequired       *bool                 `yaml:"is_required"`
+	IsReadOnly       bool                  `yaml:"is_read_only"`
+	IsImmutable      bool                  `yaml:"is_immutable"`
+	IsSecret         bool                  `yaml:"is_secret"`
+	IsName           bool                  `yaml:"is_name"`
+	Type             *string               `yaml:"type"`
+	GoTag            *string               `yaml:"go_tag"`
+	From             *SourceFieldConfig    `yaml:"from"`
+	LateInitialize   *LateInitializeConfig `yaml:"late_initialize"`
+	References       *ReferencesConfig     `yaml:"references"`
+	Compare          *CompareFieldConfig   `yaml:"compare"`
+	Print            *PrintFieldConfig     `yaml:"print"`
+	CustomField      *CustomFieldConfig    `yaml:"custom_field"`
+	Set              []SetFieldConfig      `yaml:"set"`
+	Documentation    *DocumentationConfig  `yaml:"documentation"`
+}
+
+// SourceFieldConfig points a field at a member of another operation's shape
+type SourceFieldConfig struct {
+	Operation string `yaml:"operation"`
+	Path      string `yaml:"path"`
+}
+
+// LateInitializeConfig represents late-initialization settings for a field
+type LateInitializeConfig struct {
+	MinBackoffSeconds int `yaml:"min_backoff_seconds"`
+	MaxBackoffSeconds int `yaml:"max_backoff_seconds"`
+}
+
+// ReferencesConfig represents a reference from a field to another ACK resource
+type ReferencesConfig struct {
+	ServiceName string `yaml:"service_name"`
+	Resource    string `yaml:"resource"`
+	Path        string `yaml:"path"`
+}
+
+// CompareFieldConfig represents delta comparison settings for a field
+type CompareFieldConfig struct {
+	IsIgnored     bool    `yaml:"is_ignored"`
+	NilEqualsZero bool    `yaml:"nil_equals_zero_value"`
+	Comparator    *string `yaml:"comparator"`
+}
+
+// PrintFieldConfig represents a printer column for a field
+type PrintFieldConfig struct {
+	Name     string `yaml:"name"`
+	Priority int    `yaml:"priority"`
+	Index    int    `yaml:"index"`
+}
+
+// CustomFieldConfig represents a field that does not exist in the API model
+type CustomFieldConfig struct {
+	ListOf string `yaml:"list_of"`
+	MapOf  string `yaml:"map_of"`
+}
+
+// SetFieldConfig represents how a field is set from an operation's output
+type SetFieldConfig struct {
+	Method    *string `yaml:"method"`
+	From      *string `yaml:"from"`
+	To        *string `yaml:"to"`
+	Ignore    bool    `yaml:"ignore"`
+	IgnoreNil bool    `yaml:"ignore_nil"`
+}
+
+// DocumentationConfig represents documentation overrides for a field
+type DocumentationConfig struct {
+	Append  string `yaml:"append"`
+	Prepend string `yaml:"prepend"`
+}
+
+// ExceptionsConfig represents how API errors map to controller behavior
+type ExceptionsConfig struct {
+	Errors        map[int]ErrorConfig `yaml:"errors"`
+	TerminalCodes []string            `yaml:"terminal_codes"`
+}
+
+// ErrorConfig represents the error code returned for an HTTP status code
+type ErrorConfig struct {
+	Code string `yaml:"code"`
+}
+
+// ReconcileConfig represents reconciliation settings for a resource
+type ReconcileConfig struct {
+	RequeueOnSuccessSeconds int `yaml:"requeue_on_success_seconds"`
+}
+
+// CustomOperationConfig represents an operation replaced by a hand-written method
+type CustomOperationConfig struct {
+	CustomMethodName       string `yaml:"custom_method_name"`
+	OutputWrapperFieldPath string `yaml:"output_wrapper_field_path"`
+}
+
+// ListOperationConfig represents how the list operation matches a single resource
+type ListOperationConfig struct {
+	MatchFields []string `yaml:"match_fields"`
+}
+
+// SyncedConfig represents the conditions under which a resource is considered synced
+type SyncedConfig struct {
+	When []SyncedCondition `yaml:"when"`
+}
+
+// SyncedCondition represents a single synced condition on a field path
+type SyncedCondition struct {
+	Path *string  `yaml:"path"`
+	In   []string `yaml:"in"`
+}
+
+// PrintConfig represents printer column settings for a resource
+type PrintConfig struct {
+	AddAgeColumn    bool   `yaml:"add_age_column"`
+	AddSyncedColumn *bool  `yaml:"add_synced_column"`
+	OrderBy         string `yaml:"order_by"`
+}
+
+// UnpackAttributesConfig represents resources whose fields are stored in an attributes map
+type UnpackAttributesConfig struct {
+	SetAttributesSingleAttribute bool                   `yaml:"set_attributes_single_attribute"`
+	GetAttributesInput           map[string]interface{} `yaml:"get_attributes_input"`
 }
 
 // IAMPolicy represents an AWS IAM policy document
@@ -77,10 +637,415 @@ type IAMPolicy struct {
 	Statement []PolicyStatement `json:"Statement"`
 }
 
+// AttachmentPlan lists the managed policies to attach to the controller role of each service
+type AttachmentPlan struct {
+	SchemaVersion             int              `json:"schema_version"`
+	MaxManagedPoliciesPerRole int              `json:"max_managed_policies_per_role"`
+	Roles                     []RoleAttachment `json:"roles"`
+}
+
+// RoleAttachment lists the policies written for a service's controller role in one partition
+type RoleAttachment struct {
+	Service   string          `json:"service"`
+	Partition string          `json:"partition"`
+	Policies  []PlannedPolicy `json:"policies"`
+}
+
+// PlannedPolicy represents a single managed policy file to create and attach
+type PlannedPolicy struct {
+	Name    string `json:"name"`
+	File    string `json:"file"`
+	Actions int    `json:"actions"`
+	Size    int    `json:"size"`
+}
+
 // PolicyStatement represents a single IAM policy statement
 type PolicyStatement struct {
+	Sid       string      `json:"Sid,omitempty"`
 	Effect    string      `json:"Effect"`
-	Action    []string    `json:"Action"`
-	Resource  interface{} `json:"Resource"`
+	Principal interface{} `json:"Principal,omitempty"`
+	Action    []string    `json:"Action,omitempty"`
+	NotAction []string    `json:"NotAction,omitempty"`
+	Resource  interface{} `json:"Resource,omitempty"`
 	Condition interface{} `json:"Condition,omitempty"`
 }
+
+// TrustPolicyConfig holds the cluster/account inputs used to build assume-role trust policies
+type TrustPolicyConfig struct {
+	AccountID      string
+	OIDCProvider   string
+	ClusterName    string
+	Region         string
+	Namespace      string
+	ServiceAccount string
+}
+
+// OrgStats aggregates the operations of every extracted service into org-wide numbers
+type OrgStats struct {
+	Services                 int               `json:"services"`
+	TotalOperations          int               `json:"total_operations"`
+	ControlPlaneOperations   int               `json:"control_plane_operations"`
+	SupportedOperations      int               `json:"supported_operations"`
+	SupportedControlPlaneOps int               `json:"supported_control_plane_operations"`
+	Coverage                 float64           `json:"coverage"`
+	ControlPlaneCoverage     float64           `json:"control_plane_coverage"`
+	LeastCovered             []ServiceCoverage `json:"least_covered"`
+	Trend                    *OrgTrend         `json:"trend,omitempty"`
+}
+
+// ServiceCoverage is the coverage of a single service in the org statistics
+type ServiceCoverage struct {
+	ServiceName              string  `json:"service_name"`
+	Operations               int     `json:"operations"`
+	ControlPlaneOperations   int     `json:"control_plane_operations"`
+	SupportedControlPlaneOps int     `json:"supported_control_plane_operations"`
+	Coverage                 float64 `json:"coverage"`
+}
+
+// OrgTrend is the change of the org statistics since a previous snapshot; counts and coverage
+// percentage points are differences
+type OrgTrend struct {
+	PreviousServices           int      `json:"previous_services"`
+	Operations                 int      `json:"operations"`
+	ControlPlaneOperations     int      `json:"control_plane_operations"`
+	SupportedOperations        int      `json:"supported_operations"`
+	SupportedControlPlaneOps   int      `json:"supported_control_plane_operations"`
+	CoveragePoints             float64  `json:"coverage_points"`
+	ControlPlaneCoveragePoints float64  `json:"control_plane_coverage_points"`
+	ServicesAdded              []string `json:"services_added"`
+	ServicesRemoved            []string `json:"services_removed"`
+}
+
+// NamingAudit reports how well a service's operation names fit the ACK code generator conventions
+type NamingAudit struct {
+	ServiceName         string              `json:"service_name"`
+	Operations          []OperationNaming   `json:"operations"`
+	Resources           []ResourceLifecycle `json:"resources"`
+	NonStandard         int                 `json:"non_standard_operations"`
+	IncompleteResources int                 `json:"incomplete_resources"`
+	// CustomizationEstimate counts the generator.yaml entries likely needed: unconfigured
+	// nonstandard operations plus missing lifecycle operations of resources
+	CustomizationEstimate int `json:"customization_estimate"`
+}
+
+// OperationNaming is the ACK operation type the code generator infers from an operation name
+type OperationNaming struct {
+	Operation     string `json:"operation"`
+	Verb          string `json:"verb"`
+	Resource      string `json:"resource"`
+	OperationType string `json:"operation_type,omitempty"`
+	Standard      bool   `json:"standard"`
+	Configured    bool   `json:"configured,omitempty"`
+	Ignored       bool   `json:"ignored,omitempty"`
+}
+
+// ResourceLifecycle lists the lifecycle operations found for a resource that has a Create operation
+type ResourceLifecycle struct {
+	Resource   string            `json:"resource"`
+	Operations map[string]string `json:"operations"`
+	Missing    []string          `json:"missing,omitempty"`
+}
+
+// ResponseDrift lists response fields of a resource's read operation missing from its CRD
+type ResponseDrift struct {
+	Resource      string   `json:"resource"`
+	Operation     string   `json:"operation"`
+	MissingFields []string `json:"missing_fields"`
+}
+
+// PolicyPatch describes the actions missing from a controller's recommended policy and how to add them
+type PolicyPatch struct {
+	File       string               `json:"file"`
+	Missing    []string             `json:"missing_actions"`
+	Operations []JSONPatchOperation `json:"patch"`
+	// Document is the policy file with the missing actions inserted, preserving its formatting
+	Document []byte `json:"-"`
+}
+
+// JSONPatchOperation is a single RFC 6902 JSON Patch operation
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Statuses of a service in the run metrics
+const (
+	ServiceStatusExtracted = "extracted"
+	ServiceStatusReused    = "reused"
+	ServiceStatusResumed   = "resumed"
+	ServiceStatusFailed    = "failed"
+)
+
+// Outcomes of a service in the status report
+const (
+	ServiceSucceeded = "succeeded"
+	ServiceFailed    = "failed"
+)
+
+// StatusReport is the machine-readable outcome of a run
+type StatusReport struct {
+	SchemaVersion   int                   `json:"schema_version"`
+	StartedAt       time.Time             `json:"started_at"`
+	FinishedAt      time.Time             `json:"finished_at"`
+	DurationSeconds float64               `json:"duration_seconds"`
+	Success         bool                  `json:"success"`
+	Summary         StatusSummary         `json:"summary"`
+	Services        []ServiceStatusReport `json:"services"`
+	Warnings        []string              `json:"warnings"`
+	pendingWarnings map[string][]string
+}
+
+// StatusSummary holds the totals of a status report
+type StatusSummary struct {
+	Requested  int `json:"requested"`
+	Succeeded  int `json:"succeeded"`
+	Failed     int `json:"failed"`
+	Warnings   int `json:"warnings"`
+	Operations int `json:"operations"`
+}
+
+// ServiceStatusReport is the outcome of a single service
+type ServiceStatusReport struct {
+	Service         string          `json:"service"`
+	Status          string          `json:"status"`
+	Source          string          `json:"source"`
+	ErrorCategory   ErrorCategory   `json:"error_category,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	Warnings        []string        `json:"warnings"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	Operations      int             `json:"operations"`
+	Timings         *ServiceTimings `json:"timings,omitempty"`
+}
+
+// ServiceTimings holds the time spent in each extraction phase of a service
+type ServiceTimings struct {
+	ModelParseSeconds     float64 `json:"model_parse_seconds"`
+	ControllerScanSeconds float64 `json:"controller_scan_seconds"`
+	ClassificationSeconds float64 `json:"classification_seconds"`
+}
+
+// RunMetrics summarizes a run for pipeline observability
+type RunMetrics struct {
+	SchemaVersion   int              `json:"schema_version"`
+	StartedAt       time.Time        `json:"started_at"`
+	FinishedAt      time.Time        `json:"finished_at"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	Counts          RunCounts        `json:"counts"`
+	Services        []ServiceMetrics `json:"services"`
+	Bedrock         BedrockMetrics   `json:"bedrock"`
+	Cache           CacheMetrics     `json:"cache"`
+	Errors          []string         `json:"errors"`
+}
+
+// RunCounts holds the service and operation totals of a run
+type RunCounts struct {
+	Requested  int `json:"requested_services"`
+	Succeeded  int `json:"succeeded_services"`
+	Failed     int `json:"failed_services"`
+	Operations int `json:"operations"`
+}
+
+// ServiceMetrics holds the timings and counts of a single service
+type ServiceMetrics struct {
+	Name                   string  `json:"name"`
+	Status                 string  `json:"status"`
+	Seconds                float64 `json:"seconds"`
+	ModelParseSeconds      float64 `json:"model_parse_seconds"`
+	ControllerScanSeconds  float64 `json:"controller_scan_seconds"`
+	ClassificationSeconds  float64 `json:"classification_seconds"`
+	Operations             int     `json:"operations"`
+	SupportedOperations    int     `json:"supported_operations"`
+	ControlPlaneOperations int     `json:"control_plane_operations"`
+	Error                  string  `json:"error,omitempty"`
+}
+
+// BedrockMetrics counts Bedrock invocations of a run
+type BedrockMetrics struct {
+	Invocations            int     `json:"invocations"`
+	Failures               int     `json:"failures"`
+	Throttled              int     `json:"throttled"`
+	Seconds                float64 `json:"seconds"`
+	ClassificationFailures int     `json:"classification_failures"`
+	// PromptCharacters counts the characters of the prompts sent, FollowUpPrompts the batches
+	// sent the short follow-up prompt in a session that already received the classification rules
+	PromptCharacters int `json:"prompt_characters"`
+	FollowUpPrompts  int `json:"follow_up_prompts"`
+	// Batches counts the batches Bedrock classified, with the median and 95th percentile of the
+	// seconds each took including retries
+	Batches         int     `json:"batches"`
+	BatchSecondsP50 float64 `json:"batch_seconds_p50"`
+	BatchSecondsP95 float64 `json:"batch_seconds_p95"`
+	// ResponseCharacters counts the characters of the responses, which with the prompt characters
+	// give the spend estimated at the classification model's on-demand token prices
+	ResponseCharacters int `json:"response_characters"`
+	// InputTokens and OutputTokens are the tokens Bedrock reported for the classified batches;
+	// the spend is estimated from them when Bedrock reported any
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// CacheMetrics counts work avoided through caches during a run
+type CacheMetrics struct {
+	ClassificationHits  int `json:"classification_hits"`
+	CheckpointBatchHits int `json:"checkpoint_batch_hits"`
+	UnchangedServices   int `json:"unchanged_services"`
+	ResumedServices     int `json:"resumed_services"`
+}
+
+// PolicySimulation holds the IAM policy simulator decisions for the actions a policy should allow
+type PolicySimulation struct {
+	Allowed int                `json:"allowed"`
+	Denied  int                `json:"denied"`
+	Results []ActionSimulation `json:"results"`
+	// Mismatches are the actions the policy generator grants that differ from the actions of the
+	// service's models, e.g. because of a wrong service prefix
+	Mismatches []string `json:"mismatches,omitempty"`
+}
+
+// ActionSimulation is the simulated decision for a single action
+type ActionSimulation struct {
+	Action            string   `json:"action"`
+	Resource          string   `json:"resource"`
+	Decision          string   `json:"decision"`
+	MatchedStatements []string `json:"matched_statements,omitempty"`
+}
+
+// SelfCheckStatus is the outcome of a single self-check
+type SelfCheckStatus string
+
+const (
+	SelfCheckOK   SelfCheckStatus = "ok"
+	SelfCheckWarn SelfCheckStatus = "warn"
+	SelfCheckFail SelfCheckStatus = "fail"
+)
+
+// SelfCheckResult is the outcome of verifying one directory or tool the extractor depends on
+type SelfCheckResult struct {
+	Name        string          `json:"name"`
+	Status      SelfCheckStatus `json:"status"`
+	Message     string          `json:"message"`
+	Remediation string          `json:"remediation,omitempty"`
+}
+
+// SemanticGroupSummary counts the operations of a service in one semantic group
+type SemanticGroupSummary struct {
+	Group      SemanticGroup `json:"group"`
+	Operations int           `json:"operations"`
+	Supported  int           `json:"supported"`
+}
+
+// BackstageEntity is a Backstage software catalog entity descriptor
+type BackstageEntity struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   BackstageMetadata      `yaml:"metadata"`
+	Spec       map[string]interface{} `yaml:"spec"`
+}
+
+// BackstageMetadata holds the metadata of a Backstage entity
+type BackstageMetadata struct {
+	Name        string            `yaml:"name"`
+	Title       string            `yaml:"title,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	Links       []BackstageLink   `yaml:"links,omitempty"`
+}
+
+// BackstageLink is an external link shown on a Backstage entity page
+type BackstageLink struct {
+	URL   string `yaml:"url"`
+	Title string `yaml:"title,omitempty"`
+}
+
+// BackstageOptions holds the catalog fields the extractor cannot derive from the service
+type BackstageOptions struct {
+	Owner     string
+	Lifecycle string
+	System    string
+}
+
+// CoverageBadge is a shields.io endpoint badge, see https://shields.io/badges/endpoint-badge
+type CoverageBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	CacheSeconds  int    `json:"cacheSeconds,omitempty"`
+}
+
+// RBACPairing pairs the Kubernetes RBAC rules each resource of a controller needs with the IAM
+// actions its reconciliation calls
+type RBACPairing struct {
+	SchemaVersion int    `json:"schema_version"`
+	ServiceName   string `json:"service_name"`
+	APIGroup      string `json:"api_group"`
+	// RBACSource is the controller's ClusterRole file the rules are read from, or "defaults"
+	RBACSource string            `json:"rbac_source"`
+	Resources  []ResourcePairing `json:"resources"`
+	// SharedRBACRules and SharedIAMActions are needed by the controller as a whole, e.g. reading
+	// secrets or tagging calls outside the resource packages
+	SharedRBACRules  []RBACRule `json:"shared_rbac_rules"`
+	SharedIAMActions []string   `json:"shared_iam_actions"`
+}
+
+// ResourcePairing is the RBAC rules and IAM actions of a single ACK resource
+type ResourcePairing struct {
+	Kind string `json:"kind"`
+	// Resource is the plural name of the CRD in RBAC rules, e.g. dbinstances
+	Resource   string     `json:"resource"`
+	RBACRules  []RBACRule `json:"rbac_rules"`
+	IAMActions []string   `json:"iam_actions"`
+	// Operations are the supported operations referenced in the resource's package
+	Operations []string `json:"operations"`
+}
+
+// RBACRule is a rule of a Kubernetes ClusterRole
+type RBACRule struct {
+	APIGroups []string `json:"api_groups" yaml:"apiGroups"`
+	Resources []string `json:"resources" yaml:"resources"`
+	Verbs     []string `json:"verbs" yaml:"verbs"`
+}
+
+// ClassificationEvaluation compares classifiers against a golden dataset of hand-labeled operations
+type ClassificationEvaluation struct {
+	SchemaVersion int `json:"schema_version"`
+	// Golden is the dataset file the operations were read from, or "embedded"
+	Golden      string                 `json:"golden"`
+	Operations  int                    `json:"operations"`
+	Classifiers []ClassifierEvaluation `json:"classifiers"`
+}
+
+// ClassifierEvaluation is how well a single classifier labeled the golden dataset
+type ClassifierEvaluation struct {
+	Classifier string  `json:"classifier"`
+	Accuracy   float64 `json:"accuracy"`
+	// Unclassified counts the operations the classifier returned no type for; they count as mistakes
+	Unclassified int                 `json:"unclassified"`
+	Classes      []ClassMetrics      `json:"classes"`
+	Mistakes     []EvaluationMistake `json:"mistakes"`
+	// Errors are the services the classifier failed on, mapped to the error
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// ClassMetrics is the precision and recall of a classifier for one operation type
+type ClassMetrics struct {
+	Type           OperationType `json:"type"`
+	Precision      float64       `json:"precision"`
+	Recall         float64       `json:"recall"`
+	F1             float64       `json:"f1"`
+	TruePositives  int           `json:"true_positives"`
+	FalsePositives int           `json:"false_positives"`
+	FalseNegatives int           `json:"false_negatives"`
+}
+
+// EvaluationMistake is a golden operation a classifier labeled differently, Actual is empty when it
+// returned no type
+type EvaluationMistake struct {
+	Service   string        `json:"service"`
+	Operation string        `json:"operation"`
+	Expected  OperationType `json:"expected"`
+	Actual    OperationType `json:"actual"`
+}
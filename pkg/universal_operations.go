@@ -0,0 +1,43 @@
+package extractor
+
+// taggingStatementSid names the policy statement granting the tagging actions of a service
+const taggingStatementSid = "Tagging"
+
+// taggingOperations are the tagging APIs services expose with identical semantics. They manage
+// resource metadata, so they are control plane operations without asking Bedrock.
+var taggingOperations = map[string]bool{
+	"TagResource":            true,
+	"UntagResource":          true,
+	"ListTagsForResource":    true,
+	"TagResources":           true,
+	"UntagResources":         true,
+	"ListTagsForResources":   true,
+	"ListTags":               true,
+	"ListTagsOfResource":     true,
+	"AddTags":                true,
+	"RemoveTags":             true,
+	"AddTagsToResource":      true,
+	"RemoveTagsFromResource": true,
+	"CreateTags":             true,
+	"DeleteTags":             true,
+	"DescribeTags":           true,
+}
+
+// IsTaggingOperation reports whether an operation is one of the universal tagging APIs
+func IsTaggingOperation(operationName string) bool {
+	return taggingOperations[operationName]
+}
+
+// applyUniversalClassification classifies the universal tagging operations as control plane.
+// It returns the operations it classified and the ones that still need classification.
+func applyUniversalClassification(operations []Operation) (classified []Operation, remaining []Operation) {
+	for _, op := range operations {
+		if IsTaggingOperation(op.Name) {
+			op.Type = OperationTypeControlPlane
+			classified = append(classified, op)
+		} else {
+			remaining = append(remaining, op)
+		}
+	}
+	return classified, remaining
+}
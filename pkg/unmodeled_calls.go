@@ -0,0 +1,290 @@
+package extractor
+
+import (
+	"bufio"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// crossServiceStatementSid prefixes the Sid of the policy statements granting actions of other services
+const crossServiceStatementSid = "CrossService"
+
+// ownServiceAlias is the import alias ACK controllers use for their own service's SDK package
+const ownServiceAlias = "svcsdk"
+
+var (
+	// sdkAPICallPattern matches calls through the controller's SDK client, e.g. rm.sdkapi.CreateBucket(
+	sdkAPICallPattern = regexp.MustCompile(`\bsdkapi\.([A-Z][A-Za-z0-9]*?)(?:WithContext)?\(`)
+	// recordAPICallPattern matches metrics records of API calls, e.g. RecordAPICall("CREATE", "CreateBucket", err)
+	recordAPICallPattern = regexp.MustCompile(`RecordAPICall\(\s*[^,]+,\s*"([A-Za-z0-9]+)"`)
+	// inputCallPattern matches SDK calls with an input struct, e.g. client.Decrypt(ctx, &kms.DecryptInput{
+	inputCallPattern = regexp.MustCompile(`\.([A-Z][A-Za-z0-9]*?)(?:WithContext)?\(\s*(?:\w+\s*,\s*)?&?(\w+)\.([A-Z][A-Za-z0-9]*)Input\s*\{`)
+	// clientCallPattern matches method calls on a variable or field, e.g. rm.kmsClient.DescribeKey(
+	clientCallPattern = regexp.MustCompile(`\b(\w+)\.([A-Z][A-Za-z0-9]*?)(?:WithContext)?\(`)
+	// clientConstructorPattern matches SDK clients created from a service package, e.g. rm.kmsClient = kms.NewFromConfig(cfg)
+	clientConstructorPattern = regexp.MustCompile(`(\w+)\s*:?=\s*(\w+)\.New(?:FromConfig)?\(`)
+	// clientFieldPattern matches struct fields and variables holding SDK clients, e.g. kmsClient *kms.Client or logs cloudwatchlogsiface.CloudWatchLogsAPI
+	clientFieldPattern = regexp.MustCompile(`(\w+)\s+\*?(\w+)\.(?:Client|[A-Z][A-Za-z0-9]*API)\b`)
+	// sdkImportPattern matches the import path of an AWS SDK service package or its v1 interface package
+	sdkImportPattern = regexp.MustCompile(`^github\.com/aws/aws-sdk-go(?:-v2)?/service/([a-z0-9]+)(?:/[a-z0-9]+iface)?$`)
+)
+
+// sdkPackagePrefixes maps AWS SDK service package names to IAM service prefixes where they differ
+var sdkPackagePrefixes = map[string]string{
+	"cloudwatchlogs":           "logs",
+	"cloudwatchevents":         "events",
+	"eventbridge":              "events",
+	"elasticloadbalancingv2":   "elasticloadbalancing",
+	"sfn":                      "states",
+	"cognitoidentityprovider":  "cognito-idp",
+	"applicationautoscaling":   "application-autoscaling",
+	"configservice":            "config",
+	"databasemigrationservice": "dms",
+	"elasticsearchservice":     "es",
+	"opensearch":               "es",
+	"sesv2":                    "ses",
+	"kinesisanalyticsv2":       "kinesisanalytics",
+}
+
+// sdkPackagePrefix returns the IAM service prefix of an AWS SDK service package
+func sdkPackagePrefix(packageName string) string {
+	if prefix, ok := sdkPackagePrefixes[packageName]; ok {
+		return prefix
+	}
+	return IAMServicePrefix(packageName)
+}
+
+// findUnmodeledCalls is a reverse pass over the controller code looking for API calls to operations
+// the service model does not define: deprecated operations still called through the service's own
+// client and calls to other services such as STS or KMS, which need permissions of their own.
+func findUnmodeledCalls(serviceName string, modelOperations []string) []UnmodeledCall {
+	modeled := make(map[string]bool, len(modelOperations))
+	for _, name := range modelOperations {
+		modeled[name] = true
+	}
+	return findControllerCalls(serviceName, func(operation string, cross bool) bool {
+		return cross || !modeled[operation]
+	})
+}
+
+// findControllerCalls returns the API calls in the controller code that include accepts, given the
+// operation and whether the call goes to another service, sorted by IAM action
+func findControllerCalls(serviceName string, include func(operation string, cross bool) bool) []UnmodeledCall {
+	controllerPath := findControllerForService(serviceName)
+	if controllerPath == "" {
+		return nil
+	}
+
+	ownPackages := map[string]bool{serviceName: true, IAMServicePrefix(serviceName): true}
+	if config, err := LoadControllerGeneratorConfig(serviceName); err == nil && config.SDKNames.ModelName != "" {
+		ownPackages[config.SDKNames.ModelName] = true
+	}
+	// Calls to sub-APIs are matched against their merged operations like calls to the service's own
+	for _, packageName := range subAPIPackages(serviceName) {
+		ownPackages[packageName] = true
+	}
+
+	calls := make(map[string]*UnmodeledCall)
+	record := func(service, operation string, cross bool, location Location) {
+		if !include(operation, cross) {
+			return
+		}
+		key := service + ":" + operation
+		call, ok := calls[key]
+		if !ok {
+			call = &UnmodeledCall{Service: service, Operation: operation, Action: key, CrossService: cross}
+			calls[key] = call
+		}
+		call.Locations = append(call.Locations, location)
+	}
+
+	var files []string
+	controllerFiles, _ := listControllerFiles(controllerPath)
+	for _, path := range controllerFiles {
+		if !strings.HasSuffix(path, "_test.go") {
+			files = append(files, path)
+		}
+	}
+
+	// SDK clients of other services are often created in one file and used in another,
+	// so every file's clients are collected before any calls are matched
+	aliases := make([]map[string]string, len(files))
+	clients := make(map[string]string)
+	for i, path := range files {
+		aliases[i] = sdkImportAliases(path)
+		forEachLine(path, func(line string, lineNum int) {
+			for _, pattern := range []*regexp.Regexp{clientConstructorPattern, clientFieldPattern} {
+				for _, match := range pattern.FindAllStringSubmatch(line, -1) {
+					service, ok := aliases[i][match[2]]
+					if ok && match[2] != ownServiceAlias && !ownPackages[service] && match[1] != "sdkapi" {
+						clients[match[1]] = service
+					}
+				}
+			}
+		})
+	}
+
+	ownPrefix := IAMServicePrefix(serviceName)
+	for i, path := range files {
+		relPath, _ := filepath.Rel(controllerPath, path)
+		relPath = filepath.ToSlash(relPath)
+		forEachLine(path, func(line string, lineNum int) {
+			location := Location{File: relPath, Line: lineNum}
+			for _, match := range sdkAPICallPattern.FindAllStringSubmatch(line, -1) {
+				record(ownPrefix, match[1], false, location)
+			}
+			for _, match := range recordAPICallPattern.FindAllStringSubmatch(line, -1) {
+				record(ownPrefix, match[1], false, location)
+			}
+			matched := make(map[string]bool)
+			for _, match := range inputCallPattern.FindAllStringSubmatch(line, -1) {
+				service, ok := aliases[i][match[2]]
+				if !ok || match[1] != match[3] || strings.Contains(line, "sdkapi.") {
+					continue
+				}
+				matched[match[1]] = true
+				if match[2] == ownServiceAlias || ownPackages[service] {
+					record(ownPrefix, match[1], false, location)
+				} else {
+					record(sdkPackagePrefix(service), match[1], true, location)
+				}
+			}
+			for _, match := range clientCallPattern.FindAllStringSubmatch(line, -1) {
+				if service, ok := clients[match[1]]; ok && !matched[match[2]] {
+					record(sdkPackagePrefix(service), match[2], true, location)
+				}
+			}
+		})
+	}
+
+	result := make([]UnmodeledCall, 0, len(calls))
+	for _, call := range calls {
+		result = append(result, *call)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Action < result[j].Action })
+	return result
+}
+
+// SDKServicePackage returns the service package name of an AWS SDK import path, e.g. s3 for
+// github.com/aws/aws-sdk-go-v2/service/s3 or the v1 github.com/aws/aws-sdk-go/service/s3/s3iface
+func SDKServicePackage(importPath string) (string, bool) {
+	match := sdkImportPattern.FindStringSubmatch(importPath)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// SDKOperationAction returns the IAM action of an operation called through an AWS SDK service
+// package by the controller of a service. Calls through the service's own package use the
+// service's IAM prefix, calls to other services the prefix of their package.
+func SDKOperationAction(serviceName, packageName, operation string) string {
+	if packageName == serviceName {
+		return mapOperationToIAMAction(serviceName, operation)
+	}
+	return sdkPackagePrefix(packageName) + ":" + operation
+}
+
+// forEachLine calls fn with every line of a file and its 1-based line number
+func forEachLine(path string, fn func(line string, lineNum int)) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fn(scanner.Text(), lineNum)
+	}
+}
+
+// sdkImportAliases maps the names a Go file imports AWS SDK service packages under to the service package name
+func sdkImportAliases(path string) map[string]string {
+	aliases := make(map[string]string)
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+	if err != nil {
+		return aliases
+	}
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		match := sdkImportPattern.FindStringSubmatch(importPath)
+		if match == nil {
+			continue
+		}
+		alias := importPath[strings.LastIndex(importPath, "/")+1:]
+		if spec.Name != nil {
+			alias = spec.Name.Name
+		}
+		aliases[alias] = match[1]
+	}
+	return aliases
+}
+
+// CrossServiceActions returns the IAM actions of the calls a controller makes to other services
+func CrossServiceActions(calls []UnmodeledCall) []string {
+	var actions []string
+	for _, call := range calls {
+		if call.CrossService {
+			actions = append(actions, call.Action)
+		}
+	}
+	return actions
+}
+
+// AddCrossServiceStatements grants the actions of a controller's calls to other services in one
+// statement per service. The resources of other services are unknown, so the statements apply to "*".
+func AddCrossServiceStatements(policy *IAMPolicy, calls []UnmodeledCall) {
+	actionsByService := make(map[string][]string)
+	var services []string
+	for _, call := range calls {
+		if !call.CrossService {
+			continue
+		}
+		if _, ok := actionsByService[call.Service]; !ok {
+			services = append(services, call.Service)
+		}
+		actionsByService[call.Service] = append(actionsByService[call.Service], call.Action)
+	}
+	sort.Strings(services)
+
+	for _, service := range services {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Sid:      crossServiceStatementSid + statementSidSuffix(service),
+			Effect:   "Allow",
+			Action:   actionsByService[service],
+			Resource: "*",
+		})
+	}
+}
+
+// statementSidSuffix turns a service prefix into the alphanumeric, capitalized form a Sid allows,
+// e.g. "cognito-idp" -> "CognitoIdp"
+func statementSidSuffix(service string) string {
+	var sid strings.Builder
+	upper := true
+	for _, r := range service {
+		switch {
+		case r >= 'a' && r <= 'z' && upper:
+			sid.WriteRune(r - 'a' + 'A')
+			upper = false
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			sid.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	return sid.String()
+}
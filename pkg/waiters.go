@@ -0,0 +1,67 @@
+package extractor
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// waitableTrait defines the waiters that poll an operation until a resource stabilizes
+const waitableTrait = "smithy.waiters#waitable"
+
+// waiterDefinition represents a single waiter in the smithy.waiters#waitable trait
+type waiterDefinition struct {
+	MinDelay   int  `json:"minDelay"`
+	MaxDelay   int  `json:"maxDelay"`
+	Deprecated bool `json:"deprecated"`
+	Acceptors  []struct {
+		State string `json:"state"`
+	} `json:"acceptors"`
+}
+
+// ExtractWaiters returns the waiters defined on each operation of a model, keyed by operation name
+func ExtractWaiters(model *AWSServiceModel) map[string][]Waiter {
+	waiters := make(map[string][]Waiter)
+	for shapeName, shape := range model.Shapes {
+		raw, ok := shape.Traits[waitableTrait]
+		if shape.Type != "operation" || !ok {
+			continue
+		}
+
+		var definitions map[string]waiterDefinition
+		if err := json.Unmarshal(raw, &definitions); err != nil {
+			continue
+		}
+
+		operationName := extractOperationName(shapeName)
+		for name, definition := range definitions {
+			waiter := Waiter{
+				Name:       name,
+				MinDelay:   definition.MinDelay,
+				MaxDelay:   definition.MaxDelay,
+				Deprecated: definition.Deprecated,
+			}
+			// Smithy defaults delays to 2 and 120 seconds when omitted
+			if waiter.MinDelay == 0 {
+				waiter.MinDelay = 2
+			}
+			if waiter.MaxDelay == 0 {
+				waiter.MaxDelay = 120
+			}
+			for _, acceptor := range definition.Acceptors {
+				waiter.AcceptorStates = append(waiter.AcceptorStates, acceptor.State)
+			}
+			waiters[operationName] = append(waiters[operationName], waiter)
+		}
+		sort.Slice(waiters[operationName], func(i, j int) bool {
+			return waiters[operationName][i].Name < waiters[operationName][j].Name
+		})
+	}
+	return waiters
+}
+
+// ApplyWaiters attaches the waiters defined on each operation
+func ApplyWaiters(operations []Operation, waiters map[string][]Waiter) {
+	for i := range operations {
+		operations[i].Waiters = waiters[operations[i].Name]
+	}
+}
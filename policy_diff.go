@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newPolicyDiffCommand builds the command reporting permission changes between controller versions
+func newPolicyDiffCommand() *cobra.Command {
+	var serviceName, fromRef, toRef, format string
+
+	cmd := &cobra.Command{
+		Use:   "policy-diff --service=<service> --from=<ref> --to=<ref>",
+		Short: "Report IAM actions added or removed between two controller versions",
+		Long: `Extracts the operations supported by a controller at two git refs (tags,
+branches or commits) and reports the IAM actions the newer version adds or
+removes, e.g. for release notes stating which new permissions are required.`,
+		Example: `  ack-api-extractor policy-diff --service=s3 --from=v1.0.0 --to=v1.2.0`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if serviceName == "" || fromRef == "" || toRef == "" {
+				return fmt.Errorf("--service, --from and --to are required")
+			}
+
+			diff, err := extractor.DiffControllerPermissions(serviceName, fromRef, toRef)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(diff, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			case "text":
+				printPermissionDiff(diff)
+			default:
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serviceName, "service", "", "AWS service name")
+	flags.StringVar(&fromRef, "from", "", "Controller git ref of the previous version")
+	flags.StringVar(&toRef, "to", "", "Controller git ref of the new version")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+
+	return cmd
+}
+
+// printPermissionDiff prints a permission diff as release-note friendly text
+func printPermissionDiff(diff *extractor.PermissionDiff) {
+	fmt.Printf("%s-controller %s → %s\n", diff.ServiceName, diff.From, diff.To)
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 {
+		fmt.Println("No IAM permission changes.")
+		return
+	}
+	if len(diff.Added) > 0 {
+		fmt.Printf("This release requires new permissions: %s\n", strings.Join(diff.Added, ", "))
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Printf("This release no longer requires: %s\n", strings.Join(diff.Removed, ", "))
+	}
+}
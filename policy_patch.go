@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newPolicyPatchCommand builds the command updating a controller's recommended inline policy
+func newPolicyPatchCommand() *cobra.Command {
+	var serviceName, format string
+	var write bool
+
+	cmd := &cobra.Command{
+		Use:   "policy-patch --service=<service>",
+		Short: "Add newly required actions to a controller's recommended inline policy",
+		Long: `Reads the controller's config/iam/recommended-inline-policy and adds the IAM
+actions of supported operations that the policy does not grant yet. Actions
+covered by wildcards are not added again. The result is printed as an RFC 6902
+JSON Patch, or as the policy file with the actions appended to the first Allow
+statement while preserving its formatting and ordering, so automated pull
+requests against controller repositories stay small and reviewable.`,
+		Example: `  ack-api-extractor policy-patch --service=s3
+  ack-api-extractor policy-patch --service=s3 --format=document --write`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if serviceName == "" {
+				return fmt.Errorf("--service is required")
+			}
+
+			// Extraction progress would corrupt the patch printed to stdout
+			reportProgressToStderr()
+			patch, err := extractor.PlanRecommendedPolicyPatch(serviceName)
+			if err != nil {
+				return err
+			}
+
+			if write {
+				if len(patch.Missing) == 0 {
+					fmt.Printf("%s is up to date\n", patch.File)
+					return nil
+				}
+				if err := os.WriteFile(patch.File, patch.Document, 0644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", patch.File, err)
+				}
+				fmt.Printf("Added %d action(s) to %s\n", len(patch.Missing), patch.File)
+				return nil
+			}
+
+			switch format {
+			case "json-patch":
+				operations := patch.Operations
+				if operations == nil {
+					operations = []extractor.JSONPatchOperation{}
+				}
+				data, err := json.MarshalIndent(operations, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON patch: %w", err)
+				}
+				fmt.Println(string(data))
+			case "document":
+				fmt.Print(string(patch.Document))
+			default:
+				return fmt.Errorf("unknown format %q, expected json-patch or document", format)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serviceName, "service", "", "AWS service name")
+	flags.StringVar(&format, "format", "json-patch", "Output format: json-patch or document")
+	flags.BoolVar(&write, "write", false, "Rewrite the recommended policy file in place instead of printing")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+
+	return cmd
+}
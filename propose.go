@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newProposeCommand builds the command opening pull requests that update controller policies
+func newProposeCommand() *cobra.Command {
+	var services string
+	opts := extractor.ProposeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "propose --service=<service1>[,service2...]",
+		Short: "Open pull requests adding missing IAM actions to controller policies",
+		Long: `For every service, reads config/iam/recommended-inline-policy from the head of
+the default branch of the aws-controllers-k8s/<service>-controller repository,
+adds the IAM actions of operations the controller source of that commit
+supports but the policy does not grant, and opens a pull request with the
+change. Running it again for an unchanged set of missing actions reports the
+pull request already open.
+
+The token needs permission to push branches and open pull requests; with
+--fork the branch is pushed to <fork>/<service>-controller instead.`,
+		Example: `  GITHUB_TOKEN=... ack-api-extractor propose --service=s3,dynamodb
+  ack-api-extractor propose --service=s3 --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if services == "" {
+				return fmt.Errorf("--service is required")
+			}
+			if opts.Token == "" {
+				opts.Token = os.Getenv("GITHUB_TOKEN")
+			}
+
+			failed := false
+			for _, serviceName := range strings.Split(services, ",") {
+				serviceName = strings.TrimSpace(serviceName)
+				result, err := extractor.ProposePolicyUpdate(serviceName, opts)
+				if err != nil {
+					fmt.Printf("Error proposing policy update for %s: %v\n", serviceName, err)
+					failed = true
+					continue
+				}
+
+				switch {
+				case len(result.Missing) == 0:
+					fmt.Printf("%s: recommended policy is up to date\n", serviceName)
+				case opts.DryRun:
+					fmt.Printf("%s: would add %s on branch %s\n", serviceName, strings.Join(result.Missing, ", "), result.Branch)
+				case result.Existing:
+					fmt.Printf("%s: pull request already open → %s\n", serviceName, result.PullURL)
+				default:
+					fmt.Printf("%s: added %d action(s) → %s\n", serviceName, len(result.Missing), result.PullURL)
+				}
+			}
+
+			if failed {
+				return fmt.Errorf("failed to propose some policy updates")
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&services, "service", "", "AWS service name(s), comma-separated")
+	flags.StringVar(&opts.Token, "github-token", "", "GitHub token used to push branches and open pull requests (default $GITHUB_TOKEN)")
+	flags.StringVar(&opts.Fork, "fork", "", "Owner of forks to push branches to instead of the controller repositories")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "Report the missing actions without pushing a branch or opening a pull request")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+
+	return cmd
+}
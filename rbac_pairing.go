@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newRBACPairingCommand builds the command pairing the RBAC rules and IAM actions of a controller's resources
+func newRBACPairingCommand() *cobra.Command {
+	var serviceName, format, output string
+
+	cmd := &cobra.Command{
+		Use:   "rbac-pairing --service=<service>",
+		Short: "Pair the Kubernetes RBAC rules and IAM actions each resource of a controller needs",
+		Long: `Lists every ACK resource of a controller, one per directory in pkg/resource,
+with the Kubernetes RBAC rules its CRD needs and the IAM actions of the supported
+operations its package references. RBAC rules are read from the controller's
+config/rbac/cluster-role-controller.yaml, or are the rules ACK generates when the
+file is missing. Rules and actions the controller needs outside a single
+resource, such as reading secrets or tagging helpers, are listed as shared.`,
+		Example: `  ack-api-extractor rbac-pairing --service=s3
+  ack-api-extractor rbac-pairing --service=rds --format=json --output=rds-rbac-pairing.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if serviceName == "" {
+				return fmt.Errorf("--service is required")
+			}
+			if output != "" && format != "json" {
+				return fmt.Errorf("--output requires --format=json")
+			}
+			if format != "json" && format != "text" {
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+
+			serviceOps, err := extractor.ExtractDetailedOperationsFromService(serviceName, false)
+			if err != nil {
+				return err
+			}
+			pairing, err := extractor.PairRBACAndIAM(serviceOps)
+			if err != nil {
+				return err
+			}
+
+			if format == "text" {
+				printRBACPairing(pairing)
+				return nil
+			}
+			if output != "" {
+				if err := extractor.WriteRBACPairingJSON(pairing, output); err != nil {
+					return fmt.Errorf("error writing %s: %w", output, err)
+				}
+				fmt.Printf("%s: %d resource(s) → %s\n", serviceName, len(pairing.Resources), output)
+				return nil
+			}
+			data, err := json.MarshalIndent(pairing, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serviceName, "service", "", "Service of the controller")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	flags.StringVar(&output, "output", "", "File to write the JSON pairing to instead of stdout (requires --format=json)")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+
+	return cmd
+}
+
+// printRBACPairing prints the RBAC rules and IAM actions of every resource, then the shared ones
+func printRBACPairing(pairing *extractor.RBACPairing) {
+	for _, resource := range pairing.Resources {
+		fmt.Printf("%s (%s)\n", resource.Kind, resource.Resource)
+		printRBACRules(resource.RBACRules)
+		fmt.Printf("  IAM:  %s\n", joinOrNone(resource.IAMActions))
+	}
+	if len(pairing.SharedRBACRules) > 0 || len(pairing.SharedIAMActions) > 0 {
+		fmt.Println("Shared")
+		printRBACRules(pairing.SharedRBACRules)
+		fmt.Printf("  IAM:  %s\n", joinOrNone(pairing.SharedIAMActions))
+	}
+	fmt.Printf("\n%s: %d resource(s) in %s, RBAC rules from %s\n", pairing.ServiceName, len(pairing.Resources), pairing.APIGroup, pairing.RBACSource)
+}
+
+// printRBACRules prints one line per resource of a set of rules with the API group and verbs
+func printRBACRules(rules []extractor.RBACRule) {
+	if len(rules) == 0 {
+		fmt.Println("  RBAC: none")
+		return
+	}
+	for _, rule := range rules {
+		groups := make([]string, len(rule.APIGroups))
+		for i, group := range rule.APIGroups {
+			groups[i] = group
+			if group == "" {
+				groups[i] = `""`
+			}
+		}
+		fmt.Printf("  RBAC: %s %s: %s\n", strings.Join(groups, ","), strings.Join(rule.Resources, ","), strings.Join(rule.Verbs, ", "))
+	}
+}
+
+// joinOrNone joins values with commas, or returns "none" when there are none
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newResponseDriftCommand builds the command comparing model responses with controller API types
+func newResponseDriftCommand() *cobra.Command {
+	var services, format string
+
+	cmd := &cobra.Command{
+		Use:   "response-drift --service=<service1>[,service2...]",
+		Short: "Report response fields missing from a controller's generated CRD types",
+		Long: `Compares the output shape of each resource's read operation (Describe*,
+Get* or List*) with the resource's Spec and Status types in the controller's
+apis/v1alpha1 package and reports response fields present in the model but
+missing from the CRD. Such fields usually mean the controller's generated code
+is stale and needs to be regenerated. Exits non-zero when drift is found.`,
+		Example: `  ack-api-extractor response-drift --service=dynamodb,rds`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if services == "" {
+				return fmt.Errorf("--service is required")
+			}
+
+			drifted := false
+			var serviceNames []string
+			report := make(map[string][]extractor.ResponseDrift)
+			for _, serviceName := range strings.Split(services, ",") {
+				serviceName = strings.TrimSpace(serviceName)
+				drifts, err := extractor.DetectResponseDrift(serviceName)
+				if err != nil {
+					fmt.Printf("Error checking %s: %v\n", serviceName, err)
+					continue
+				}
+				serviceNames = append(serviceNames, serviceName)
+				report[serviceName] = drifts
+				if len(drifts) > 0 {
+					drifted = true
+				}
+			}
+
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			case "text":
+				for _, serviceName := range serviceNames {
+					drifts := report[serviceName]
+					for _, drift := range drifts {
+						fmt.Printf("%s: %s: %s returns %s, missing from the CRD\n",
+							serviceName, drift.Resource, drift.Operation, strings.Join(drift.MissingFields, ", "))
+					}
+					fmt.Printf("%s: %d resource(s) with response drift\n", serviceName, len(drifts))
+				}
+			default:
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+
+			if drifted {
+				return fmt.Errorf("response drift found")
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&services, "service", "", "AWS service name(s), comma-separated")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+
+	return cmd
+}
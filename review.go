@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newExportReviewCommand builds the command exporting classifications that need human review
+func newExportReviewCommand() *cobra.Command {
+	var input, output, classificationCache, overrides string
+
+	cmd := &cobra.Command{
+		Use:   "export-review --input=<dir> --output=<file.csv>",
+		Short: "Export classifications needing review to a reviewer-friendly CSV",
+		Long: `Reads the operations files written by a classification run and exports the
+classifications that need a human decision: operations whose classification
+failed (unknown), operations sibling services classified differently
+(conflicting, according to --classification-cache) and classifications that
+contradict the operation's verb (low-confidence). The CSV opens in any
+spreadsheet; reviewers fill the decision column with accept or reject and may
+set corrected_type. Fold the decisions back in with import-review.`,
+		Example: `  ack-api-extractor export-review --input=./results --output=review.csv --classification-cache=cache.json`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if input == "" || output == "" {
+				return fmt.Errorf("--input and --output are required")
+			}
+			if classificationCache == "" {
+				if cacheDir, err := extractor.CacheDir(); err == nil {
+					classificationCache = filepath.Join(cacheDir, classificationCacheFile)
+				}
+			}
+			if classificationCache != "" {
+				if err := extractor.LoadClassificationCache(classificationCache); err != nil {
+					return fmt.Errorf("error loading classification cache: %w", err)
+				}
+			}
+			if overrides != "" {
+				if _, err := extractor.LoadClassificationOverrides(overrides); err != nil {
+					return err
+				}
+			}
+
+			services, err := extractor.ReadOperationsDir(input)
+			if err != nil {
+				return err
+			}
+			items := extractor.ReviewCandidates(services)
+			if err := extractor.WriteReviewCSV(items, output); err != nil {
+				return err
+			}
+			fmt.Printf("%d classification(s) to review → %s\n", len(items), output)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&input, "input", "", "Output directory of a previous run containing operations JSON files")
+	flags.StringVar(&output, "output", "", "CSV file to write")
+	flags.StringVar(&classificationCache, "classification-cache", "", "Classification cache used to find operations classified differently across services (default classification-cache.json in the cache directory)")
+	flags.StringVar(&overrides, "classification-overrides", "", "YAML overrides file; operations already decided there are not exported")
+	cmd.MarkFlagDirname("input")
+	cmd.MarkFlagFilename("output", "csv")
+	cmd.MarkFlagFilename("classification-cache", "json")
+	cmd.MarkFlagFilename("classification-overrides", "yaml", "yml")
+
+	return cmd
+}
+
+// newImportReviewCommand builds the command folding reviewer decisions into classification overrides
+func newImportReviewCommand() *cobra.Command {
+	var review, overrides string
+
+	cmd := &cobra.Command{
+		Use:   "import-review --review=<file.csv> --classification-overrides=<file.yaml>",
+		Short: "Fold reviewer decisions back in as classification overrides",
+		Long: `Reads a review CSV exported by export-review and merges its decisions into a
+classification overrides file. Accepted rows keep their type; rejected rows
+use corrected_type, or the opposite plane when it is empty. Pass the overrides
+file to later runs with --classification-overrides so the decisions take
+precedence over Bedrock classification.`,
+		Example: `  ack-api-extractor import-review --review=review.csv --classification-overrides=overrides.yaml`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if review == "" || overrides == "" {
+				return fmt.Errorf("--review and --classification-overrides are required")
+			}
+
+			items, err := extractor.ReadReviewCSV(review)
+			if err != nil {
+				return err
+			}
+			existing, err := extractor.LoadClassificationOverrides(overrides)
+			if err != nil {
+				return err
+			}
+
+			merged, skipped := extractor.MergeReviewDecisions(existing, items)
+			for _, message := range skipped {
+				fmt.Printf("Warning: %s\n", message)
+			}
+			if err := extractor.SaveClassificationOverrides(existing, overrides); err != nil {
+				return err
+			}
+			fmt.Printf("%d decision(s) merged into %s\n", merged, overrides)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&review, "review", "", "Review CSV with accept/reject decisions")
+	flags.StringVar(&overrides, "classification-overrides", "", "YAML overrides file to create or update")
+	cmd.MarkFlagFilename("review", "csv")
+	cmd.MarkFlagFilename("classification-overrides", "yaml", "yml")
+
+	return cmd
+}
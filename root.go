@@ -0,0 +1,1506 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// envPrefix is prepended to upper-cased flag names to form their environment variable,
+// e.g. --generate-policies can be set with ACK_EXTRACTOR_GENERATE_POLICIES=true
+const envPrefix = "ACK_EXTRACTOR_"
+
+// combinedOperationsFile is the file name used by --single-file
+const combinedOperationsFile = "operations.json"
+
+// serviceControlPolicyFile is the file name used by --generate-scp
+const serviceControlPolicyFile = "ack-scp.json"
+
+// combinedPolicyFile is the base name of the policy files written by --generate-combined-policy
+const combinedPolicyFile = "ack-combined-policy"
+
+// runMetricsFile is the file in the output directory summarizing timings, counts and errors of a run
+const runMetricsFile = "run-metrics.json"
+
+// attachmentPlanFile lists the generated policies to attach to each controller role
+const attachmentPlanFile = "attachment-plan.json"
+
+// statusFile is the machine-readable outcome of a run, written to the output directory
+const statusFile = "status.json"
+
+// stateFile is the global file in the cache directory recording the input hashes of extracted
+// services for every output directory
+const stateFile = "state.json"
+
+// legacyStateFile is the per-output-directory state file written by earlier versions
+const legacyStateFile = ".ack-api-extractor-state.json"
+
+// classificationCacheFile is the default classification cache in the cache directory
+const classificationCacheFile = "classification-cache.json"
+
+// checkpointFile is the file in the output directory recording the progress of a run
+const checkpointFile = ".ack-api-extractor-checkpoint.jsonl"
+
+// Directory, Bedrock and GitHub flags shared by every command
+var (
+	modelsDirFlag        string
+	controllersDirFlag   string
+	modelPathFlag        string
+	controllerPathFlag   string
+	cacheDirFlag         string
+	modelSourcesFlag     []string
+	guardrailIDFlag      string
+	guardrailVersionFlag string
+	githubMaxWaitFlag    time.Duration
+	configFileFlag       string
+)
+
+// extractOptions holds the flags of the root extraction command
+type extractOptions struct {
+	// flags are the parsed flags of the command, from which the settings invalidating the reuse of
+	// previous output are derived
+	flags                   *pflag.FlagSet
+	services                string
+	serviceFile             string
+	output                  string
+	outputLocation          string
+	previousOutput          string
+	inPlace                 bool
+	failedServices          int
+	commitPartial           bool
+	classify                bool
+	generatePolicies        bool
+	strict                  bool
+	partitions              []string
+	promptTemplate          string
+	agentInstruction        string
+	classificationCache     string
+	classificationOverrides string
+	classificationRules     string
+	classifySample          string
+	acceptDrift             bool
+	onClassificationFailure string
+	roadmap                 string
+	exclusions              string
+	resourceLevelSupport    string
+	accessLevels            string
+	groupByAccessLevel      bool
+	arnTypes                string
+	groupByARNType          bool
+	matchers                string
+	scanLimits              extractor.ScanLimits
+	linkIssues              bool
+	resolveOwners           bool
+	generateExamples        bool
+	generateOpenAPI         bool
+	generateBackstage       bool
+	generateBadges          bool
+	badgeLabel              string
+	backstage               extractor.BackstageOptions
+	exportDataPlane         bool
+	generateAppPolicies     bool
+	singleFile              bool
+	only                    []string
+	noController            bool
+	subAPIs                 []string
+	detectSubAPIs           bool
+	resume                  bool
+	force                   bool
+	generateTrustPolicies   bool
+	generateSCP             bool
+	skipPreflight           bool
+	generateCombinedPolicy  bool
+	scpPrincipalARNs        []string
+	controllerReleases      []string
+	trust                   extractor.TrustPolicyConfig
+}
+
+// newRootCommand builds the ack-api-extractor command tree
+func newRootCommand() *cobra.Command {
+	opts := &extractOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "ack-api-extractor",
+		Short: "Extract and analyze AWS API operations for ACK controllers",
+		Long: `Extracts the operations of AWS service API models, checks which ones are
+implemented by the matching ACK controller and writes <service>-operations.json
+files. Optionally classifies operations with AWS Bedrock and generates IAM
+permission and trust policies.
+
+Every flag can also be set through an environment variable named
+ACK_EXTRACTOR_<FLAG>, e.g. ACK_EXTRACTOR_SERVICE=dynamodb, or in config.yaml
+under its name with underscores, e.g. generate_policies: true. Flags given on
+the command line take precedence over environment variables, which take
+precedence over the config file.`,
+		Example: `  ack-api-extractor --service=dynamodb --output=./results --classify --generate-policies
+  ack-api-extractor completion bash > /etc/bash_completion.d/ack-api-extractor`,
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			extractor.SetProgressReporter(extractor.NewTextProgressReporter(os.Stdout))
+			if err := bindEnvironment(cmd); err != nil {
+				return err
+			}
+			if err := bindConfig(cmd); err != nil {
+				return err
+			}
+			if err := configureDirectories(); err != nil {
+				return err
+			}
+			if err := extractor.SetGitHubRateLimitWait(githubMaxWaitFlag); err != nil {
+				return fmt.Errorf("error parsing --github-max-wait: %w", err)
+			}
+			return configureGuardrail()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (opts.services == "" && opts.serviceFile == "" && len(opts.controllerReleases) == 0) || opts.output == "" {
+				return fmt.Errorf("--service, --service-file or --controller-release, and --output are required")
+			}
+			opts.flags = cmd.Flags()
+			return runExtractToSink(opts)
+		},
+	}
+
+	persistentFlags := cmd.PersistentFlags()
+	persistentFlags.StringVar(&configFileFlag, "config", "", "Config file setting defaults for any flag (default config.yaml in $XDG_CONFIG_HOME/ack-api-extractor)")
+	persistentFlags.StringVar(&modelsDirFlag, "models-dir", "", "Directory containing the AWS service model directories (default ../api-models-aws/models)")
+	persistentFlags.StringVar(&controllersDirFlag, "controllers-dir", "", "Directory containing the <service>-controller directories (default ..)")
+	persistentFlags.StringVar(&modelPathFlag, "model-path-template", "", "Where models are found in --models-dir and local model sources, with {root} and {service} placeholders and glob wildcards (default "+extractor.DefaultModelPathTemplate+")")
+	persistentFlags.StringVar(&controllerPathFlag, "controller-path-template", "", "Where controllers are found, with {root} for --controllers-dir, {service} and glob wildcards (default "+extractor.DefaultControllerPathTemplate+")")
+	persistentFlags.StringVar(&cacheDirFlag, "cache-dir", "", "Directory for the classification cache and run state (default $XDG_CACHE_HOME/ack-api-extractor)")
+	persistentFlags.StringSliceVar(&modelSourcesFlag, "model-source", nil, "Additional model sources consulted after --models-dir in precedence order: directories laid out like api-models-aws/models, http(s) URLs serving <service>.json, or go-sdk for the aws-sdk-go-v2 packages controllers build against")
+	persistentFlags.StringVar(&guardrailIDFlag, "guardrail-id", "", "ID or ARN of a Bedrock Guardrail applied to every classification agent invocation")
+	persistentFlags.StringVar(&guardrailVersionFlag, "guardrail-version", "", "Version of the Bedrock Guardrail: a version number or DRAFT")
+	persistentFlags.DurationVar(&githubMaxWaitFlag, "github-max-wait", extractor.DefaultGitHubRateLimitWait, "Longest time GitHub requests wait for an exhausted rate limit to reset before failing")
+	cmd.MarkPersistentFlagFilename("config", "yaml", "yml")
+	cmd.MarkPersistentFlagDirname("models-dir")
+	cmd.MarkPersistentFlagDirname("cache-dir")
+	cmd.MarkPersistentFlagDirname("controllers-dir")
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.services, "service", "", "AWS service name(s), comma-separated (e.g., acm,dynamodb,lambda)")
+	flags.StringVar(&opts.serviceFile, "service-file", "", "File with newline-separated service names, or - to read them from stdin")
+	flags.StringVar(&opts.output, "output", "", "Output directory for files (creates <service>-operations.json), or a remote sink: s3://<bucket>[/<prefix>] or gist://[<id>]")
+	flags.BoolVar(&opts.inPlace, "in-place", false, "Write files straight into the output directory instead of staging them in <output>.partial and swapping it into place once every service succeeded, e.g. when the output directory is a mount point")
+	flags.BoolVar(&opts.singleFile, "single-file", false, "Write all services into one combined operations.json instead of one file per service")
+	flags.StringSliceVar(&opts.only, "only", nil, "Limit the operations written to the operations files to subsets, comma-separated: control-plane or data-plane, and supported or unsupported; counts and coverage still cover every operation")
+	flags.StringSliceVar(&opts.subAPIs, "sub-apis", nil, "Sub-API models whose operations are merged into a service's, as <service>=<model> pairs, comma-separated (e.g. s3=s3-control,dynamodb=dynamodb-streams)")
+	flags.BoolVar(&opts.detectSubAPIs, "detect-sub-apis", false, "Detect sub-APIs from the AWS SDK packages a controller imports that share the service's IAM prefix")
+	flags.BoolVar(&opts.noController, "no-controller", false, "Extract services without an ACK controller: skip controller scanning and generate policies for the control plane operations a new controller would need")
+	flags.StringSliceVar(&opts.controllerReleases, "controller-release", nil, "Scan the source of tagged controller releases downloaded from GitHub instead of the controllers directory, e.g. s3-controller@v1.0.4 or s3-controller@latest; their services are extracted when --service is not given")
+	flags.BoolVar(&opts.force, "force", false, "Re-extract services even when their model and controller are unchanged since the last run")
+	flags.BoolVar(&opts.commitPartial, "commit-partial", false, "Replace the output directory with the output of the services that succeeded even when others failed; the run still fails")
+	flags.BoolVar(&opts.resume, "resume", false, "Record progress in a checkpoint in <output>.partial, or in the output directory with --in-place, and resume an interrupted or failed run from it")
+	flags.BoolVar(&opts.classify, "classify", false, "Enable AWS Bedrock inline agent classification of operations as control plane vs data plane")
+	flags.BoolVar(&opts.generatePolicies, "generate-policies", false, "Generate recommended IAM policies for supported operations")
+	flags.StringSliceVar(&opts.partitions, "partitions", []string{extractor.DefaultPartition}, "Partitions to generate policies for, comma-separated (e.g., aws,aws-us-gov,aws-cn)")
+	flags.BoolVar(&opts.strict, "strict", false, "Treat policy lint warnings as failures")
+	flags.StringVar(&opts.onClassificationFailure, "on-classification-failure", string(extractor.ClassificationFailureContinue), "What to do when classifying a service fails: continue with unknown operations, fail the service, or classify heuristically from operation names (continue, fail or heuristic)")
+	flags.BoolVar(&opts.acceptDrift, "accept-drift", false, "Accept classifications that flip operations between planes since an earlier run instead of keeping the earlier type")
+	flags.BoolVar(&opts.skipPreflight, "skip-bedrock-preflight", false, "Skip the probe invocation verifying Bedrock access before classification starts")
+	flags.StringVar(&opts.classifySample, "classify-sample", "", "Classify only a random sample of unsupported operations (e.g. 10%) and estimate the control/data plane split")
+	flags.StringVar(&opts.promptTemplate, "prompt-template", "", "Path to a Go text/template file overriding the embedded classification prompt")
+	flags.StringVar(&opts.agentInstruction, "agent-instruction", "", "Path to a text file overriding the embedded system instruction of the classification agent")
+	flags.StringVar(&opts.classificationCache, "classification-cache", "", "JSON file used to reuse classifications of identically named operations across services and runs (default classification-cache.json in the cache directory)")
+	flags.StringVar(&opts.classificationOverrides, "classification-overrides", "", "YAML file of reviewed operation types that take precedence over classification")
+	flags.StringVar(&opts.classificationRules, "classification-rules", "", "YAML file of rules correcting known Bedrock misclassifications by operation name pattern, checked before the embedded rules")
+	flags.StringVar(&opts.roadmap, "roadmap", "", "YAML file mapping service names to planned operations")
+	flags.StringVar(&opts.exclusions, "exclusions", "", "YAML file mapping service names to internal or console-only operations and the reason they are excluded")
+	flags.StringVar(&opts.resourceLevelSupport, "resource-level-support", "", "YAML file mapping IAM service prefixes to actions without resource-level permissions, added to the embedded dataset")
+	flags.StringVar(&opts.accessLevels, "access-levels", "", "YAML file mapping IAM service prefixes to actions and their access levels, overriding the embedded dataset")
+	flags.BoolVar(&opts.groupByAccessLevel, "group-policy-by-access-level", false, "Grant the actions of generated policies in one statement per IAM access level (List, Read, Write, Permissions management, Tagging)")
+	flags.StringVar(&opts.arnTypes, "arn-types", "", "YAML file mapping IAM service prefixes to ARN types and their actions, replacing the embedded dataset for those services")
+	flags.BoolVar(&opts.groupByARNType, "group-policy-by-arn-type", false, "Grant the actions of generated policies in one statement per ARN type (e.g. table, index and stream for DynamoDB), scoped to the ARNs of that type")
+	flags.StringVar(&opts.matchers, "matchers", "", "YAML file defining extra controller match patterns per service")
+	defaultLimits := extractor.DefaultScanLimits()
+	flags.Int64Var(&opts.scanLimits.MaxFileSize, "max-scan-file-size", defaultLimits.MaxFileSize, "Size in bytes above which controller files are not scanned, 0 for no limit")
+	flags.StringSliceVar(&opts.scanLimits.SkipDirs, "scan-skip-dirs", defaultLimits.SkipDirs, "Directory names never scanned in controllers, comma-separated")
+	flags.DurationVar(&opts.scanLimits.Timeout, "scan-timeout", defaultLimits.Timeout, "Maximum time spent scanning one controller, 0 for no limit; files not reached are reported as skipped")
+	flags.BoolVar(&opts.linkIssues, "link-issues", false, "Link unsupported operations to open GitHub issues in the aws-controllers-k8s org (uses GITHUB_TOKEN when set)")
+	flags.BoolVar(&opts.resolveOwners, "resolve-owners", false, "Attribute supported operations to their CODEOWNERS entry and the last author of the matched line (git blame)")
+	flags.BoolVar(&opts.generateExamples, "generate-examples", false, "Generate minimal example request payloads per operation into <service>-examples.json")
+	flags.BoolVar(&opts.generateOpenAPI, "generate-openapi", false, "Render the operations as an OpenAPI 3.1 document into <service>-openapi.json")
+	flags.BoolVar(&opts.generateBadges, "generate-badges", false, "Write a shields.io endpoint badge showing the coverage of each service into <service>-badge.json")
+	flags.StringVar(&opts.badgeLabel, "badge-label", extractor.DefaultBadgeLabel, "Label of the coverage badges")
+	flags.BoolVar(&opts.generateBackstage, "generate-backstage", false, "Describe each controller as a Backstage catalog entity with its coverage into <service>-catalog-info.yaml, plus a TechDocs page <service>-coverage.md")
+	flags.StringVar(&opts.backstage.Owner, "backstage-owner", "aws-controllers-k8s", "Owner of the Backstage catalog entities")
+	flags.StringVar(&opts.backstage.Lifecycle, "backstage-lifecycle", "production", "Lifecycle of the Backstage catalog entities")
+	flags.StringVar(&opts.backstage.System, "backstage-system", "", "System the Backstage catalog entities belong to (optional)")
+	flags.BoolVar(&opts.exportDataPlane, "export-data-plane", false, "Write the operations classified as data plane into <service>-dataplane-operations.json for application teams (requires --classify)")
+	flags.BoolVar(&opts.generateAppPolicies, "generate-app-policies", false, "Generate application-facing IAM policies granting the data plane actions of each service into <service>-app-policy.json (requires --classify)")
+	flags.BoolVar(&opts.generateTrustPolicies, "generate-trust-policies", false, "Generate IRSA and EKS Pod Identity trust policies for the controller role")
+	flags.BoolVar(&opts.generateSCP, "generate-scp", false, "Generate an AWS Organizations service control policy allowing the principals given with --scp-principal-arn only the actions the generated policies of all extracted services grant")
+	flags.BoolVar(&opts.generateCombinedPolicy, "generate-combined-policy", false, "Generate one deduplicated IAM policy for a single role shared by the controllers of all extracted services into ack-combined-policy.json")
+	flags.StringSliceVar(&opts.scpPrincipalARNs, "scp-principal-arn", nil, "Principal ARN patterns the service control policy applies to (e.g. arn:aws:iam::*:role/ack-*); required with --generate-scp")
+	flags.StringVar(&opts.trust.AccountID, "account-id", "", "AWS account ID hosting the EKS cluster (used for trust policies)")
+	flags.StringVar(&opts.trust.OIDCProvider, "oidc-provider", "", "EKS cluster OIDC provider URL (used for the IRSA trust policy)")
+	flags.StringVar(&opts.trust.ClusterName, "cluster-name", "", "EKS cluster name (scopes the Pod Identity trust policy)")
+	flags.StringVar(&opts.trust.Region, "region", "", "EKS cluster region (scopes the Pod Identity trust policy)")
+	flags.StringVar(&opts.trust.Namespace, "namespace", "ack-system", "Kubernetes namespace of the controller service account")
+	flags.StringVar(&opts.trust.ServiceAccount, "service-account", "", "Name of the controller service account trust policies are scoped to (default ack-<service>-controller)")
+
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+	cmd.MarkFlagFilename("prompt-template")
+	cmd.MarkFlagFilename("agent-instruction", "txt")
+	cmd.MarkFlagFilename("service-file", "txt")
+	cmd.MarkFlagFilename("classification-cache", "json")
+	cmd.MarkFlagFilename("classification-overrides", "yaml", "yml")
+	cmd.MarkFlagFilename("classification-rules", "yaml", "yml")
+	cmd.MarkFlagFilename("roadmap", "yaml", "yml")
+	cmd.MarkFlagFilename("exclusions", "yaml", "yml")
+	cmd.MarkFlagFilename("resource-level-support", "yaml", "yml")
+	cmd.MarkFlagFilename("access-levels", "yaml", "yml")
+	cmd.MarkFlagFilename("arn-types", "yaml", "yml")
+	cmd.MarkFlagFilename("matchers", "yaml", "yml")
+	cmd.MarkFlagDirname("output")
+
+	cmd.AddCommand(newServicesCommand())
+	cmd.AddCommand(newLintModelCommand())
+	cmd.AddCommand(newBenchCommand())
+	cmd.AddCommand(newPolicyDiffCommand())
+	cmd.AddCommand(newNamingAuditCommand())
+	cmd.AddCommand(newResponseDriftCommand())
+	cmd.AddCommand(newPolicyPatchCommand())
+	cmd.AddCommand(newSimulateCommand())
+	cmd.AddCommand(newCompareManagedPolicyCommand())
+	cmd.AddCommand(newClassifyCommand())
+	cmd.AddCommand(newEvalCommand())
+	cmd.AddCommand(newServeCommand())
+	cmd.AddCommand(newSelfCheckCommand())
+	cmd.AddCommand(newDoctorCommand())
+	cmd.AddCommand(newMigrateCommand())
+	cmd.AddCommand(newStatsCommand())
+	cmd.AddCommand(newProposeCommand())
+	cmd.AddCommand(newExportReviewCommand())
+	cmd.AddCommand(newImportReviewCommand())
+	cmd.AddCommand(newTUICommand())
+	cmd.AddCommand(newControllerCallsCommand())
+	cmd.AddCommand(newRBACPairingCommand())
+	cmd.AddCommand(newOpsCommand())
+
+	return cmd
+}
+
+// newServicesCommand builds the command listing services available in the models directory
+func newServicesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "services",
+		Short: "List the services available in the models directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			services, err := extractor.ListAvailableServices()
+			if err != nil {
+				return err
+			}
+			for _, service := range services {
+				fmt.Println(service)
+			}
+			return nil
+		},
+	}
+}
+
+// bindEnvironment sets every flag not given on the command line from its ACK_EXTRACTOR_* variable.
+// Flags set this way count as changed, so the config file doesn't override them.
+func bindEnvironment(cmd *cobra.Command) error {
+	var bindErr error
+	flags := cmd.Flags()
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if bindErr != nil || flag.Changed {
+			return
+		}
+		value, ok := os.LookupEnv(envVarForFlag(flag.Name))
+		if !ok {
+			return
+		}
+		if err := flags.Set(flag.Name, value); err != nil {
+			bindErr = fmt.Errorf("invalid value %q for %s: %w", value, envVarForFlag(flag.Name), err)
+		}
+	})
+	return bindErr
+}
+
+// bindConfig sets every flag neither given on the command line nor through the environment from
+// the config file key named like the flag, with underscores or dashes. Keys no command has a flag
+// for are rejected, so typos don't go unnoticed; keys of other commands' flags are ignored.
+func bindConfig(cmd *cobra.Command) error {
+	if configFileFlag != "" {
+		if err := extractor.SetConfigFile(configFileFlag); err != nil {
+			return err
+		}
+	}
+	config, err := extractor.LoadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(config.Settings))
+	for key := range config.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	flags := cmd.Flags()
+	for _, key := range keys {
+		name := strings.ReplaceAll(key, "_", "-")
+		flag := flags.Lookup(name)
+		if name == "config" || name == "help" || flag == nil && !commandTreeHasFlag(cmd.Root(), name) {
+			return fmt.Errorf("unknown setting %q in %s", key, config.Path)
+		}
+		if flag == nil || flag.Changed {
+			continue
+		}
+		values, err := configValues(config.Settings[key])
+		if err == nil && len(values) > 1 && !strings.HasSuffix(flag.Value.Type(), "Slice") {
+			err = fmt.Errorf("a list is only accepted for list flags")
+		}
+		for _, value := range values {
+			if err == nil {
+				err = flags.Set(name, value)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value for %s in %s: %w", key, config.Path, err)
+		}
+	}
+	return nil
+}
+
+// configValues returns the flag values of a config file setting: a scalar or a list of scalars
+func configValues(setting interface{}) ([]string, error) {
+	switch value := setting.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, element := range value {
+			switch element.(type) {
+			case []interface{}, map[string]interface{}:
+				return nil, fmt.Errorf("expected a list of scalars")
+			}
+			values = append(values, fmt.Sprint(element))
+		}
+		return values, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("expected a scalar or a list")
+	default:
+		return []string{fmt.Sprint(value)}, nil
+	}
+}
+
+// commandTreeHasFlag reports whether any command below cmd, including cmd, has a flag
+func commandTreeHasFlag(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+		return true
+	}
+	for _, child := range cmd.Commands() {
+		if commandTreeHasFlag(child, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// envVarForFlag returns the environment variable bound to a flag
+func envVarForFlag(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// completeServiceNames completes the last entry of a comma-separated --service value
+// from the service directories available in the models directory
+func completeServiceNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Completion does not run the pre-run hooks, so honor --models-dir here
+	if err := configureDirectories(); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	services, err := extractor.ListAvailableServices()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	prefix := ""
+	current := toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+		current = toComplete[i+1:]
+	}
+
+	var completions []string
+	for _, service := range services {
+		if strings.HasPrefix(service, current) {
+			completions = append(completions, prefix+service)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// resolveServices combines the comma-separated --service list with the services read from
+// --service-file, where "-" reads stdin. Duplicates are removed.
+func resolveServices(serviceList, serviceFile string) ([]string, error) {
+	var names []string
+	if serviceList != "" {
+		names = append(names, strings.Split(serviceList, ",")...)
+	}
+
+	if serviceFile != "" {
+		fileNames, err := readNameList(serviceFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading service file: %w", err)
+		}
+		names = append(names, fileNames...)
+	}
+
+	services := uniqueNames(names)
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services given")
+	}
+	return services, nil
+}
+
+// readNameList reads names from a file, or from stdin when the path is "-". Lines may hold
+// comma-separated names; blank lines and lines starting with # are skipped.
+func readNameList(path string) ([]string, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	var names []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, strings.Split(line, ",")...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// uniqueNames trims names and drops empty and duplicate ones, keeping the first occurrence
+func uniqueNames(names []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
+
+// runExtract extracts operations and writes output files for every requested service
+func runExtract(opts *extractOptions) error {
+	if opts.promptTemplate != "" {
+		if err := extractor.LoadPromptTemplate(opts.promptTemplate); err != nil {
+			return fmt.Errorf("error loading prompt template: %w", err)
+		}
+	}
+	if opts.agentInstruction != "" {
+		if err := extractor.LoadAgentInstruction(opts.agentInstruction); err != nil {
+			return fmt.Errorf("error loading agent instruction: %w", err)
+		}
+	}
+
+	if opts.classificationCache == "" {
+		path, err := extractor.CachePath(classificationCacheFile)
+		if err != nil {
+			fmt.Printf("Warning: classification cache disabled: %v\n", err)
+		}
+		opts.classificationCache = path
+	}
+	if opts.classificationCache != "" {
+		if err := extractor.LoadClassificationCache(opts.classificationCache); err != nil {
+			return fmt.Errorf("error loading classification cache: %w", err)
+		}
+	}
+
+	if opts.classificationOverrides != "" {
+		if _, err := extractor.LoadClassificationOverrides(opts.classificationOverrides); err != nil {
+			return err
+		}
+	}
+
+	if opts.classificationRules != "" {
+		if err := extractor.LoadClassificationRules(opts.classificationRules); err != nil {
+			return err
+		}
+	}
+
+	extractor.SetNoController(opts.noController)
+	if err := extractor.SetSubAPIs(opts.subAPIs); err != nil {
+		return fmt.Errorf("error parsing --sub-apis: %w", err)
+	}
+	extractor.SetDetectSubAPIs(opts.detectSubAPIs)
+	extractor.SetAcceptClassificationDrift(opts.acceptDrift)
+	if err := extractor.SetClassificationFailurePolicy(extractor.ClassificationFailurePolicy(opts.onClassificationFailure)); err != nil {
+		return fmt.Errorf("error parsing --on-classification-failure: %w", err)
+	}
+
+	if opts.classifySample != "" {
+		percent, err := extractor.ParseSamplePercent(opts.classifySample)
+		if err != nil {
+			return fmt.Errorf("error parsing --classify-sample: %w", err)
+		}
+		if !opts.classify {
+			fmt.Println("Warning: --classify-sample has no effect without --classify")
+		}
+		extractor.SetClassificationSample(percent)
+	}
+
+	onlyFilter, err := extractor.ParseOperationSubsets(opts.only)
+	if err != nil {
+		return fmt.Errorf("error parsing --only: %w", err)
+	}
+	if onlyFilter.Type != "" && !opts.classify {
+		fmt.Println("Warning: without --classify only operations typed by --classification-overrides are in the control-plane and data-plane subsets")
+	}
+
+	if (opts.exportDataPlane || opts.generateAppPolicies) && !opts.classify {
+		fmt.Println("Warning: without --classify only operations typed data plane by --classification-overrides are exported")
+	}
+
+	if opts.roadmap != "" {
+		if err := extractor.LoadRoadmap(opts.roadmap); err != nil {
+			return fmt.Errorf("error loading roadmap: %w", err)
+		}
+	}
+
+	if opts.exclusions != "" {
+		if err := extractor.LoadExclusions(opts.exclusions); err != nil {
+			return fmt.Errorf("error loading exclusions: %w", err)
+		}
+	}
+
+	if opts.resourceLevelSupport != "" {
+		if err := extractor.LoadResourceLevelSupport(opts.resourceLevelSupport); err != nil {
+			return fmt.Errorf("error loading resource-level support: %w", err)
+		}
+	}
+
+	if opts.accessLevels != "" {
+		if err := extractor.LoadAccessLevels(opts.accessLevels); err != nil {
+			return fmt.Errorf("error loading access levels: %w", err)
+		}
+	}
+	extractor.SetGroupPolicyByAccessLevel(opts.groupByAccessLevel)
+
+	if opts.generateSCP && len(opts.scpPrincipalARNs) == 0 {
+		return fmt.Errorf("--generate-scp requires --scp-principal-arn; a service control policy for the whole account would deny every other action to every principal")
+	}
+	if opts.groupByAccessLevel && opts.groupByARNType {
+		return fmt.Errorf("--group-policy-by-access-level and --group-policy-by-arn-type cannot be combined")
+	}
+	if opts.arnTypes != "" {
+		if err := extractor.LoadARNTypes(opts.arnTypes); err != nil {
+			return fmt.Errorf("error loading ARN types: %w", err)
+		}
+	}
+	extractor.SetGroupPolicyByARNType(opts.groupByARNType)
+
+	if opts.matchers != "" {
+		if err := extractor.LoadMatcherConfig(opts.matchers); err != nil {
+			return fmt.Errorf("error loading matcher config: %w", err)
+		}
+	}
+
+	if err := extractor.SetScanLimits(opts.scanLimits); err != nil {
+		return fmt.Errorf("error setting scan limits: %w", err)
+	}
+
+	for _, partition := range opts.partitions {
+		if err := extractor.ValidatePartition(partition); err != nil {
+			return err
+		}
+	}
+
+	var releaseServices []string
+	for _, reference := range opts.controllerReleases {
+		release, err := extractor.ParseControllerRelease(reference)
+		if err != nil {
+			return err
+		}
+		if err := extractor.UseControllerRelease(release); err != nil {
+			return fmt.Errorf("error downloading controller release: %w", err)
+		}
+		fmt.Printf("Scanning %s-controller %s from %s\n", release.Service, release.Tag, release.Path)
+		releaseServices = append(releaseServices, release.Service)
+	}
+
+	services := releaseServices
+	if opts.services != "" || opts.serviceFile != "" {
+		var err error
+		if services, err = resolveServices(opts.services, opts.serviceFile); err != nil {
+			return err
+		}
+	}
+	var features []string
+	if opts.classify {
+		features = append(features, "Bedrock classification")
+	}
+	if opts.generatePolicies {
+		features = append(features, "IAM policy generation")
+	}
+	if opts.generateTrustPolicies {
+		features = append(features, "trust policy generation")
+	}
+	if opts.generateSCP {
+		features = append(features, "service control policy generation")
+	}
+	if opts.generateCombinedPolicy {
+		features = append(features, "combined policy generation")
+	}
+
+	// A missing permission or model access would otherwise only surface after the first batches
+	if opts.classify && !opts.skipPreflight {
+		fmt.Println("Checking Bedrock access...")
+		if err := extractor.CheckBedrockAccess(); err != nil {
+			return fmt.Errorf("Bedrock pre-flight check failed: %w", err)
+		}
+	}
+
+	if len(features) > 0 {
+		fmt.Printf("Generating files with %s for %d service(s)\n\n", strings.Join(features, " and "), len(services))
+	} else {
+		fmt.Printf("Generating files for %d service(s)\n\n", len(services))
+	}
+
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(opts.output, 0755); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+
+	totalOperations := 0
+	successfulServices := 0
+	combined := extractor.NewCombinedOperations()
+
+	// With --resume, every completed service and classification batch is appended to a checkpoint
+	// so an interrupted run can pick up where it left off when rerun with --resume
+	var checkpoint *extractor.Checkpoint
+	if opts.resume {
+		checkpoint, err = extractor.EnableCheckpoint(filepath.Join(opts.output, checkpointFile))
+		if err != nil {
+			return fmt.Errorf("error loading checkpoint: %w", err)
+		}
+		defer checkpoint.Close()
+	} else if err := os.Remove(filepath.Join(opts.output, checkpointFile)); err != nil && !os.IsNotExist(err) {
+		// A checkpoint left by an earlier run no longer matches the output this run writes
+		return fmt.Errorf("error removing stale checkpoint: %w", err)
+	}
+
+	// Services whose model, controller and settings are unchanged since the last run reuse its output
+	statePath, err := extractor.CachePath(stateFile)
+	if err != nil {
+		return err
+	}
+	state, err := extractor.LoadRunState(statePath, firstNonEmpty(opts.outputLocation, opts.output))
+	if err != nil {
+		return err
+	}
+	// Hashes recorded by earlier versions next to the output are migrated into the global state
+	if legacy, legacyErr := extractor.LoadLegacyRunState(filepath.Join(firstNonEmpty(opts.previousOutput, opts.output), legacyStateFile)); legacyErr == nil {
+		for serviceName, hash := range legacy {
+			if _, ok := state.Services[serviceName]; !ok {
+				state.Record(serviceName, hash)
+			}
+		}
+	}
+	settings, err := extractionSettings(opts)
+	if err != nil {
+		return err
+	}
+	previous := loadPreviousOutput(opts)
+
+	metrics := extractor.NewRunMetrics(len(services))
+	report := extractor.NewStatusReport(len(services))
+	plan := extractor.NewAttachmentPlan()
+
+	// Running classification totals help decide whether a costly run is worth finishing
+	var dashboard *classificationDashboard
+	if opts.classify {
+		dashboard = startClassificationDashboard()
+	}
+	defer dashboard.Stop()
+
+	for _, serviceName := range services {
+		serviceStart := time.Now()
+		status := extractor.ServiceStatusExtracted
+		// recordService adds the outcome of the service to the run metrics and the status report
+		recordService := func(serviceOps *extractor.ServiceOperations, err error) {
+			metricsStatus := status
+			if err != nil {
+				metricsStatus = extractor.ServiceStatusFailed
+			}
+			metrics.RecordService(serviceName, serviceOps, metricsStatus, time.Since(serviceStart), err)
+			report.RecordService(serviceName, serviceOps, status, time.Since(serviceStart), err)
+		}
+
+		if checkpoint != nil {
+			if serviceOps, ok := checkpoint.CompletedService(serviceName); ok {
+				fmt.Printf("%s: already completed, skipping (resumed from checkpoint)\n", serviceName)
+				status = extractor.ServiceStatusResumed
+				recordService(serviceOps, nil)
+				combined.Services[serviceName] = serviceOps
+				totalOperations += len(serviceOps.Operations)
+				successfulServices++
+				continue
+			}
+		}
+
+		inputHash, hashErr := extractor.ServiceInputHash(serviceName, settings...)
+		if hashErr != nil {
+			reportProblem(report, serviceName, "Warning: Failed to hash inputs of %s: %v", serviceName, hashErr)
+		}
+
+		serviceOps := previous(serviceName)
+		if serviceOps != nil && inputHash != "" && !opts.force && state.Unchanged(serviceName, inputHash) {
+			fmt.Printf("%s: unchanged since last run, reusing previous output\n", serviceName)
+			status = extractor.ServiceStatusReused
+		} else {
+			if !opts.noController && !extractor.HasController(serviceName) {
+				reportProblem(report, serviceName, "Warning: no controller found for %s, all operations will be unsupported; use --no-controller for services without a controller", serviceName)
+			}
+			serviceOps, err = extractor.ExtractDetailedOperationsFromService(serviceName, opts.classify)
+			if err != nil {
+				fmt.Printf("Error extracting operations for %s: %v\n", serviceName, err)
+				recordService(nil, err)
+				continue
+			}
+			for _, warning := range serviceOps.Warnings {
+				report.Warn(serviceName, "%s", warning)
+			}
+		}
+
+		if len(serviceOps.Operations) == 0 {
+			fmt.Printf("No operations found for %s\n", serviceName)
+			recordService(serviceOps, extractor.CategorizeError(extractor.ErrorCategoryNoOperations, fmt.Errorf("no operations found")))
+			continue
+		}
+
+		if opts.linkIssues {
+			issues, issuesErr := extractor.FindTrackingIssues(serviceName)
+			if issuesErr != nil {
+				reportProblem(report, serviceName, "Warning: Failed to look up GitHub issues for %s: %v", serviceName, issuesErr)
+			} else {
+				linked := extractor.LinkIssues(serviceOps.Operations, issues)
+				fmt.Printf("%s: %d unsupported operation(s) linked to open issues\n", serviceName, linked)
+			}
+		}
+
+		if opts.resolveOwners {
+			owned, ownersErr := extractor.ResolveOwnership(serviceName, serviceOps.Operations)
+			if ownersErr != nil {
+				reportProblem(report, serviceName, "Warning: Failed to resolve owners for %s: %v", serviceName, ownersErr)
+			}
+			fmt.Printf("%s: %d supported operation(s) attributed to owners\n", serviceName, owned)
+		}
+
+		combined.Services[serviceName] = serviceOps
+		if opts.singleFile {
+			fmt.Printf("%s: %d operations\n", serviceName, len(serviceOps.Operations))
+		} else {
+			written := serviceOps
+			if len(opts.only) > 0 {
+				written = serviceOps.Subset(opts.only, onlyFilter)
+			}
+			outputFile := filepath.Join(opts.output, serviceName+"-operations.json")
+			if writeErr := extractor.WriteServiceOperationsJSON(written, outputFile); writeErr != nil {
+				fmt.Printf("Error writing JSON file for %s: %v\n", serviceName, writeErr)
+				recordService(serviceOps, extractor.CategorizeError(extractor.ErrorCategoryOutput, writeErr))
+				continue
+			}
+
+			fmt.Printf("%s: %d operations → %s\n", serviceName, len(written.Operations), outputFile)
+		}
+		fmt.Printf("%s: %.1f%% coverage of declarative operations\n", serviceName, serviceOps.RelevantCoverage)
+		fmt.Printf("%s: %.1f%% coverage (%d implemented, %d partially implemented, %d ignored, %d excluded, %d planned, %d unsupported)\n",
+			serviceName, serviceOps.SupportCoverage,
+			serviceOps.SupportStatusCounts[extractor.SupportImplemented],
+			serviceOps.SupportStatusCounts[extractor.SupportPartiallyImplemented],
+			serviceOps.SupportStatusCounts[extractor.SupportIntentionallyIgnored],
+			serviceOps.SupportStatusCounts[extractor.SupportExcluded],
+			serviceOps.SupportStatusCounts[extractor.SupportPlanned],
+			serviceOps.SupportStatusCounts[extractor.SupportUnsupported])
+
+		if len(serviceOps.SemanticGroups) > 0 {
+			var groups []string
+			for _, group := range serviceOps.SemanticGroups {
+				groups = append(groups, fmt.Sprintf("%d %s (%d supported)", group.Operations, group.Group, group.Supported))
+			}
+			fmt.Printf("%s: %s\n", serviceName, strings.Join(groups, ", "))
+		}
+
+		if decided := extractor.CountRuleClassifications(serviceOps.Operations); decided > 0 {
+			fmt.Printf("%s: %d Describe/List operation(s) classified by rules without Bedrock\n", serviceName, decided)
+		}
+		if corrected := extractor.CountClassificationCorrections(serviceOps.Operations); corrected > 0 {
+			fmt.Printf("%s: %d classification(s) corrected by rules\n", serviceName, corrected)
+		}
+
+		if prediction := serviceOps.SupportPrediction; prediction != nil {
+			fmt.Printf("%s: %d operation(s) predicted from %d resource(s), %d supported, %d missing, %d supported beyond the prediction\n",
+				serviceName, prediction.PredictedOperations, len(prediction.Resources), prediction.ConfirmedOperations,
+				len(prediction.MissingOperations), len(prediction.UnpredictedOperations))
+		}
+
+		if len(serviceOps.ModelSources) > 1 {
+			var sources []string
+			for _, source := range extractor.ModelSources() {
+				if count, ok := serviceOps.ModelSources[source]; ok {
+					sources = append(sources, fmt.Sprintf("%d from %s", count, source))
+				}
+			}
+			fmt.Printf("%s: operations merged from %d model sources (%s)\n", serviceName, len(serviceOps.ModelSources), strings.Join(sources, ", "))
+		}
+
+		if sample := serviceOps.ClassificationSample; sample != nil && sample.SampledOperations > 0 {
+			fmt.Printf("%s: estimated %.1f%% ± %.1f%% control plane (%d of %d sampled operations classified as control plane)\n",
+				serviceName, sample.EstimatedControlPlaneShare, sample.MarginOfError, sample.ControlPlaneOps, sample.SampledOperations)
+		}
+
+		for _, call := range serviceOps.UnmodeledCalls {
+			kind := "not in the model"
+			if call.CrossService {
+				kind = "cross-service"
+			}
+			fmt.Printf("%s: controller calls %s (%s) at %s:%d\n", serviceName, call.Action, kind, call.Locations[0].File, call.Locations[0].Line)
+		}
+
+		timedOut := 0
+		for _, skipped := range serviceOps.SkippedPaths {
+			switch skipped.Reason {
+			case extractor.SkipReasonExcludedDir:
+				fmt.Printf("%s: skipped %s (%s)\n", serviceName, skipped.Path, skipped.Reason)
+			case extractor.SkipReasonTimeout:
+				timedOut++
+			default:
+				reportProblem(report, serviceName, "Warning: %s: skipped %s (%s)", serviceName, skipped.Path, skipped.Reason)
+			}
+		}
+		if timedOut > 0 {
+			reportProblem(report, serviceName, "Warning: %s: controller scan timed out, %d file(s) not scanned", serviceName, timedOut)
+		}
+
+		if len(serviceOps.ResolutionDiscrepancies) > 0 {
+			reportProblem(report, serviceName, "Warning: %s: %d operation(s) found by only one of the service shape and operation shapes", serviceName, len(serviceOps.ResolutionDiscrepancies))
+		}
+
+		for _, gap := range serviceOps.DependencyGaps {
+			reportProblem(report, serviceName, "Warning: %s: %s is implemented without a read operation for %s (%s)",
+				serviceName, gap.Operation, gap.Reason, strings.Join(gap.Requires, " or "))
+		}
+
+		if counts := serviceOps.ImplementationCounts; len(counts) > 0 {
+			fmt.Printf("%s: %d generated, %d custom, %d mixed implementation(s), %.1f%% need hand-written code\n", serviceName,
+				counts[extractor.ImplementationGenerated], counts[extractor.ImplementationCustom], counts[extractor.ImplementationMixed],
+				serviceOps.CustomImplementation)
+		}
+
+		for _, density := range serviceOps.FileDensity {
+			if density.Hot {
+				fmt.Printf("%s: hot file %s implements %d operations\n", serviceName, density.File, density.Operations)
+			}
+		}
+
+		if opts.generatePolicies {
+			for _, partition := range opts.partitions {
+				writePermissionPolicy(serviceName, serviceOps, partition, opts, plan, report)
+			}
+		}
+
+		if opts.generateExamples {
+			writeExamples(serviceName, serviceOps, opts.output, report)
+		}
+
+		if opts.generateOpenAPI {
+			writeOpenAPI(serviceName, serviceOps, opts.output, report)
+		}
+
+		if opts.generateBackstage {
+			writeBackstageCatalog(serviceName, serviceOps, opts, report)
+		}
+
+		if opts.generateBadges {
+			writeCoverageBadge(serviceName, serviceOps, opts, report)
+		}
+
+		if opts.exportDataPlane {
+			writeDataPlaneOperations(serviceName, serviceOps, opts.output, report)
+		}
+
+		if opts.generateAppPolicies {
+			for _, partition := range opts.partitions {
+				writeAppPolicy(serviceName, serviceOps, partition, opts.output, report)
+			}
+		}
+
+		if opts.generateTrustPolicies {
+			for _, partition := range opts.partitions {
+				writeTrustPolicies(serviceName, partition, opts.output, opts.trust, report)
+			}
+		}
+		// Heuristic classifications are retried with Bedrock next run instead of being kept as unchanged
+		if inputHash != "" && extractor.CountHeuristicClassifications(serviceOps.Operations) == 0 {
+			state.Record(serviceName, inputHash)
+		}
+		recordService(serviceOps, nil)
+		if checkpoint != nil {
+			if err := checkpoint.CompleteService(serviceOps); err != nil {
+				reportProblem(report, "", "Warning: Failed to write checkpoint: %v", err)
+			}
+		}
+		totalOperations += len(serviceOps.Operations)
+		successfulServices++
+	}
+
+	if err := state.Save(); err != nil {
+		reportProblem(report, "", "Warning: Failed to save state file: %v", err)
+	}
+
+	dashboard.Stop()
+	if classification := extractor.ClassificationMetrics(); classification.Batches > 0 {
+		fmt.Printf("\n%s\n", formatClassificationMetrics(classification))
+	}
+
+	if opts.classify && opts.classificationCache != "" {
+		if err := extractor.SaveClassificationCache(opts.classificationCache); err != nil {
+			reportProblem(report, "", "Error saving classification cache: %v", err)
+		}
+	}
+
+	// Operations of several services sharing a name stay apart in combined artifacts, but are
+	// worth knowing about when reading them
+	if opts.singleFile || opts.generateSCP || opts.generateCombinedPolicy {
+		combined.NameCollisions = extractor.FindNameCollisions(combined.Services)
+		if len(combined.NameCollisions) > 0 {
+			reportProblem(report, "", "Warning: %d operation name(s) extracted from more than one service, kept apart by service in combined files: %s",
+				len(combined.NameCollisions), extractor.DescribeNameCollisions(combined.NameCollisions, 5))
+		}
+	}
+
+	if opts.singleFile {
+		combined.TotalOperations = totalOperations
+		written := combined
+		if len(opts.only) > 0 {
+			written = extractor.NewCombinedOperations()
+			written.TotalOperations = totalOperations
+			written.NameCollisions = combined.NameCollisions
+			for serviceName, serviceOps := range combined.Services {
+				written.Services[serviceName] = serviceOps.Subset(opts.only, onlyFilter)
+			}
+		}
+		combinedFile := filepath.Join(opts.output, combinedOperationsFile)
+		if err := extractor.WriteCombinedOperationsJSON(written, combinedFile); err != nil {
+			return fmt.Errorf("error writing combined JSON file: %w", err)
+		}
+		fmt.Printf("\nAll services → %s\n", combinedFile)
+	}
+
+	if opts.generateSCP {
+		writeServiceControlPolicy(combined.Services, opts, report)
+	}
+
+	if opts.generateCombinedPolicy {
+		for _, partition := range opts.partitions {
+			writeCombinedPolicy(combined.Services, partition, opts, report)
+		}
+	}
+
+	if opts.generatePolicies {
+		planFile := filepath.Join(opts.output, attachmentPlanFile)
+		if err := extractor.WriteAttachmentPlanJSON(plan, planFile); err != nil {
+			reportProblem(report, "", "Error writing attachment plan: %v", err)
+		} else {
+			fmt.Printf("Policy attachment plan → %s\n", planFile)
+		}
+	}
+
+	// Keep the checkpoint while services are still missing so they can be retried with --resume
+	if checkpoint != nil && successfulServices == len(services) {
+		if err := checkpoint.Remove(); err != nil {
+			reportProblem(report, "", "Warning: Failed to remove checkpoint: %v", err)
+		}
+	}
+
+	metrics.Finish()
+	metricsFile := filepath.Join(opts.output, runMetricsFile)
+	if err := extractor.WriteRunMetricsJSON(metrics, metricsFile); err != nil {
+		reportProblem(report, "", "Warning: Failed to write run metrics: %v", err)
+	}
+
+	report.Finish()
+	reportFile := filepath.Join(opts.output, statusFile)
+	if err := extractor.WriteStatusReportJSON(report, reportFile); err != nil {
+		fmt.Printf("Warning: Failed to write status report: %v\n", err)
+	}
+
+	opts.failedServices = len(services) - successfulServices
+	fmt.Printf("\n%d/%d services succeeded, %d warning(s), %d operations extracted → %s\n",
+		report.Summary.Succeeded, report.Summary.Requested, report.Summary.Warnings, totalOperations, reportFile)
+	for _, failed := range report.FailedServices() {
+		fmt.Printf("  %s: %s: %s\n", failed.Service, failed.ErrorCategory, failed.Error)
+	}
+	return nil
+}
+
+// reportProblem prints a warning or an error that does not fail the run and records it in the
+// status report, for a service or for the whole run when serviceName is empty
+func reportProblem(report *extractor.StatusReport, serviceName, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Println(message)
+	report.Warn(serviceName, "%s", strings.TrimPrefix(message, "Warning: "))
+}
+
+// configureGuardrail attaches the Bedrock Guardrail given on the command line or in config.yaml to
+// classification calls. The ID and version are taken together from the same place.
+func configureGuardrail() error {
+	id, version := guardrailIDFlag, guardrailVersionFlag
+	if id == "" && version == "" {
+		config, err := extractor.LoadUserConfig()
+		if err != nil {
+			return err
+		}
+		id, version = config.GuardrailID, config.GuardrailVersion
+	}
+	if err := extractor.SetGuardrail(id, version); err != nil {
+		return fmt.Errorf("error configuring the Bedrock Guardrail: %w", err)
+	}
+	return nil
+}
+
+// configureDirectories points the extractor at the models, controllers and cache directories and
+// the path templates given on the command line, falling back to the ones in config.yaml of the
+// config directory and then to well-known container mount points when the defaults don't exist
+func configureDirectories() error {
+	config, err := extractor.LoadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	modelsDir := firstNonEmpty(modelsDirFlag, config.ModelsDir)
+	if modelsDir != "" {
+		if err := extractor.SetModelsDir(modelsDir); err != nil {
+			return err
+		}
+	}
+	controllersDir := firstNonEmpty(controllersDirFlag, config.ControllersDir)
+	if controllersDir != "" {
+		if err := extractor.SetControllersDir(controllersDir); err != nil {
+			return err
+		}
+	}
+	if err := extractor.SetModelPathTemplate(firstNonEmpty(modelPathFlag, config.ModelPathTemplate)); err != nil {
+		return err
+	}
+	if err := extractor.SetControllerPathTemplate(firstNonEmpty(controllerPathFlag, config.ControllerPathTemplate)); err != nil {
+		return err
+	}
+	sources := modelSourcesFlag
+	if len(sources) == 0 {
+		sources = config.ModelSources
+	}
+	if err := extractor.SetModelSources(sources); err != nil {
+		return err
+	}
+	cacheDir := firstNonEmpty(cacheDirFlag, config.CacheDir)
+	if cacheDir != "" {
+		if err := extractor.SetCacheDir(cacheDir); err != nil {
+			return err
+		}
+	}
+
+	// Containers mount the repositories at well-known paths instead of next to the working directory
+	extractor.DetectMounts(modelsDir != "", controllersDir != "")
+	return nil
+}
+
+// firstNonEmpty returns the first of the values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// writePermissionPolicy generates, lints and writes the permission policy of a service for one partition.
+// The aws partition is written to <service>-policy.json, other partitions to <service>-policy-<partition>.json.
+// Policies exceeding the managed policy size limit are split into numbered files, e.g. <service>-policy-1.json,
+// and every written file is added to the attachment plan.
+func writePermissionPolicy(serviceName string, serviceOps *extractor.ServiceOperations, partition string, opts *extractOptions, plan *extractor.AttachmentPlan, report *extractor.StatusReport) {
+	policy, policyErr := extractor.GeneratePartitionPolicy(serviceName, serviceOps.Operations, partition)
+	if policyErr != nil {
+		reportProblem(report, serviceName, "Error generating policy for %s: %v", serviceName, policyErr)
+		return
+	}
+
+	extractor.AddCrossServiceStatements(policy, serviceOps.UnmodeledCalls)
+	knownActions, knownErr := extractor.PolicyKnownActions(serviceName, serviceOps)
+	if knownErr != nil {
+		reportProblem(report, serviceName, "Warning: policy actions of %s not checked against the model: %v", serviceName, knownErr)
+	}
+
+	policies, splitErr := extractor.SplitPolicy(policy)
+	if splitErr != nil {
+		reportProblem(report, serviceName, "Error: policy for %s not written: %v", serviceName, splitErr)
+		return
+	}
+
+	baseName := serviceName + "-policy"
+	if partition != extractor.DefaultPartition {
+		baseName = fmt.Sprintf("%s-policy-%s", serviceName, partition)
+	}
+	files := make([]string, len(policies))
+	blocked := false
+	for i, chunk := range policies {
+		files[i] = filepath.Join(opts.output, baseName+".json")
+		if len(policies) > 1 {
+			files[i] = filepath.Join(opts.output, fmt.Sprintf("%s-%d.json", baseName, i+1))
+		}
+
+		label := serviceName
+		if len(policies) > 1 {
+			label = filepath.Base(files[i])
+		}
+		findings := extractor.LintPolicy(*chunk, knownActions)
+		for _, finding := range findings {
+			fmt.Printf("%s: policy %s [%s] statement %d: %s\n", label, finding.Severity, finding.Rule, finding.Statement, finding.Message)
+			if finding.Severity != extractor.SeverityInfo {
+				report.Warn(serviceName, "policy %s [%s] statement %d: %s", finding.Severity, finding.Rule, finding.Statement, finding.Message)
+			}
+		}
+		if extractor.HasBlockingFindings(findings, opts.strict) {
+			reportProblem(report, serviceName, "Error: policy for %s failed validation, not writing %s", serviceName, files[i])
+			blocked = true
+		}
+	}
+	// A partial set of policies would silently drop permissions from the role
+	if blocked {
+		return
+	}
+
+	for i, chunk := range policies {
+		if writePolicyErr := extractor.WritePolicyJSON(chunk, files[i]); writePolicyErr != nil {
+			reportProblem(report, serviceName, "Error writing policy file for %s: %v", serviceName, writePolicyErr)
+			return
+		}
+	}
+	plan.Add(serviceName, partition, policies, files)
+	if len(policies) > 1 {
+		fmt.Printf("%s: policy split into %d managed policies → %s-*.json\n", serviceName, len(policies), filepath.Join(opts.output, baseName))
+	} else {
+		fmt.Printf("%s: policy → %s\n", serviceName, files[0])
+	}
+}
+
+// reportProgressToStderr sends progress to stderr, keeping stdout for the document a command prints
+func reportProgressToStderr() {
+	extractor.SetProgressReporter(extractor.NewTextProgressReporter(os.Stderr))
+}
+
+// reuseNeutralFlags are the flags that don't change the operations extracted for a service: they
+// select the services and outputs, configure artifacts generated from the reused operations, or
+// say where caches live. Every other flag, including ones added later, invalidates the reuse of
+// previous output when its value changes.
+var reuseNeutralFlags = map[string]bool{
+	"service": true, "service-file": true, "output": true, "in-place": true, "commit-partial": true, "single-file": true, "only": true,
+	"force": true, "resume": true, "config": true, "cache-dir": true, "classification-cache": true,
+	"skip-bedrock-preflight": true, "github-max-wait": true, "help": true,
+	"generate-policies": true, "partitions": true, "strict": true, "generate-examples": true, "generate-openapi": true,
+	"generate-badges": true, "badge-label": true, "generate-backstage": true, "backstage-owner": true,
+	"backstage-lifecycle": true, "backstage-system": true, "export-data-plane": true, "generate-app-policies": true,
+	"generate-trust-policies": true, "generate-scp": true, "generate-combined-policy": true, "scp-principal-arn": true,
+	"account-id": true, "oidc-provider": true, "cluster-name": true, "region": true, "namespace": true, "service-account": true,
+}
+
+// extractionSettings returns the values of the flags that change extraction output, with the
+// contents of the configuration files they name, so a change to any of them invalidates the reuse
+// of previous output
+func extractionSettings(opts *extractOptions) ([]string, error) {
+	var settings []string
+	var err error
+	opts.flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || reuseNeutralFlags[flag.Name] {
+			return
+		}
+		value := flag.Value.String()
+		if _, isFile := flag.Annotations[cobra.BashCompFilenameExt]; isFile && value != "" {
+			data, readErr := os.ReadFile(value)
+			if readErr != nil && !os.IsNotExist(readErr) {
+				err = fmt.Errorf("failed to read %s: %w", value, readErr)
+				return
+			}
+			value = string(data)
+		}
+		settings = append(settings, flag.Name+"="+value)
+	})
+	return settings, err
+}
+
+// loadPreviousOutput returns a lookup of the operations a previous run wrote to the output directory.
+// The lookup returns nil for services without previous output or whose output --only limited to a
+// subset, since the other files are generated from every operation.
+func loadPreviousOutput(opts *extractOptions) func(serviceName string) *extractor.ServiceOperations {
+	dir := firstNonEmpty(opts.previousOutput, opts.output)
+	if opts.singleFile {
+		combined, err := extractor.ReadCombinedOperationsJSON(filepath.Join(dir, combinedOperationsFile))
+		return func(serviceName string) *extractor.ServiceOperations {
+			if err != nil || combined.Services[serviceName] == nil || len(combined.Services[serviceName].Only) > 0 {
+				return nil
+			}
+			return combined.Services[serviceName]
+		}
+	}
+	return func(serviceName string) *extractor.ServiceOperations {
+		serviceOps, err := extractor.ReadServiceOperationsJSON(filepath.Join(dir, serviceName+"-operations.json"))
+		if err != nil || len(serviceOps.Only) > 0 {
+			return nil
+		}
+		return serviceOps
+	}
+}
+
+// writeServiceControlPolicy generates and writes the service control policy covering all extracted services
+func writeServiceControlPolicy(services map[string]*extractor.ServiceOperations, opts *extractOptions, report *extractor.StatusReport) {
+	policy, err := extractor.GenerateServiceControlPolicy(services, opts.scpPrincipalARNs)
+	if err != nil {
+		reportProblem(report, "", "Error generating service control policy: %v", err)
+		return
+	}
+	if err := extractor.CheckServiceControlPolicySize(policy); err != nil {
+		reportProblem(report, "", "Warning: %v", err)
+	}
+
+	scpFile := filepath.Join(opts.output, serviceControlPolicyFile)
+	if err := extractor.WritePolicyJSON(policy, scpFile); err != nil {
+		reportProblem(report, "", "Error writing service control policy: %v", err)
+		return
+	}
+	fmt.Printf("\nService control policy for %d service(s) → %s\n", len(services), scpFile)
+}
+
+// writeCombinedPolicy generates, lints and writes the policy of a role shared by the controllers of all
+// extracted services for one partition, split into numbered files like per-service policies
+func writeCombinedPolicy(services map[string]*extractor.ServiceOperations, partition string, opts *extractOptions, report *extractor.StatusReport) {
+	policy, skipped, err := extractor.GenerateCombinedPolicy(services, partition)
+	if err != nil {
+		reportProblem(report, "", "Error generating combined policy: %v", err)
+		return
+	}
+	for _, serviceName := range skipped {
+		reportProblem(report, serviceName, "Warning: %s has no supported operations, not included in the combined policy", serviceName)
+	}
+
+	policies, err := extractor.SplitPolicy(policy)
+	if err != nil {
+		reportProblem(report, "", "Error: combined policy not written: %v", err)
+		return
+	}
+
+	// A partial set of known actions would report the actions of the other services as unknown
+	knownActions := make(map[string]bool)
+	for serviceName, serviceOps := range services {
+		actions, err := extractor.PolicyKnownActions(serviceName, serviceOps)
+		if err != nil {
+			reportProblem(report, serviceName, "Warning: combined policy actions not checked against the models: %v", err)
+			knownActions = nil
+			break
+		}
+		for action := range actions {
+			knownActions[action] = true
+		}
+	}
+
+	baseName := combinedPolicyFile
+	if partition != extractor.DefaultPartition {
+		baseName = fmt.Sprintf("%s-%s", combinedPolicyFile, partition)
+	}
+	files := make([]string, len(policies))
+	for i, chunk := range policies {
+		files[i] = filepath.Join(opts.output, baseName+".json")
+		if len(policies) > 1 {
+			files[i] = filepath.Join(opts.output, fmt.Sprintf("%s-%d.json", baseName, i+1))
+		}
+		findings := extractor.LintPolicy(*chunk, knownActions)
+		for _, finding := range findings {
+			fmt.Printf("%s: policy %s [%s] statement %d: %s\n", filepath.Base(files[i]), finding.Severity, finding.Rule, finding.Statement, finding.Message)
+			if finding.Severity != extractor.SeverityInfo {
+				report.Warn("", "combined policy %s [%s] statement %d: %s", finding.Severity, finding.Rule, finding.Statement, finding.Message)
+			}
+		}
+		// A partial set of policies would silently drop permissions from the shared role
+		if extractor.HasBlockingFindings(findings, opts.strict) {
+			reportProblem(report, "", "Error: combined policy failed validation, not writing %s", files[i])
+			return
+		}
+	}
+
+	fmt.Printf("\nCombined policy for %d service(s) in %s:\n", len(services)-len(skipped), partition)
+	for i, chunk := range policies {
+		if err := extractor.WritePolicyJSON(chunk, files[i]); err != nil {
+			reportProblem(report, "", "Error writing combined policy: %v", err)
+			return
+		}
+		fmt.Printf("  %s → %s\n", extractor.CombinedPolicyName(i, len(policies)), files[i])
+	}
+}
+
+// writeOpenAPI renders and writes the OpenAPI document of a service
+func writeOpenAPI(serviceName string, serviceOps *extractor.ServiceOperations, outputDir string, report *extractor.StatusReport) {
+	document, err := extractor.GenerateServiceOpenAPI(serviceName, serviceOps.Operations)
+	if err != nil {
+		reportProblem(report, serviceName, "Error generating OpenAPI document for %s: %v", serviceName, err)
+		return
+	}
+
+	openAPIFile := filepath.Join(outputDir, serviceName+"-openapi.json")
+	if err := extractor.WriteOpenAPIJSON(document, openAPIFile); err != nil {
+		reportProblem(report, serviceName, "Error writing OpenAPI document for %s: %v", serviceName, err)
+		return
+	}
+	fmt.Printf("%s: OpenAPI document → %s\n", serviceName, openAPIFile)
+}
+
+// writeExamples generates and writes example request payloads for a service
+func writeExamples(serviceName string, serviceOps *extractor.ServiceOperations, outputDir string, report *extractor.StatusReport) {
+	examples, err := extractor.GenerateServiceExamples(serviceName, serviceOps.Operations)
+	if err != nil {
+		reportProblem(report, serviceName, "Error generating examples for %s: %v", serviceName, err)
+		return
+	}
+
+	examplesFile := filepath.Join(outputDir, serviceName+"-examples.json")
+	if err := extractor.WriteServiceExamplesJSON(examples, examplesFile); err != nil {
+		reportProblem(report, serviceName, "Error writing examples file for %s: %v", serviceName, err)
+		return
+	}
+	fmt.Printf("%s: %d examples → %s\n", serviceName, len(examples.Examples), examplesFile)
+}
+
+// writeBackstageCatalog writes the Backstage catalog entities and TechDocs coverage page of a service.
+// With --generate-openapi the catalog also describes the service API, defined by its OpenAPI document.
+func writeBackstageCatalog(serviceName string, serviceOps *extractor.ServiceOperations, opts *extractOptions, report *extractor.StatusReport) {
+	openAPIFile := ""
+	if opts.generateOpenAPI {
+		openAPIFile = serviceName + "-openapi.json"
+	}
+	entities := extractor.NewBackstageEntities(serviceOps, opts.backstage, openAPIFile)
+	catalogFile := filepath.Join(opts.output, serviceName+"-catalog-info.yaml")
+	if err := extractor.WriteBackstageCatalog(entities, catalogFile); err != nil {
+		reportProblem(report, serviceName, "Error writing Backstage catalog for %s: %v", serviceName, err)
+		return
+	}
+
+	coverageFile := filepath.Join(opts.output, serviceName+"-coverage.md")
+	if err := extractor.WriteCoverageMarkdown(serviceOps, coverageFile); err != nil {
+		reportProblem(report, serviceName, "Error writing coverage page for %s: %v", serviceName, err)
+		return
+	}
+	fmt.Printf("%s: Backstage catalog → %s, coverage page → %s\n", serviceName, catalogFile, coverageFile)
+}
+
+// writeCoverageBadge writes the shields.io endpoint badge showing the coverage of a service
+func writeCoverageBadge(serviceName string, serviceOps *extractor.ServiceOperations, opts *extractOptions, report *extractor.StatusReport) {
+	badge := extractor.NewCoverageBadge(serviceOps, opts.badgeLabel)
+	badgeFile := filepath.Join(opts.output, serviceName+"-badge.json")
+	if err := extractor.WriteCoverageBadgeJSON(badge, badgeFile); err != nil {
+		reportProblem(report, serviceName, "Error writing badge for %s: %v", serviceName, err)
+		return
+	}
+	fmt.Printf("%s: %s badge → %s\n", serviceName, badge.Message, badgeFile)
+}
+
+// writeDataPlaneOperations writes the data plane operations of a service for application teams
+func writeDataPlaneOperations(serviceName string, serviceOps *extractor.ServiceOperations, outputDir string, report *extractor.StatusReport) {
+	export := extractor.NewDataPlaneOperations(serviceName, serviceOps.Operations)
+	dataPlaneFile := filepath.Join(outputDir, serviceName+extractor.DataPlaneOperationsSuffix)
+	if err := extractor.WriteDataPlaneOperationsJSON(export, dataPlaneFile); err != nil {
+		reportProblem(report, serviceName, "Error writing data plane operations for %s: %v", serviceName, err)
+		return
+	}
+	fmt.Printf("%s: %d data plane operations → %s\n", serviceName, export.DataPlaneOperations, dataPlaneFile)
+}
+
+// writeAppPolicy generates and writes the application-facing policy of a service for one partition,
+// to <service>-app-policy.json for the aws partition and <service>-app-policy-<partition>.json otherwise
+func writeAppPolicy(serviceName string, serviceOps *extractor.ServiceOperations, partition, outputDir string, report *extractor.StatusReport) {
+	policy, err := extractor.GenerateDataPlanePolicy(serviceName, serviceOps.Operations, partition)
+	if err != nil {
+		fmt.Printf("Skipping application policy for %s: %v\n", serviceName, err)
+		return
+	}
+	if validateErr := extractor.ValidatePolicyJSON(*policy); validateErr != nil {
+		reportProblem(report, serviceName, "Warning: Application policy validation failed for %s: %v", serviceName, validateErr)
+	}
+
+	policyFile := filepath.Join(outputDir, serviceName+"-app-policy.json")
+	if partition != extractor.DefaultPartition {
+		policyFile = filepath.Join(outputDir, fmt.Sprintf("%s-app-policy-%s.json", serviceName, partition))
+	}
+	if err := extractor.WritePolicyJSON(policy, policyFile); err != nil {
+		reportProblem(report, serviceName, "Error writing application policy for %s: %v", serviceName, err)
+		return
+	}
+	fmt.Printf("%s: application policy → %s\n", serviceName, policyFile)
+}
+
+// writeTrustPolicies generates and writes the IRSA and Pod Identity trust policies of a service for
+// one partition, to <service>-trust-policy-<kind>.json for the aws partition and
+// <service>-trust-policy-<kind>-<partition>.json otherwise
+func writeTrustPolicies(serviceName, partition, outputDir string, trustConfig extractor.TrustPolicyConfig, report *extractor.StatusReport) {
+	policyFile := func(kind string) string {
+		if partition == extractor.DefaultPartition {
+			return filepath.Join(outputDir, fmt.Sprintf("%s-trust-policy-%s.json", serviceName, kind))
+		}
+		return filepath.Join(outputDir, fmt.Sprintf("%s-trust-policy-%s-%s.json", serviceName, kind, partition))
+	}
+
+	if trustConfig.OIDCProvider == "" {
+		fmt.Printf("Skipping IRSA trust policy for %s: --oidc-provider not set\n", serviceName)
+	} else if irsaPolicy, err := extractor.GenerateIRSATrustPolicy(serviceName, trustConfig, partition); err != nil {
+		reportProblem(report, serviceName, "Error generating IRSA trust policy for %s: %v", serviceName, err)
+	} else {
+		writeTrustPolicy(serviceName, policyFile("irsa"), irsaPolicy, report)
+	}
+
+	podIdentityPolicy, err := extractor.GeneratePodIdentityTrustPolicy(trustConfig, partition)
+	if err != nil {
+		reportProblem(report, serviceName, "Error generating Pod Identity trust policy for %s: %v", serviceName, err)
+		return
+	}
+	writeTrustPolicy(serviceName, policyFile("pod-identity"), podIdentityPolicy, report)
+}
+
+// writeTrustPolicy validates and writes a single trust policy file
+func writeTrustPolicy(serviceName, policyFile string, policy *extractor.IAMPolicy, report *extractor.StatusReport) {
+	if validateErr := extractor.ValidateTrustPolicyJSON(*policy); validateErr != nil {
+		reportProblem(report, serviceName, "Warning: Trust policy validation failed for %s: %v", serviceName, validateErr)
+	}
+	if err := extractor.WritePolicyJSON(policy, policyFile); err != nil {
+		reportProblem(report, serviceName, "Error writing trust policy file for %s: %v", serviceName, err)
+		return
+	}
+	fmt.Printf("%s: trust policy → %s\n", serviceName, policyFile)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// settingsFor parses the arguments with the extraction command's flags and returns its extraction settings
+func settingsFor(t *testing.T, args ...string) []string {
+	t.Helper()
+	cmd := newRootCommand()
+	cmd.InitDefaultHelpFlag()
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := extractionSettings(&extractOptions{flags: cmd.Flags()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return settings
+}
+
+func TestExtractionSettings(t *testing.T) {
+	cmd := newRootCommand()
+	cmd.InitDefaultHelpFlag()
+	if err := cmd.ParseFlags(nil); err != nil {
+		t.Fatal(err)
+	}
+	for name := range reuseNeutralFlags {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("reuseNeutralFlags lists %s, which is not a flag", name)
+		}
+	}
+
+	roadmap := filepath.Join(t.TempDir(), "roadmap.yaml")
+	if err := os.WriteFile(roadmap, []byte("foo: [CreateBar]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	base := settingsFor(t, "--service=foo", "--roadmap="+roadmap)
+	if !contains(base, "roadmap=foo: [CreateBar]\n") || !contains(base, "classify=false") {
+		t.Errorf("settings %q miss the roadmap contents or --classify", base)
+	}
+
+	// Flags that select services or outputs leave the settings alone
+	if settings := settingsFor(t, "--service=foo,bar", "--roadmap="+roadmap, "--generate-policies", "--output=out"); !reflect.DeepEqual(settings, base) {
+		t.Errorf("selecting services and outputs changed the settings:\n%q\n%q", settings, base)
+	}
+	for _, args := range [][]string{{"--classify"}, {"--max-scan-file-size=1"}, {"--scan-timeout=1s"}} {
+		if settings := settingsFor(t, append(args, "--service=foo", "--roadmap="+roadmap)...); reflect.DeepEqual(settings, base) {
+			t.Errorf("%s did not change the settings", strings.Join(args, " "))
+		}
+	}
+
+	if err := os.WriteFile(roadmap, []byte("foo: [DeleteBar]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if settings := settingsFor(t, "--service=foo", "--roadmap="+roadmap); reflect.DeepEqual(settings, base) {
+		t.Error("changing the roadmap did not change the settings")
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newSelfCheckCommand builds the command verifying the mounted directories before a run
+func newSelfCheckCommand() *cobra.Command {
+	var output, format string
+
+	cmd := &cobra.Command{
+		Use:   "self-check",
+		Short: "Verify the models, controllers, cache and output directories before running",
+		Long: `Checks that the models directory contains readable service models, that the
+controllers directory contains <service>-controller directories, that the cache
+directory and the optional output directory are writable, and that git is
+available. Meant as the first step of a container entrypoint or an init
+container, so missing or read-only mounts fail fast. Exits non-zero when a
+check fails; warnings don't fail the check.`,
+		Example: `  docker run --rm -v $PWD/api-models-aws/models:/models:ro -v $PWD:/controllers:ro \
+    ack-api-extractor self-check --output=/out`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printCheckResults(extractor.RunSelfCheck(output), format)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&output, "output", "", "Output directory to verify write access for")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.MarkFlagDirname("output")
+
+	return cmd
+}
+
+// printCheckResults prints self-check results with their remediation and fails when a check failed
+func printCheckResults(results []extractor.SelfCheckResult, format string) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "text":
+		for _, result := range results {
+			fmt.Printf("%-5s %-12s %s\n", result.Status, result.Name, result.Message)
+			if result.Remediation != "" && result.Status != extractor.SelfCheckOK {
+				fmt.Printf("%-18s → %s\n", "", result.Remediation)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown format %q, expected text or json", format)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == extractor.SelfCheckFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
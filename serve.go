@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	extractorv1 "github.com/aws-controllers-k8s/ack-api-extractor/api/extractor/v1"
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newServeCommand builds the command serving the extractor over gRPC
+func newServeCommand() *cobra.Command {
+	var address string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve extraction, policy generation and classification over gRPC",
+		Long: `Starts a gRPC server implementing extractor.v1.ExtractorService, defined in
+api/extractor/v1/extractor.proto, so platforms written in other languages can
+extract operations, generate policies and classify operations with typed
+contracts. Server reflection is enabled for tools such as grpcurl. Requests are
+served concurrently against the models and controllers directories of the
+server.`,
+		Example: `  ack-api-extractor serve --address=:50051
+  grpcurl -plaintext -d '{"service_name": "dynamodb"}' localhost:50051 extractor.v1.ExtractorService/ExtractService`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", address, err)
+			}
+
+			server := grpc.NewServer()
+			extractorv1.RegisterExtractorServiceServer(server, &extractorServer{})
+			reflection.Register(server)
+
+			stop := make(chan os.Signal, 1)
+			signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-stop
+				server.GracefulStop()
+			}()
+
+			fmt.Printf("Serving gRPC on %s\n", listener.Addr())
+			return server.Serve(listener)
+		},
+	}
+
+	cmd.Flags().StringVar(&address, "address", ":50051", "Address to listen on")
+
+	return cmd
+}
+
+// extractorServer implements the gRPC ExtractorService on top of the extractor package
+type extractorServer struct {
+	extractorv1.UnimplementedExtractorServiceServer
+
+	// mu guards extractions and pending
+	mu sync.Mutex
+	// extractions holds the last extraction of each service, with and without classification
+	extractions map[string]extraction
+	// pending holds the extractions in progress, which concurrent requests for the same key wait for
+	pending map[string]*pendingExtraction
+	// classifyMu serializes the requests calling Bedrock: classifications of a service reuse the
+	// agent session of the run, whose history concurrent batches would interleave
+	classifyMu sync.Mutex
+}
+
+// pendingExtraction is an extraction in progress; done is closed once serviceOps and err are set
+type pendingExtraction struct {
+	done       chan struct{}
+	serviceOps *extractor.ServiceOperations
+	err        error
+}
+
+// extraction is a cached extraction of a service and the hash of the inputs it was extracted from
+type extraction struct {
+	inputHash  string
+	serviceOps *extractor.ServiceOperations
+}
+
+// serviceOperations returns the operations of a service, extracting them only when the model or
+// controller changed since the last request, so paging through a large service doesn't extract it
+// again for every page. Concurrent requests for the same service share one extraction. The returned
+// operations are shared between requests and must not be modified.
+func (s *extractorServer) serviceOperations(ctx context.Context, serviceName string, classify bool) (*extractor.ServiceOperations, error) {
+	key := fmt.Sprintf("%s/%t", serviceName, classify)
+	for {
+		// Services whose inputs can't be hashed are extracted every time, which reports the problem
+		inputHash, hashErr := extractor.ServiceInputHash(serviceName)
+
+		s.mu.Lock()
+		if cached, ok := s.extractions[key]; ok && hashErr == nil && cached.inputHash == inputHash {
+			s.mu.Unlock()
+			return cached.serviceOps, nil
+		}
+		if pending, ok := s.pending[key]; ok {
+			s.mu.Unlock()
+			select {
+			case <-pending.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// The extraction of a request that was cancelled is retried with this request's context
+			if errors.Is(pending.err, context.Canceled) || errors.Is(pending.err, context.DeadlineExceeded) {
+				continue
+			}
+			return pending.serviceOps, pending.err
+		}
+		pending := &pendingExtraction{done: make(chan struct{})}
+		if s.pending == nil {
+			s.pending = make(map[string]*pendingExtraction)
+		}
+		s.pending[key] = pending
+		s.mu.Unlock()
+
+		pending.serviceOps, pending.err = s.extract(ctx, serviceName, classify)
+
+		s.mu.Lock()
+		delete(s.pending, key)
+		if pending.err == nil && hashErr == nil {
+			if s.extractions == nil {
+				s.extractions = make(map[string]extraction)
+			}
+			s.extractions[key] = extraction{inputHash: inputHash, serviceOps: pending.serviceOps}
+		}
+		s.mu.Unlock()
+		close(pending.done)
+		return pending.serviceOps, pending.err
+	}
+}
+
+// extract extracts the operations of a service, holding classifyMu while they are classified
+func (s *extractorServer) extract(ctx context.Context, serviceName string, classify bool) (*extractor.ServiceOperations, error) {
+	if classify {
+		s.classifyMu.Lock()
+		defer s.classifyMu.Unlock()
+	}
+	return extractor.ExtractDetailedOperationsFromServiceContext(ctx, serviceName, classify)
+}
+
+// ExtractService extracts the operations of a service
+func (s *extractorServer) ExtractService(ctx context.Context, req *extractorv1.ExtractServiceRequest) (*extractorv1.ExtractServiceResponse, error) {
+	if req.GetServiceName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_name is required")
+	}
+	filter, err := operationFilter(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	serviceOps, err := s.serviceOperations(ctx, req.GetServiceName(), req.GetClassify())
+	if err != nil {
+		return nil, extractionStatus(err)
+	}
+
+	// Unpaged responses keep the operations in extraction order
+	operations := extractor.FilterOperations(serviceOps.Operations, filter)
+	matched := len(operations)
+	var nextCursor string
+	if req.GetLimit() != 0 || req.GetCursor() != "" {
+		operations, nextCursor, err = extractor.PageOperations(operations, int(req.GetLimit()), req.GetCursor())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	operationsJSON, err := json.Marshal(operations)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal operations: %v", err)
+	}
+	resp := &extractorv1.ExtractServiceResponse{
+		ServiceName:            serviceOps.ServiceName,
+		TotalOperations:        int32(serviceOps.TotalOperations),
+		SupportedOperations:    int32(serviceOps.SupportedOperations),
+		ControlPlaneOperations: int32(serviceOps.ControlPlaneOps),
+		SupportCoverage:        serviceOps.SupportCoverage,
+		RelevantCoverage:       serviceOps.RelevantCoverage,
+		OperationsJson:         string(operationsJSON),
+		NextCursor:             nextCursor,
+		MatchedOperations:      int32(matched),
+	}
+	for _, op := range operations {
+		operation := &extractorv1.Operation{
+			Name:               op.Name,
+			Type:               op.Type.String(),
+			SupportStatus:      op.SupportStatus.String(),
+			Relevance:          op.Relevance,
+			Declarative:        op.Declarative,
+			ApiVersion:         op.APIVersion,
+			ReleaseStage:       string(op.ReleaseStage),
+			Consistency:        string(op.Consistency),
+			ExclusionReason:    op.ExclusionReason,
+			PredictedResource:  op.PredictedResource,
+			CloudformationType: op.CloudFormationType,
+		}
+		for _, location := range op.Locations {
+			operation.Locations = append(operation.Locations, &extractorv1.Location{File: location.File, Line: int32(location.Line)})
+		}
+		resp.Operations = append(resp.Operations, operation)
+	}
+	return resp, nil
+}
+
+// operationFilter builds the filter of an ExtractService request
+func operationFilter(req *extractorv1.ExtractServiceRequest) (extractor.OperationFilter, error) {
+	filter := extractor.OperationFilter{NamePrefix: req.GetNamePrefix()}
+	if req.GetType() != "" {
+		opType, err := extractor.ParseOperationType(req.GetType())
+		if err != nil {
+			return filter, err
+		}
+		filter.Type = opType
+	}
+	if req.Supported != nil {
+		supported := req.GetSupported()
+		filter.Supported = &supported
+	}
+	return filter, nil
+}
+
+// GeneratePolicy generates the permission policy of a service, split into managed policies when it is too large
+func (s *extractorServer) GeneratePolicy(ctx context.Context, req *extractorv1.GeneratePolicyRequest) (*extractorv1.GeneratePolicyResponse, error) {
+	serviceName := req.GetServiceName()
+	if serviceName == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_name is required")
+	}
+	partition := req.GetPartition()
+	if partition == "" {
+		partition = extractor.DefaultPartition
+	}
+	if err := extractor.ValidatePartition(partition); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	serviceOps, err := s.serviceOperations(ctx, serviceName, false)
+	if err != nil {
+		return nil, extractionStatus(err)
+	}
+	policy, err := extractor.GeneratePartitionPolicy(serviceName, serviceOps.Operations, partition)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	extractor.AddCrossServiceStatements(policy, serviceOps.UnmodeledCalls)
+	// Without the model the unknown-action rule is skipped rather than failing the request
+	knownActions, _ := extractor.PolicyKnownActions(serviceName, serviceOps)
+
+	policies, err := extractor.SplitPolicy(policy)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	resp := &extractorv1.GeneratePolicyResponse{}
+	for i, chunk := range policies {
+		document, err := json.MarshalIndent(chunk, "", "  ")
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal policy: %v", err)
+		}
+		resp.Policies = append(resp.Policies, &extractorv1.Policy{
+			Name:     extractor.ManagedPolicyName(serviceName, i, len(policies)),
+			Document: string(document),
+		})
+		for _, finding := range extractor.LintPolicy(*chunk, knownActions) {
+			resp.Findings = append(resp.Findings, &extractorv1.PolicyFinding{
+				Severity:  string(finding.Severity),
+				Rule:      finding.Rule,
+				Policy:    int32(i),
+				Statement: int32(finding.Statement),
+				Message:   finding.Message,
+			})
+		}
+	}
+	return resp, nil
+}
+
+// Classify classifies operation names with Bedrock
+func (s *extractorServer) Classify(ctx context.Context, req *extractorv1.ClassifyRequest) (*extractorv1.ClassifyResponse, error) {
+	if len(req.GetOperations()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "operations is required")
+	}
+	serviceName := req.GetServiceName()
+	if serviceName == "" {
+		serviceName = "aws"
+	}
+	s.classifyMu.Lock()
+	defer s.classifyMu.Unlock()
+
+	operations := make([]extractor.Operation, 0, len(req.GetOperations()))
+	for _, name := range uniqueNames(req.GetOperations()) {
+		operations = append(operations, extractor.Operation{Name: name})
+	}
+	classification, err := extractor.ClassifyOperations(serviceName, operations)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "classification failed: %v", err)
+	}
+	return &extractorv1.ClassifyResponse{
+		ControlPlane: classification.ControlPlane,
+		DataPlane:    classification.DataPlane,
+	}, nil
+}
+
+// extractionStatus converts an extraction error to a gRPC status using its error category
+func extractionStatus(err error) error {
+	code := codes.Internal
+	switch extractor.ErrorCategoryOf(err) {
+	case extractor.ErrorCategoryModelNotFound:
+		code = codes.NotFound
+	case extractor.ErrorCategoryModelInvalid, extractor.ErrorCategoryNoOperations:
+		code = codes.FailedPrecondition
+	}
+	if errors.Is(err, context.Canceled) {
+		code = codes.Canceled
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Error())
+}
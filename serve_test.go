@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	extractorv1 "github.com/aws-controllers-k8s/ack-api-extractor/api/extractor/v1"
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+	"github.com/aws-controllers-k8s/ack-api-extractor/pkg/extractortest"
+)
+
+func TestExtractServicePagesFromCache(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("foo").Operations("CreateBar", "DeleteBar", "DescribeBar"))
+	w.AddController(t, "foo", extractortest.NewController().SDKCall("bar", "CreateBar").FS())
+	s := &extractorServer{}
+	ctx := context.Background()
+
+	var names []string
+	req := &extractorv1.ExtractServiceRequest{ServiceName: "foo", Limit: 1}
+	for page := 0; ; page++ {
+		resp, err := s.ExtractService(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, op := range resp.GetOperations() {
+			names = append(names, op.GetName())
+		}
+		if page == 0 {
+			if cached := s.extractions["foo/false"].serviceOps; cached == nil {
+				t.Fatal("the extraction of foo was not cached")
+			}
+		}
+		if resp.GetNextCursor() == "" {
+			break
+		}
+		req.Cursor = resp.GetNextCursor()
+	}
+	if len(names) != 3 {
+		t.Errorf("paged through %v, want 3 operations", names)
+	}
+	cached := s.extractions["foo/false"].serviceOps
+
+	if _, err := s.GeneratePolicy(ctx, &extractorv1.GeneratePolicyRequest{ServiceName: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if s.extractions["foo/false"].serviceOps != cached {
+		t.Error("GeneratePolicy extracted foo again although nothing changed")
+	}
+
+	// A changed controller is extracted again
+	hooks := filepath.Join(w.ControllersDir, "foo-controller", "pkg", "resource", "bar", "hooks.go")
+	if err := os.WriteFile(hooks, []byte("package bar\n\n\t_, err = rm.sdkapi.DeleteBar(ctx, input)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.ExtractService(ctx, &extractorv1.ExtractServiceRequest{ServiceName: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.extractions["foo/false"].serviceOps == cached || resp.GetSupportedOperations() != 2 {
+		t.Errorf("%d supported operations after changing the controller, want 2 from a new extraction", resp.GetSupportedOperations())
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.ExtractService(cancelled, &extractorv1.ExtractServiceRequest{ServiceName: "foo", Classify: true}); status.Code(err) != codes.Canceled {
+		t.Errorf("cancelled request returned %v, want Canceled", err)
+	}
+}
+
+func TestServiceOperationsSharesExtractions(t *testing.T) {
+	w := extractortest.NewWorkspace(t)
+	w.AddModel(t, extractortest.NewModel("foo").Operations("CreateBar", "DeleteBar"))
+	w.AddController(t, "foo", extractortest.NewController().SDKCall("bar", "CreateBar").FS())
+	s := &extractorServer{}
+	ctx := context.Background()
+
+	type result struct {
+		serviceOps *extractor.ServiceOperations
+		err        error
+	}
+	request := func() chan result {
+		results := make(chan result, 1)
+		go func() {
+			serviceOps, err := s.serviceOperations(ctx, "foo", false)
+			results <- result{serviceOps, err}
+		}()
+		return results
+	}
+
+	// A request for a service being extracted waits for that extraction
+	pending := &pendingExtraction{done: make(chan struct{})}
+	s.pending = map[string]*pendingExtraction{"foo/false": pending}
+	results := request()
+	select {
+	case got := <-results:
+		t.Fatalf("request returned %v before the pending extraction finished", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+	shared := &extractor.ServiceOperations{ServiceName: "foo"}
+	pending.serviceOps = shared
+	finish := func(pending *pendingExtraction) {
+		s.mu.Lock()
+		delete(s.pending, "foo/false")
+		s.mu.Unlock()
+		close(pending.done)
+	}
+	finish(pending)
+	if got := <-results; got.err != nil || got.serviceOps != shared {
+		t.Errorf("request returned %v, %v, want the pending extraction", got.serviceOps, got.err)
+	}
+
+	// The extraction of a cancelled request is retried
+	pending = &pendingExtraction{done: make(chan struct{}), err: context.Canceled}
+	s.mu.Lock()
+	s.pending["foo/false"] = pending
+	s.mu.Unlock()
+	results = request()
+	finish(pending)
+	got := <-results
+	if got.err != nil || got.serviceOps == nil || got.serviceOps == shared || got.serviceOps.SupportedOperations != 1 {
+		t.Fatalf("request returned %v, %v, want a new extraction", got.serviceOps, got.err)
+	}
+
+	// Requests answered from the kept extraction don't wait for Bedrock
+	s.classifyMu.Lock()
+	defer s.classifyMu.Unlock()
+	select {
+	case got := <-request():
+		if got.serviceOps != s.extractions["foo/false"].serviceOps {
+			t.Error("the kept extraction was not returned")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a cached request waited for a classification")
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newSimulateCommand builds the command verifying a policy with the IAM policy simulator
+func newSimulateCommand() *cobra.Command {
+	var serviceName, policyFile, format string
+	var resourceARNs []string
+
+	cmd := &cobra.Command{
+		Use:   "simulate --service=<service>",
+		Short: "Verify a policy allows every extracted action with the IAM policy simulator",
+		Long: `Extracts the operations of a service and runs the policy generated for them, or
+the policy given with --policy, through the IAM SimulateCustomPolicy API for
+each action the generated policy should grant, named from the operations and
+IAM prefix of the service model rather than by the generator. Actions the
+policy does not actually allow, and generated actions that are not actions
+of the model, e.g. because of a wrong service prefix or action name, are
+reported and make the command fail. Requires AWS credentials allowed to call
+iam:SimulateCustomPolicy.`,
+		Example: `  ack-api-extractor simulate --service=sfn
+  ack-api-extractor simulate --service=s3 --policy=../s3-controller/config/iam/recommended-inline-policy`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if serviceName == "" {
+				return fmt.Errorf("--service is required")
+			}
+
+			// Extraction progress would corrupt JSON printed to stdout
+			reportProgressToStderr()
+			serviceOps, err := extractor.ExtractDetailedOperationsFromService(serviceName, false)
+			if err != nil {
+				return err
+			}
+			// Actions are named from the model so a wrong mapping in the generator is caught
+			actions, mismatches, err := extractor.ExpectedPolicyActions(serviceName, serviceOps.Operations)
+			if err != nil {
+				return err
+			}
+
+			var document []byte
+			if policyFile != "" {
+				document, err = os.ReadFile(policyFile)
+				if err != nil {
+					return fmt.Errorf("failed to read policy: %w", err)
+				}
+			} else {
+				policy, err := extractor.GenerateSinglePolicy(serviceName, serviceOps.Operations)
+				if err != nil {
+					return err
+				}
+				document, err = json.Marshal(policy)
+				if err != nil {
+					return fmt.Errorf("failed to marshal policy: %w", err)
+				}
+			}
+
+			simulation, err := extractor.SimulatePolicy(document, actions, resourceARNs)
+			if err != nil {
+				return err
+			}
+			simulation.Mismatches = mismatches
+
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(simulation, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			case "text":
+				for _, result := range simulation.Results {
+					if result.Decision != extractor.SimulationAllowed {
+						fmt.Printf("%s on %s: %s\n", result.Action, result.Resource, result.Decision)
+					}
+				}
+				for _, action := range simulation.Mismatches {
+					fmt.Printf("%s is granted by the generated policy but is not an action of the model\n", action)
+				}
+				fmt.Printf("%d of %d action(s) allowed\n", simulation.Allowed, len(simulation.Results))
+			default:
+				return fmt.Errorf("unknown format %q, expected text or json", format)
+			}
+
+			if simulation.Denied > 0 {
+				return fmt.Errorf("%d action(s) not allowed by the policy", simulation.Denied)
+			}
+			if len(simulation.Mismatches) > 0 {
+				return fmt.Errorf("%d generated action(s) are not actions of the model", len(simulation.Mismatches))
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serviceName, "service", "", "AWS service name")
+	flags.StringVar(&policyFile, "policy", "", "Policy file to simulate instead of the generated policy")
+	flags.StringSliceVar(&resourceARNs, "resource-arn", nil, "Resource ARNs to simulate the actions against (default *)")
+	flags.StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.RegisterFlagCompletionFunc("service", completeServiceNames)
+	cmd.MarkFlagFilename("policy", "json")
+
+	return cmd
+}
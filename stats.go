@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// newStatsCommand builds the command aggregating generated outputs into org-wide statistics
+func newStatsCommand() *cobra.Command {
+	var previous, format string
+	var top int
+
+	cmd := &cobra.Command{
+		Use:   "stats <output-dir>...",
+		Short: "Print org-wide operation and coverage statistics of generated outputs",
+		Long: `Aggregates the operations of every service in one or more output directories
+(the combined operations.json or the <service>-operations.json files) and
+prints the total number of operations and control plane operations across
+AWS, the overall ACK coverage, and the least-covered services. A service's
+coverage is its control plane coverage when its operations were classified
+and its support coverage otherwise. With --previous, the change since an
+earlier snapshot is reported as well. Services found in several directories
+are taken from the last one.`,
+		Example: `  ack-api-extractor stats ./results
+  ack-api-extractor stats ./results --previous=./results-2025-q1 --format=markdown`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			services, err := readOperationsPaths(args)
+			if err != nil {
+				return err
+			}
+			stats := extractor.ComputeOrgStats(services, top)
+
+			if previous != "" {
+				previousServices, err := readOperationsPaths([]string{previous})
+				if err != nil {
+					return fmt.Errorf("error reading previous snapshot: %w", err)
+				}
+				extractor.CompareOrgStats(stats, extractor.ComputeOrgStats(previousServices, top), services, previousServices)
+			}
+
+			switch format {
+			case "json":
+				data, err := json.MarshalIndent(stats, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(data))
+			case "text":
+				printOrgStats(stats)
+			case "markdown":
+				printOrgStatsMarkdown(stats)
+			default:
+				return fmt.Errorf("unknown format %q, expected text, json or markdown", format)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&previous, "previous", "", "Output directory of an earlier snapshot to report the trend against")
+	flags.IntVar(&top, "top", 10, "Number of least-covered services to list")
+	flags.StringVar(&format, "format", "text", "Output format: text, json or markdown")
+	cmd.MarkFlagDirname("previous")
+
+	return cmd
+}
+
+// printOrgStats prints the org statistics as text
+func printOrgStats(stats *extractor.OrgStats) {
+	fmt.Printf("Services:                 %d\n", stats.Services)
+	fmt.Printf("Operations:               %d\n", stats.TotalOperations)
+	fmt.Printf("Control plane operations: %d (%d supported)\n", stats.ControlPlaneOperations, stats.SupportedControlPlaneOps)
+	fmt.Printf("Coverage:                 %.1f%% of operations, %.1f%% of control plane operations\n", stats.Coverage, stats.ControlPlaneCoverage)
+
+	if trend := stats.Trend; trend != nil {
+		fmt.Printf("\nSince the previous snapshot (%d services):\n", trend.PreviousServices)
+		fmt.Printf("  %+d operations, %+d control plane operations, %+d supported control plane operations\n",
+			trend.Operations, trend.ControlPlaneOperations, trend.SupportedControlPlaneOps)
+		fmt.Printf("  coverage %+.1f points, control plane coverage %+.1f points\n", trend.CoveragePoints, trend.ControlPlaneCoveragePoints)
+		if len(trend.ServicesAdded) > 0 {
+			fmt.Printf("  services added: %s\n", strings.Join(trend.ServicesAdded, ", "))
+		}
+		if len(trend.ServicesRemoved) > 0 {
+			fmt.Printf("  services removed: %s\n", strings.Join(trend.ServicesRemoved, ", "))
+		}
+	}
+
+	if len(stats.LeastCovered) > 0 {
+		fmt.Printf("\nLeast covered services:\n")
+		for i, service := range stats.LeastCovered {
+			fmt.Printf("%3d. %-30s %5.1f%%  (%d of %d control plane operations)\n",
+				i+1, service.ServiceName, service.Coverage, service.SupportedControlPlaneOps, service.ControlPlaneOperations)
+		}
+	}
+}
+
+// printOrgStatsMarkdown prints the org statistics as Markdown tables, ready to paste into a report
+func printOrgStatsMarkdown(stats *extractor.OrgStats) {
+	changes := make([]string, 6)
+	if trend := stats.Trend; trend != nil {
+		changes = []string{
+			fmt.Sprintf("%+d", stats.Services-trend.PreviousServices),
+			fmt.Sprintf("%+d", trend.Operations),
+			fmt.Sprintf("%+d", trend.ControlPlaneOperations),
+			fmt.Sprintf("%+d", trend.SupportedControlPlaneOps),
+			fmt.Sprintf("%+.1f pts", trend.CoveragePoints),
+			fmt.Sprintf("%+.1f pts", trend.ControlPlaneCoveragePoints),
+		}
+	}
+	fmt.Println("| Metric | Value | Change |")
+	fmt.Println("| --- | ---: | ---: |")
+	fmt.Printf("| Services | %d | %s |\n", stats.Services, changes[0])
+	fmt.Printf("| Operations | %d | %s |\n", stats.TotalOperations, changes[1])
+	fmt.Printf("| Control plane operations | %d | %s |\n", stats.ControlPlaneOperations, changes[2])
+	fmt.Printf("| Supported control plane operations | %d | %s |\n", stats.SupportedControlPlaneOps, changes[3])
+	fmt.Printf("| Coverage | %.1f%% | %s |\n", stats.Coverage, changes[4])
+	fmt.Printf("| Control plane coverage | %.1f%% | %s |\n", stats.ControlPlaneCoverage, changes[5])
+
+	if len(stats.LeastCovered) > 0 {
+		fmt.Println()
+		fmt.Println("| # | Service | Coverage | Supported | Control plane operations |")
+		fmt.Println("| ---: | --- | ---: | ---: | ---: |")
+		for i, service := range stats.LeastCovered {
+			fmt.Printf("| %d | %s | %.1f%% | %d | %d |\n", i+1, service.ServiceName, service.Coverage, service.SupportedControlPlaneOps, service.ControlPlaneOperations)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+// tuiPageSize is the number of list rows shown at once
+const tuiPageSize = 20
+
+// newTUICommand builds the interactive terminal UI command
+func newTUICommand() *cobra.Command {
+	var exportDir string
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Pick services, run the extraction and browse operations interactively",
+		Long: `Opens an interactive terminal UI to pick services, toggle Bedrock
+classification, watch the extraction progress and browse the extracted
+operations with fuzzy search. The filtered operations view can be exported
+to a JSON file.`,
+		Example: `  ack-api-extractor tui --models-dir=../api-models-aws/models --export-dir=./results`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			services, err := extractor.ListAvailableServices()
+			if err != nil {
+				return err
+			}
+			if len(services) == 0 {
+				return fmt.Errorf("no services found in the models directory")
+			}
+
+			program := tea.NewProgram(newTUIModel(services, exportDir), tea.WithAltScreen())
+			// Progress is shown on the progress screen instead of being printed over the UI
+			extractor.SetProgressReporter(extractor.ProgressFunc(func(event extractor.ProgressEvent) {
+				program.Send(progressEventMsg(event))
+			}))
+			_, err = program.Run()
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&exportDir, "export-dir", ".", "Directory exported views are written to")
+	cmd.MarkFlagDirname("export-dir")
+
+	return cmd
+}
+
+// tuiScreen identifies the screen the UI is showing
+type tuiScreen int
+
+const (
+	screenSelect tuiScreen = iota
+	screenProgress
+	screenBrowse
+)
+
+// tuiRow is a single operation shown in the browse screen
+type tuiRow struct {
+	Service   string              `json:"service"`
+	Operation extractor.Operation `json:"operation"`
+}
+
+// serviceExtractedMsg reports the result of extracting one service
+type serviceExtractedMsg struct {
+	service string
+	ops     *extractor.ServiceOperations
+	err     error
+}
+
+// progressEventMsg reports a progress event of the running extraction
+type progressEventMsg extractor.ProgressEvent
+
+// tuiModel is the bubbletea model of the terminal UI
+type tuiModel struct {
+	screen    tuiScreen
+	exportDir string
+
+	services []string
+	selected map[string]bool
+	classify bool
+
+	// filter is the fuzzy search query of the current list; filtering is true while it is being typed
+	filter    string
+	filtering bool
+	cursor    int
+
+	queue    []string
+	current  string
+	activity string
+	errors   []string
+	results  []*extractor.ServiceOperations
+	started  time.Time
+
+	// operations, batch and batches follow the progress events of the current service
+	operations int
+	batch      int
+	batches    int
+	warnings   []string
+
+	rows   []tuiRow
+	status string
+}
+
+// newTUIModel creates the UI model for the given services
+func newTUIModel(services []string, exportDir string) tuiModel {
+	return tuiModel{
+		services:  services,
+		selected:  make(map[string]bool),
+		exportDir: exportDir,
+	}
+}
+
+// Init implements tea.Model
+func (m tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.filtering {
+			return m.updateFilter(msg), nil
+		}
+		switch m.screen {
+		case screenSelect:
+			return m.updateSelect(msg)
+		case screenBrowse:
+			return m.updateBrowse(msg)
+		}
+	case serviceExtractedMsg:
+		if msg.err != nil {
+			m.errors = append(m.errors, fmt.Sprintf("%s: %v", msg.service, msg.err))
+		} else {
+			m.results = append(m.results, msg.ops)
+		}
+		return m.extractNext()
+	case progressEventMsg:
+		return m.updateProgress(extractor.ProgressEvent(msg)), nil
+	}
+	return m, nil
+}
+
+// updateProgress follows a progress event of the running extraction
+func (m tuiModel) updateProgress(event extractor.ProgressEvent) tuiModel {
+	switch event.Kind {
+	case extractor.ProgressModelParsed:
+		m.operations = event.Operations
+	case extractor.ProgressBatchStarted, extractor.ProgressBatchClassified, extractor.ProgressBatchReused:
+		m.batch, m.batches = event.Batch, event.Batches
+	case extractor.ProgressWarning:
+		service := event.Service
+		if service == "" {
+			service = m.current
+		}
+		m.warnings = append(m.warnings, fmt.Sprintf("%s: %s", service, event.Message))
+		return m
+	}
+	m.activity = event.Message
+	return m
+}
+
+// updateFilter edits the fuzzy search query
+func (m tuiModel) updateFilter(msg tea.KeyMsg) tuiModel {
+	switch msg.Type {
+	case tea.KeyEnter, tea.KeyEsc:
+		m.filtering = false
+	case tea.KeyBackspace:
+		if len(m.filter) > 0 {
+			runes := []rune(m.filter)
+			m.filter = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+	}
+	m.cursor = 0
+	return m
+}
+
+// updateSelect handles keys on the service selection screen
+func (m tuiModel) updateSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := m.visibleServices()
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "/":
+		m.filtering = true
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = min(m.cursor+1, max(len(visible)-1, 0))
+	case " ":
+		if m.cursor < len(visible) {
+			service := visible[m.cursor]
+			m.selected[service] = !m.selected[service]
+		}
+	case "c":
+		m.classify = !m.classify
+	case "enter":
+		for _, service := range m.services {
+			if m.selected[service] {
+				m.queue = append(m.queue, service)
+			}
+		}
+		if len(m.queue) == 0 {
+			m.status = "Select at least one service with space"
+			return m, nil
+		}
+		m.screen = screenProgress
+		m.started = time.Now()
+		return m.extractNext()
+	}
+	return m, nil
+}
+
+// updateBrowse handles keys on the operations browser
+func (m tuiModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := m.visibleRows()
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "/":
+		m.filtering = true
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = min(m.cursor+1, max(len(visible)-1, 0))
+	case "e":
+		path, err := m.export(visible)
+		if err != nil {
+			m.status = fmt.Sprintf("Export failed: %v", err)
+		} else {
+			m.status = fmt.Sprintf("Exported %d operation(s) to %s", len(visible), path)
+		}
+	}
+	return m, nil
+}
+
+// extractNext starts extracting the next queued service, or switches to the browser when done
+func (m tuiModel) extractNext() (tea.Model, tea.Cmd) {
+	if len(m.queue) == 0 {
+		m.screen = screenBrowse
+		m.current = ""
+		m.cursor = 0
+		m.filter = ""
+		m.rows = buildTUIRows(m.results)
+		m.status = fmt.Sprintf("Extracted %d service(s) in %s", len(m.results), time.Since(m.started).Round(time.Millisecond))
+		return m, nil
+	}
+
+	service := m.queue[0]
+	m.queue = m.queue[1:]
+	m.current = service
+	m.activity = ""
+	m.operations, m.batch, m.batches = 0, 0, 0
+	classify := m.classify
+	return m, func() tea.Msg {
+		ops, err := extractor.ExtractDetailedOperationsFromService(service, classify)
+		return serviceExtractedMsg{service: service, ops: ops, err: err}
+	}
+}
+
+// buildTUIRows flattens the extracted services into sorted browser rows
+func buildTUIRows(results []*extractor.ServiceOperations) []tuiRow {
+	var rows []tuiRow
+	for _, serviceOps := range results {
+		for _, op := range serviceOps.Operations {
+			rows = append(rows, tuiRow{Service: serviceOps.ServiceName, Operation: op})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Service != rows[j].Service {
+			return rows[i].Service < rows[j].Service
+		}
+		return rows[i].Operation.Name < rows[j].Operation.Name
+	})
+	return rows
+}
+
+// visibleServices returns the services matching the filter
+func (m tuiModel) visibleServices() []string {
+	var visible []string
+	for _, service := range m.services {
+		if fuzzyMatch(m.filter, service) {
+			visible = append(visible, service)
+		}
+	}
+	return visible
+}
+
+// visibleRows returns the operations whose service, name, type or support status match the filter
+func (m tuiModel) visibleRows() []tuiRow {
+	var visible []tuiRow
+	for _, row := range m.rows {
+		text := strings.Join([]string{row.Service, row.Operation.Name, row.Operation.Type.String(), row.Operation.SupportStatus.String()}, " ")
+		if fuzzyMatch(m.filter, text) {
+			visible = append(visible, row)
+		}
+	}
+	return visible
+}
+
+// export writes the given rows to a timestamped JSON file in the export directory
+func (m tuiModel) export(rows []tuiRow) (string, error) {
+	if err := os.MkdirAll(m.exportDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal operations: %w", err)
+	}
+	path := filepath.Join(m.exportDir, fmt.Sprintf("operations-view-%s.json", time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// View implements tea.Model
+func (m tuiModel) View() string {
+	var b strings.Builder
+	switch m.screen {
+	case screenSelect:
+		m.viewSelect(&b)
+	case screenProgress:
+		m.viewProgress(&b)
+	case screenBrowse:
+		m.viewBrowse(&b)
+	}
+	if m.status != "" {
+		fmt.Fprintf(&b, "\n%s\n", m.status)
+	}
+	return b.String()
+}
+
+// viewSelect renders the service selection screen
+func (m tuiModel) viewSelect(b *strings.Builder) {
+	classification := "off"
+	if m.classify {
+		classification = "on"
+	}
+	fmt.Fprintf(b, "Select services (%d selected, classification %s)\n", countSelected(m.selected), classification)
+	m.viewFilter(b)
+
+	visible := m.visibleServices()
+	start, end := pageBounds(m.cursor, len(visible))
+	for i := start; i < end; i++ {
+		cursor, check := "  ", "[ ]"
+		if i == m.cursor {
+			cursor = "> "
+		}
+		if m.selected[visible[i]] {
+			check = "[x]"
+		}
+		fmt.Fprintf(b, "%s%s %s\n", cursor, check, visible[i])
+	}
+	b.WriteString("\nspace select • c toggle classification • / search • enter extract • q quit\n")
+}
+
+// viewProgress renders the extraction progress screen
+func (m tuiModel) viewProgress(b *strings.Builder) {
+	done := len(m.results) + len(m.errors)
+	total := done + len(m.queue) + 1
+	fmt.Fprintf(b, "Extracting %s (%d/%d, %s elapsed)\n", m.current, done+1, total, time.Since(m.started).Round(time.Second))
+	if m.operations > 0 {
+		fmt.Fprintf(b, "  %d operations in the model\n", m.operations)
+	}
+	if m.batches > 0 {
+		fmt.Fprintf(b, "  Classifying %s batch %d/%d\n", progressBar(m.batch, m.batches, 20), m.batch, m.batches)
+	}
+	fmt.Fprintf(b, "  %s\n\n", m.activity)
+	for _, serviceOps := range m.results {
+		fmt.Fprintf(b, "  ✓ %s: %d operations, %.1f%% coverage\n", serviceOps.ServiceName, len(serviceOps.Operations), serviceOps.SupportCoverage)
+	}
+	for _, err := range m.errors {
+		fmt.Fprintf(b, "  ✗ %s\n", err)
+	}
+	m.viewWarnings(b)
+}
+
+// viewWarnings renders the warnings reported by the extraction
+func (m tuiModel) viewWarnings(b *strings.Builder) {
+	for _, warning := range m.warnings {
+		fmt.Fprintf(b, "  ! %s\n", warning)
+	}
+}
+
+// progressBar renders done out of total as a bar of the given width
+func progressBar(done, total, width int) string {
+	filled := min(done*width/max(total, 1), width)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+}
+
+// viewBrowse renders the operations browser
+func (m tuiModel) viewBrowse(b *strings.Builder) {
+	visible := m.visibleRows()
+	fmt.Fprintf(b, "Operations (%d of %d)\n", len(visible), len(m.rows))
+	m.viewFilter(b)
+
+	start, end := pageBounds(m.cursor, len(visible))
+	for i := start; i < end; i++ {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		op := visible[i].Operation
+		fmt.Fprintf(b, "%s%-16s %-40s %-14s %s\n", cursor, visible[i].Service, op.Name, op.Type, op.SupportStatus)
+	}
+	if m.cursor < len(visible) {
+		op := visible[m.cursor].Operation
+		if op.File != "" {
+			fmt.Fprintf(b, "\n%s:%d\n", op.File, op.Line)
+		}
+	}
+	for _, err := range m.errors {
+		fmt.Fprintf(b, "\nError: %s", err)
+	}
+	for _, warning := range m.warnings {
+		fmt.Fprintf(b, "\nWarning: %s", warning)
+	}
+	b.WriteString("\n/ search • e export view • q quit\n")
+}
+
+// viewFilter renders the search query line
+func (m tuiModel) viewFilter(b *strings.Builder) {
+	switch {
+	case m.filtering:
+		fmt.Fprintf(b, "Search: %s_\n\n", m.filter)
+	case m.filter != "":
+		fmt.Fprintf(b, "Search: %s\n\n", m.filter)
+	default:
+		b.WriteString("\n")
+	}
+}
+
+// pageBounds returns the range of rows to show so the cursor stays visible
+func pageBounds(cursor, total int) (int, int) {
+	start := 0
+	if cursor >= tuiPageSize {
+		start = cursor - tuiPageSize + 1
+	}
+	return start, min(start+tuiPageSize, total)
+}
+
+// countSelected counts the selected entries of a selection map
+func countSelected(selection map[string]bool) int {
+	count := 0
+	for _, selected := range selection {
+		if selected {
+			count++
+		}
+	}
+	return count
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in order in text, ignoring case and spaces
+func fuzzyMatch(pattern, text string) bool {
+	text = strings.ToLower(text)
+	position := 0
+	for _, r := range strings.ToLower(pattern) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		index := strings.IndexRune(text[position:], r)
+		if index == -1 {
+			return false
+		}
+		position += index + len(string(r))
+	}
+	return true
+}
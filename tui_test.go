@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	extractor "github.com/aws-controllers-k8s/ack-api-extractor/pkg"
+)
+
+func TestTUIProgressScreen(t *testing.T) {
+	m := newTUIModel([]string{"foo"}, t.TempDir())
+	m.screen = screenProgress
+	m.current = "foo"
+
+	for _, event := range []extractor.ProgressEvent{
+		{Kind: extractor.ProgressModelParsed, Service: "foo", Operations: 12, Message: "Parsed model of foo"},
+		{Kind: extractor.ProgressBatchStarted, Service: "foo", Batch: 2, Batches: 4, Message: "Processing batch 2/4"},
+		{Kind: extractor.ProgressWarning, Service: "foo", Message: "failed to write checkpoint"},
+	} {
+		updated, _ := m.Update(progressEventMsg(event))
+		m = updated.(tuiModel)
+	}
+
+	view := m.View()
+	for _, want := range []string{"12 operations in the model", "[##########..........] batch 2/4", "Processing batch 2/4", "! foo: failed to write checkpoint"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("progress screen lacks %q:\n%s", want, view)
+		}
+	}
+}